@@ -12,12 +12,16 @@ var (
 	ErrGenericPermission      = errors.New("invalid permission")
 	ErrGenericInternalServer  = errors.New("internal server error")
 	ErrGenericRequestTimedOut = errors.New("request timeout error")
+	ErrGenericTooManyRequests = errors.New("too many requests")
+	ErrGenericRequestTooLarge = errors.New("request entity too large")
 )
 
 var (
-	ErrTenantNameIsEmpty      = errors.New("tenant name is empty")
-	ErrTenantNameAlreadyExist = errors.New("tenant name already exists")
-	ErrTenantNameIsInvalid    = errors.New("tenant name is invalid")
+	ErrTenantNameIsEmpty               = errors.New("tenant name is empty")
+	ErrTenantNameAlreadyExist          = errors.New("tenant name already exists")
+	ErrTenantNameIsInvalid             = errors.New("tenant name is invalid")
+	ErrTenantRegenerationModeIsInvalid = errors.New("tenant regeneration mode is invalid")
+	ErrTenantSignerBackendIsInvalid    = errors.New("invalid tenant signer backend")
 )
 
 var (
@@ -39,6 +43,10 @@ var (
 	ErrAccountResetTokenIsExpired    = errors.New("account reset token is expired")
 	ErrAccountIsBanned               = errors.New("account is banned")
 	ErrAccountIsInactive             = errors.New("account is inactive")
+	ErrAccountRefreshTokenIsEmpty    = errors.New("account refresh token is empty")
+	ErrAccountRefreshTokenIsInvalid  = errors.New("account refresh token is invalid")
+	ErrAccountRefreshTokenIsExpired  = errors.New("account refresh token is expired")
+	ErrAccountIsLocked               = errors.New("account is locked")
 )
 
 var (
@@ -50,6 +58,9 @@ var (
 	ErrProductIDIsEmpty                      = errors.New("product id is empty")
 	ErrProductIDIsInvalid                    = errors.New("product id is invalid")
 	ErrProductTokenExpirationFormatIsInvalid = errors.New("product token expiration format is invalid")
+	ErrProductTokenPermissionIsInvalid       = errors.New("product token permission is invalid")
+	ErrProductTokenScopeIsInvalid            = errors.New("resource does not belong to the product of the token")
+	ErrProductTokenExpiryIsInvalid           = errors.New("product token expiry must be in the future")
 )
 
 var (
@@ -78,34 +89,56 @@ var (
 	ErrPolicyInvalidHeartbeatBasis           = errors.New("policy heartbeat basis is invalid")
 	ErrPolicyInvalidCheckinInterval          = errors.New("policy checkin interval basis is invalid")
 	ErrPolicyEntitlementAlreadyExist         = errors.New("policy entitlement already exists")
+	ErrPolicyInvalidResurrectionStrategy     = errors.New("policy heartbeat resurrection strategy is invalid")
+	ErrPolicyKeysAreEmpty                    = errors.New("policy keys and key count are empty")
+	ErrPolicyKeyCountIsInvalid               = errors.New("policy key count is invalid")
+	ErrPolicyKeyAlreadyExist                 = errors.New("policy key already exists")
+	ErrPolicyIsNotPooled                     = errors.New("policy does not use a key pool")
+	ErrPolicyIsProtected                     = errors.New("policy is protected and can only be managed by an admin")
+	ErrPolicySignedKeysWithPool              = errors.New("policy signed keys cannot be used with a key pool")
+	ErrPolicyKeyOverlapPeriodIsInvalid       = errors.New("policy key overlap period must not be less than zero")
+	ErrPolicySignerBackendIsInvalid          = errors.New("invalid policy signer backend")
 )
 
 var (
-	ErrLicenseNameIsEmpty              = errors.New("license name is empty")
-	ErrLicenseProductIDIsEmpty         = errors.New("license product id is empty")
-	ErrLicensePolicyIDIsEmpty          = errors.New("license policy id is empty")
-	ErrLicenseExpiryFormatIsInvalid    = errors.New("license expiry format is invalid")
-	ErrLicenseIDIsEmpty                = errors.New("license id is empty")
-	ErrLicenseIDIsInvalid              = errors.New("license id is invalid")
-	ErrLicenseActionIsEmpty            = errors.New("license action is empty")
-	ErrLicenseActionIsInvalid          = errors.New("license action is invalid")
-	ErrLicenseIsSuspended              = errors.New("license is suspended")
-	ErrLicenseHasExpired               = errors.New("license has expired")
-	ErrLicenseIsBanned                 = errors.New("license is banned")
-	ErrLicenseMaxMachinesIsInvalid     = errors.New("license max machines is invalid")
-	ErrLicenseMaxUsesIsInvalid         = errors.New("license max uses is invalid")
-	ErrLicenseMaxUsersIsInvalid        = errors.New("license max users is invalid")
-	ErrLicenseKeyIsEmpty               = errors.New("license key is empty")
-	ErrLicenseIncrementIsEmpty         = errors.New("license increment value is empty")
-	ErrLicenseIncrementIsInvalid       = errors.New("license increment value is invalid")
-	ErrLicenseDecrementIsEmpty         = errors.New("license decrement value is empty")
-	ErrLicenseDecrementIsInvalid       = errors.New("license decrement value is invalid")
-	ErrLicenseNotActivated             = errors.New("license has not been activated")
-	ErrLicenseStatusInvalidToReinstate = errors.New("invalid license status to be reinstated")
-	ErrLicenseMaxUsesExceeded          = errors.New("license maximum uses exceeded")
-	ErrLicenseExpireDateIsInvalid      = errors.New("license expire date is invalid")
-	ErrLicenseKeyIsInvalid             = errors.New("license key is invalid")
-	ErrLicenseMaxMachineExceeded       = errors.New("license max machine exceeded")
+	ErrLicenseNameIsEmpty                  = errors.New("license name is empty")
+	ErrLicenseProductIDIsEmpty             = errors.New("license product id is empty")
+	ErrLicensePolicyIDIsEmpty              = errors.New("license policy id is empty")
+	ErrLicenseExpiryFormatIsInvalid        = errors.New("license expiry format is invalid")
+	ErrLicenseIDIsEmpty                    = errors.New("license id is empty")
+	ErrLicenseIDIsInvalid                  = errors.New("license id is invalid")
+	ErrLicenseActionIsEmpty                = errors.New("license action is empty")
+	ErrLicenseActionIsInvalid              = errors.New("license action is invalid")
+	ErrLicenseIsSuspended                  = errors.New("license is suspended")
+	ErrLicenseHasExpired                   = errors.New("license has expired")
+	ErrLicenseIsBanned                     = errors.New("license is banned")
+	ErrLicenseMaxMachinesIsInvalid         = errors.New("license max machines is invalid")
+	ErrLicenseMaxUsesIsInvalid             = errors.New("license max uses is invalid")
+	ErrLicenseMaxUsersIsInvalid            = errors.New("license max users is invalid")
+	ErrLicenseKeyIsEmpty                   = errors.New("license key is empty")
+	ErrLicenseIncrementIsEmpty             = errors.New("license increment value is empty")
+	ErrLicenseIncrementIsInvalid           = errors.New("license increment value is invalid")
+	ErrLicenseDecrementIsEmpty             = errors.New("license decrement value is empty")
+	ErrLicenseDecrementIsInvalid           = errors.New("license decrement value is invalid")
+	ErrLicenseNotActivated                 = errors.New("license has not been activated")
+	ErrLicenseStatusInvalidToReinstate     = errors.New("invalid license status to be reinstated")
+	ErrLicenseMaxUsesExceeded              = errors.New("license maximum uses exceeded")
+	ErrLicenseExpireDateIsInvalid          = errors.New("license expire date is invalid")
+	ErrLicenseKeyIsInvalid                 = errors.New("license key is invalid")
+	ErrLicenseMaxMachineExceeded           = errors.New("license max machine exceeded")
+	ErrLicenseIsNodeLocked                 = errors.New("license is node-locked and already has a machine")
+	ErrLicenseKeyPoolIsExhausted           = errors.New("license key pool of the policy is exhausted")
+	ErrLicenseUsersAreEmpty                = errors.New("license users are empty")
+	ErrLicenseUserIsInvalid                = errors.New("license user is invalid")
+	ErrLicenseUserAlreadyExist             = errors.New("license user already exists")
+	ErrLicenseUserIsNotAttached            = errors.New("license user is not attached to the license")
+	ErrLicenseMaxUsersExceeded             = errors.New("license max users exceeded")
+	ErrLicenseEntitlementAlreadyExist      = errors.New("license entitlement already exists")
+	ErrLicenseEntitlementIsNotAttached     = errors.New("license entitlement is not attached to the license")
+	ErrLicenseNonceIsReplayed              = errors.New("license action nonce has already been used")
+	ErrLicenseTokenExpiryIsInvalid         = errors.New("license token expiry is invalid")
+	ErrLicenseTokenMaxActivationsIsInvalid = errors.New("license token max activations must not be negative")
+	ErrLicenseTokenScopeIsInvalid          = errors.New("resource does not belong to the license of the token")
 )
 
 var (
@@ -118,37 +151,47 @@ var (
 	ErrMachineActionIsEmpty                    = errors.New("machine action is empty")
 	ErrMachineActionIsInvalid                  = errors.New("machine action is invalid")
 	ErrMachineActionCheckoutTTLIsInvalid       = errors.New("machine license TTL is invalid (must be >= 3600 or <= 31556952 seconds)")
+	ErrMachineHeartbeatIsDead                  = errors.New("machine heartbeat is dead")
+	ErrMachineLicenseTokenActivationsExceeded  = errors.New("license token max activations exceeded")
 )
 
 var ErrCodeMapper = map[error]string{
-	nil:                              "00000",
-	ErrGenericInternalServer:         "50000",
-	ErrInvalidDatabaseClient:         "50001",
-	ErrGenericRequestTimedOut:        "50004",
-	ErrGenericBadRequest:             "40000",
-	ErrGenericUnauthorized:           "40001",
-	ErrGenericPermission:             "40003",
-	ErrTenantNameIsEmpty:             "42000",
-	ErrTenantNameAlreadyExist:        "42001",
-	ErrTenantNameIsInvalid:           "42002",
-	ErrAccountUsernameIsEmpty:        "43000",
-	ErrAccountEmailIsEmpty:           "43001",
-	ErrAccountRoleIsEmpty:            "43002",
-	ErrAccountRoleIsInvalid:          "43003",
-	ErrAccountPasswordIsEmpty:        "43004",
-	ErrAccountUsernameAlreadyExist:   "43005",
-	ErrAccountEmailAlreadyExist:      "43006",
-	ErrAccountActionIsEmpty:          "43007",
-	ErrAccountActionIsInvalid:        "43008",
-	ErrAccountUsernameIsInvalid:      "43009",
-	ErrAccountCurrentPasswordIsEmpty: "49010",
-	ErrAccountNewPasswordIsEmpty:     "49011",
-	ErrAccountPasswordNotMatch:       "49012",
-	ErrAccountResetTokenIsEmpty:      "49013",
-	ErrAccountResetTokenIsInvalid:    "49014",
-	ErrAccountResetTokenIsExpired:    "49015",
-	ErrAccountIsBanned:               "49016",
-	ErrAccountIsInactive:             "49017",
+	nil:                                "00000",
+	ErrGenericInternalServer:           "50000",
+	ErrInvalidDatabaseClient:           "50001",
+	ErrGenericRequestTimedOut:          "50004",
+	ErrGenericBadRequest:               "40000",
+	ErrGenericUnauthorized:             "40001",
+	ErrGenericPermission:               "40003",
+	ErrGenericTooManyRequests:          "40029",
+	ErrGenericRequestTooLarge:          "40013",
+	ErrTenantNameIsEmpty:               "42000",
+	ErrTenantNameAlreadyExist:          "42001",
+	ErrTenantNameIsInvalid:             "42002",
+	ErrTenantRegenerationModeIsInvalid: "42003",
+	ErrTenantSignerBackendIsInvalid:    "42004",
+	ErrAccountUsernameIsEmpty:          "43000",
+	ErrAccountEmailIsEmpty:             "43001",
+	ErrAccountRoleIsEmpty:              "43002",
+	ErrAccountRoleIsInvalid:            "43003",
+	ErrAccountPasswordIsEmpty:          "43004",
+	ErrAccountUsernameAlreadyExist:     "43005",
+	ErrAccountEmailAlreadyExist:        "43006",
+	ErrAccountActionIsEmpty:            "43007",
+	ErrAccountActionIsInvalid:          "43008",
+	ErrAccountUsernameIsInvalid:        "43009",
+	ErrAccountCurrentPasswordIsEmpty:   "49010",
+	ErrAccountNewPasswordIsEmpty:       "49011",
+	ErrAccountPasswordNotMatch:         "49012",
+	ErrAccountResetTokenIsEmpty:        "49013",
+	ErrAccountResetTokenIsInvalid:      "49014",
+	ErrAccountResetTokenIsExpired:      "49015",
+	ErrAccountIsBanned:                 "49016",
+	ErrAccountIsInactive:               "49017",
+	ErrAccountRefreshTokenIsEmpty:      "49018",
+	ErrAccountRefreshTokenIsInvalid:    "49019",
+	ErrAccountRefreshTokenIsExpired:    "49020",
+	ErrAccountIsLocked:                 "49021",
 
 	ErrProductNameIsEmpty:                    "44000",
 	ErrProductCodeIsEmpty:                    "44001",
@@ -158,6 +201,9 @@ var ErrCodeMapper = map[error]string{
 	ErrProductIDIsEmpty:                      "44005",
 	ErrProductIDIsInvalid:                    "44006",
 	ErrProductTokenExpirationFormatIsInvalid: "44007",
+	ErrProductTokenPermissionIsInvalid:       "44008",
+	ErrProductTokenScopeIsInvalid:            "44009",
+	ErrProductTokenExpiryIsInvalid:           "44010",
 	ErrEntitlementIDIsEmpty:                  "45000",
 	ErrEntitlementNameIsEmpty:                "45001",
 	ErrEntitlementCodeIsEmpty:                "45002",
@@ -180,6 +226,15 @@ var ErrCodeMapper = map[error]string{
 	ErrPolicyInvalidHeartbeatBasis:           "46014",
 	ErrPolicyInvalidCheckinInterval:          "46015",
 	ErrPolicyEntitlementAlreadyExist:         "46016",
+	ErrPolicyInvalidResurrectionStrategy:     "46017",
+	ErrPolicyKeysAreEmpty:                    "46018",
+	ErrPolicyKeyCountIsInvalid:               "46019",
+	ErrPolicyKeyAlreadyExist:                 "46020",
+	ErrPolicyIsNotPooled:                     "46021",
+	ErrPolicyIsProtected:                     "46022",
+	ErrPolicySignedKeysWithPool:              "46023",
+	ErrPolicyKeyOverlapPeriodIsInvalid:       "46024",
+	ErrPolicySignerBackendIsInvalid:          "46025",
 	ErrLicenseNameIsEmpty:                    "47001",
 	ErrLicenseProductIDIsEmpty:               "47002",
 	ErrLicensePolicyIDIsEmpty:                "47003",
@@ -205,6 +260,19 @@ var ErrCodeMapper = map[error]string{
 	ErrLicenseExpireDateIsInvalid:            "47023",
 	ErrLicenseKeyIsInvalid:                   "47024",
 	ErrLicenseMaxMachineExceeded:             "47025",
+	ErrLicenseIsNodeLocked:                   "47026",
+	ErrLicenseKeyPoolIsExhausted:             "47027",
+	ErrLicenseUsersAreEmpty:                  "47028",
+	ErrLicenseUserIsInvalid:                  "47029",
+	ErrLicenseUserAlreadyExist:               "47030",
+	ErrLicenseUserIsNotAttached:              "47031",
+	ErrLicenseMaxUsersExceeded:               "47032",
+	ErrLicenseEntitlementAlreadyExist:        "47033",
+	ErrLicenseEntitlementIsNotAttached:       "47034",
+	ErrLicenseNonceIsReplayed:                "47035",
+	ErrLicenseTokenExpiryIsInvalid:           "47036",
+	ErrLicenseTokenMaxActivationsIsInvalid:   "47037",
+	ErrLicenseTokenScopeIsInvalid:            "47038",
 
 	ErrMachineIDIsEmpty:                        "48000",
 	ErrMachineIDIsInvalid:                      "48001",
@@ -215,37 +283,47 @@ var ErrCodeMapper = map[error]string{
 	ErrMachineActionIsEmpty:                    "48006",
 	ErrMachineActionIsInvalid:                  "48007",
 	ErrMachineActionCheckoutTTLIsInvalid:       "48008",
+	ErrMachineHeartbeatIsDead:                  "48009",
+	ErrMachineLicenseTokenActivationsExceeded:  "48010",
 }
 
 var ErrMessageMapper = map[error]string{
-	nil:                              "OK",
-	ErrGenericInternalServer:         ErrGenericInternalServer.Error(),
-	ErrGenericRequestTimedOut:        ErrGenericRequestTimedOut.Error(),
-	ErrInvalidDatabaseClient:         ErrInvalidDatabaseClient.Error(),
-	ErrGenericBadRequest:             ErrGenericBadRequest.Error(),
-	ErrGenericUnauthorized:           ErrGenericUnauthorized.Error(),
-	ErrGenericPermission:             ErrGenericPermission.Error(),
-	ErrTenantNameIsEmpty:             ErrTenantNameIsEmpty.Error(),
-	ErrTenantNameAlreadyExist:        ErrTenantNameAlreadyExist.Error(),
-	ErrAccountEmailAlreadyExist:      ErrAccountEmailAlreadyExist.Error(),
-	ErrTenantNameIsInvalid:           ErrTenantNameIsInvalid.Error(),
-	ErrAccountUsernameIsEmpty:        ErrAccountUsernameIsEmpty.Error(),
-	ErrAccountEmailIsEmpty:           ErrAccountEmailIsEmpty.Error(),
-	ErrAccountRoleIsEmpty:            ErrAccountRoleIsEmpty.Error(),
-	ErrAccountRoleIsInvalid:          ErrAccountRoleIsInvalid.Error(),
-	ErrAccountPasswordIsEmpty:        ErrAccountPasswordIsEmpty.Error(),
-	ErrAccountUsernameAlreadyExist:   ErrAccountUsernameAlreadyExist.Error(),
-	ErrAccountActionIsEmpty:          ErrAccountActionIsEmpty.Error(),
-	ErrAccountActionIsInvalid:        ErrAccountActionIsInvalid.Error(),
-	ErrAccountUsernameIsInvalid:      ErrAccountUsernameIsInvalid.Error(),
-	ErrAccountCurrentPasswordIsEmpty: ErrAccountCurrentPasswordIsEmpty.Error(),
-	ErrAccountNewPasswordIsEmpty:     ErrAccountNewPasswordIsEmpty.Error(),
-	ErrAccountPasswordNotMatch:       ErrAccountPasswordNotMatch.Error(),
-	ErrAccountResetTokenIsEmpty:      ErrAccountResetTokenIsEmpty.Error(),
-	ErrAccountResetTokenIsInvalid:    ErrAccountResetTokenIsInvalid.Error(),
-	ErrAccountResetTokenIsExpired:    ErrAccountResetTokenIsExpired.Error(),
-	ErrAccountIsBanned:               ErrAccountIsBanned.Error(),
-	ErrAccountIsInactive:             ErrAccountIsInactive.Error(),
+	nil:                                "OK",
+	ErrGenericInternalServer:           ErrGenericInternalServer.Error(),
+	ErrGenericRequestTimedOut:          ErrGenericRequestTimedOut.Error(),
+	ErrInvalidDatabaseClient:           ErrInvalidDatabaseClient.Error(),
+	ErrGenericBadRequest:               ErrGenericBadRequest.Error(),
+	ErrGenericUnauthorized:             ErrGenericUnauthorized.Error(),
+	ErrGenericPermission:               ErrGenericPermission.Error(),
+	ErrGenericTooManyRequests:          ErrGenericTooManyRequests.Error(),
+	ErrGenericRequestTooLarge:          ErrGenericRequestTooLarge.Error(),
+	ErrTenantNameIsEmpty:               ErrTenantNameIsEmpty.Error(),
+	ErrTenantNameAlreadyExist:          ErrTenantNameAlreadyExist.Error(),
+	ErrAccountEmailAlreadyExist:        ErrAccountEmailAlreadyExist.Error(),
+	ErrTenantNameIsInvalid:             ErrTenantNameIsInvalid.Error(),
+	ErrTenantRegenerationModeIsInvalid: ErrTenantRegenerationModeIsInvalid.Error(),
+	ErrTenantSignerBackendIsInvalid:    ErrTenantSignerBackendIsInvalid.Error(),
+	ErrAccountUsernameIsEmpty:          ErrAccountUsernameIsEmpty.Error(),
+	ErrAccountEmailIsEmpty:             ErrAccountEmailIsEmpty.Error(),
+	ErrAccountRoleIsEmpty:              ErrAccountRoleIsEmpty.Error(),
+	ErrAccountRoleIsInvalid:            ErrAccountRoleIsInvalid.Error(),
+	ErrAccountPasswordIsEmpty:          ErrAccountPasswordIsEmpty.Error(),
+	ErrAccountUsernameAlreadyExist:     ErrAccountUsernameAlreadyExist.Error(),
+	ErrAccountActionIsEmpty:            ErrAccountActionIsEmpty.Error(),
+	ErrAccountActionIsInvalid:          ErrAccountActionIsInvalid.Error(),
+	ErrAccountUsernameIsInvalid:        ErrAccountUsernameIsInvalid.Error(),
+	ErrAccountCurrentPasswordIsEmpty:   ErrAccountCurrentPasswordIsEmpty.Error(),
+	ErrAccountNewPasswordIsEmpty:       ErrAccountNewPasswordIsEmpty.Error(),
+	ErrAccountPasswordNotMatch:         ErrAccountPasswordNotMatch.Error(),
+	ErrAccountResetTokenIsEmpty:        ErrAccountResetTokenIsEmpty.Error(),
+	ErrAccountResetTokenIsInvalid:      ErrAccountResetTokenIsInvalid.Error(),
+	ErrAccountResetTokenIsExpired:      ErrAccountResetTokenIsExpired.Error(),
+	ErrAccountIsBanned:                 ErrAccountIsBanned.Error(),
+	ErrAccountIsInactive:               ErrAccountIsInactive.Error(),
+	ErrAccountRefreshTokenIsEmpty:      ErrAccountRefreshTokenIsEmpty.Error(),
+	ErrAccountRefreshTokenIsInvalid:    ErrAccountRefreshTokenIsInvalid.Error(),
+	ErrAccountRefreshTokenIsExpired:    ErrAccountRefreshTokenIsExpired.Error(),
+	ErrAccountIsLocked:                 ErrAccountIsLocked.Error(),
 
 	ErrProductNameIsEmpty:                    ErrProductNameIsEmpty.Error(),
 	ErrProductCodeIsEmpty:                    ErrProductCodeIsEmpty.Error(),
@@ -255,6 +333,9 @@ var ErrMessageMapper = map[error]string{
 	ErrProductIDIsEmpty:                      ErrProductIDIsEmpty.Error(),
 	ErrProductIDIsInvalid:                    ErrProductIDIsInvalid.Error(),
 	ErrProductTokenExpirationFormatIsInvalid: ErrProductTokenExpirationFormatIsInvalid.Error(),
+	ErrProductTokenPermissionIsInvalid:       ErrProductTokenPermissionIsInvalid.Error(),
+	ErrProductTokenScopeIsInvalid:            ErrProductTokenScopeIsInvalid.Error(),
+	ErrProductTokenExpiryIsInvalid:           ErrProductTokenExpiryIsInvalid.Error(),
 	ErrEntitlementIDIsEmpty:                  ErrEntitlementIDIsEmpty.Error(),
 	ErrEntitlementNameIsEmpty:                ErrEntitlementNameIsEmpty.Error(),
 	ErrEntitlementCodeIsEmpty:                ErrEntitlementCodeIsEmpty.Error(),
@@ -277,6 +358,15 @@ var ErrMessageMapper = map[error]string{
 	ErrPolicyInvalidHeartbeatBasis:           ErrPolicyInvalidHeartbeatBasis.Error(),
 	ErrPolicyInvalidCheckinInterval:          ErrPolicyInvalidCheckinInterval.Error(),
 	ErrPolicyEntitlementAlreadyExist:         ErrPolicyEntitlementAlreadyExist.Error(),
+	ErrPolicyInvalidResurrectionStrategy:     ErrPolicyInvalidResurrectionStrategy.Error(),
+	ErrPolicyKeysAreEmpty:                    ErrPolicyKeysAreEmpty.Error(),
+	ErrPolicyKeyCountIsInvalid:               ErrPolicyKeyCountIsInvalid.Error(),
+	ErrPolicyKeyAlreadyExist:                 ErrPolicyKeyAlreadyExist.Error(),
+	ErrPolicyIsNotPooled:                     ErrPolicyIsNotPooled.Error(),
+	ErrPolicyIsProtected:                     ErrPolicyIsProtected.Error(),
+	ErrPolicySignedKeysWithPool:              ErrPolicySignedKeysWithPool.Error(),
+	ErrPolicyKeyOverlapPeriodIsInvalid:       ErrPolicyKeyOverlapPeriodIsInvalid.Error(),
+	ErrPolicySignerBackendIsInvalid:          ErrPolicySignerBackendIsInvalid.Error(),
 	ErrLicenseNameIsEmpty:                    ErrLicenseNameIsEmpty.Error(),
 	ErrLicenseProductIDIsEmpty:               ErrLicenseProductIDIsEmpty.Error(),
 	ErrLicensePolicyIDIsEmpty:                ErrLicensePolicyIDIsEmpty.Error(),
@@ -302,6 +392,19 @@ var ErrMessageMapper = map[error]string{
 	ErrLicenseExpireDateIsInvalid:            ErrLicenseExpireDateIsInvalid.Error(),
 	ErrLicenseKeyIsInvalid:                   ErrLicenseKeyIsInvalid.Error(),
 	ErrLicenseMaxMachineExceeded:             ErrLicenseMaxMachineExceeded.Error(),
+	ErrLicenseIsNodeLocked:                   ErrLicenseIsNodeLocked.Error(),
+	ErrLicenseKeyPoolIsExhausted:             ErrLicenseKeyPoolIsExhausted.Error(),
+	ErrLicenseUsersAreEmpty:                  ErrLicenseUsersAreEmpty.Error(),
+	ErrLicenseUserIsInvalid:                  ErrLicenseUserIsInvalid.Error(),
+	ErrLicenseUserAlreadyExist:               ErrLicenseUserAlreadyExist.Error(),
+	ErrLicenseUserIsNotAttached:              ErrLicenseUserIsNotAttached.Error(),
+	ErrLicenseMaxUsersExceeded:               ErrLicenseMaxUsersExceeded.Error(),
+	ErrLicenseEntitlementAlreadyExist:        ErrLicenseEntitlementAlreadyExist.Error(),
+	ErrLicenseEntitlementIsNotAttached:       ErrLicenseEntitlementIsNotAttached.Error(),
+	ErrLicenseNonceIsReplayed:                ErrLicenseNonceIsReplayed.Error(),
+	ErrLicenseTokenExpiryIsInvalid:           ErrLicenseTokenExpiryIsInvalid.Error(),
+	ErrLicenseTokenMaxActivationsIsInvalid:   ErrLicenseTokenMaxActivationsIsInvalid.Error(),
+	ErrLicenseTokenScopeIsInvalid:            ErrLicenseTokenScopeIsInvalid.Error(),
 
 	ErrMachineIDIsEmpty:                        ErrMachineIDIsEmpty.Error(),
 	ErrMachineIDIsInvalid:                      ErrMachineIDIsInvalid.Error(),
@@ -312,4 +415,6 @@ var ErrMessageMapper = map[error]string{
 	ErrMachineActionIsEmpty:                    ErrMachineActionIsEmpty.Error(),
 	ErrMachineActionIsInvalid:                  ErrMachineActionIsInvalid.Error(),
 	ErrMachineActionCheckoutTTLIsInvalid:       ErrMachineActionCheckoutTTLIsInvalid.Error(),
+	ErrMachineHeartbeatIsDead:                  ErrMachineHeartbeatIsDead.Error(),
+	ErrMachineLicenseTokenActivationsExceeded:  ErrMachineLicenseTokenActivationsExceeded.Error(),
 }
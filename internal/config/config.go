@@ -3,12 +3,14 @@ package config
 const SuperAdminUsername = "superadmin"
 
 const (
-	ServerMode           = "server.mode"
-	ServerHttpPort       = "server.http_port"
-	ServerEnableTLS      = "server.enable_tls"
-	ServerCertFile       = "server.cert_file"
-	ServerKeyFile        = "server.key_file"
-	ServerRequestTimeout = "server.request_timeout"
+	ServerMode                  = "server.mode"
+	ServerHttpPort              = "server.http_port"
+	ServerEnableTLS             = "server.enable_tls"
+	ServerCertFile              = "server.cert_file"
+	ServerKeyFile               = "server.key_file"
+	ServerRequestTimeout        = "server.request_timeout"
+	ServerEnableResponseSigning = "server.enable_response_signing"
+	ServerTrustedProxies        = "server.trusted_proxies"
 )
 
 const (
@@ -19,6 +21,22 @@ const (
 	TracerURI = "tracer.uri"
 )
 
+const (
+	CryptoKEK             = "crypto.kek"
+	CryptoKEKFile         = "crypto.kek_file"
+	CryptoPreviousKEK     = "crypto.previous_kek"
+	CryptoPreviousKEKFile = "crypto.previous_kek_file"
+)
+
+const (
+	SignerPKCS11Module     = "signer.pkcs11_module"
+	SignerPKCS11TokenLabel = "signer.pkcs11_token_label"
+	SignerPKCS11PIN        = "signer.pkcs11_pin"
+	SignerRemoteURL        = "signer.remote_url"
+	SignerRemoteToken      = "signer.remote_token"
+	SignerRemoteTimeout    = "signer.remote_timeout"
+)
+
 const (
 	PostgresHost     = "postgres.host"
 	PostgresPort     = "postgres.port"
@@ -28,5 +46,27 @@ const (
 )
 
 const (
-	AccessTokenTTL = "access_token.ttl"
+	AccessTokenTTL        = "access_token.ttl"
+	AccessTokenTenantsTTL = "access_token.tenants"
+	AccessTokenRolesTTL   = "access_token.roles"
+	RefreshTokenTTL       = "refresh_token.ttl"
+)
+
+const (
+	LoginMaxFailedAttempts      = "login.max_failed_attempts"
+	LoginMaxFailedAttemptsPerIP = "login.max_failed_attempts_per_ip"
+	LoginLockoutDuration        = "login.lockout_duration"
+)
+
+const (
+	LicenseExpirationSweepInterval = "license.expiration_sweep_interval"
+	LicenseNonceTTL                = "license.nonce_ttl"
+)
+
+const (
+	MachineHeartbeatMonitorInterval = "machine.heartbeat_monitor_interval"
+)
+
+const (
+	RateLimitWindows = "rate_limit.windows"
 )
@@ -12,6 +12,8 @@ const (
 	AccountActionGenerateResetToken = "password-token"
 	AccountActionBan                = "ban"
 	AccountActionUnban              = "unban"
+	AccountActionRevokeSessions     = "revoke-sessions"
+	AccountActionUnlock             = "unlock"
 )
 
 var ValidAccountActionMapper = map[string]bool{
@@ -20,4 +22,16 @@ var ValidAccountActionMapper = map[string]bool{
 	AccountActionGenerateResetToken: true,
 	AccountActionBan:                true,
 	AccountActionUnban:              true,
+	AccountActionRevokeSessions:     true,
+	AccountActionUnlock:             true,
 }
+
+const (
+	// DefaultLoginMaxFailedAttempts is the number of failed logins after which an account is locked
+	DefaultLoginMaxFailedAttempts = 5
+	// DefaultLoginMaxFailedAttemptsPerIP is the number of failed logins after which the logins of an IP are throttled
+	DefaultLoginMaxFailedAttemptsPerIP = 20
+	// DefaultLoginLockoutDuration is the duration (in seconds) of the lockouts, and the window in which failed logins
+	// are counted
+	DefaultLoginLockoutDuration = 900
+)
@@ -5,6 +5,12 @@ const (
 	PublicKeyPemFormat   = "-----BEGIN PUBLIC KEY-----\n%s\n-----END PUBLIC KEY-----"
 )
 
+const (
+	// CertificateFileVersion is the version of the signed content of license files and machine files.
+	// It must be bumped on any change that existing verifiers cannot safely ignore.
+	CertificateFileVersion = 1
+)
+
 const (
 	MachineFileFormat = "-----BEGIN MACHINE FILE-----\n%s\n-----END MACHINE FILE-----"
 	LicenseFileFormat = "-----BEGIN LICENSE FILE-----\n%s\n-----END LICENSE FILE-----"
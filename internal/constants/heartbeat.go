@@ -1,8 +1,22 @@
 package constants
 
+const (
+	// DefaultHeartbeatDuration is the heartbeat duration (in seconds) used when the policy does not specify one
+	DefaultHeartbeatDuration = 600
+	// DefaultHeartbeatMonitorInterval is the interval (in seconds) between two runs of the heartbeat monitor
+	DefaultHeartbeatMonitorInterval = 60
+)
+
 const (
 	HeartbeatStatusNotStarted = iota
 	HeartbeatStatusAlive
 	HeartbeatStatusDead
 	HeartbeatStatusResurrected
 )
+
+var HeartbeatStatusMapper = map[int]string{
+	HeartbeatStatusNotStarted:  "not_started",
+	HeartbeatStatusAlive:       "alive",
+	HeartbeatStatusDead:        "dead",
+	HeartbeatStatusResurrected: "resurrected",
+}
@@ -41,8 +41,12 @@ const (
 	ContentDigestHeader             = "Content-Digest"
 	ContentTransferEncodingHeader   = "Content-Transfer-Encoding"
 	ContentDescriptionHeader        = "Content-Description"
+	DateHeader                      = "Date"
 	OriginHeader                    = "Origin"
+	SignatureHeader                 = "Signature"
+	SignatureInputHeader            = "Signature-Input"
 	XRequestIDHeader                = "X-Request-ID"
+	XSignatureNonceHeader           = "X-Signature-Nonce"
 	XRequestedWithHeader            = "X-Requested-With"
 	XAPIKeyHeader                   = "X-API-Key"
 	XRateLimitWindowHeader          = "X-RateLimit-Window" // The current rate limiting window that is closest to being reached, percentage-wise.
@@ -51,8 +55,6 @@ const (
 	RetryAfterHeader                = "Retry-After"
 	XRateLimitRemainingHeader       = "X-RateLimit-Remaining" //	The number of requests remaining in the current rate limit window.
 	XRateLimitResetHeader           = "X-RateLimit-Reset"     //	The time at which the current rate limit window resets in UTC epoch seconds.
-	XLicenseChecksumHeader          = "X-License-Checksum"
-	XMachineChecksumHeader          = "X-Machine-Checksum"
 )
 
 const (
@@ -64,11 +66,26 @@ const (
 	ContextValueTenant      = "tenant"
 	ContextValueSubject     = "subject"
 	ContextValueAudience    = "audience"
+	ContextValueRole        = "role"
+	ContextValueProduct     = "product"
+	ContextValueLicense     = "license"
+	ContextValueTokenID     = "token_id"
+	ContextValueTokenExpiry = "token_expiry"
+
+	// ContextValueProtectedPolicyAccess is set by the permission middlewares to whether the subject may manage
+	// resources under protected policies
+	ContextValueProtectedPolicyAccess = "protected_policy_access"
 )
 
 type QueryCommonParam struct {
 	Limit  *int `form:"limit" validate:"optional" example:"10"`
 	Offset *int `form:"offset" validate:"optional" example:"10"`
+	// ProductID restricts the listed resources to the product of the product token of the request.
+	// It is never bound from the query
+	ProductID *string `form:"-" json:"-" swaggerignore:"true"`
+	// LicenseID restricts the listed resources to the license of the license token of the request.
+	// It is never bound from the query
+	LicenseID *string `form:"-" json:"-" swaggerignore:"true"`
 }
 
 func (req *QueryCommonParam) Validate() {
@@ -8,6 +8,13 @@ const (
 	MaximumLicenseTTL = 31556952
 )
 
+const (
+	// DefaultLicenseExpirationSweepInterval is the interval (in seconds) between two runs of the license expiration sweeper
+	DefaultLicenseExpirationSweepInterval = 60
+	// DefaultLicenseNonceTTL is the window (in seconds) during which a nonce cannot be reused for a license action
+	DefaultLicenseNonceTTL = 300
+)
+
 const (
 	LicenseActionValidate       = "validate"
 	LicenseActionSuspend        = "suspend"
@@ -32,6 +39,13 @@ var ValidLicenseActionMapper = map[string]interface{}{
 	LicenseActionResetUsage:     true,
 }
 
+const (
+	// LicenseEntitlementSourcePolicy - The entitlement is attached to the license's policy
+	LicenseEntitlementSourcePolicy = "policy"
+	// LicenseEntitlementSourceLicense - The entitlement is attached to the license itself
+	LicenseEntitlementSourceLicense = "license"
+)
+
 //The status of the license to filter by. One of: ACTIVE, INACTIVE, EXPIRED, SUSPENDED, or BANNED.
 
 const (
@@ -44,11 +58,17 @@ const (
 )
 
 const (
-	LicenseValidationStatusValid          = "valid"             // The validated license resource or license key is valid.
-	LicenseValidationStatusSuspended      = "suspended"         // The validated license has been suspended.
-	LicenseValidationStatusExpired        = "expired"           // The validated license is expired.
-	LicenseValidationStatusBanned         = "banned"            // The user that owns the validated license has been banned.
-	LicenseValidationStatusOverdue        = "overdue"           // The validated license is overdue for check-in.
-	LicenseValidationStatusNoMachine      = "no_machine"        // Not activated. The validated license does not meet its node-locked policy's requirement of exactly 1 associated machine.
-	LicenseValidationStatusTooManyMachine = "too_many_machines" // The validated license has exceeded its policy's machine limit.
+	LicenseValidationStatusValid                    = "valid"                      // The validated license resource or license key is valid.
+	LicenseValidationStatusSuspended                = "suspended"                  // The validated license has been suspended.
+	LicenseValidationStatusExpired                  = "expired"                    // The validated license is expired.
+	LicenseValidationStatusAccessRevoked            = "access_revoked"             // The validated license is expired and its policy revokes access on expiry.
+	LicenseValidationStatusBanned                   = "banned"                     // The user that owns the validated license has been banned.
+	LicenseValidationStatusOverdue                  = "overdue"                    // The validated license is overdue for check-in.
+	LicenseValidationStatusNoMachine                = "no_machine"                 // Not activated. The validated license does not meet its node-locked policy's requirement of exactly 1 associated machine.
+	LicenseValidationStatusNoMachines               = "no_machines"                // Not activated. The validated license does not meet its floating policy's requirement of at least 1 associated machine.
+	LicenseValidationStatusTooManyMachine           = "too_many_machines"          // The validated license has exceeded its policy's machine limit.
+	LicenseValidationStatusFingerprintScopeMismatch = "fingerprint_scope_mismatch" // The scoped fingerprint does not belong to an activated machine of the validated license.
+	LicenseValidationStatusProductScopeMismatch     = "product_scope_mismatch"     // The scoped product does not match the validated license's product.
+	LicenseValidationStatusPolicyScopeMismatch      = "policy_scope_mismatch"      // The scoped policy does not match the validated license's policy.
+	LicenseValidationStatusEntitlementsMissing      = "entitlements_missing"       // The validated license is not granted every scoped entitlement.
 )
@@ -1,18 +1,35 @@
 package constants
 
+import "go-license-management/internal/utils"
+
 const (
 	// PolicySchemeED25519 signs license keys with your account's
 	// Ed25519 signing key,
-	PolicySchemeED25519 = "ED25519"
+	PolicySchemeED25519 = utils.SigningSchemeED25519
 
 	// PolicySchemeRSA2048PKCS1 signs license keys with your account's
 	// 2048-bit RSA private key using RSA PKCS1 v1.5 padding
-	PolicySchemeRSA2048PKCS1 = "RSA2048PKCS1"
+	PolicySchemeRSA2048PKCS1 = utils.SigningSchemeRSA2048PKCS1
+
+	// PolicySchemeRSA2048PSS signs license keys with your account's
+	// 2048-bit RSA private key using RSA PSS padding
+	PolicySchemeRSA2048PSS = utils.SigningSchemeRSA2048PSS
+
+	// PolicySchemeRSA4096PSS signs license keys with your account's
+	// 4096-bit RSA private key using RSA PSS padding
+	PolicySchemeRSA4096PSS = utils.SigningSchemeRSA4096PSS
+
+	// PolicySchemeECDSAP256 signs license keys with your account's
+	// ECDSA private key on the NIST P-256 curve
+	PolicySchemeECDSAP256 = utils.SigningSchemeECDSAP256
 )
 
 var ValidPolicySchemeMapper = map[string]bool{
 	PolicySchemeED25519:      true,
 	PolicySchemeRSA2048PKCS1: true,
+	PolicySchemeRSA2048PSS:   true,
+	PolicySchemeRSA4096PSS:   true,
+	PolicySchemeECDSAP256:    true,
 }
 
 const (
@@ -93,6 +110,20 @@ var ValidPolicyHeartbeatBasisMapper = map[string]bool{
 	PolicyHeartbeatBasisFromFirstPing: true,
 }
 
+const (
+	// PolicyHeartbeatResurrectionStrategyNoRevive - Dead machines cannot be resurrected by a late heartbeat ping. This is the default.
+	PolicyHeartbeatResurrectionStrategyNoRevive = "no_revive"
+
+	// PolicyHeartbeatResurrectionStrategyAlwaysRevive - Dead machines are resurrected by their next heartbeat ping,
+	// as long as the license has room for the machine.
+	PolicyHeartbeatResurrectionStrategyAlwaysRevive = "always_revive"
+)
+
+var ValidPolicyHeartbeatResurrectionStrategyMapper = map[string]bool{
+	PolicyHeartbeatResurrectionStrategyNoRevive:     true,
+	PolicyHeartbeatResurrectionStrategyAlwaysRevive: true,
+}
+
 const (
 	// PolicyOverageStrategyNoOverage - Do not allow overages. Attempts to exceed limits will fail. This is the default.
 	PolicyOverageStrategyNoOverage = "no_overage"
@@ -104,3 +135,37 @@ var ValidPolicyOverageStrategyMapper = map[string]bool{
 	PolicyOverageStrategyNoOverage:   true,
 	PolicyOverageStrategyAlwaysAllow: true,
 }
+
+// MaxPolicyKeyPoolBatchSize is the maximum number of keys which can be loaded into or generated for a policy pool at once.
+const MaxPolicyKeyPoolBatchSize = 1000
+
+// PolicyKeyDefaultOverlapPeriod is the default period, in seconds, during which a rotated signing key is still accepted.
+const PolicyKeyDefaultOverlapPeriod = 30 * 24 * 60 * 60
+
+const (
+	// PolicyKeyStatusActive - the key pair currently signing the certificates of the policy.
+	PolicyKeyStatusActive = "active"
+
+	// PolicyKeyStatusRotated - a rotated key, the certificates it signed are accepted until it retires.
+	PolicyKeyStatusRotated = "rotated"
+
+	// PolicyKeyStatusRetired - a rotated key past its overlap period, the certificates it signed are rejected.
+	PolicyKeyStatusRetired = "retired"
+)
+
+const (
+	// PolicySignerBackendDatabase - the private key is stored on the policy, sealed with the key encryption key. This is the default.
+	PolicySignerBackendDatabase = "database"
+
+	// PolicySignerBackendPKCS11 - the private key is generated and kept in a PKCS#11 token (HSM), only a reference is stored.
+	PolicySignerBackendPKCS11 = "pkcs11"
+
+	// PolicySignerBackendRemote - the private key is generated and kept by a remote signing service, only a reference is stored.
+	PolicySignerBackendRemote = "remote"
+)
+
+var ValidPolicySignerBackendMapper = map[string]bool{
+	PolicySignerBackendDatabase: true,
+	PolicySignerBackendPKCS11:   true,
+	PolicySignerBackendRemote:   true,
+}
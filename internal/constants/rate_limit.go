@@ -0,0 +1,8 @@
+package constants
+
+// DefaultRateLimitWindows are the windows applied to licenses of rate limited policies when none are configured.
+// Each window is formatted as `<limit>/<duration>`, e.g. at most 60 requests every 30 seconds.
+var DefaultRateLimitWindows = []string{"60/30s", "500/5m", "2400/1h"}
+
+// MaxRateLimitedRequestBodySize is the maximum size (in bytes) of the body of the requests read to be rate limited.
+const MaxRateLimitedRequestBodySize = 1 << 20
@@ -8,6 +8,12 @@ const (
 	RoleAdmin = "admin"
 	// RoleUser is the default role when creating account. Can be used client-side to communicate with server
 	RoleUser = "user"
+	// RoleProduct is the role of the requests authenticated with a product token. Its permissions are the ones granted
+	// to the token, and are restricted to the resources of the product of the token
+	RoleProduct = "product"
+	// RoleLicense is the role of the requests authenticated with a license token. It can only act on the license of
+	// the token and its machines
+	RoleLicense = "license"
 )
 
 var ValidRoleMapper = map[string]bool{
@@ -20,3 +26,10 @@ var ValidAccountCreationRoleMapper = map[string]bool{
 	RoleAdmin: true,
 	RoleUser:  true,
 }
+
+// ValidTokenRoleMapper are the roles of the requests authenticated with a token of the X-API-Key header, whose
+// permissions are not managed with casbin.
+var ValidTokenRoleMapper = map[string]bool{
+	RoleProduct: true,
+	RoleLicense: true,
+}
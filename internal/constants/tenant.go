@@ -0,0 +1,22 @@
+package constants
+
+const (
+	// JWTDuration is the lifetime (in seconds) of the access tokens issued to tenant accounts
+	JWTDuration = 3600
+	// RefreshTokenDuration is the lifetime (in seconds) of the refresh tokens
+	RefreshTokenDuration = 2592000
+	// MaxTenantKeyRingSize is the maximum number of previous tenant keys still accepted to verify access tokens
+	MaxTenantKeyRingSize = 3
+)
+
+const (
+	// TenantRegenerationModeGraceful keeps verifying the tokens signed with the previous key until they expire
+	TenantRegenerationModeGraceful = "graceful"
+	// TenantRegenerationModeEmergency revokes the tokens signed with any previous key immediately
+	TenantRegenerationModeEmergency = "emergency"
+)
+
+var ValidTenantRegenerationModeMapper = map[string]bool{
+	TenantRegenerationModeGraceful:  true,
+	TenantRegenerationModeEmergency: true,
+}
@@ -79,9 +79,13 @@ func SeedingCasbinPermissions() error {
 		policies = append(policies, []string{record[1], record[2], record[3]})
 	}
 
-	_, err = e.AddPolicies(policies)
-	if err != nil {
-		return err
+	// Rules are added one by one, as adding them in batch adds none of them once any of them exists. This way, the
+	// rules introduced by an upgrade are granted on top of the rules already seeded.
+	for _, policy := range policies {
+		_, err = e.AddPolicy(policy)
+		if err != nil {
+			return err
+		}
 	}
 
 	err = e.LoadPolicy()
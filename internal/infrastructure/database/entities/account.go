@@ -1,6 +1,7 @@
 package entities
 
 import (
+	"github.com/google/uuid"
 	"github.com/uptrace/bun"
 	"time"
 )
@@ -35,3 +36,25 @@ type Account struct {
 	Tenant              *Tenant                `bun:"rel:belongs-to,join:tenant_name=name"`
 	Role                *Role                  `bun:"rel:belongs-to,join:role_name=name"`
 }
+
+// LoginFailure records a failed login attempt, to lock the account and throttle the IP after too many failures.
+// Failures of the superadmin have no tenant.
+type LoginFailure struct {
+	bun.BaseModel `bun:"table:login_failures,alias:lf" swaggerignore:"true"`
+
+	ID         uuid.UUID `bun:"id,pk,type:uuid"`
+	TenantName string    `bun:"tenant_name,type:varchar(256)"`
+	Username   string    `bun:"username,type:varchar(128),notnull"`
+	IP         string    `bun:"ip,type:varchar(64),notnull"`
+	CreatedAt  time.Time `bun:"created_at,nullzero,notnull,default:current_timestamp"`
+}
+
+// AccountLockout locks the account out of logging in until LockedUntil.
+type AccountLockout struct {
+	bun.BaseModel `bun:"table:account_lockouts,alias:al" swaggerignore:"true"`
+
+	TenantName  string    `bun:"tenant_name,pk,type:varchar(256)"`
+	Username    string    `bun:"username,pk,type:varchar(128)"`
+	LockedUntil time.Time `bun:"locked_until,notnull"`
+	CreatedAt   time.Time `bun:"created_at,nullzero,notnull,default:current_timestamp"`
+}
@@ -10,7 +10,7 @@ type Key struct {
 	bun.BaseModel `bun:"table:keys,alias:k"`
 
 	ID        uuid.UUID `bun:"id,pk,type:uuid"`
-	Key       string    `bun:"key,nullzero"`
+	Key       string    `bun:"key,type:varchar(256),notnull,unique"`
 	PolicyID  uuid.UUID `bun:"policy_id,type:uuid"`
 	CreatedAt time.Time `bun:"created_at,nullzero,notnull,default:current_timestamp"`
 	UpdatedAt time.Time `bun:"updated_at,nullzero,notnull,default:current_timestamp"`
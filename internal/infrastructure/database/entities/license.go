@@ -13,7 +13,7 @@ type License struct {
 	PolicyID                  uuid.UUID              `bun:"policy_id,type:uuid,notnull"`
 	ProductID                 uuid.UUID              `bun:"product_id,type:uuid,notnull"`
 	TenantName                string                 `bun:"tenant_name,type:varchar(256),notnull"`
-	Key                       string                 `bun:"key,type:varchar(256),notnull"`
+	Key                       string                 `bun:"key,type:text,notnull"`
 	Name                      string                 `bun:"name,type:varchar(256),notnull"`
 	LastValidatedChecksum     string                 `bun:"last_validated_checksum,type:varchar(1028),notnull"`
 	Status                    string                 `bun:"status,type:varchar(64),notnull"`
@@ -37,3 +37,46 @@ type License struct {
 	Product                   *Product               `bun:"rel:belongs-to,join:product_id=id"`
 	Policy                    *Policy                `bun:"rel:belongs-to,join:policy_id=id"`
 }
+
+type LicenseUser struct {
+	bun.BaseModel `bun:"table:license_users,alias:lu" swaggerignore:"true"`
+
+	ID         uuid.UUID `bun:"id,pk,type:uuid"`
+	TenantName string    `bun:"tenant_name,type:varchar(256),notnull"`
+	LicenseID  uuid.UUID `bun:"license_id,type:uuid,notnull,unique:license_users_license_id_username_key"`
+	Username   string    `bun:"username,type:varchar(128),notnull,unique:license_users_license_id_username_key"`
+	CreatedAt  time.Time `bun:"created_at,nullzero,notnull,default:current_timestamp"`
+	UpdatedAt  time.Time `bun:"updated_at,nullzero,notnull,default:current_timestamp"`
+	License    *License  `bun:"rel:belongs-to,join:license_id=id"`
+	Account    *Account  `bun:"rel:belongs-to,join:username=username,join:tenant_name=tenant_name"`
+}
+
+type LicenseEntitlement struct {
+	bun.BaseModel `bun:"table:license_entitlements,alias:le" swaggerignore:"true"`
+
+	ID            uuid.UUID              `bun:"id,pk,type:uuid"`
+	TenantName    string                 `bun:"tenant_name,type:varchar(256),notnull"`
+	LicenseID     uuid.UUID              `bun:"license_id,type:uuid,notnull,unique:license_entitlements_license_id_entitlement_id_key"`
+	EntitlementID uuid.UUID              `bun:"entitlement_id,type:uuid,notnull,unique:license_entitlements_license_id_entitlement_id_key"`
+	Metadata      map[string]interface{} `bun:"type:jsonb,nullzero"`
+	CreatedAt     time.Time              `bun:"created_at,nullzero,notnull,default:current_timestamp"`
+	UpdatedAt     time.Time              `bun:"updated_at,nullzero,notnull,default:current_timestamp"`
+	Tenant        *Tenant                `bun:"rel:belongs-to,join:tenant_name=name"`
+	License       *License               `bun:"rel:belongs-to,join:license_id=id"`
+	Entitlement   *Entitlement           `bun:"rel:belongs-to,join:entitlement_id=id"`
+}
+
+// LicenseNonce is a nonce sent along with a license action. It is kept until ExpiresAt, so the action cannot be replayed.
+type LicenseNonce struct {
+	bun.BaseModel `bun:"table:license_nonces,alias:lnn" swaggerignore:"true"`
+
+	ID         uuid.UUID `bun:"id,pk,type:uuid"`
+	TenantName string    `bun:"tenant_name,type:varchar(256),notnull"`
+	LicenseID  uuid.UUID `bun:"license_id,type:uuid,notnull,unique:license_nonces_license_id_nonce_key"`
+	Nonce      int       `bun:"nonce,notnull,unique:license_nonces_license_id_nonce_key"`
+	Action     string    `bun:"action,type:varchar(64),notnull"`
+	ExpiresAt  time.Time `bun:"expires_at,notnull"`
+	CreatedAt  time.Time `bun:"created_at,nullzero,notnull,default:current_timestamp"`
+	Tenant     *Tenant   `bun:"rel:belongs-to,join:tenant_name=name"`
+	License    *License  `bun:"rel:belongs-to,join:license_id=id"`
+}
@@ -9,36 +9,40 @@ import (
 type Policy struct {
 	bun.BaseModel `bun:"table:policies,alias:p" swaggerignore:"true"`
 
-	ID                 uuid.UUID              `bun:"id,pk,type:uuid"`
-	ProductID          uuid.UUID              `bun:"product_id,type:uuid"`
-	TenantName         string                 `bun:"tenant_name,type:varchar(256),notnull"`
-	PublicKey          string                 `bun:"public_key,type:varchar(4096),notnull"`
-	PrivateKey         string                 `bun:"private_key,type:varchar(4096),notnull"`
-	Name               string                 `bun:"name,type:varchar(256),nullzero"`
-	Scheme             string                 `bun:"scheme,type:varchar(128),nullzero"`
-	ExpirationStrategy string                 `bun:"expiration_strategy,type:varchar(64),nullzero"`
-	CheckInInterval    string                 `bun:"check_in_interval,type:varchar(64),nullzero"`
-	OverageStrategy    string                 `bun:"overage_strategy,type:varchar(64),nullzero"`
-	HeartbeatBasis     string                 `bun:"heartbeat_basis,type:varchar(64),nullzero"`
-	RenewalBasis       string                 `bun:"renewal_basis,type:varchar(64),nullzero"`
-	Duration           int64                  `bun:"duration,nullzero"`
-	MaxMachines        int                    `bun:"max_machines,nullzero"`
-	MaxUses            int                    `bun:"max_uses,nullzero"`
-	MaxUsers           int                    `bun:"max_users,nullzero"`
-	HeartbeatDuration  int                    `bun:"heartbeat_duration,nullzero"`
-	Strict             bool                   `bun:"strict,default:false"`
-	Floating           bool                   `bun:"floating,default:false"`
-	UsePool            bool                   `bun:"use_pool,default:false"`
-	RateLimited        bool                   `bun:"rate_limited,default:false"`
-	Encrypted          bool                   `bun:"encrypted,default:false"`
-	Protected          bool                   `bun:"protected,default:false"`
-	RequireCheckIn     bool                   `bun:"require_check_in,default:false"`
-	RequireHeartbeat   bool                   `bun:"require_heartbeat,default:false,notnull"`
-	Metadata           map[string]interface{} `bun:"type:jsonb,nullzero"`
-	CreatedAt          time.Time              `bun:"created_at,nullzero,notnull,default:current_timestamp"`
-	UpdatedAt          time.Time              `bun:"updated_at,nullzero,notnull,default:current_timestamp"`
-	Tenant             *Tenant                `bun:"rel:belongs-to,join:tenant_name=name"`
-	Product            *Product               `bun:"rel:belongs-to,join:product_id=id"`
+	ID                            uuid.UUID              `bun:"id,pk,type:uuid"`
+	ProductID                     uuid.UUID              `bun:"product_id,type:uuid"`
+	TenantName                    string                 `bun:"tenant_name,type:varchar(256),notnull"`
+	PublicKey                     string                 `bun:"public_key,type:text,notnull"`
+	PrivateKey                    string                 `bun:"private_key,type:text,notnull"`
+	SignerBackend                 string                 `bun:"signer_backend,type:varchar(64),nullzero"`
+	SignerKeyID                   string                 `bun:"signer_key_id,type:varchar(256),nullzero"`
+	Name                          string                 `bun:"name,type:varchar(256),nullzero"`
+	Scheme                        string                 `bun:"scheme,type:varchar(128),nullzero"`
+	ExpirationStrategy            string                 `bun:"expiration_strategy,type:varchar(64),nullzero"`
+	CheckInInterval               string                 `bun:"check_in_interval,type:varchar(64),nullzero"`
+	OverageStrategy               string                 `bun:"overage_strategy,type:varchar(64),nullzero"`
+	HeartbeatBasis                string                 `bun:"heartbeat_basis,type:varchar(64),nullzero"`
+	HeartbeatResurrectionStrategy string                 `bun:"heartbeat_resurrection_strategy,type:varchar(64),nullzero"`
+	RenewalBasis                  string                 `bun:"renewal_basis,type:varchar(64),nullzero"`
+	Duration                      int64                  `bun:"duration,nullzero"`
+	MaxMachines                   int                    `bun:"max_machines,nullzero"`
+	MaxUses                       int                    `bun:"max_uses,nullzero"`
+	MaxUsers                      int                    `bun:"max_users,nullzero"`
+	HeartbeatDuration             int                    `bun:"heartbeat_duration,nullzero"`
+	Strict                        bool                   `bun:"strict,default:false"`
+	Floating                      bool                   `bun:"floating,default:false"`
+	UsePool                       bool                   `bun:"use_pool,default:false"`
+	SignedKeys                    bool                   `bun:"signed_keys,default:false"`
+	RateLimited                   bool                   `bun:"rate_limited,default:false"`
+	Encrypted                     bool                   `bun:"encrypted,default:false"`
+	Protected                     bool                   `bun:"protected,default:false"`
+	RequireCheckIn                bool                   `bun:"require_check_in,default:false"`
+	RequireHeartbeat              bool                   `bun:"require_heartbeat,default:false,notnull"`
+	Metadata                      map[string]interface{} `bun:"type:jsonb,nullzero"`
+	CreatedAt                     time.Time              `bun:"created_at,nullzero,notnull,default:current_timestamp"`
+	UpdatedAt                     time.Time              `bun:"updated_at,nullzero,notnull,default:current_timestamp"`
+	Tenant                        *Tenant                `bun:"rel:belongs-to,join:tenant_name=name"`
+	Product                       *Product               `bun:"rel:belongs-to,join:product_id=id"`
 }
 
 type PolicyEntitlement struct {
@@ -55,3 +59,21 @@ type PolicyEntitlement struct {
 	Policy        *Policy                `bun:"rel:belongs-to,join:policy_id=id"`
 	Entitlement   *Entitlement           `bun:"rel:belongs-to,join:entitlement_id=id"`
 }
+
+// PolicyKey is a signing key pair previously used by a policy. The current key pair of a policy is stored on the policy
+// itself, rotated keys are kept so certificates signed with them can still be verified until they retire.
+type PolicyKey struct {
+	bun.BaseModel `bun:"table:policy_keys,alias:pkey" swaggerignore:"true"`
+
+	ID         uuid.UUID `bun:"id,pk,type:uuid"`
+	KID        string    `bun:"kid,type:varchar(64),notnull,unique"`
+	TenantName string    `bun:"tenant_name,type:varchar(256),notnull"`
+	PolicyID   uuid.UUID `bun:"policy_id,type:uuid,notnull"`
+	Scheme     string    `bun:"scheme,type:varchar(128),notnull"`
+	PublicKey  string    `bun:"public_key,type:text,notnull"`
+	RetiresAt  time.Time `bun:"retires_at,nullzero"`
+	CreatedAt  time.Time `bun:"created_at,nullzero,notnull,default:current_timestamp"`
+	UpdatedAt  time.Time `bun:"updated_at,nullzero,notnull,default:current_timestamp"`
+	Tenant     *Tenant   `bun:"rel:belongs-to,join:tenant_name=name"`
+	Policy     *Policy   `bun:"rel:belongs-to,join:policy_id=id"`
+}
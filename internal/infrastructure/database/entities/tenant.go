@@ -1,6 +1,7 @@
 package entities
 
 import (
+	"github.com/google/uuid"
 	"github.com/uptrace/bun"
 	"time"
 )
@@ -11,6 +12,20 @@ type Tenant struct {
 	Name              string    `bun:"name,pk,type:varchar(256),notnull"`
 	Ed25519PublicKey  string    `bun:"ed25519_public_key,type:varchar(512),notnull"`
 	Ed25519PrivateKey string    `bun:"ed25519_private_key,type:varchar(512),notnull"`
+	SignerBackend     string    `bun:"signer_backend,type:varchar(64),nullzero"`
 	CreatedAt         time.Time `bun:"created_at,nullzero,notnull,default:current_timestamp"`
 	UpdatedAt         time.Time `bun:"updated_at,nullzero,notnull,default:current_timestamp"`
 }
+
+// TenantKey is a previous public key of a tenant. Access tokens signed with it are still accepted until ExpiresAt.
+type TenantKey struct {
+	bun.BaseModel `bun:"table:tenant_keys,alias:tnk" swaggerignore:"true"`
+
+	ID               uuid.UUID `bun:"id,pk,type:uuid"`
+	KID              string    `bun:"kid,type:varchar(64),notnull,unique"`
+	TenantName       string    `bun:"tenant_name,type:varchar(256),notnull"`
+	Ed25519PublicKey string    `bun:"ed25519_public_key,type:varchar(512),notnull"`
+	ExpiresAt        time.Time `bun:"expires_at,notnull"`
+	CreatedAt        time.Time `bun:"created_at,nullzero,notnull,default:current_timestamp"`
+	Tenant           *Tenant   `bun:"rel:belongs-to,join:tenant_name=name"`
+}
@@ -9,20 +9,57 @@ import (
 type ProductToken struct {
 	bun.BaseModel `bun:"table:product_tokens,alias:pt" swaggerignore:"true"`
 
+	ID          uuid.UUID `bun:"id,pk,type:uuid"`
+	ProductID   uuid.UUID `bun:"product_id,type:uuid,notnull"`
+	TenantName  string    `bun:"tenant_name,type:varchar(256),notnull"`
+	Name        string    `bun:"name,type:varchar(256)"`
+	TokenHash   string    `bun:"token_hash,type:varchar(64),unique,notnull"`
+	Permissions []string  `bun:"permissions,type:jsonb"`
+	Expiry      time.Time `bun:"expiry,nullzero"`
+	CreatedAt   time.Time `bun:"created_at,nullzero,notnull,default:current_timestamp"`
+	Product     *Product  `bun:"rel:belongs-to,join:product_id=id"`
+}
+
+type LicenseToken struct {
+	bun.BaseModel `bun:"table:license_tokens,alias:lt" swaggerignore:"true"`
+
+	ID             uuid.UUID `bun:"id,pk,type:uuid"`
+	LicenseID      uuid.UUID `bun:"license_id,type:uuid,notnull"`
+	TenantName     string    `bun:"tenant_name,type:varchar(256),notnull"`
+	Name           string    `bun:"name,type:varchar(256)"`
+	TokenHash      string    `bun:"token_hash,type:varchar(64),unique,notnull"`
+	MaxActivations int       `bun:"max_activations,type:integer,notnull,default:0"`
+	Activations    int       `bun:"activations,type:integer,notnull,default:0"`
+	Expiry         time.Time `bun:"expiry,nullzero"`
+	CreatedAt      time.Time `bun:"created_at,nullzero,notnull,default:current_timestamp"`
+	License        *License  `bun:"rel:belongs-to,join:license_id=id"`
+}
+
+// RefreshToken is a single-use refresh token. Refreshing the access token rotates the refresh token with a new one of
+// the same family, and reusing a rotated refresh token revokes its whole family.
+type RefreshToken struct {
+	bun.BaseModel `bun:"table:refresh_tokens,alias:rt" swaggerignore:"true"`
+
 	ID         uuid.UUID `bun:"id,pk,type:uuid"`
-	ProductID  uuid.UUID `bun:"product_id,type:uuid,notnull"`
-	TenantName string    `bun:"tenant_name,type:varchar(256),notnull"`
-	Token      string    `bun:"token,type:varchar(128)"`
+	FamilyID   uuid.UUID `bun:"family_id,type:uuid,notnull"`
+	TenantName string    `bun:"tenant_name,type:varchar(256)"`
+	Username   string    `bun:"username,type:varchar(256),notnull"`
+	TokenHash  string    `bun:"token_hash,type:varchar(64),unique,notnull"`
+	ExpiresAt  time.Time `bun:"expires_at,notnull"`
+	UsedAt     time.Time `bun:"used_at,nullzero"`
 	CreatedAt  time.Time `bun:"created_at,nullzero,notnull,default:current_timestamp"`
-	Product    *Product  `bun:"rel:belongs-to,join:product_id=id"`
 }
 
-//type LicenseToken struct {
-//	bun.BaseModel `bun:"table:license_tokens,alias:lt" swaggerignore:"true"`
-//
-//	Token      string    `bun:"token,pk,type:varchar(256)"`
-//	TenantName string    `bun:"tenant_name,type:varchar(256),notnull"`
-//	LicenseID  uuid.UUID `bun:"license_id,type:uuid,notnull"`
-//	CreatedAt  time.Time `bun:"created_at,nullzero,notnull,default:current_timestamp"`
-//	License    *License  `bun:"rel:belongs-to,join:license_id=id"`
-//}
+// RevokedToken revokes access tokens before they expire. A revocation with a JTI revokes the access token with this
+// ID, a revocation without JTI revokes every access token of the account issued up to it. Revocations are kept until
+// the tokens they revoke have expired.
+type RevokedToken struct {
+	bun.BaseModel `bun:"table:revoked_tokens,alias:rvt" swaggerignore:"true"`
+
+	ID         uuid.UUID `bun:"id,pk,type:uuid"`
+	JTI        string    `bun:"jti,type:varchar(64),unique,nullzero"`
+	TenantName string    `bun:"tenant_name,type:varchar(256),notnull"`
+	Username   string    `bun:"username,type:varchar(256),notnull"`
+	ExpiresAt  time.Time `bun:"expires_at,notnull"`
+	CreatedAt  time.Time `bun:"created_at,nullzero,notnull,default:current_timestamp"`
+}
@@ -11,6 +11,7 @@ import (
 	"go-license-management/internal/config"
 	"go-license-management/internal/constants"
 	"go-license-management/internal/infrastructure/database/entities"
+	"go-license-management/internal/infrastructure/envelope"
 	"go-license-management/internal/infrastructure/logging"
 	"go-license-management/internal/utils"
 	"time"
@@ -26,6 +27,45 @@ const (
 
 var postgresClient *bun.DB
 
+// schemaUpgrades alters the tables created by previous versions, as creating a table that already exists does not
+// add its new columns. The statements run on every startup, so they must be idempotent.
+var schemaUpgrades = []string{
+	// Machine heartbeats
+	`ALTER TABLE machines ADD COLUMN IF NOT EXISTS last_death_event_sent_at timestamptz`,
+	`ALTER TABLE machines ADD COLUMN IF NOT EXISTS resurrected_at timestamptz`,
+	`ALTER TABLE machines ADD COLUMN IF NOT EXISTS deactivated boolean NOT NULL DEFAULT false`,
+	`ALTER TABLE policies ADD COLUMN IF NOT EXISTS heartbeat_resurrection_strategy varchar(64)`,
+	// Pooled keys are pulled by a single license
+	`CREATE UNIQUE INDEX IF NOT EXISTS keys_key_key ON keys (key)`,
+	// RSA-4096 keys of the policies exceed the former varchar(4096)
+	`ALTER TABLE policies ALTER COLUMN public_key TYPE text`,
+	`ALTER TABLE policies ALTER COLUMN private_key TYPE text`,
+	// Signed license keys exceed the former varchar(256) key columns
+	`ALTER TABLE policies ADD COLUMN IF NOT EXISTS signed_keys boolean DEFAULT false`,
+	`ALTER TABLE licenses ALTER COLUMN key TYPE text`,
+	`ALTER TABLE machines ALTER COLUMN license_key TYPE text`,
+	// Signer backends
+	`ALTER TABLE policies ADD COLUMN IF NOT EXISTS signer_backend varchar(64)`,
+	`ALTER TABLE policies ADD COLUMN IF NOT EXISTS signer_key_id varchar(256)`,
+	`ALTER TABLE tenants ADD COLUMN IF NOT EXISTS signer_backend varchar(64)`,
+	// Product tokens are stored hashed, the former plain tokens are hashed and keep their full access to the product
+	`ALTER TABLE product_tokens ADD COLUMN IF NOT EXISTS name varchar(256)`,
+	`ALTER TABLE product_tokens ADD COLUMN IF NOT EXISTS token_hash varchar(64)`,
+	`ALTER TABLE product_tokens ADD COLUMN IF NOT EXISTS permissions jsonb`,
+	`ALTER TABLE product_tokens ADD COLUMN IF NOT EXISTS expiry timestamptz`,
+	`DO $$
+	BEGIN
+		IF EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'product_tokens' AND column_name = 'token') THEN
+			UPDATE product_tokens SET token_hash = encode(sha256(convert_to(token, 'UTF8')), 'hex'), permissions = '["*"]'
+			WHERE token_hash IS NULL AND token IS NOT NULL;
+			DELETE FROM product_tokens WHERE token_hash IS NULL;
+			ALTER TABLE product_tokens DROP COLUMN token;
+		END IF;
+	END $$`,
+	`ALTER TABLE product_tokens ALTER COLUMN token_hash SET NOT NULL`,
+	`CREATE UNIQUE INDEX IF NOT EXISTS product_tokens_token_hash_key ON product_tokens (token_hash)`,
+}
+
 func GetInstance() *bun.DB {
 	return postgresClient
 }
@@ -90,6 +130,10 @@ func SeedingDatabase() error {
 	if err != nil {
 		return err
 	}
+	privateKey, err = envelope.GetInstance().Seal(privateKey, envelope.MasterKeyAssociatedData(config.SuperAdminUsername))
+	if err != nil {
+		return err
+	}
 	superadmin := entities.Master{
 		Username:          config.SuperAdminUsername,
 		RoleName:          constants.RoleSuperAdmin,
@@ -133,6 +177,15 @@ func CreateSchemaIfNotExists() error {
 		return err
 	}
 
+	_, err = GetInstance().NewCreateTable().
+		Model((*entities.TenantKey)(nil)).
+		IfNotExists().
+		ForeignKey(`("tenant_name") REFERENCES "tenants" ("name") ON DELETE CASCADE`).
+		Exec(context.Background())
+	if err != nil {
+		return err
+	}
+
 	_, err = GetInstance().
 		NewCreateTable().
 		Model((*entities.Role)(nil)).
@@ -153,6 +206,25 @@ func CreateSchemaIfNotExists() error {
 		return err
 	}
 
+	// Login failures and lockouts of the superadmin have no tenant
+	_, err = GetInstance().
+		NewCreateTable().
+		Model((*entities.LoginFailure)(nil)).
+		IfNotExists().
+		Exec(context.Background())
+	if err != nil {
+		return err
+	}
+
+	_, err = GetInstance().
+		NewCreateTable().
+		Model((*entities.AccountLockout)(nil)).
+		IfNotExists().
+		Exec(context.Background())
+	if err != nil {
+		return err
+	}
+
 	_, err = GetInstance().
 		NewCreateTable().
 		Model((*entities.Product)(nil)).
@@ -224,6 +296,65 @@ func CreateSchemaIfNotExists() error {
 		return err
 	}
 
+	_, err = GetInstance().NewCreateTable().
+		Model((*entities.LicenseUser)(nil)).
+		IfNotExists().
+		ForeignKey(`("tenant_name") REFERENCES "tenants" ("name") ON DELETE CASCADE`).
+		ForeignKey(`("license_id") REFERENCES "licenses" ("id") ON DELETE CASCADE`).
+		ForeignKey(`("username", "tenant_name") REFERENCES "accounts" ("username", "tenant_name") ON DELETE CASCADE`).
+		Exec(context.Background())
+	if err != nil {
+		return err
+	}
+
+	_, err = GetInstance().NewCreateTable().
+		Model((*entities.LicenseEntitlement)(nil)).
+		IfNotExists().
+		ForeignKey(`("tenant_name") REFERENCES "tenants" ("name") ON DELETE CASCADE`).
+		ForeignKey(`("license_id") REFERENCES "licenses" ("id") ON DELETE CASCADE`).
+		ForeignKey(`("entitlement_id") REFERENCES "entitlements" ("id") ON DELETE CASCADE`).
+		Exec(context.Background())
+	if err != nil {
+		return err
+	}
+
+	_, err = GetInstance().NewCreateTable().
+		Model((*entities.LicenseNonce)(nil)).
+		IfNotExists().
+		ForeignKey(`("tenant_name") REFERENCES "tenants" ("name") ON DELETE CASCADE`).
+		ForeignKey(`("license_id") REFERENCES "licenses" ("id") ON DELETE CASCADE`).
+		Exec(context.Background())
+	if err != nil {
+		return err
+	}
+
+	_, err = GetInstance().NewCreateTable().
+		Model((*entities.LicenseToken)(nil)).
+		IfNotExists().
+		ForeignKey(`("tenant_name") REFERENCES "tenants" ("name") ON DELETE CASCADE`).
+		ForeignKey(`("license_id") REFERENCES "licenses" ("id") ON DELETE CASCADE`).
+		Exec(context.Background())
+	if err != nil {
+		return err
+	}
+
+	// Refresh tokens of the superadmin have no tenant
+	_, err = GetInstance().NewCreateTable().
+		Model((*entities.RefreshToken)(nil)).
+		IfNotExists().
+		Exec(context.Background())
+	if err != nil {
+		return err
+	}
+
+	_, err = GetInstance().NewCreateTable().
+		Model((*entities.RevokedToken)(nil)).
+		IfNotExists().
+		Exec(context.Background())
+	if err != nil {
+		return err
+	}
+
 	_, err = GetInstance().NewCreateTable().
 		Model((*entities.Key)(nil)).
 		IfNotExists().
@@ -232,6 +363,23 @@ func CreateSchemaIfNotExists() error {
 	if err != nil {
 		return err
 	}
+
+	_, err = GetInstance().NewCreateTable().
+		Model((*entities.PolicyKey)(nil)).
+		IfNotExists().
+		ForeignKey(`("tenant_name") REFERENCES "tenants" ("name") ON DELETE CASCADE`).
+		ForeignKey(`("policy_id") REFERENCES "policies" ("id") ON DELETE CASCADE`).
+		Exec(context.Background())
+	if err != nil {
+		return err
+	}
+
+	for _, statement := range schemaUpgrades {
+		_, err = GetInstance().ExecContext(context.Background(), statement)
+		if err != nil {
+			return err
+		}
+	}
 	logging.GetInstance().GetLogger().Info("completed initializing database schemas")
 
 	return nil
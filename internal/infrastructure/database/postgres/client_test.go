@@ -269,29 +269,127 @@ func TestNewPostgresClient_CreateLicenseSchema(t *testing.T) {
 	assert.NoError(t, err)
 }
 
-//func TestNewPostgresClient_CreateLicenseTokenSchema(t *testing.T) {
-//
-//	viper.Set(config.PostgresHost, "127.0.0.1")
-//	viper.Set(config.PostgresPort, "5432")
-//	viper.Set(config.PostgresDatabase, "licenses")
-//	viper.Set(config.PostgresUsername, "postgres")
-//	viper.Set(config.PostgresPassword, "123qweA#")
-//
-//	dbClient, err := NewPostgresClient(
-//		viper.GetString(config.PostgresHost),
-//		viper.GetString(config.PostgresPort),
-//		viper.GetString(config.PostgresDatabase),
-//		viper.GetString(config.PostgresUsername),
-//		viper.GetString(config.PostgresPassword),
-//	)
-//	assert.NoError(t, err)
-//	assert.NotNil(t, dbClient)
-//
-//	_, err = dbClient.NewCreateTable().Model((*entities.LicenseToken)(nil)).
-//		ForeignKey(`("license_id") REFERENCES "licenses" ("id") ON DELETE CASCADE`).
-//		Exec(context.Background())
-//	assert.NoError(t, err)
-//}
+func TestNewPostgresClient_CreateLicenseTokenSchema(t *testing.T) {
+
+	viper.Set(config.PostgresHost, "127.0.0.1")
+	viper.Set(config.PostgresPort, "5432")
+	viper.Set(config.PostgresDatabase, "licenses")
+	viper.Set(config.PostgresUsername, "postgres")
+	viper.Set(config.PostgresPassword, "123qweA#")
+
+	dbClient, err := NewPostgresClient(
+		viper.GetString(config.PostgresHost),
+		viper.GetString(config.PostgresPort),
+		viper.GetString(config.PostgresDatabase),
+		viper.GetString(config.PostgresUsername),
+		viper.GetString(config.PostgresPassword),
+	)
+	assert.NoError(t, err)
+	assert.NotNil(t, dbClient)
+
+	_, err = dbClient.NewCreateTable().Model((*entities.LicenseToken)(nil)).
+		IfNotExists().
+		ForeignKey(`("tenant_name") REFERENCES "tenants" ("name") ON DELETE CASCADE`).
+		ForeignKey(`("license_id") REFERENCES "licenses" ("id") ON DELETE CASCADE`).
+		Exec(context.Background())
+	assert.NoError(t, err)
+}
+
+func TestNewPostgresClient_CreateRefreshTokenSchema(t *testing.T) {
+
+	viper.Set(config.PostgresHost, "127.0.0.1")
+	viper.Set(config.PostgresPort, "5432")
+	viper.Set(config.PostgresDatabase, "licenses")
+	viper.Set(config.PostgresUsername, "postgres")
+	viper.Set(config.PostgresPassword, "123qweA#")
+
+	dbClient, err := NewPostgresClient(
+		viper.GetString(config.PostgresHost),
+		viper.GetString(config.PostgresPort),
+		viper.GetString(config.PostgresDatabase),
+		viper.GetString(config.PostgresUsername),
+		viper.GetString(config.PostgresPassword),
+	)
+	assert.NoError(t, err)
+	assert.NotNil(t, dbClient)
+
+	_, err = dbClient.NewCreateTable().Model((*entities.RefreshToken)(nil)).
+		IfNotExists().
+		Exec(context.Background())
+	assert.NoError(t, err)
+}
+
+func TestNewPostgresClient_CreateRevokedTokenSchema(t *testing.T) {
+
+	viper.Set(config.PostgresHost, "127.0.0.1")
+	viper.Set(config.PostgresPort, "5432")
+	viper.Set(config.PostgresDatabase, "licenses")
+	viper.Set(config.PostgresUsername, "postgres")
+	viper.Set(config.PostgresPassword, "123qweA#")
+
+	dbClient, err := NewPostgresClient(
+		viper.GetString(config.PostgresHost),
+		viper.GetString(config.PostgresPort),
+		viper.GetString(config.PostgresDatabase),
+		viper.GetString(config.PostgresUsername),
+		viper.GetString(config.PostgresPassword),
+	)
+	assert.NoError(t, err)
+	assert.NotNil(t, dbClient)
+
+	_, err = dbClient.NewCreateTable().Model((*entities.RevokedToken)(nil)).
+		IfNotExists().
+		Exec(context.Background())
+	assert.NoError(t, err)
+}
+
+func TestNewPostgresClient_CreateLoginFailureSchema(t *testing.T) {
+
+	viper.Set(config.PostgresHost, "127.0.0.1")
+	viper.Set(config.PostgresPort, "5432")
+	viper.Set(config.PostgresDatabase, "licenses")
+	viper.Set(config.PostgresUsername, "postgres")
+	viper.Set(config.PostgresPassword, "123qweA#")
+
+	dbClient, err := NewPostgresClient(
+		viper.GetString(config.PostgresHost),
+		viper.GetString(config.PostgresPort),
+		viper.GetString(config.PostgresDatabase),
+		viper.GetString(config.PostgresUsername),
+		viper.GetString(config.PostgresPassword),
+	)
+	assert.NoError(t, err)
+	assert.NotNil(t, dbClient)
+
+	_, err = dbClient.NewCreateTable().Model((*entities.LoginFailure)(nil)).
+		IfNotExists().
+		Exec(context.Background())
+	assert.NoError(t, err)
+}
+
+func TestNewPostgresClient_CreateAccountLockoutSchema(t *testing.T) {
+
+	viper.Set(config.PostgresHost, "127.0.0.1")
+	viper.Set(config.PostgresPort, "5432")
+	viper.Set(config.PostgresDatabase, "licenses")
+	viper.Set(config.PostgresUsername, "postgres")
+	viper.Set(config.PostgresPassword, "123qweA#")
+
+	dbClient, err := NewPostgresClient(
+		viper.GetString(config.PostgresHost),
+		viper.GetString(config.PostgresPort),
+		viper.GetString(config.PostgresDatabase),
+		viper.GetString(config.PostgresUsername),
+		viper.GetString(config.PostgresPassword),
+	)
+	assert.NoError(t, err)
+	assert.NotNil(t, dbClient)
+
+	_, err = dbClient.NewCreateTable().Model((*entities.AccountLockout)(nil)).
+		IfNotExists().
+		Exec(context.Background())
+	assert.NoError(t, err)
+}
 
 func TestNewPostgresClient_CreateKeySchema(t *testing.T) {
 
@@ -340,6 +438,58 @@ func TestNewPostgresClient_CreateMachineSchema(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestNewPostgresClient_CreateLicenseUserSchema(t *testing.T) {
+
+	viper.Set(config.PostgresHost, "127.0.0.1")
+	viper.Set(config.PostgresPort, "5432")
+	viper.Set(config.PostgresDatabase, "licenses")
+	viper.Set(config.PostgresUsername, "postgres")
+	viper.Set(config.PostgresPassword, "123qweA#")
+
+	dbClient, err := NewPostgresClient(
+		viper.GetString(config.PostgresHost),
+		viper.GetString(config.PostgresPort),
+		viper.GetString(config.PostgresDatabase),
+		viper.GetString(config.PostgresUsername),
+		viper.GetString(config.PostgresPassword),
+	)
+	assert.NoError(t, err)
+	assert.NotNil(t, dbClient)
+
+	_, err = dbClient.NewCreateTable().Model((*entities.LicenseUser)(nil)).
+		ForeignKey(`("tenant_name") REFERENCES "tenants" ("name") ON DELETE CASCADE`).
+		ForeignKey(`("license_id") REFERENCES "licenses" ("id") ON DELETE CASCADE`).
+		ForeignKey(`("username", "tenant_name") REFERENCES "accounts" ("username", "tenant_name") ON DELETE CASCADE`).
+		Exec(context.Background())
+	assert.NoError(t, err)
+}
+
+func TestNewPostgresClient_CreateLicenseEntitlementSchema(t *testing.T) {
+
+	viper.Set(config.PostgresHost, "127.0.0.1")
+	viper.Set(config.PostgresPort, "5432")
+	viper.Set(config.PostgresDatabase, "licenses")
+	viper.Set(config.PostgresUsername, "postgres")
+	viper.Set(config.PostgresPassword, "123qweA#")
+
+	dbClient, err := NewPostgresClient(
+		viper.GetString(config.PostgresHost),
+		viper.GetString(config.PostgresPort),
+		viper.GetString(config.PostgresDatabase),
+		viper.GetString(config.PostgresUsername),
+		viper.GetString(config.PostgresPassword),
+	)
+	assert.NoError(t, err)
+	assert.NotNil(t, dbClient)
+
+	_, err = dbClient.NewCreateTable().Model((*entities.LicenseEntitlement)(nil)).
+		ForeignKey(`("tenant_name") REFERENCES "tenants" ("name") ON DELETE CASCADE`).
+		ForeignKey(`("license_id") REFERENCES "licenses" ("id") ON DELETE CASCADE`).
+		ForeignKey(`("entitlement_id") REFERENCES "entitlements" ("id") ON DELETE CASCADE`).
+		Exec(context.Background())
+	assert.NoError(t, err)
+}
+
 func TestGetInstance(t *testing.T) {
 	viper.Set(config.PostgresHost, "127.0.0.1")
 	viper.Set(config.PostgresPort, "5432")
@@ -363,3 +513,77 @@ func TestGetInstance(t *testing.T) {
 
 	fmt.Println(licenses[0].Product)
 }
+
+func TestNewPostgresClient_CreatePolicyKeySchema(t *testing.T) {
+
+	viper.Set(config.PostgresHost, "127.0.0.1")
+	viper.Set(config.PostgresPort, "5432")
+	viper.Set(config.PostgresDatabase, "licenses")
+	viper.Set(config.PostgresUsername, "postgres")
+	viper.Set(config.PostgresPassword, "123qweA#")
+
+	dbClient, err := NewPostgresClient(
+		viper.GetString(config.PostgresHost),
+		viper.GetString(config.PostgresPort),
+		viper.GetString(config.PostgresDatabase),
+		viper.GetString(config.PostgresUsername),
+		viper.GetString(config.PostgresPassword),
+	)
+	assert.NoError(t, err)
+	assert.NotNil(t, dbClient)
+
+	_, err = dbClient.NewCreateTable().Model((*entities.PolicyKey)(nil)).
+		ForeignKey(`("tenant_name") REFERENCES "tenants" ("name") ON DELETE CASCADE`).
+		ForeignKey(`("policy_id") REFERENCES "policies" ("id") ON DELETE CASCADE`).
+		Exec(context.Background())
+	assert.NoError(t, err)
+}
+
+func TestNewPostgresClient_CreateTenantKeySchema(t *testing.T) {
+
+	viper.Set(config.PostgresHost, "127.0.0.1")
+	viper.Set(config.PostgresPort, "5432")
+	viper.Set(config.PostgresDatabase, "licenses")
+	viper.Set(config.PostgresUsername, "postgres")
+	viper.Set(config.PostgresPassword, "123qweA#")
+
+	dbClient, err := NewPostgresClient(
+		viper.GetString(config.PostgresHost),
+		viper.GetString(config.PostgresPort),
+		viper.GetString(config.PostgresDatabase),
+		viper.GetString(config.PostgresUsername),
+		viper.GetString(config.PostgresPassword),
+	)
+	assert.NoError(t, err)
+	assert.NotNil(t, dbClient)
+
+	_, err = dbClient.NewCreateTable().Model((*entities.TenantKey)(nil)).
+		ForeignKey(`("tenant_name") REFERENCES "tenants" ("name") ON DELETE CASCADE`).
+		Exec(context.Background())
+	assert.NoError(t, err)
+}
+
+func TestNewPostgresClient_CreateLicenseNonceSchema(t *testing.T) {
+
+	viper.Set(config.PostgresHost, "127.0.0.1")
+	viper.Set(config.PostgresPort, "5432")
+	viper.Set(config.PostgresDatabase, "licenses")
+	viper.Set(config.PostgresUsername, "postgres")
+	viper.Set(config.PostgresPassword, "123qweA#")
+
+	dbClient, err := NewPostgresClient(
+		viper.GetString(config.PostgresHost),
+		viper.GetString(config.PostgresPort),
+		viper.GetString(config.PostgresDatabase),
+		viper.GetString(config.PostgresUsername),
+		viper.GetString(config.PostgresPassword),
+	)
+	assert.NoError(t, err)
+	assert.NotNil(t, dbClient)
+
+	_, err = dbClient.NewCreateTable().Model((*entities.LicenseNonce)(nil)).
+		ForeignKey(`("tenant_name") REFERENCES "tenants" ("name") ON DELETE CASCADE`).
+		ForeignKey(`("license_id") REFERENCES "licenses" ("id") ON DELETE CASCADE`).
+		Exec(context.Background())
+	assert.NoError(t, err)
+}
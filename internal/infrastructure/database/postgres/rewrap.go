@@ -0,0 +1,98 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/uptrace/bun"
+	"go-license-management/internal/infrastructure/database/entities"
+	"go-license-management/internal/infrastructure/envelope"
+	"go-license-management/internal/infrastructure/logging"
+)
+
+// RewrapPrivateKeys re-wraps the private keys of the superadmin, the tenants and the policies with the current key
+// encryption key. Keys sealed with a previous key encryption key are re-wrapped and plain keys are sealed.
+// It returns the number of updated keys.
+func RewrapPrivateKeys(ctx context.Context) (int, error) {
+	if GetInstance() == nil {
+		return 0, fmt.Errorf("database client is not initialized")
+	}
+
+	if !envelope.GetInstance().Enabled() {
+		return 0, envelope.ErrKEKIsMissing
+	}
+
+	count := 0
+	err := GetInstance().RunInTx(ctx, &sql.TxOptions{}, func(ctx context.Context, tx bun.Tx) error {
+		masters := make([]entities.Master, 0)
+		err := tx.NewSelect().Model(&masters).Scan(ctx)
+		if err != nil {
+			return err
+		}
+		for _, master := range masters {
+			var changed bool
+			master.Ed25519PrivateKey, changed, err = envelope.GetInstance().Rewrap(master.Ed25519PrivateKey, envelope.MasterKeyAssociatedData(master.Username))
+			if err != nil {
+				return fmt.Errorf("superadmin [%s]: %w", master.Username, err)
+			}
+			if !changed {
+				continue
+			}
+			_, err = tx.NewUpdate().Model(&master).Column("ed25519_private_key").WherePK().Exec(ctx)
+			if err != nil {
+				return err
+			}
+			count++
+		}
+
+		tenants := make([]entities.Tenant, 0)
+		err = tx.NewSelect().Model(&tenants).Scan(ctx)
+		if err != nil {
+			return err
+		}
+		for _, tenant := range tenants {
+			var changed bool
+			tenant.Ed25519PrivateKey, changed, err = envelope.GetInstance().Rewrap(tenant.Ed25519PrivateKey, envelope.TenantKeyAssociatedData(tenant.Name))
+			if err != nil {
+				return fmt.Errorf("tenant [%s]: %w", tenant.Name, err)
+			}
+			if !changed {
+				continue
+			}
+			_, err = tx.NewUpdate().Model(&tenant).Column("ed25519_private_key").WherePK().Exec(ctx)
+			if err != nil {
+				return err
+			}
+			count++
+		}
+
+		policies := make([]entities.Policy, 0)
+		err = tx.NewSelect().Model(&policies).Scan(ctx)
+		if err != nil {
+			return err
+		}
+		for _, policy := range policies {
+			var changed bool
+			policy.PrivateKey, changed, err = envelope.GetInstance().Rewrap(policy.PrivateKey, envelope.PolicyKeyAssociatedData(policy.ID.String()))
+			if err != nil {
+				return fmt.Errorf("policy [%s]: %w", policy.ID, err)
+			}
+			if !changed {
+				continue
+			}
+			_, err = tx.NewUpdate().Model(&policy).Column("private_key").WherePK().Exec(ctx)
+			if err != nil {
+				return err
+			}
+			count++
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	logging.GetInstance().GetLogger().Info(fmt.Sprintf("re-wrapped [%d] private keys", count))
+	return count, nil
+}
@@ -0,0 +1,326 @@
+package envelope
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// Prefix marks the values sealed with envelope encryption, in format
+// `kek:v1:<kek id>:<wrapped data key>:<ciphertext>`. Each value is encrypted with its own AES-256-GCM data key,
+// which is itself encrypted with the key encryption key (KEK), so rotating the KEK only re-wraps the data keys.
+// The ciphertext is bound to the row holding the value with associated data, so a sealed value copied into another
+// row cannot be opened.
+const Prefix = "kek:v1:"
+
+// KEKSize is the size, in bytes, of the key encryption key.
+const KEKSize = 32
+
+const dataKeySize = 32
+
+var (
+	ErrKEKIsInvalid         = errors.New("key encryption key must be 32 bytes, base64 encoded")
+	ErrKEKIsMissing         = errors.New("key encryption key is not configured")
+	ErrKEKIsUnknown         = errors.New("value is sealed with an unknown key encryption key")
+	ErrSealedValueIsInvalid = errors.New("sealed value is invalid")
+)
+
+type keyEncryptionKey struct {
+	id  string
+	key []byte
+}
+
+// Envelope seals and opens private key material with the configured key encryption key.
+// Previous keys are only used to open values sealed before a rotation, see Rewrap.
+type Envelope struct {
+	current  *keyEncryptionKey
+	previous []*keyEncryptionKey
+}
+
+var envelopeInstance = &Envelope{}
+
+func GetInstance() *Envelope {
+	return envelopeInstance
+}
+
+// NewEnvelope creates the envelope from base64 encoded key encryption keys. Without a current key, values are
+// stored as they are.
+func NewEnvelope(current string, previous ...string) (*Envelope, error) {
+	envelope := &Envelope{}
+
+	if current != "" {
+		kek, err := newKeyEncryptionKey(current)
+		if err != nil {
+			return nil, err
+		}
+		envelope.current = kek
+	}
+
+	for _, encoded := range previous {
+		if encoded == "" {
+			continue
+		}
+		kek, err := newKeyEncryptionKey(encoded)
+		if err != nil {
+			return nil, err
+		}
+		envelope.previous = append(envelope.previous, kek)
+	}
+
+	if envelope.current == nil && len(envelope.previous) > 0 {
+		return nil, ErrKEKIsMissing
+	}
+
+	envelopeInstance = envelope
+	return envelope, nil
+}
+
+// NewKEK generates a new base64 encoded key encryption key.
+func NewKEK() (string, error) {
+	key := make([]byte, KEKSize)
+	_, err := io.ReadFull(rand.Reader, key)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(key), nil
+}
+
+// LoadKEK returns the key encryption key set in the config, or read from the file when it is not set.
+func LoadKEK(value string, file string) (string, error) {
+	if value != "" || file == "" {
+		return strings.TrimSpace(value), nil
+	}
+
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(content)), nil
+}
+
+// MasterKeyAssociatedData returns the associated data of the private key of the superadmin.
+func MasterKeyAssociatedData(username string) string {
+	return associatedData("masters", username)
+}
+
+// TenantKeyAssociatedData returns the associated data of the private key of the tenant.
+func TenantKeyAssociatedData(tenantName string) string {
+	return associatedData("tenants", tenantName)
+}
+
+// PolicyKeyAssociatedData returns the associated data of the private key of the policy.
+func PolicyKeyAssociatedData(policyID string) string {
+	return associatedData("policies", policyID)
+}
+
+func associatedData(table string, id string) string {
+	return fmt.Sprintf("%s:%d:%s", table, len(id), id)
+}
+
+// Enabled reports whether a key encryption key is configured.
+func (e *Envelope) Enabled() bool {
+	return e.current != nil
+}
+
+// IsSealed reports whether the value is sealed with envelope encryption.
+func IsSealed(value string) bool {
+	return strings.HasPrefix(value, Prefix)
+}
+
+// Seal encrypts the value with a new data key wrapped with the current key encryption key, bound to the associated
+// data of the row holding it. Values that are already sealed are returned as they are, as well as every value when
+// no key encryption key is configured.
+func (e *Envelope) Seal(value string, associatedData string) (string, error) {
+	if value == "" || IsSealed(value) || !e.Enabled() {
+		return value, nil
+	}
+
+	dataKey := make([]byte, dataKeySize)
+	_, err := io.ReadFull(rand.Reader, dataKey)
+	if err != nil {
+		return "", err
+	}
+
+	wrappedKey, err := encrypt(e.current.key, dataKey, nil)
+	if err != nil {
+		return "", err
+	}
+
+	ciphertext, err := encrypt(dataKey, []byte(value), []byte(associatedData))
+	if err != nil {
+		return "", err
+	}
+
+	return e.format(e.current.id, wrappedKey, ciphertext), nil
+}
+
+// Open decrypts a sealed value with the key encryption key it was sealed with, checking that it was sealed with the
+// associated data of the row holding it. Values that are not sealed are returned as they are, so keys stored before
+// envelope encryption was enabled keep working.
+func (e *Envelope) Open(value string, associatedData string) (string, error) {
+	if !IsSealed(value) {
+		return value, nil
+	}
+
+	kek, wrappedKey, ciphertext, err := e.parse(value)
+	if err != nil {
+		return "", err
+	}
+
+	dataKey, err := decrypt(kek.key, wrappedKey, nil)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", ErrSealedValueIsInvalid, err)
+	}
+
+	plaintext, err := decrypt(dataKey, ciphertext, []byte(associatedData))
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", ErrSealedValueIsInvalid, err)
+	}
+
+	return string(plaintext), nil
+}
+
+// Rewrap re-wraps the data key of a value sealed with a previous key encryption key with the current one.
+// Values that are not sealed yet are sealed with the associated data. It reports whether the value changed.
+func (e *Envelope) Rewrap(value string, associatedData string) (string, bool, error) {
+	if !e.Enabled() {
+		return value, false, ErrKEKIsMissing
+	}
+
+	if value == "" {
+		return value, false, nil
+	}
+
+	if !IsSealed(value) {
+		sealed, err := e.Seal(value, associatedData)
+		return sealed, err == nil, err
+	}
+
+	kek, wrappedKey, ciphertext, err := e.parse(value)
+	if err != nil {
+		return value, false, err
+	}
+	if kek == e.current {
+		return value, false, nil
+	}
+
+	dataKey, err := decrypt(kek.key, wrappedKey, nil)
+	if err != nil {
+		return value, false, fmt.Errorf("%w: %s", ErrSealedValueIsInvalid, err)
+	}
+
+	wrappedKey, err = encrypt(e.current.key, dataKey, nil)
+	if err != nil {
+		return value, false, err
+	}
+
+	return e.format(e.current.id, wrappedKey, ciphertext), true, nil
+}
+
+func (e *Envelope) format(kid string, wrappedKey []byte, ciphertext []byte) string {
+	return Prefix + strings.Join([]string{
+		kid,
+		base64.StdEncoding.EncodeToString(wrappedKey),
+		base64.StdEncoding.EncodeToString(ciphertext),
+	}, ":")
+}
+
+func (e *Envelope) parse(value string) (*keyEncryptionKey, []byte, []byte, error) {
+	parts := strings.Split(strings.TrimPrefix(value, Prefix), ":")
+	if len(parts) != 3 {
+		return nil, nil, nil, ErrSealedValueIsInvalid
+	}
+
+	kek := e.lookup(parts[0])
+	if kek == nil {
+		if !e.Enabled() {
+			return nil, nil, nil, ErrKEKIsMissing
+		}
+		return nil, nil, nil, fmt.Errorf("%w: %s", ErrKEKIsUnknown, parts[0])
+	}
+
+	wrappedKey, err := base64.StdEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("%w: %s", ErrSealedValueIsInvalid, err)
+	}
+
+	ciphertext, err := base64.StdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("%w: %s", ErrSealedValueIsInvalid, err)
+	}
+
+	return kek, wrappedKey, ciphertext, nil
+}
+
+func (e *Envelope) lookup(kid string) *keyEncryptionKey {
+	if e.current != nil && e.current.id == kid {
+		return e.current
+	}
+	for _, kek := range e.previous {
+		if kek.id == kid {
+			return kek
+		}
+	}
+	return nil
+}
+
+func newKeyEncryptionKey(encoded string) (*keyEncryptionKey, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil || len(key) != KEKSize {
+		return nil, ErrKEKIsInvalid
+	}
+
+	digest := sha256.Sum256(key)
+	return &keyEncryptionKey{
+		id:  hex.EncodeToString(digest[:8]),
+		key: key,
+	}, nil
+}
+
+// encrypt encrypts the plaintext with AES-256-GCM, the nonce is prepended to the ciphertext.
+func encrypt(key []byte, plaintext []byte, associatedData []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	_, err = io.ReadFull(rand.Reader, nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	return gcm.Seal(nonce, nonce, plaintext, associatedData), nil
+}
+
+func decrypt(key []byte, ciphertext []byte, associatedData []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ciphertext) < gcm.NonceSize() {
+		return nil, errors.New("ciphertext is too short")
+	}
+
+	nonce, ciphertext := ciphertext[:gcm.NonceSize()], ciphertext[gcm.NonceSize():]
+	return gcm.Open(nil, nonce, ciphertext, associatedData)
+}
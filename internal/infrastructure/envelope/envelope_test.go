@@ -0,0 +1,155 @@
+package envelope
+
+import (
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testPrivateKey = "MC4CAQAwBQYDK2VwBCIEIKvMdDHZ1s8Dz5qD4Ve3kxGiBEp3p3L7J4aiIzN0dnpG"
+
+var testAssociatedData = TenantKeyAssociatedData("tenant")
+
+func TestEnvelope_SealOpen(t *testing.T) {
+	kek, err := NewKEK()
+	assert.NoError(t, err)
+
+	envelope, err := NewEnvelope(kek)
+	assert.NoError(t, err)
+	assert.True(t, envelope.Enabled())
+
+	sealed, err := envelope.Seal(testPrivateKey, testAssociatedData)
+	assert.NoError(t, err)
+	assert.True(t, IsSealed(sealed))
+	assert.NotContains(t, sealed, testPrivateKey)
+	assert.LessOrEqual(t, len(sealed), 512)
+
+	// Sealing is idempotent, so entities read back from the database can be stored again as they are
+	resealed, err := envelope.Seal(sealed, testAssociatedData)
+	assert.NoError(t, err)
+	assert.Equal(t, sealed, resealed)
+
+	opened, err := envelope.Open(sealed, testAssociatedData)
+	assert.NoError(t, err)
+	assert.Equal(t, testPrivateKey, opened)
+
+	// Values stored before envelope encryption was enabled are returned as they are
+	opened, err = envelope.Open(testPrivateKey, testAssociatedData)
+	assert.NoError(t, err)
+	assert.Equal(t, testPrivateKey, opened)
+
+	_, err = envelope.Open(sealed[:len(sealed)-8]+"AAAAAAA=", testAssociatedData)
+	assert.ErrorIs(t, err, ErrSealedValueIsInvalid)
+
+	// Values copied into another row cannot be opened
+	_, err = envelope.Open(sealed, TenantKeyAssociatedData("other"))
+	assert.ErrorIs(t, err, ErrSealedValueIsInvalid)
+	_, err = envelope.Open(sealed, MasterKeyAssociatedData("tenant"))
+	assert.ErrorIs(t, err, ErrSealedValueIsInvalid)
+}
+
+func TestEnvelope_Disabled(t *testing.T) {
+	envelope, err := NewEnvelope("")
+	assert.NoError(t, err)
+	assert.False(t, envelope.Enabled())
+
+	sealed, err := envelope.Seal(testPrivateKey, testAssociatedData)
+	assert.NoError(t, err)
+	assert.Equal(t, testPrivateKey, sealed)
+
+	kek, err := NewKEK()
+	assert.NoError(t, err)
+	other, err := NewEnvelope(kek)
+	assert.NoError(t, err)
+	sealed, err = other.Seal(testPrivateKey, testAssociatedData)
+	assert.NoError(t, err)
+
+	_, err = envelope.Open(sealed, testAssociatedData)
+	assert.ErrorIs(t, err, ErrKEKIsMissing)
+
+	_, _, err = envelope.Rewrap(sealed, testAssociatedData)
+	assert.ErrorIs(t, err, ErrKEKIsMissing)
+}
+
+func TestEnvelope_Rewrap(t *testing.T) {
+	previousKEK, err := NewKEK()
+	assert.NoError(t, err)
+	currentKEK, err := NewKEK()
+	assert.NoError(t, err)
+
+	previous, err := NewEnvelope(previousKEK)
+	assert.NoError(t, err)
+	sealed, err := previous.Seal(testPrivateKey, testAssociatedData)
+	assert.NoError(t, err)
+
+	current, err := NewEnvelope(currentKEK)
+	assert.NoError(t, err)
+	_, err = current.Open(sealed, testAssociatedData)
+	assert.ErrorIs(t, err, ErrKEKIsUnknown)
+
+	// Values sealed with the previous key can still be opened until they are re-wrapped
+	rotating, err := NewEnvelope(currentKEK, previousKEK)
+	assert.NoError(t, err)
+	opened, err := rotating.Open(sealed, testAssociatedData)
+	assert.NoError(t, err)
+	assert.Equal(t, testPrivateKey, opened)
+
+	rewrapped, changed, err := rotating.Rewrap(sealed, testAssociatedData)
+	assert.NoError(t, err)
+	assert.True(t, changed)
+	assert.NotEqual(t, sealed, rewrapped)
+	// Only the data key is re-wrapped, the ciphertext is unchanged
+	assert.Equal(t, sealed[strings.LastIndex(sealed, ":"):], rewrapped[strings.LastIndex(rewrapped, ":"):])
+
+	opened, err = current.Open(rewrapped, testAssociatedData)
+	assert.NoError(t, err)
+	assert.Equal(t, testPrivateKey, opened)
+
+	_, changed, err = rotating.Rewrap(rewrapped, testAssociatedData)
+	assert.NoError(t, err)
+	assert.False(t, changed)
+
+	// Plain values are sealed
+	rewrapped, changed, err = rotating.Rewrap(testPrivateKey, testAssociatedData)
+	assert.NoError(t, err)
+	assert.True(t, changed)
+	assert.True(t, IsSealed(rewrapped))
+}
+
+func TestNewEnvelope_InvalidKEK(t *testing.T) {
+	_, err := NewEnvelope("not-a-key")
+	assert.ErrorIs(t, err, ErrKEKIsInvalid)
+
+	_, err = NewEnvelope("c2hvcnQ=")
+	assert.ErrorIs(t, err, ErrKEKIsInvalid)
+
+	kek, err := NewKEK()
+	assert.NoError(t, err)
+	_, err = NewEnvelope("", kek)
+	assert.ErrorIs(t, err, ErrKEKIsMissing)
+}
+
+func TestLoadKEK(t *testing.T) {
+	kek, err := NewKEK()
+	assert.NoError(t, err)
+
+	file := filepath.Join(t.TempDir(), "kek")
+	assert.NoError(t, os.WriteFile(file, []byte(kek+"\n"), 0600))
+
+	loaded, err := LoadKEK("", file)
+	assert.NoError(t, err)
+	assert.Equal(t, kek, loaded)
+
+	loaded, err = LoadKEK(kek, "")
+	assert.NoError(t, err)
+	assert.Equal(t, kek, loaded)
+
+	loaded, err = LoadKEK("", "")
+	assert.NoError(t, err)
+	assert.Empty(t, loaded)
+
+	_, err = LoadKEK("", filepath.Join(t.TempDir(), "missing"))
+	assert.Error(t, err)
+}
@@ -38,4 +38,39 @@ type LicenseFileContent struct {
 	Enc string `json:"enc"`
 	Sig string `json:"sig"`
 	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+// LicenseValidationScope narrows a license validation. The license is only valid if it matches every provided scope.
+type LicenseValidationScope struct {
+	Fingerprint  *string  `json:"fingerprint" validate:"optional" example:"test"`
+	ProductID    *string  `json:"product_id" validate:"optional" example:"test"`
+	PolicyID     *string  `json:"policy_id" validate:"optional" example:"test"`
+	Entitlements []string `json:"entitlements" validate:"optional" example:"test"`
+}
+
+func (req *LicenseValidationScope) Validate() error {
+	if req.Fingerprint != nil && utils.DerefPointer(req.Fingerprint) == "" {
+		return cerrors.ErrMachineFingerprintIsEmpty
+	}
+
+	if req.ProductID != nil {
+		if _, err := uuid.Parse(utils.DerefPointer(req.ProductID)); err != nil {
+			return cerrors.ErrProductIDIsInvalid
+		}
+	}
+
+	if req.PolicyID != nil {
+		if _, err := uuid.Parse(utils.DerefPointer(req.PolicyID)); err != nil {
+			return cerrors.ErrPolicyIDIsInvalid
+		}
+	}
+
+	for _, code := range req.Entitlements {
+		if code == "" {
+			return cerrors.ErrEntitlementCodeIsEmpty
+		}
+	}
+
+	return nil
 }
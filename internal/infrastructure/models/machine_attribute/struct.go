@@ -86,4 +86,5 @@ type MachineLicenseFileContent struct {
 	Enc string `json:"enc"`
 	Sig string `json:"sig"`
 	Alg string `json:"alg"`
+	Kid string `json:"kid"`
 }
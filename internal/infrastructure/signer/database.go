@@ -0,0 +1,68 @@
+package signer
+
+import (
+	"context"
+	"go-license-management/internal/infrastructure/database/entities"
+	"go-license-management/internal/infrastructure/envelope"
+	"go-license-management/internal/utils"
+)
+
+// DatabaseBackend stores the private key on the policy, sealed with the key encryption key.
+// The key is only opened inside the signer, when signing.
+type DatabaseBackend struct{}
+
+func NewDatabaseBackend() *DatabaseBackend {
+	return &DatabaseBackend{}
+}
+
+func (b *DatabaseBackend) NewKey(ctx context.Context, policy *entities.Policy) error {
+	privateKey, publicKey, err := utils.NewKeyPair(policy.Scheme)
+	if err != nil {
+		return err
+	}
+
+	privateKey, err = envelope.GetInstance().Seal(privateKey, envelope.PolicyKeyAssociatedData(policy.ID.String()))
+	if err != nil {
+		return err
+	}
+
+	policy.PrivateKey = privateKey
+	policy.PublicKey = publicKey
+	policy.SignerKeyID = ""
+	return nil
+}
+
+func (b *DatabaseBackend) Signer(ctx context.Context, policy *entities.Policy) (Signer, error) {
+	if policy.PrivateKey == "" {
+		return nil, ErrSignerKeyIsMissing
+	}
+
+	privateKey, err := envelope.GetInstance().Open(policy.PrivateKey, envelope.PolicyKeyAssociatedData(policy.ID.String()))
+	if err != nil {
+		return nil, err
+	}
+
+	return &databaseSigner{
+		scheme:     policy.Scheme,
+		publicKey:  policy.PublicKey,
+		privateKey: privateKey,
+	}, nil
+}
+
+type databaseSigner struct {
+	scheme     string
+	publicKey  string
+	privateKey string
+}
+
+func (s *databaseSigner) Scheme() string {
+	return s.scheme
+}
+
+func (s *databaseSigner) PublicKey() string {
+	return s.publicKey
+}
+
+func (s *databaseSigner) Sign(ctx context.Context, message []byte) ([]byte, error) {
+	return utils.SignMessage(s.scheme, s.privateKey, message)
+}
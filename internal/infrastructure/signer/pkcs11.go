@@ -0,0 +1,326 @@
+//go:build pkcs11
+
+package signer
+
+import (
+	"context"
+	"crypto/ecdh"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/miekg/pkcs11"
+	"go-license-management/internal/infrastructure/database/entities"
+	"go-license-management/internal/utils"
+	"math/big"
+	"sync"
+)
+
+// EdDSA mechanisms were added by PKCS#11 v3.0 and are missing from the bindings.
+const (
+	ckmECEdwardsKeyPairGen = 0x00001055
+	ckmEdDSA               = 0x00001057
+)
+
+var (
+	oidCurveP256    = asn1.ObjectIdentifier{1, 2, 840, 10045, 3, 1, 7}
+	oidCurveEd25519 = asn1.ObjectIdentifier{1, 3, 101, 112}
+)
+
+var ErrPKCS11TokenIsNotFound = errors.New("pkcs11 token is not found")
+
+// PKCS11Backend generates the private keys in a PKCS#11 token (HSM) and signs with them inside the token.
+// Only the label of the key is stored on the policy. The module is loaded once, and a single logged in session
+// is shared by the requests.
+type PKCS11Backend struct {
+	mu      sync.Mutex
+	ctx     *pkcs11.Ctx
+	session pkcs11.SessionHandle
+}
+
+// NewPKCS11Backend loads the PKCS#11 module and logs in the token with the label using the user PIN.
+func NewPKCS11Backend(module string, tokenLabel string, pin string) (Backend, error) {
+	ctx := pkcs11.New(module)
+	if ctx == nil {
+		return nil, fmt.Errorf("failed to load pkcs11 module [%s]", module)
+	}
+
+	err := ctx.Initialize()
+	if err != nil {
+		return nil, err
+	}
+
+	slots, err := ctx.GetSlotList(true)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, slot := range slots {
+		info, err := ctx.GetTokenInfo(slot)
+		if err != nil {
+			return nil, err
+		}
+		if info.Label != tokenLabel {
+			continue
+		}
+
+		session, err := ctx.OpenSession(slot, pkcs11.CKF_SERIAL_SESSION|pkcs11.CKF_RW_SESSION)
+		if err != nil {
+			return nil, err
+		}
+
+		err = ctx.Login(session, pkcs11.CKU_USER, pin)
+		if err != nil {
+			_ = ctx.CloseSession(session)
+			return nil, err
+		}
+
+		return &PKCS11Backend{ctx: ctx, session: session}, nil
+	}
+
+	return nil, fmt.Errorf("%w: [%s]", ErrPKCS11TokenIsNotFound, tokenLabel)
+}
+
+func (b *PKCS11Backend) NewKey(ctx context.Context, policy *entities.Policy) error {
+	label := uuid.NewString()
+
+	mechanism, publicTemplate, err := keyPairTemplate(policy.Scheme)
+	if err != nil {
+		return err
+	}
+	publicTemplate = append(publicTemplate,
+		pkcs11.NewAttribute(pkcs11.CKA_TOKEN, true),
+		pkcs11.NewAttribute(pkcs11.CKA_VERIFY, true),
+		pkcs11.NewAttribute(pkcs11.CKA_LABEL, label),
+	)
+	privateTemplate := []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_TOKEN, true),
+		pkcs11.NewAttribute(pkcs11.CKA_PRIVATE, true),
+		pkcs11.NewAttribute(pkcs11.CKA_SIGN, true),
+		pkcs11.NewAttribute(pkcs11.CKA_SENSITIVE, true),
+		pkcs11.NewAttribute(pkcs11.CKA_EXTRACTABLE, false),
+		pkcs11.NewAttribute(pkcs11.CKA_LABEL, label),
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	publicHandle, _, err := b.ctx.GenerateKeyPair(b.session, []*pkcs11.Mechanism{pkcs11.NewMechanism(mechanism, nil)}, publicTemplate, privateTemplate)
+	if err != nil {
+		return err
+	}
+
+	publicKey, err := b.exportPublicKey(policy.Scheme, publicHandle)
+	if err != nil {
+		return err
+	}
+
+	policy.PrivateKey = ""
+	policy.PublicKey = publicKey
+	policy.SignerKeyID = label
+	return nil
+}
+
+func (b *PKCS11Backend) Signer(ctx context.Context, policy *entities.Policy) (Signer, error) {
+	if policy.SignerKeyID == "" {
+		return nil, ErrSignerKeyIsMissing
+	}
+
+	return &pkcs11Signer{
+		backend:   b,
+		label:     policy.SignerKeyID,
+		scheme:    policy.Scheme,
+		publicKey: policy.PublicKey,
+	}, nil
+}
+
+// keyPairTemplate returns the key pair generation mechanism and the public key attributes of the scheme.
+func keyPairTemplate(scheme string) (uint, []*pkcs11.Attribute, error) {
+	switch scheme {
+	case utils.SigningSchemeED25519:
+		params, err := asn1.Marshal(oidCurveEd25519)
+		if err != nil {
+			return 0, nil, err
+		}
+		return ckmECEdwardsKeyPairGen, []*pkcs11.Attribute{pkcs11.NewAttribute(pkcs11.CKA_EC_PARAMS, params)}, nil
+	case utils.SigningSchemeECDSAP256:
+		params, err := asn1.Marshal(oidCurveP256)
+		if err != nil {
+			return 0, nil, err
+		}
+		return pkcs11.CKM_EC_KEY_PAIR_GEN, []*pkcs11.Attribute{pkcs11.NewAttribute(pkcs11.CKA_EC_PARAMS, params)}, nil
+	case utils.SigningSchemeRSA2048PKCS1, utils.SigningSchemeRSA2048PSS:
+		return pkcs11.CKM_RSA_PKCS_KEY_PAIR_GEN, rsaTemplate(2048), nil
+	case utils.SigningSchemeRSA4096PSS:
+		return pkcs11.CKM_RSA_PKCS_KEY_PAIR_GEN, rsaTemplate(4096), nil
+	default:
+		return 0, nil, fmt.Errorf("%w: [%s]", utils.ErrSigningSchemeIsNotSupported, scheme)
+	}
+}
+
+func rsaTemplate(bits int) []*pkcs11.Attribute {
+	return []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_MODULUS_BITS, bits),
+		pkcs11.NewAttribute(pkcs11.CKA_PUBLIC_EXPONENT, []byte{1, 0, 1}),
+	}
+}
+
+// exportPublicKey reads the public key from the token, and encodes it as the keys generated in the database.
+func (b *PKCS11Backend) exportPublicKey(scheme string, handle pkcs11.ObjectHandle) (string, error) {
+	switch scheme {
+	case utils.SigningSchemeED25519, utils.SigningSchemeECDSAP256:
+		attributes, err := b.ctx.GetAttributeValue(b.session, handle, []*pkcs11.Attribute{pkcs11.NewAttribute(pkcs11.CKA_EC_POINT, nil)})
+		if err != nil {
+			return "", err
+		}
+
+		point := ecPoint(attributes[0].Value)
+
+		var publicKey any
+		if scheme == utils.SigningSchemeED25519 {
+			if len(point) != ed25519.PublicKeySize {
+				return "", fmt.Errorf("ed25519 public key size is [%d] bytes", len(point))
+			}
+			publicKey = ed25519.PublicKey(point)
+		} else {
+			publicKey, err = ecdh.P256().NewPublicKey(point)
+			if err != nil {
+				return "", err
+			}
+		}
+
+		publicKeyBytes, err := x509.MarshalPKIXPublicKey(publicKey)
+		if err != nil {
+			return "", err
+		}
+		return base64.StdEncoding.EncodeToString(publicKeyBytes), nil
+	default:
+		attributes, err := b.ctx.GetAttributeValue(b.session, handle, []*pkcs11.Attribute{
+			pkcs11.NewAttribute(pkcs11.CKA_MODULUS, nil),
+			pkcs11.NewAttribute(pkcs11.CKA_PUBLIC_EXPONENT, nil),
+		})
+		if err != nil {
+			return "", err
+		}
+
+		publicKey := &rsa.PublicKey{
+			N: new(big.Int).SetBytes(attributes[0].Value),
+			E: int(new(big.Int).SetBytes(attributes[1].Value).Int64()),
+		}
+		publicKeyPEM := pem.EncodeToMemory(&pem.Block{
+			Type:  utils.RSAPublicKeyStr,
+			Bytes: x509.MarshalPKCS1PublicKey(publicKey),
+		})
+		return base64.StdEncoding.EncodeToString(publicKeyPEM), nil
+	}
+}
+
+// ecPoint unwraps CKA_EC_POINT, which is a DER encoded octet string, although some tokens return the raw point.
+func ecPoint(value []byte) []byte {
+	var point []byte
+	rest, err := asn1.Unmarshal(value, &point)
+	if err != nil || len(rest) > 0 {
+		return value
+	}
+	return point
+}
+
+// findPrivateKey returns the handle of the private key with the label. The caller holds the lock.
+func (b *PKCS11Backend) findPrivateKey(label string) (pkcs11.ObjectHandle, error) {
+	err := b.ctx.FindObjectsInit(b.session, []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_CLASS, pkcs11.CKO_PRIVATE_KEY),
+		pkcs11.NewAttribute(pkcs11.CKA_LABEL, label),
+	})
+	if err != nil {
+		return 0, err
+	}
+	defer b.ctx.FindObjectsFinal(b.session)
+
+	handles, _, err := b.ctx.FindObjects(b.session, 1)
+	if err != nil {
+		return 0, err
+	}
+	if len(handles) == 0 {
+		return 0, fmt.Errorf("%w: [%s]", ErrSignerKeyIsMissing, label)
+	}
+	return handles[0], nil
+}
+
+type pkcs11Signer struct {
+	backend   *PKCS11Backend
+	label     string
+	scheme    string
+	publicKey string
+}
+
+func (s *pkcs11Signer) Scheme() string {
+	return s.scheme
+}
+
+func (s *pkcs11Signer) PublicKey() string {
+	return s.publicKey
+}
+
+func (s *pkcs11Signer) Sign(ctx context.Context, message []byte) ([]byte, error) {
+	var mechanism *pkcs11.Mechanism
+	input := message
+	switch s.scheme {
+	case utils.SigningSchemeED25519:
+		mechanism = pkcs11.NewMechanism(ckmEdDSA, nil)
+	case utils.SigningSchemeECDSAP256:
+		// CKM_ECDSA signs a digest and returns the raw r || s values
+		mechanism = pkcs11.NewMechanism(pkcs11.CKM_ECDSA, nil)
+		hashed := sha256.Sum256(message)
+		input = hashed[:]
+	case utils.SigningSchemeRSA2048PKCS1:
+		mechanism = pkcs11.NewMechanism(pkcs11.CKM_SHA512_RSA_PKCS, nil)
+	case utils.SigningSchemeRSA2048PSS, utils.SigningSchemeRSA4096PSS:
+		mechanism = pkcs11.NewMechanism(pkcs11.CKM_SHA512_RSA_PKCS_PSS, pkcs11.NewPSSParams(pkcs11.CKM_SHA512, pkcs11.CKG_MGF1_SHA512, 64))
+	default:
+		return nil, fmt.Errorf("%w: [%s]", utils.ErrSigningSchemeIsNotSupported, s.scheme)
+	}
+
+	s.backend.mu.Lock()
+	defer s.backend.mu.Unlock()
+
+	handle, err := s.backend.findPrivateKey(s.label)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.backend.ctx.SignInit(s.backend.session, []*pkcs11.Mechanism{mechanism}, handle)
+	if err != nil {
+		return nil, err
+	}
+
+	signature, err := s.backend.ctx.Sign(s.backend.session, input)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.scheme == utils.SigningSchemeECDSAP256 {
+		return ecdsaASN1Signature(signature)
+	}
+	return signature, nil
+}
+
+// ecdsaASN1Signature converts a raw r || s ECDSA signature to the ASN.1 DER encoding expected by the verifier.
+func ecdsaASN1Signature(signature []byte) ([]byte, error) {
+	if len(signature) == 0 || len(signature)%2 != 0 {
+		return nil, ErrSignatureIsInvalid
+	}
+
+	half := len(signature) / 2
+	return asn1.Marshal(struct {
+		R, S *big.Int
+	}{
+		R: new(big.Int).SetBytes(signature[:half]),
+		S: new(big.Int).SetBytes(signature[half:]),
+	})
+}
@@ -0,0 +1,10 @@
+//go:build !pkcs11
+
+package signer
+
+import "fmt"
+
+// NewPKCS11Backend is only available when building with the pkcs11 tag, which requires cgo.
+func NewPKCS11Backend(module string, tokenLabel string, pin string) (Backend, error) {
+	return nil, fmt.Errorf("%w: [pkcs11]", ErrBackendIsNotCompiled)
+}
@@ -0,0 +1,53 @@
+//go:build pkcs11
+
+package signer
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"go-license-management/internal/constants"
+	"go-license-management/internal/infrastructure/database/entities"
+	"go-license-management/internal/utils"
+	"os"
+	"testing"
+)
+
+// TestPKCS11Backend_Sign runs against a SoftHSM token, e.g.
+//
+//	softhsm2-util --init-token --free --label license --pin 1234 --so-pin 1234
+//	PKCS11_MODULE=/usr/lib/softhsm/libsofthsm2.so PKCS11_TOKEN_LABEL=license PKCS11_PIN=1234 go test -tags pkcs11 ./internal/infrastructure/signer/
+func TestPKCS11Backend_Sign(t *testing.T) {
+	module := os.Getenv("PKCS11_MODULE")
+	if module == "" {
+		t.Skip("PKCS11_MODULE is not set")
+	}
+
+	backend, err := NewPKCS11Backend(module, os.Getenv("PKCS11_TOKEN_LABEL"), os.Getenv("PKCS11_PIN"))
+	assert.NoError(t, err)
+
+	NewRegistry(map[string]Backend{constants.PolicySignerBackendPKCS11: backend})
+	defer NewRegistry(nil)
+
+	for _, scheme := range utils.SigningSchemes() {
+		t.Run(scheme, func(t *testing.T) {
+			policy := &entities.Policy{Scheme: scheme, SignerBackend: constants.PolicySignerBackendPKCS11}
+			err := GetInstance().NewPolicyKey(context.Background(), policy)
+			assert.NoError(t, err)
+
+			// Only the label of the key is stored on the policy
+			assert.Empty(t, policy.PrivateKey)
+			assert.NotEmpty(t, policy.SignerKeyID)
+
+			assertSigner(t, policy)
+		})
+	}
+}
+
+func TestECDSAASN1Signature(t *testing.T) {
+	_, err := ecdsaASN1Signature([]byte{1, 2, 3})
+	assert.ErrorIs(t, err, ErrSignatureIsInvalid)
+
+	signature, err := ecdsaASN1Signature(append(make([]byte, 31), 1, 0, 2))
+	assert.NoError(t, err)
+	assert.NotEmpty(t, signature)
+}
@@ -0,0 +1,163 @@
+package signer
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go-license-management/internal/infrastructure/database/entities"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+var ErrRemoteSignerFailed = errors.New("remote signer request failed")
+
+const remoteSignerResponseLimit = 1 << 20
+
+// RemoteBackend delegates key generation and signing to a signing service over HTTP, e.g. a KMS gateway:
+//
+//	POST {{url}}/keys                  {"scheme": "..."}                    -> {"key_id": "...", "public_key": "..."}
+//	POST {{url}}/keys/{{key_id}}/sign  {"scheme": "...", "message": "..."}  -> {"signature": "..."}
+//
+// The public key uses the same encoding as the keys generated in the database, the message and the signature
+// are base64 encoded. Requests carry the configured token as a bearer token.
+type RemoteBackend struct {
+	url    string
+	token  string
+	client *http.Client
+}
+
+func NewRemoteBackend(baseURL string, token string, timeout time.Duration) *RemoteBackend {
+	return &RemoteBackend{
+		url:    strings.TrimSuffix(baseURL, "/"),
+		token:  token,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+type remoteKeyRequest struct {
+	Scheme string `json:"scheme"`
+}
+
+type remoteKeyResponse struct {
+	KeyID     string `json:"key_id"`
+	PublicKey string `json:"public_key"`
+}
+
+type remoteSignRequest struct {
+	Scheme  string `json:"scheme"`
+	Message string `json:"message"`
+}
+
+type remoteSignResponse struct {
+	Signature string `json:"signature"`
+}
+
+func (b *RemoteBackend) NewKey(ctx context.Context, policy *entities.Policy) error {
+	key := &remoteKeyResponse{}
+	err := b.post(ctx, "/keys", &remoteKeyRequest{Scheme: policy.Scheme}, key)
+	if err != nil {
+		return err
+	}
+
+	if key.KeyID == "" || key.PublicKey == "" {
+		return fmt.Errorf("%w: key_id and public_key are required", ErrRemoteSignerFailed)
+	}
+
+	policy.PrivateKey = ""
+	policy.PublicKey = key.PublicKey
+	policy.SignerKeyID = key.KeyID
+	return nil
+}
+
+func (b *RemoteBackend) Signer(ctx context.Context, policy *entities.Policy) (Signer, error) {
+	if policy.SignerKeyID == "" {
+		return nil, ErrSignerKeyIsMissing
+	}
+
+	return &remoteSigner{
+		backend:   b,
+		keyID:     policy.SignerKeyID,
+		scheme:    policy.Scheme,
+		publicKey: policy.PublicKey,
+	}, nil
+}
+
+func (b *RemoteBackend) post(ctx context.Context, path string, body any, out any) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, b.url+path, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if b.token != "" {
+		req.Header.Set("Authorization", "Bearer "+b.token)
+	}
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrRemoteSignerFailed, err)
+	}
+	defer resp.Body.Close()
+
+	content, err := io.ReadAll(io.LimitReader(resp.Body, remoteSignerResponseLimit))
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrRemoteSignerFailed, err)
+	}
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("%w: status [%d]", ErrRemoteSignerFailed, resp.StatusCode)
+	}
+
+	err = json.Unmarshal(content, out)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrRemoteSignerFailed, err)
+	}
+	return nil
+}
+
+type remoteSigner struct {
+	backend   *RemoteBackend
+	keyID     string
+	scheme    string
+	publicKey string
+}
+
+func (s *remoteSigner) Scheme() string {
+	return s.scheme
+}
+
+func (s *remoteSigner) PublicKey() string {
+	return s.publicKey
+}
+
+func (s *remoteSigner) Sign(ctx context.Context, message []byte) ([]byte, error) {
+	resp := &remoteSignResponse{}
+	err := s.backend.post(ctx, "/keys/"+url.PathEscape(s.keyID)+"/sign", &remoteSignRequest{
+		Scheme:  s.scheme,
+		Message: base64.StdEncoding.EncodeToString(message),
+	}, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(resp.Signature)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrSignatureIsInvalid, err)
+	}
+
+	err = verifySignature(s.scheme, s.publicKey, message, signature)
+	if err != nil {
+		return nil, err
+	}
+	return signature, nil
+}
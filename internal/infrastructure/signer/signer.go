@@ -0,0 +1,144 @@
+package signer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go-license-management/internal/constants"
+	"go-license-management/internal/infrastructure/database/entities"
+	"go-license-management/internal/utils"
+)
+
+var (
+	ErrBackendIsUnavailable = errors.New("signer backend is not configured")
+	ErrBackendIsNotCompiled = errors.New("signer backend is not compiled in this build")
+	ErrSignerKeyIsMissing   = errors.New("signer key reference is missing")
+	ErrSignatureIsInvalid   = errors.New("signature returned by the signer backend is invalid")
+)
+
+// Signer signs messages with the current key pair of a policy. The private key never leaves the signer:
+// depending on the backend it is held in memory, in a PKCS#11 token, or by a remote signing service.
+type Signer interface {
+	// Scheme returns the signing scheme of the key pair
+	Scheme() string
+	// PublicKey returns the verify key (public key) of the key pair
+	PublicKey() string
+	// Sign returns the raw signature of the message, as expected by the verifier of the scheme
+	Sign(ctx context.Context, message []byte) ([]byte, error)
+}
+
+// Backend generates and holds the private keys of the policies assigned to it.
+type Backend interface {
+	// NewKey generates a key pair with the scheme of the policy, then sets the public key and the reference
+	// to the private key on the policy
+	NewKey(ctx context.Context, policy *entities.Policy) error
+	// Signer returns the signer of the current key pair of the policy
+	Signer(ctx context.Context, policy *entities.Policy) (Signer, error)
+}
+
+// Registry holds the signer backends, indexed by the name stored on the policies and the tenants.
+// The database backend is always registered.
+type Registry struct {
+	backends map[string]Backend
+}
+
+var registryInstance = newRegistry(nil)
+
+func GetInstance() *Registry {
+	return registryInstance
+}
+
+// NewRegistry creates the registry with the database backend and the external backends which are configured.
+func NewRegistry(backends map[string]Backend) *Registry {
+	registryInstance = newRegistry(backends)
+	return registryInstance
+}
+
+func newRegistry(backends map[string]Backend) *Registry {
+	registry := &Registry{
+		backends: map[string]Backend{
+			constants.PolicySignerBackendDatabase: NewDatabaseBackend(),
+		},
+	}
+	for name, backend := range backends {
+		registry.backends[name] = backend
+	}
+	return registry
+}
+
+// Backend returns the backend registered under the name. An empty name selects the database backend.
+func (r *Registry) Backend(name string) (Backend, error) {
+	if name == "" {
+		name = constants.PolicySignerBackendDatabase
+	}
+
+	backend, ok := r.backends[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: [%s]", ErrBackendIsUnavailable, name)
+	}
+	return backend, nil
+}
+
+// NewPolicyKey generates a new key pair for the policy on its signer backend.
+func (r *Registry) NewPolicyKey(ctx context.Context, policy *entities.Policy) error {
+	backend, err := r.Backend(policy.SignerBackend)
+	if err != nil {
+		return err
+	}
+	return backend.NewKey(ctx, policy)
+}
+
+// PolicySigner returns the signer of the current key pair of the policy.
+func (r *Registry) PolicySigner(ctx context.Context, policy *entities.Policy) (Signer, error) {
+	backend, err := r.Backend(policy.SignerBackend)
+	if err != nil {
+		return nil, err
+	}
+	return backend.Signer(ctx, policy)
+}
+
+// NewLicenseKey signs the data with the signer.
+// Returns a license string in format {{signature}}.{{data}}
+func NewLicenseKey(ctx context.Context, signer Signer, data any) (string, error) {
+	bData, err := json.Marshal(data)
+	if err != nil {
+		return "", err
+	}
+
+	signature, err := signer.Sign(ctx, bData)
+	if err != nil {
+		return "", err
+	}
+
+	return utils.EncodeLicenseKey(signature, bData), nil
+}
+
+// NewSignedKey signs the payload with the signer into a compact key that can be verified offline.
+// Returns a key string in format {{scheme}}/{{payload}}.{{signature}}
+func NewSignedKey(ctx context.Context, signer Signer, payload any) (string, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+
+	signature, err := signer.Sign(ctx, data)
+	if err != nil {
+		return "", err
+	}
+
+	return utils.EncodeSignedKey(signer.Scheme(), signature, data), nil
+}
+
+// verifySignature checks a signature produced outside the process against the public key of the policy,
+// so a misbehaving backend cannot issue certificates which fail offline verification.
+func verifySignature(scheme string, publicKey string, message []byte, signature []byte) error {
+	valid, _, err := utils.VerifyLicenseKey(scheme, publicKey, utils.EncodeLicenseKey(signature, message))
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrSignatureIsInvalid, err)
+	}
+	if !valid {
+		return ErrSignatureIsInvalid
+	}
+	return nil
+}
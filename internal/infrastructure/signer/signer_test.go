@@ -0,0 +1,212 @@
+package signer
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go-license-management/internal/constants"
+	"go-license-management/internal/infrastructure/database/entities"
+	"go-license-management/internal/infrastructure/envelope"
+	"go-license-management/internal/utils"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type testPayload struct {
+	ID      string `json:"id"`
+	Product string `json:"product"`
+}
+
+var payload = testPayload{ID: "b8c4d7f2-3c1a-4f0e-9a62-2f7d8e1c5b90", Product: "go-license-management"}
+
+// assertSigner signs a license key and a signed key with the signer, and verifies them with the public key of the policy.
+func assertSigner(t *testing.T, policy *entities.Policy) {
+	s, err := GetInstance().PolicySigner(context.Background(), policy)
+	assert.NoError(t, err)
+	assert.Equal(t, policy.Scheme, s.Scheme())
+	assert.Equal(t, policy.PublicKey, s.PublicKey())
+
+	licenseKey, err := NewLicenseKey(context.Background(), s, payload)
+	assert.NoError(t, err)
+
+	valid, data, err := utils.VerifyLicenseKey(policy.Scheme, policy.PublicKey, licenseKey)
+	assert.NoError(t, err)
+	assert.True(t, valid)
+
+	decoded := testPayload{}
+	assert.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, payload, decoded)
+
+	signedKey, err := NewSignedKey(context.Background(), s, payload)
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(signedKey, policy.Scheme+"/"))
+
+	scheme, _, err := utils.VerifySignedKey(policy.PublicKey, signedKey)
+	assert.NoError(t, err)
+	assert.Equal(t, policy.Scheme, scheme)
+}
+
+func TestDatabaseBackend_Sign(t *testing.T) {
+	kek, err := envelope.NewKEK()
+	assert.NoError(t, err)
+	_, err = envelope.NewEnvelope(kek)
+	assert.NoError(t, err)
+	defer envelope.NewEnvelope("")
+
+	NewRegistry(nil)
+	for _, scheme := range utils.SigningSchemes() {
+		t.Run(scheme, func(t *testing.T) {
+			policy := &entities.Policy{Scheme: scheme}
+			err := GetInstance().NewPolicyKey(context.Background(), policy)
+			assert.NoError(t, err)
+
+			// The private key is sealed as soon as it is generated
+			assert.True(t, envelope.IsSealed(policy.PrivateKey))
+			assert.NotEmpty(t, policy.PublicKey)
+			assert.Empty(t, policy.SignerKeyID)
+
+			assertSigner(t, policy)
+		})
+	}
+}
+
+func TestRegistry_Backend(t *testing.T) {
+	registry := NewRegistry(nil)
+
+	backend, err := registry.Backend("")
+	assert.NoError(t, err)
+	assert.IsType(t, &DatabaseBackend{}, backend)
+
+	_, err = registry.Backend(constants.PolicySignerBackendRemote)
+	assert.ErrorIs(t, err, ErrBackendIsUnavailable)
+
+	err = registry.NewPolicyKey(context.Background(), &entities.Policy{Scheme: utils.SigningSchemeED25519, SignerBackend: constants.PolicySignerBackendRemote})
+	assert.ErrorIs(t, err, ErrBackendIsUnavailable)
+}
+
+// remoteSignerStub is an in-memory signing service implementing the remote signer protocol.
+type remoteSignerStub struct {
+	mu      sync.Mutex
+	token   string
+	tamper  bool
+	keys    map[string]string
+	schemes map[string]string
+}
+
+func newRemoteSignerStub(token string) *remoteSignerStub {
+	return &remoteSignerStub{token: token, keys: map[string]string{}, schemes: map[string]string{}}
+}
+
+func (s *remoteSignerStub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Authorization") != "Bearer "+s.token {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch {
+	case r.Method == http.MethodPost && r.URL.Path == "/keys":
+		req := &remoteKeyRequest{}
+		_ = json.NewDecoder(r.Body).Decode(req)
+
+		privateKey, publicKey, err := utils.NewKeyPair(req.Scheme)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		keyID := uuid.NewString()
+		s.keys[keyID] = privateKey
+		s.schemes[keyID] = req.Scheme
+		_ = json.NewEncoder(w).Encode(&remoteKeyResponse{KeyID: keyID, PublicKey: publicKey})
+	case r.Method == http.MethodPost && strings.HasPrefix(r.URL.Path, "/keys/") && strings.HasSuffix(r.URL.Path, "/sign"):
+		keyID := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/keys/"), "/sign")
+		privateKey, ok := s.keys[keyID]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		req := &remoteSignRequest{}
+		_ = json.NewDecoder(r.Body).Decode(req)
+		message, _ := base64.StdEncoding.DecodeString(req.Message)
+		if s.tamper {
+			message = append(message, ' ')
+		}
+
+		signature, err := utils.SignMessage(s.schemes[keyID], privateKey, message)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(&remoteSignResponse{Signature: base64.StdEncoding.EncodeToString(signature)})
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestRemoteBackend_Sign(t *testing.T) {
+	stub := newRemoteSignerStub("remote-token")
+	server := httptest.NewServer(stub)
+	defer server.Close()
+
+	NewRegistry(map[string]Backend{
+		constants.PolicySignerBackendRemote: NewRemoteBackend(server.URL+"/", "remote-token", 5*time.Second),
+	})
+	defer NewRegistry(nil)
+
+	for _, scheme := range utils.SigningSchemes() {
+		t.Run(scheme, func(t *testing.T) {
+			policy := &entities.Policy{Scheme: scheme, SignerBackend: constants.PolicySignerBackendRemote}
+			err := GetInstance().NewPolicyKey(context.Background(), policy)
+			assert.NoError(t, err)
+
+			// Only a reference to the private key is stored on the policy
+			assert.Empty(t, policy.PrivateKey)
+			assert.NotEmpty(t, policy.SignerKeyID)
+			assert.NotEmpty(t, policy.PublicKey)
+
+			assertSigner(t, policy)
+		})
+	}
+}
+
+func TestRemoteBackend_Errors(t *testing.T) {
+	stub := newRemoteSignerStub("remote-token")
+	server := httptest.NewServer(stub)
+	defer server.Close()
+
+	backend := NewRemoteBackend(server.URL, "remote-token", 5*time.Second)
+	policy := &entities.Policy{Scheme: utils.SigningSchemeED25519, SignerBackend: constants.PolicySignerBackendRemote}
+	err := backend.NewKey(context.Background(), policy)
+	assert.NoError(t, err)
+
+	// Signatures which do not match the public key of the policy are rejected
+	stub.mu.Lock()
+	stub.tamper = true
+	stub.mu.Unlock()
+	s, err := backend.Signer(context.Background(), policy)
+	assert.NoError(t, err)
+	_, err = NewLicenseKey(context.Background(), s, payload)
+	assert.ErrorIs(t, err, ErrSignatureIsInvalid)
+
+	// Unknown keys and authentication failures are reported
+	s, err = backend.Signer(context.Background(), &entities.Policy{Scheme: utils.SigningSchemeED25519, PublicKey: policy.PublicKey, SignerKeyID: "unknown"})
+	assert.NoError(t, err)
+	_, err = s.Sign(context.Background(), []byte("message"))
+	assert.ErrorIs(t, err, ErrRemoteSignerFailed)
+
+	err = NewRemoteBackend(server.URL, "invalid-token", 5*time.Second).NewKey(context.Background(), policy)
+	assert.ErrorIs(t, err, ErrRemoteSignerFailed)
+
+	_, err = backend.Signer(context.Background(), &entities.Policy{Scheme: utils.SigningSchemeED25519})
+	assert.ErrorIs(t, err, ErrSignerKeyIsMissing)
+}
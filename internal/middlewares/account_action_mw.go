@@ -70,6 +70,10 @@ func AccountActionPermissionValidationMW() gin.HandlerFunc {
 			permission = permissions.UserBan
 		case constants.AccountActionUnban:
 			permission = permissions.UserUnban
+		case constants.AccountActionRevokeSessions:
+			permission = permissions.UserSessionsRevoke
+		case constants.AccountActionUnlock:
+			permission = permissions.UserUnlock
 		case constants.AccountActionUpdatePassword:
 			permission = permissions.UserPasswordUpdate
 		case constants.AccountActionResetPassword, constants.AccountActionGenerateResetToken:
@@ -88,6 +92,13 @@ func AccountActionPermissionValidationMW() gin.HandlerFunc {
 			return
 
 		}
+		if constants.ValidTokenRoleMapper[ctx.GetString(constants.ContextValueRole)] {
+			if validateTokenPermission(ctx, permission) {
+				ctx.Next()
+			}
+			return
+		}
+
 		permObjects := strings.Split(permission, ".")
 
 		ok, err := e.Enforce(
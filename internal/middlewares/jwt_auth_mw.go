@@ -13,6 +13,7 @@ import (
 	"go-license-management/internal/infrastructure/database/postgres"
 	"go-license-management/internal/infrastructure/logging"
 	"go-license-management/internal/response"
+	"go-license-management/internal/utils"
 	"net/http"
 	"strings"
 	"time"
@@ -23,6 +24,19 @@ func JWTValidationMW() gin.HandlerFunc {
 		logging.GetInstance().GetLogger().Info("validating jwt token")
 
 		authHeader := ctx.GetHeader(constants.AuthorizationHeader)
+		// Requests without a bearer token may be authenticated with a product or a license token
+		if apiKey := ctx.GetHeader(constants.XAPIKeyHeader); authHeader == "" && apiKey != "" {
+			validated := false
+			if strings.HasPrefix(apiKey, utils.LicenseTokenPrefix) {
+				validated = licenseTokenValidation(ctx, apiKey)
+			} else {
+				validated = productTokenValidation(ctx, apiKey)
+			}
+			if validated {
+				ctx.Next()
+			}
+			return
+		}
 		if authHeader == "" {
 			ctx.AbortWithStatusJSON(
 				http.StatusUnauthorized,
@@ -88,7 +102,13 @@ func JWTValidationMW() gin.HandlerFunc {
 				return
 			}
 
-			publicKey, err := base64.StdEncoding.DecodeString(tenant.Ed25519PublicKey)
+			// Tokens signed before the keys of the tenant were regenerated are verified with its key ring
+			verifyKey, err := tenantVerifyKey(ctx, tenant, authHdrPart[1])
+			if err != nil {
+				logging.GetInstance().GetLogger().Error(err.Error())
+			}
+
+			publicKey, err := base64.StdEncoding.DecodeString(verifyKey)
 			if err != nil {
 				logging.GetInstance().GetLogger().Error(err.Error())
 				ctx.AbortWithStatusJSON(
@@ -249,6 +269,10 @@ func JWTValidationMW() gin.HandlerFunc {
 				return
 			}
 			ctx.Set(constants.ContextValueAudience, audience)
+			// The audience of the token is the role of the subject
+			if len(audience) > 0 {
+				ctx.Set(constants.ContextValueRole, audience[0])
+			}
 
 			subject, err := parsedToken.Claims.GetSubject()
 			if err != nil {
@@ -346,6 +370,72 @@ func JWTValidationMW() gin.HandlerFunc {
 				return
 			}
 
+			jti, ok := parsedToken.Claims.(jwt.MapClaims)["jti"].(string)
+			if !ok || jti == "" {
+				logging.GetInstance().GetLogger().Error("missing [jti] claims")
+				ctx.AbortWithStatusJSON(
+					http.StatusUnauthorized,
+					response.NewResponse(ctx).ToResponse(
+						cerrors.ErrCodeMapper[cerrors.ErrGenericUnauthorized],
+						"missing [jti] claims",
+						nil,
+						nil,
+						nil,
+					),
+				)
+				return
+			}
+
+			issuedAt, err := parsedToken.Claims.GetIssuedAt()
+			if err != nil || issuedAt == nil {
+				logging.GetInstance().GetLogger().Error("invalid [iat] claims")
+				ctx.AbortWithStatusJSON(
+					http.StatusUnauthorized,
+					response.NewResponse(ctx).ToResponse(
+						cerrors.ErrCodeMapper[cerrors.ErrGenericUnauthorized],
+						"invalid [iat] claims",
+						nil,
+						nil,
+						nil,
+					),
+				)
+				return
+			}
+
+			tokenTenant, _ := tenantCtx.(string)
+			revoked, err := tokenRevoked(ctx, jti, tokenTenant, subject, issuedAt.Time)
+			if err != nil {
+				logging.GetInstance().GetLogger().Error(err.Error())
+				ctx.AbortWithStatusJSON(
+					http.StatusInternalServerError,
+					response.NewResponse(ctx).ToResponse(
+						cerrors.ErrCodeMapper[cerrors.ErrGenericInternalServer],
+						cerrors.ErrMessageMapper[cerrors.ErrGenericInternalServer],
+						nil,
+						nil,
+						nil,
+					),
+				)
+				return
+			}
+
+			if revoked {
+				logging.GetInstance().GetLogger().Error(fmt.Sprintf("token [%s] of [%s] has been revoked", jti, subject))
+				ctx.AbortWithStatusJSON(
+					http.StatusUnauthorized,
+					response.NewResponse(ctx).ToResponse(
+						cerrors.ErrCodeMapper[cerrors.ErrGenericUnauthorized],
+						"token has been revoked",
+						nil,
+						nil,
+						nil,
+					),
+				)
+				return
+			}
+			ctx.Set(constants.ContextValueTokenID, jti)
+			ctx.Set(constants.ContextValueTokenExpiry, exp.Time)
+
 		default:
 			ctx.AbortWithStatusJSON(http.StatusUnauthorized, map[string]interface{}{})
 			return
@@ -354,3 +444,37 @@ func JWTValidationMW() gin.HandlerFunc {
 		ctx.Next()
 	}
 }
+
+// tenantVerifyKey returns the public key verifying the token, according to its key id. Tokens without a key id or
+// with the key id of the current key are verified with the current key of the tenant.
+func tenantVerifyKey(ctx *gin.Context, tenant *entities.Tenant, tokenString string) (string, error) {
+	token, _, err := jwt.NewParser().ParseUnverified(tokenString, jwt.MapClaims{})
+	if err != nil {
+		return tenant.Ed25519PublicKey, err
+	}
+
+	kid, _ := token.Header["kid"].(string)
+	if kid == "" || kid == utils.KeyID(tenant.Ed25519PublicKey) {
+		return tenant.Ed25519PublicKey, nil
+	}
+
+	tenantKey := &entities.TenantKey{}
+	err = postgres.GetInstance().NewSelect().Model(tenantKey).
+		Where("tenant_name = ?", tenant.Name).
+		Where("kid = ?", kid).
+		Where("expires_at > ?", time.Now()).
+		Scan(ctx)
+	if err != nil {
+		return tenant.Ed25519PublicKey, fmt.Errorf("key [%s] of tenant [%s] is not in its key ring: %w", kid, tenant.Name, err)
+	}
+	return tenantKey.Ed25519PublicKey, nil
+}
+
+// tokenRevoked reports whether the access token has been revoked, either by its own ID or along with every access
+// token of its account issued up to the revocation. Both the issue time and the revocations of the account are at
+// second precision, so a token issued in the same second as the revocation is revoked too.
+func tokenRevoked(ctx *gin.Context, jti, tenantName, username string, issuedAt time.Time) (bool, error) {
+	return postgres.GetInstance().NewSelect().Model((*entities.RevokedToken)(nil)).
+		Where("jti = ? OR (jti IS NULL AND tenant_name = ? AND username = ? AND created_at >= ?)", jti, tenantName, username, issuedAt).
+		Exists(ctx)
+}
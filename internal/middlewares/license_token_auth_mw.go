@@ -0,0 +1,158 @@
+package middlewares
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go-license-management/internal/cerrors"
+	"go-license-management/internal/constants"
+	"go-license-management/internal/infrastructure/database/entities"
+	"go-license-management/internal/infrastructure/database/postgres"
+	"go-license-management/internal/infrastructure/logging"
+	"go-license-management/internal/response"
+	"go-license-management/internal/utils"
+	"net/http"
+	"time"
+)
+
+// licenseTokenValidation authenticates the request with the license token of the X-API-Key header.
+// The subject of the request is the token, with the license role. Requests targeting another license, or the
+// machines of another license, are rejected.
+// Returns false when the request has been aborted
+func licenseTokenValidation(ctx *gin.Context, apiKey string) bool {
+	tenantName := ctx.Param("tenant_name")
+	if tenantName == "" {
+		ctx.AbortWithStatusJSON(
+			http.StatusUnauthorized,
+			response.NewResponse(ctx).ToResponse(
+				cerrors.ErrCodeMapper[cerrors.ErrGenericUnauthorized],
+				"invalid request url",
+				nil,
+				nil,
+				nil,
+			),
+		)
+		return false
+	}
+
+	// Only the hash of the token is stored
+	licenseToken := &entities.LicenseToken{}
+	err := postgres.GetInstance().NewSelect().Model(licenseToken).
+		Where("token_hash = ?", utils.HashToken(apiKey)).
+		Where("tenant_name = ?", tenantName).
+		Scan(ctx)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			logging.GetInstance().GetLogger().Error("invalid license token")
+			ctx.AbortWithStatusJSON(
+				http.StatusUnauthorized,
+				response.NewResponse(ctx).ToResponse(
+					cerrors.ErrCodeMapper[cerrors.ErrGenericUnauthorized],
+					cerrors.ErrMessageMapper[cerrors.ErrGenericUnauthorized],
+					nil,
+					nil,
+					nil,
+				),
+			)
+			return false
+		}
+		logging.GetInstance().GetLogger().Error(err.Error())
+		ctx.AbortWithStatusJSON(
+			http.StatusInternalServerError,
+			response.NewResponse(ctx).ToResponse(
+				cerrors.ErrCodeMapper[cerrors.ErrGenericInternalServer],
+				cerrors.ErrMessageMapper[cerrors.ErrGenericInternalServer],
+				nil,
+				nil,
+				nil,
+			),
+		)
+		return false
+	}
+
+	if !licenseToken.Expiry.IsZero() && licenseToken.Expiry.Before(time.Now()) {
+		logging.GetInstance().GetLogger().Error(fmt.Sprintf("license token [%s] has expired", licenseToken.ID))
+		ctx.AbortWithStatusJSON(
+			http.StatusUnauthorized,
+			response.NewResponse(ctx).ToResponse(
+				cerrors.ErrCodeMapper[cerrors.ErrGenericUnauthorized],
+				"token has expired",
+				nil,
+				nil,
+				nil,
+			),
+		)
+		return false
+	}
+
+	ctx.Set(constants.ContextValueTenant, licenseToken.TenantName)
+	ctx.Set(constants.ContextValueSubject, licenseToken.ID.String())
+	ctx.Set(constants.ContextValueRole, constants.RoleLicense)
+	ctx.Set(constants.ContextValueLicense, licenseToken.LicenseID.String())
+
+	licenseID, err := resourceLicenseID(ctx, tenantName)
+	if err != nil {
+		logging.GetInstance().GetLogger().Error(err.Error())
+		ctx.AbortWithStatusJSON(
+			http.StatusInternalServerError,
+			response.NewResponse(ctx).ToResponse(
+				cerrors.ErrCodeMapper[cerrors.ErrGenericInternalServer],
+				cerrors.ErrMessageMapper[cerrors.ErrGenericInternalServer],
+				nil,
+				nil,
+				nil,
+			),
+		)
+		return false
+	}
+
+	if licenseID != uuid.Nil && licenseID != licenseToken.LicenseID {
+		logging.GetInstance().GetLogger().Info(
+			fmt.Sprintf("license token [%s] of license [%s] cannot access resources of license [%s]",
+				licenseToken.ID, licenseToken.LicenseID, licenseID),
+		)
+		ctx.AbortWithStatusJSON(
+			http.StatusForbidden,
+			response.NewResponse(ctx).ToResponse(
+				cerrors.ErrCodeMapper[cerrors.ErrLicenseTokenScopeIsInvalid],
+				cerrors.ErrMessageMapper[cerrors.ErrLicenseTokenScopeIsInvalid],
+				nil,
+				nil,
+				nil,
+			),
+		)
+		return false
+	}
+
+	return true
+}
+
+// resourceLicenseID returns the license of the resource targeted by the route, i.e. the license or the machine of
+// the url. Returns uuid.Nil when the route targets no resource or the resource does not exist, which is reported
+// by the handler.
+func resourceLicenseID(ctx *gin.Context, tenantName string) (uuid.UUID, error) {
+	if licenseID, err := uuid.Parse(ctx.Param("license_id")); err == nil {
+		return licenseID, nil
+	}
+
+	machineID, err := uuid.Parse(ctx.Param("machine_id"))
+	if err != nil {
+		return uuid.Nil, nil
+	}
+
+	var licenseID uuid.UUID
+	err = postgres.GetInstance().NewSelect().Model((*entities.Machine)(nil)).
+		Column("license_id").
+		Where("id = ?", machineID).
+		Where("tenant_name = ?", tenantName).
+		Scan(ctx, &licenseID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return uuid.Nil, nil
+		}
+		return uuid.Nil, err
+	}
+	return licenseID, nil
+}
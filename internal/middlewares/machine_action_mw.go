@@ -86,6 +86,13 @@ func MachineActionPermissionValidationMW() gin.HandlerFunc {
 			return
 
 		}
+		if constants.ValidTokenRoleMapper[ctx.GetString(constants.ContextValueRole)] {
+			if validateTokenPermission(ctx, permission) {
+				ctx.Next()
+			}
+			return
+		}
+
 		permObjects := strings.Split(permission, ".")
 
 		ok, err := e.Enforce(
@@ -176,6 +176,10 @@ func JWTMasterValidationMW() gin.HandlerFunc {
 				return
 			}
 			ctx.Set(constants.ContextValueAudience, audience)
+			// The audience of the token is the role of the subject
+			if len(audience) > 0 {
+				ctx.Set(constants.ContextValueRole, audience[0])
+			}
 
 			subject, err := parsedToken.Claims.GetSubject()
 			if err != nil {
@@ -0,0 +1,168 @@
+package middlewares
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go-license-management/internal/cerrors"
+	"go-license-management/internal/constants"
+	"go-license-management/internal/infrastructure/database/entities"
+	"go-license-management/internal/infrastructure/database/postgres"
+	"go-license-management/internal/infrastructure/logging"
+	"go-license-management/internal/response"
+	"go-license-management/internal/utils"
+	"net/http"
+	"time"
+)
+
+// productTokenValidation authenticates the request with the product token of the X-API-Key header.
+// The subject of the request is the token, with the product role and the permissions granted to the token.
+// Requests targeting the resources of another product are rejected.
+// Returns false when the request has been aborted
+func productTokenValidation(ctx *gin.Context, apiKey string) bool {
+	tenantName := ctx.Param("tenant_name")
+	if tenantName == "" {
+		ctx.AbortWithStatusJSON(
+			http.StatusUnauthorized,
+			response.NewResponse(ctx).ToResponse(
+				cerrors.ErrCodeMapper[cerrors.ErrGenericUnauthorized],
+				"invalid request url",
+				nil,
+				nil,
+				nil,
+			),
+		)
+		return false
+	}
+
+	// Only the hash of the token is stored
+	productToken := &entities.ProductToken{}
+	err := postgres.GetInstance().NewSelect().Model(productToken).
+		Where("token_hash = ?", utils.HashToken(apiKey)).
+		Where("tenant_name = ?", tenantName).
+		Scan(ctx)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			logging.GetInstance().GetLogger().Error("invalid product token")
+			ctx.AbortWithStatusJSON(
+				http.StatusUnauthorized,
+				response.NewResponse(ctx).ToResponse(
+					cerrors.ErrCodeMapper[cerrors.ErrGenericUnauthorized],
+					cerrors.ErrMessageMapper[cerrors.ErrGenericUnauthorized],
+					nil,
+					nil,
+					nil,
+				),
+			)
+			return false
+		}
+		logging.GetInstance().GetLogger().Error(err.Error())
+		ctx.AbortWithStatusJSON(
+			http.StatusInternalServerError,
+			response.NewResponse(ctx).ToResponse(
+				cerrors.ErrCodeMapper[cerrors.ErrGenericInternalServer],
+				cerrors.ErrMessageMapper[cerrors.ErrGenericInternalServer],
+				nil,
+				nil,
+				nil,
+			),
+		)
+		return false
+	}
+
+	if !productToken.Expiry.IsZero() && productToken.Expiry.Before(time.Now()) {
+		logging.GetInstance().GetLogger().Error(fmt.Sprintf("product token [%s] has expired", productToken.ID))
+		ctx.AbortWithStatusJSON(
+			http.StatusUnauthorized,
+			response.NewResponse(ctx).ToResponse(
+				cerrors.ErrCodeMapper[cerrors.ErrGenericUnauthorized],
+				"token has expired",
+				nil,
+				nil,
+				nil,
+			),
+		)
+		return false
+	}
+
+	ctx.Set(constants.ContextValueTenant, productToken.TenantName)
+	ctx.Set(constants.ContextValueSubject, productToken.ID.String())
+	ctx.Set(constants.ContextValueRole, constants.RoleProduct)
+	ctx.Set(constants.ContextValueProduct, productToken.ProductID.String())
+	ctx.Set(constants.ContextValuePermissions, productToken.Permissions)
+	if !productToken.Expiry.IsZero() {
+		ctx.Set(constants.ContextValueTokenExpiry, productToken.Expiry)
+	}
+
+	productID, err := resourceProductID(ctx, tenantName)
+	if err != nil {
+		logging.GetInstance().GetLogger().Error(err.Error())
+		ctx.AbortWithStatusJSON(
+			http.StatusInternalServerError,
+			response.NewResponse(ctx).ToResponse(
+				cerrors.ErrCodeMapper[cerrors.ErrGenericInternalServer],
+				cerrors.ErrMessageMapper[cerrors.ErrGenericInternalServer],
+				nil,
+				nil,
+				nil,
+			),
+		)
+		return false
+	}
+
+	if productID != uuid.Nil && productID != productToken.ProductID {
+		logging.GetInstance().GetLogger().Info(
+			fmt.Sprintf("product token [%s] of product [%s] cannot access resources of product [%s]",
+				productToken.ID, productToken.ProductID, productID),
+		)
+		ctx.AbortWithStatusJSON(
+			http.StatusForbidden,
+			response.NewResponse(ctx).ToResponse(
+				cerrors.ErrCodeMapper[cerrors.ErrProductTokenScopeIsInvalid],
+				cerrors.ErrMessageMapper[cerrors.ErrProductTokenScopeIsInvalid],
+				nil,
+				nil,
+				nil,
+			),
+		)
+		return false
+	}
+
+	return true
+}
+
+// resourceProductID returns the product of the resource targeted by the route, i.e. the product, the policy or the
+// license of the url. Returns uuid.Nil when the route targets no resource or the resource does not exist, which
+// is reported by the handler.
+func resourceProductID(ctx *gin.Context, tenantName string) (uuid.UUID, error) {
+	if productID, err := uuid.Parse(ctx.Param("product_id")); err == nil {
+		return productID, nil
+	}
+
+	var model interface{}
+	var id uuid.UUID
+	var err error
+	if id, err = uuid.Parse(ctx.Param("policy_id")); err == nil {
+		model = (*entities.Policy)(nil)
+	} else if id, err = uuid.Parse(ctx.Param("license_id")); err == nil {
+		model = (*entities.License)(nil)
+	} else {
+		return uuid.Nil, nil
+	}
+
+	var productID uuid.UUID
+	err = postgres.GetInstance().NewSelect().Model(model).
+		Column("product_id").
+		Where("id = ?", id).
+		Where("tenant_name = ?", tenantName).
+		Scan(ctx, &productID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return uuid.Nil, nil
+		}
+		return uuid.Nil, err
+	}
+	return productID, nil
+}
@@ -0,0 +1,157 @@
+package middlewares
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/spf13/viper"
+	"go-license-management/internal/cerrors"
+	"go-license-management/internal/config"
+	"go-license-management/internal/constants"
+	"go-license-management/internal/infrastructure/database/entities"
+	"go-license-management/internal/infrastructure/database/postgres"
+	"go-license-management/internal/infrastructure/logging"
+	"go-license-management/internal/response"
+	"go-license-management/internal/utils"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+var (
+	rateLimiter     *utils.RateLimiter
+	rateLimiterOnce sync.Once
+)
+
+func getRateLimiter() *utils.RateLimiter {
+	rateLimiterOnce.Do(func() {
+		windows := viper.GetStringSlice(config.RateLimitWindows)
+		if len(windows) == 0 {
+			windows = constants.DefaultRateLimitWindows
+		}
+
+		parsed, err := utils.ParseRateLimitWindows(windows)
+		if err != nil {
+			logging.GetInstance().GetLogger().Error(fmt.Sprintf("%s, falling back to the default rate limit windows", err.Error()))
+			parsed, _ = utils.ParseRateLimitWindows(constants.DefaultRateLimitWindows)
+		}
+		rateLimiter = utils.NewRateLimiter(parsed)
+	})
+	return rateLimiter
+}
+
+// LicenseRateLimitMW rate limits the license actions of licenses whose policy is rate limited.
+// Requests are keyed by license key and client IP. Bodies larger than 1 MiB are rejected.
+func LicenseRateLimitMW() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		body, err := io.ReadAll(http.MaxBytesReader(ctx.Writer, ctx.Request.Body, constants.MaxRateLimitedRequestBodySize))
+		if err != nil {
+			logging.GetInstance().GetLogger().Error(err.Error())
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				ctx.AbortWithStatusJSON(
+					http.StatusRequestEntityTooLarge,
+					response.NewResponse(ctx).ToResponse(
+						cerrors.ErrCodeMapper[cerrors.ErrGenericRequestTooLarge],
+						cerrors.ErrMessageMapper[cerrors.ErrGenericRequestTooLarge],
+						nil,
+						nil,
+						nil,
+					),
+				)
+				return
+			}
+			ctx.AbortWithStatusJSON(
+				http.StatusBadRequest,
+				response.NewResponse(ctx).ToResponse(
+					cerrors.ErrCodeMapper[cerrors.ErrGenericBadRequest],
+					cerrors.ErrMessageMapper[cerrors.ErrGenericBadRequest],
+					nil,
+					nil,
+					nil,
+				),
+			)
+			return
+		}
+		// Restore the body for the handler
+		ctx.Request.Body = io.NopCloser(bytes.NewBuffer(body))
+
+		// Malformed requests and unknown licenses are reported by the handler
+		var req struct {
+			LicenseKey string `json:"license_key"`
+		}
+		if err = json.Unmarshal(body, &req); err != nil || req.LicenseKey == "" {
+			ctx.Next()
+			return
+		}
+
+		license := &entities.License{}
+		err = postgres.GetInstance().NewSelect().Model(license).Relation("Policy").Where("l.key = ?", req.LicenseKey).Limit(1).Scan(ctx)
+		if err != nil {
+			ctx.Next()
+			return
+		}
+
+		applyRateLimit(ctx, license)
+	}
+}
+
+// MachineRateLimitMW rate limits the machine actions of machines whose license policy is rate limited.
+// Requests are keyed by license key and client IP.
+func MachineRateLimitMW() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		// Malformed requests and unknown machines are reported by the handler
+		machineID, err := uuid.Parse(ctx.Param("machine_id"))
+		if err != nil {
+			ctx.Next()
+			return
+		}
+
+		machine := &entities.Machine{ID: machineID}
+		err = postgres.GetInstance().NewSelect().Model(machine).Relation("License").Relation("License.Policy").WherePK().Scan(ctx)
+		if err != nil || machine.License == nil {
+			ctx.Next()
+			return
+		}
+
+		applyRateLimit(ctx, machine.License)
+	}
+}
+
+func applyRateLimit(ctx *gin.Context, license *entities.License) {
+	if license.Policy == nil || !license.Policy.RateLimited {
+		ctx.Next()
+		return
+	}
+
+	result := getRateLimiter().Allow(fmt.Sprintf("%s:%s", license.Key, ctx.ClientIP()), time.Now())
+	ctx.Header(constants.XRateLimitWindowHeader, result.Window)
+	ctx.Header(constants.XRateLimitCountHeader, strconv.Itoa(result.Count))
+	ctx.Header(constants.XRateLimitLimitHeader, strconv.Itoa(result.Limit))
+	ctx.Header(constants.XRateLimitRemainingHeader, strconv.Itoa(result.Remaining))
+	ctx.Header(constants.XRateLimitResetHeader, strconv.FormatInt(result.Reset.UTC().Unix(), 10))
+
+	if !result.Allowed {
+		logging.GetInstance().GetLogger().Info(fmt.Sprintf("license [%s] exceeded rate limit window [%s]", license.ID, result.Window))
+		ctx.Header(constants.RetryAfterHeader, strconv.Itoa(int(math.Ceil(result.RetryAfter.Seconds()))))
+		ctx.AbortWithStatusJSON(
+			http.StatusTooManyRequests,
+			response.NewResponse(ctx).ToResponse(
+				cerrors.ErrCodeMapper[cerrors.ErrGenericTooManyRequests],
+				cerrors.ErrMessageMapper[cerrors.ErrGenericTooManyRequests],
+				nil,
+				nil,
+				nil,
+			),
+		)
+		return
+	}
+
+	ctx.Next()
+}
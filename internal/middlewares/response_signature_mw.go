@@ -0,0 +1,182 @@
+package middlewares
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"go-license-management/internal/constants"
+	"go-license-management/internal/infrastructure/database/entities"
+	"go-license-management/internal/infrastructure/database/postgres"
+	"go-license-management/internal/infrastructure/envelope"
+	"go-license-management/internal/infrastructure/logging"
+	"go-license-management/internal/utils"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ResponseSignatureMW signs the responses of the tenant routes with the Ed25519 key of the tenant, following
+// HTTP Message Signatures (RFC 9421). The signature covers the status, the request path, the date, the request id
+// and the digest of the content, so clients can detect responses tampered with in transit, and the nonce of the
+// request, so a response cannot be replayed to another request.
+// The response is buffered until the handlers complete, as the digest covers the whole content.
+func ResponseSignatureMW() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		tenantName := ctx.Param("tenant_name")
+		if tenantName == "" {
+			ctx.Next()
+			return
+		}
+
+		writer := &SignatureResponseWriter{
+			ResponseWriter: ctx.Writer,
+			status:         http.StatusOK,
+			body:           &bytes.Buffer{},
+		}
+		ctx.Writer = writer
+
+		ctx.Next()
+
+		ctx.Writer = writer.ResponseWriter
+		signingKey, verifyKey, err := tenantSigningKey(ctx, tenantName)
+		if err == nil {
+			err = writer.sign(ctx, signingKey, verifyKey)
+		}
+		if err != nil {
+			// The response is sent unsigned, clients expecting a signature reject it
+			logging.GetInstance().GetLogger().Error(fmt.Sprintf("failed to sign response: %s", err.Error()))
+		}
+		writer.flush()
+	}
+}
+
+// SignatureResponseWriter buffers the status and the content of the response until it is signed.
+type SignatureResponseWriter struct {
+	gin.ResponseWriter
+	mu      sync.Mutex
+	status  int
+	written bool
+	flushed bool
+	body    *bytes.Buffer
+}
+
+func (w *SignatureResponseWriter) WriteHeader(code int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if code > 0 && !w.written {
+		w.status = code
+	}
+}
+
+func (w *SignatureResponseWriter) WriteHeaderNow() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.written = true
+}
+
+func (w *SignatureResponseWriter) Write(data []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	// Handlers still running once the response is sent, e.g. after a timeout, cannot write anymore
+	if w.flushed {
+		return 0, http.ErrHandlerTimeout
+	}
+	w.written = true
+	return w.body.Write(data)
+}
+
+func (w *SignatureResponseWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+func (w *SignatureResponseWriter) Status() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.status
+}
+
+func (w *SignatureResponseWriter) Size() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if !w.written {
+		return -1
+	}
+	return w.body.Len()
+}
+
+func (w *SignatureResponseWriter) Written() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.written
+}
+
+// tenantSigningKey returns the opened Ed25519 signing key and the verify key of the tenant.
+func tenantSigningKey(ctx *gin.Context, tenantName string) (string, string, error) {
+	tenant := &entities.Tenant{Name: tenantName}
+	err := postgres.GetInstance().NewSelect().Model(tenant).WherePK().Scan(ctx)
+	if err != nil {
+		return "", "", err
+	}
+
+	signingKey, err := envelope.GetInstance().Open(tenant.Ed25519PrivateKey, envelope.TenantKeyAssociatedData(tenant.Name))
+	if err != nil {
+		return "", "", err
+	}
+	return signingKey, tenant.Ed25519PublicKey, nil
+}
+
+// sign sets the Content-Digest, Signature-Input and Signature headers of the buffered response.
+func (w *SignatureResponseWriter) sign(ctx *gin.Context, signingKey string, verifyKey string) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	now := time.Now().UTC()
+	signature := &utils.HTTPResponseSignature{
+		Status:        w.status,
+		Path:          ctx.Request.URL.EscapedPath(),
+		Date:          now.Format(http.TimeFormat),
+		RequestID:     ctx.GetString(constants.RequestIDField),
+		ContentDigest: utils.ContentDigest(w.body.Bytes()),
+		Created:       now.Unix(),
+		KeyID:         utils.KeyID(verifyKey),
+	}
+	// Invalid nonces are not signed, so the client rejects the response
+	nonce := ctx.Request.Header.Get(constants.XSignatureNonceHeader)
+	if utils.IsValidHTTPSignatureNonce(nonce) {
+		signature.Nonce = nonce
+	}
+
+	signatureInput, signatureValue, err := signature.Sign(signingKey)
+	if err != nil {
+		return err
+	}
+
+	header := w.ResponseWriter.Header()
+	header.Set(constants.DateHeader, signature.Date)
+	header.Set(constants.XRequestIDHeader, signature.RequestID)
+	header.Set(constants.ContentDigestHeader, signature.ContentDigest)
+	header.Set(constants.SignatureInputHeader, signatureInput)
+	header.Set(constants.SignatureHeader, signatureValue)
+	return nil
+}
+
+// flush writes the buffered response to the underlying writer.
+func (w *SignatureResponseWriter) flush() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.flushed = true
+	w.ResponseWriter.WriteHeader(w.status)
+	if w.body.Len() > 0 {
+		_, err := w.ResponseWriter.Write(w.body.Bytes())
+		if err != nil {
+			logging.GetInstance().GetLogger().Error(err.Error())
+		}
+	}
+}
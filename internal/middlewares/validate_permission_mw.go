@@ -8,6 +8,7 @@ import (
 	"go-license-management/internal/constants"
 	"go-license-management/internal/infrastructure/casbin_adapter"
 	"go-license-management/internal/infrastructure/logging"
+	"go-license-management/internal/permissions"
 	"go-license-management/internal/response"
 	"net/http"
 	"strings"
@@ -15,6 +16,12 @@ import (
 
 func PermissionValidationMW(permission string) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
+		if constants.ValidTokenRoleMapper[ctx.GetString(constants.ContextValueRole)] {
+			if validateTokenPermission(ctx, permission) {
+				ctx.Next()
+			}
+			return
+		}
 
 		e, err := casbin.NewEnforcer(casbin_adapter.GetEnforcerModel(), casbin_adapter.GetAdapter())
 		if err != nil {
@@ -99,6 +106,78 @@ func PermissionValidationMW(permission string) gin.HandlerFunc {
 				permObjects[1]),
 		)
 
+		// Resources under protected policies are checked by the services, against the permission of the subject
+		protectedObjects := strings.Split(permissions.PolicyProtectedManage, ".")
+		protectedAccess, err := e.Enforce(
+			ctx.GetString(constants.ContextValueTenant),
+			ctx.GetString(constants.ContextValueSubject),
+			protectedObjects[0],
+			protectedObjects[1],
+		)
+		if err != nil {
+			logging.GetInstance().GetLogger().Error(err.Error())
+			ctx.AbortWithStatusJSON(
+				http.StatusInternalServerError,
+				response.NewResponse(ctx).ToResponse(
+					cerrors.ErrCodeMapper[cerrors.ErrGenericInternalServer],
+					cerrors.ErrMessageMapper[cerrors.ErrGenericInternalServer],
+					nil,
+					nil,
+					nil,
+				),
+			)
+			return
+		}
+		ctx.Set(constants.ContextValueProtectedPolicyAccess, protectedAccess)
+
 		ctx.Next()
 	}
 }
+
+// validateTokenPermission checks the permission of the requests authenticated with a product or a license token,
+// whose permissions are not managed with casbin. Product tokens have the permissions granted to them, restricted to
+// the ones of the product role, and license tokens the ones of the license role.
+// Returns false when the request has been aborted
+func validateTokenPermission(ctx *gin.Context, permission string) bool {
+	if !tokenHasPermission(ctx, permission) {
+		logging.GetInstance().GetLogger().Info(
+			fmt.Sprintf("invalid permission: domain [%s] | %s token [%s] | permission [%s]",
+				ctx.GetString(constants.ContextValueTenant),
+				ctx.GetString(constants.ContextValueRole),
+				ctx.GetString(constants.ContextValueSubject),
+				permission),
+		)
+		ctx.AbortWithStatusJSON(
+			http.StatusForbidden,
+			response.NewResponse(ctx).ToResponse(
+				cerrors.ErrCodeMapper[cerrors.ErrGenericPermission],
+				fmt.Sprintf("%s token [%s] does not have permission to perform the requested action", ctx.GetString(constants.ContextValueRole), ctx.GetString(constants.ContextValueSubject)),
+				nil,
+				nil,
+				nil,
+			),
+		)
+		return false
+	}
+	logging.GetInstance().GetLogger().Info(
+		fmt.Sprintf("valid permission: domain [%s] | %s token [%s] | permission [%s]",
+			ctx.GetString(constants.ContextValueTenant),
+			ctx.GetString(constants.ContextValueRole),
+			ctx.GetString(constants.ContextValueSubject),
+			permission),
+	)
+
+	ctx.Set(constants.ContextValueProtectedPolicyAccess, tokenHasPermission(ctx, permissions.PolicyProtectedManage))
+	return true
+}
+
+// tokenHasPermission reports whether the product or license token of the request has the permission.
+func tokenHasPermission(ctx *gin.Context, permission string) bool {
+	switch ctx.GetString(constants.ContextValueRole) {
+	case constants.RoleProduct:
+		return permissions.ProductTokenHasPermission(ctx.GetStringSlice(constants.ContextValuePermissions), permission)
+	case constants.RoleLicense:
+		return permissions.LicenseTokenHasPermission(permission)
+	}
+	return false
+}
@@ -1,6 +1,9 @@
 package permissions
 
 import (
+	"github.com/gin-gonic/gin"
+	"go-license-management/internal/constants"
+	"go-license-management/internal/infrastructure/database/entities"
 	"strings"
 )
 
@@ -25,7 +28,9 @@ const (
 	UserPasswordReset  = "user_password.reset"
 	UserPasswordUpdate = "user_password.update"
 	UserRead           = "user.read"
+	UserSessionsRevoke = "user_sessions.revoke"
 	UserUnban          = "user.unban"
+	UserUnlock         = "user.unlock"
 	UserUpdate         = "user.update"
 )
 
@@ -51,6 +56,8 @@ const (
 	PolicyUpdate             = "policy.update"
 	PolicyEntitlementsAttach = "policy_entitlements.attach"
 	PolicyEntitlementsDetach = "policy_entitlements.detach"
+	PolicyKeysRotate         = "policy_keys.rotate"
+	PolicyProtectedManage    = "policy_protected.manage"
 )
 
 const (
@@ -101,7 +108,9 @@ var SuperAdminPermissionMapper = map[string]bool{
 	UserPasswordReset:         true,
 	UserPasswordUpdate:        true,
 	UserRead:                  true,
+	UserSessionsRevoke:        true,
 	UserUnban:                 true,
+	UserUnlock:                true,
 	UserUpdate:                true,
 	EntitlementCreate:         true,
 	EntitlementDelete:         true,
@@ -118,6 +127,8 @@ var SuperAdminPermissionMapper = map[string]bool{
 	PolicyUpdate:              true,
 	PolicyEntitlementsAttach:  true,
 	PolicyEntitlementsDetach:  true,
+	PolicyKeysRotate:          true,
+	PolicyProtectedManage:     true,
 	LicenseCheckIn:            true,
 	LicenseCheckOut:           true,
 	LicenseCreate:             true,
@@ -162,7 +173,9 @@ var AdminPermissionMapper = map[string]bool{
 	UserPasswordReset:         true,
 	UserPasswordUpdate:        true,
 	UserRead:                  true,
+	UserSessionsRevoke:        true,
 	UserUnban:                 true,
+	UserUnlock:                true,
 	UserUpdate:                true,
 	EntitlementCreate:         true,
 	EntitlementDelete:         true,
@@ -179,6 +192,8 @@ var AdminPermissionMapper = map[string]bool{
 	PolicyUpdate:              true,
 	PolicyEntitlementsAttach:  true,
 	PolicyEntitlementsDetach:  true,
+	PolicyKeysRotate:          true,
+	PolicyProtectedManage:     true,
 	LicenseCheckIn:            true,
 	LicenseCheckOut:           true,
 	LicenseCreate:             true,
@@ -223,7 +238,9 @@ var ProductPermissionMapper = map[string]bool{
 	UserPasswordReset:         false,
 	UserPasswordUpdate:        false,
 	UserRead:                  false,
+	UserSessionsRevoke:        false,
 	UserUnban:                 false,
+	UserUnlock:                false,
 	UserUpdate:                false,
 	EntitlementCreate:         false,
 	EntitlementDelete:         false,
@@ -240,6 +257,8 @@ var ProductPermissionMapper = map[string]bool{
 	PolicyUpdate:              true,
 	PolicyEntitlementsAttach:  true,
 	PolicyEntitlementsDetach:  true,
+	PolicyKeysRotate:          true,
+	PolicyProtectedManage:     false,
 	LicenseCheckIn:            true,
 	LicenseCheckOut:           true,
 	LicenseCreate:             true,
@@ -269,6 +288,8 @@ var ProductPermissionMapper = map[string]bool{
 	MachineHeartbeatReset:     false,
 }
 
+// LicensePermissionMapper are the permissions of the license tokens, which can only act on their own license and its
+// machines.
 var LicensePermissionMapper = map[string]bool{
 	TenantCreate:              false,
 	TenantUpdate:              false,
@@ -284,7 +305,9 @@ var LicensePermissionMapper = map[string]bool{
 	UserPasswordReset:         false,
 	UserPasswordUpdate:        false,
 	UserRead:                  false,
+	UserSessionsRevoke:        false,
 	UserUnban:                 false,
+	UserUnlock:                false,
 	UserUpdate:                false,
 	EntitlementCreate:         false,
 	EntitlementDelete:         false,
@@ -301,6 +324,8 @@ var LicensePermissionMapper = map[string]bool{
 	PolicyUpdate:              false,
 	PolicyEntitlementsAttach:  false,
 	PolicyEntitlementsDetach:  false,
+	PolicyKeysRotate:          false,
+	PolicyProtectedManage:     false,
 	LicenseCheckIn:            true,
 	LicenseCheckOut:           true,
 	LicenseCreate:             false,
@@ -312,21 +337,21 @@ var LicensePermissionMapper = map[string]bool{
 	LicenseSuspend:            false,
 	LicenseValidate:           true,
 	LicenseUpdate:             false,
-	LicenseUsageDecrement:     true,
-	LicenseUsageIncrement:     true,
-	LicenseTokensGenerate:     true,
+	LicenseUsageDecrement:     false,
+	LicenseUsageIncrement:     false,
+	LicenseTokensGenerate:     false,
 	LicenseUsageReset:         false,
-	LicenseEntitlementsAttach: true,
-	LicenseEntitlementsDetach: true,
+	LicenseEntitlementsAttach: false,
+	LicenseEntitlementsDetach: false,
 	LicensePolicyUpdate:       false,
 	LicenseUsersAttach:        false,
 	LicenseUsersDetach:        false,
-	MachineCreate:             false,
-	MachineDelete:             false,
-	MachineRead:               false,
+	MachineCreate:             true,
+	MachineDelete:             true,
+	MachineRead:               true,
 	MachineUpdate:             false,
-	MachineCheckOut:           false,
-	MachineHeartbeatPing:      false,
+	MachineCheckOut:           true,
+	MachineHeartbeatPing:      true,
 	MachineHeartbeatReset:     false,
 }
 
@@ -345,7 +370,9 @@ var UserPermissionMapper = map[string]bool{
 	UserPasswordReset:         true,
 	UserPasswordUpdate:        true,
 	UserRead:                  true,
+	UserSessionsRevoke:        false,
 	UserUnban:                 false,
+	UserUnlock:                false,
 	UserUpdate:                true,
 	EntitlementCreate:         true,
 	EntitlementDelete:         true,
@@ -362,6 +389,8 @@ var UserPermissionMapper = map[string]bool{
 	PolicyUpdate:              true,
 	PolicyEntitlementsAttach:  true,
 	PolicyEntitlementsDetach:  true,
+	PolicyKeysRotate:          true,
+	PolicyProtectedManage:     false,
 	LicenseCheckIn:            true,
 	LicenseCheckOut:           true,
 	LicenseCreate:             true,
@@ -462,3 +491,47 @@ func CreateUserPermission() [][]string {
 	}
 	return result
 }
+
+// ProductTokenPermissionWildcard grants a product token all the permissions of the product role.
+const ProductTokenPermissionWildcard = "*"
+
+// ValidateProductTokenPermissions reports whether the permissions can be granted to a product token, i.e. whether
+// each of them is the wildcard or a permission of the product role.
+func ValidateProductTokenPermissions(tokenPermissions []string) bool {
+	for _, permission := range tokenPermissions {
+		if permission != ProductTokenPermissionWildcard && !ProductPermissionMapper[permission] {
+			return false
+		}
+	}
+	return true
+}
+
+// ProductTokenHasPermission reports whether a product token granted the token permissions has the permission.
+// The wildcard only grants the permissions of the product role.
+func ProductTokenHasPermission(tokenPermissions []string, permission string) bool {
+	if !ProductPermissionMapper[permission] {
+		return false
+	}
+
+	for _, tokenPermission := range tokenPermissions {
+		if tokenPermission == ProductTokenPermissionWildcard || tokenPermission == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// LicenseTokenHasPermission reports whether the license tokens have the permission.
+func LicenseTokenHasPermission(permission string) bool {
+	return LicensePermissionMapper[permission]
+}
+
+// CanManageProtectedPolicy reports whether the subject of the request may manage the licenses and machines under the
+// policy, or change the policy. Protected policies require the `policy_protected.manage` permission, evaluated by the
+// permission middlewares.
+func CanManageProtectedPolicy(ctx *gin.Context, policy *entities.Policy) bool {
+	if policy == nil || !policy.Protected {
+		return true
+	}
+	return ctx.GetBool(constants.ContextValueProtectedPolicyAccess)
+}
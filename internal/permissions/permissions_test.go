@@ -75,3 +75,29 @@ func TestCreateUserPermission(t *testing.T) {
 		assert.NoError(t, err)
 	}
 }
+
+func TestValidateProductTokenPermissions(t *testing.T) {
+	assert.True(t, ValidateProductTokenPermissions([]string{ProductTokenPermissionWildcard}))
+	assert.True(t, ValidateProductTokenPermissions([]string{LicenseCreate, LicenseRead}))
+	assert.False(t, ValidateProductTokenPermissions([]string{LicenseRead, TenantDelete}))
+	assert.False(t, ValidateProductTokenPermissions([]string{"license.unknown"}))
+}
+
+func TestProductTokenHasPermission(t *testing.T) {
+	assert.True(t, ProductTokenHasPermission([]string{ProductTokenPermissionWildcard}, LicenseCreate))
+	assert.True(t, ProductTokenHasPermission([]string{LicenseRead}, LicenseRead))
+	assert.False(t, ProductTokenHasPermission([]string{LicenseRead}, LicenseCreate))
+
+	// The wildcard does not grant permissions outside the product role
+	assert.False(t, ProductTokenHasPermission([]string{ProductTokenPermissionWildcard}, TenantDelete))
+	assert.False(t, ProductTokenHasPermission([]string{ProductTokenPermissionWildcard}, MachineCreate))
+}
+
+func TestLicenseTokenHasPermission(t *testing.T) {
+	for _, permission := range []string{LicenseValidate, LicenseCheckOut, MachineCreate, MachineDelete, MachineHeartbeatPing} {
+		assert.True(t, LicenseTokenHasPermission(permission), permission)
+	}
+	for _, permission := range []string{LicenseCreate, LicenseRenew, LicenseTokensGenerate, MachineHeartbeatReset, ProductRead} {
+		assert.False(t, LicenseTokenHasPermission(permission), permission)
+	}
+}
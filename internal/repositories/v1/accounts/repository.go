@@ -2,6 +2,7 @@ package accounts
 
 import (
 	"context"
+	"database/sql"
 	"github.com/uptrace/bun"
 	"go-license-management/internal/cerrors"
 	"go-license-management/internal/constants"
@@ -133,3 +134,89 @@ func (repo *AccountRepository) DeleteAccountByPK(ctx context.Context, tenantName
 	}
 	return nil
 }
+
+// RevokeAccountSessions revokes every access token of the account issued up to the revocation, and deletes the
+// refresh tokens of the account.
+func (repo *AccountRepository) RevokeAccountSessions(ctx context.Context, revokedToken *entities.RevokedToken) error {
+	if repo.database == nil {
+		return cerrors.ErrInvalidDatabaseClient
+	}
+
+	tx, err := repo.database.BeginTx(ctx, &sql.TxOptions{})
+	defer func() {
+		cErr := tx.Commit()
+		if cErr != nil && err == nil {
+			err = cErr
+		}
+	}()
+
+	_, err = tx.NewInsert().Model(revokedToken).Exec(ctx)
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	_, err = tx.NewDelete().Model((*entities.RefreshToken)(nil)).
+		Where("tenant_name = ?", revokedToken.TenantName).
+		Where("username = ?", revokedToken.Username).
+		Exec(ctx)
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return nil
+}
+
+// DeleteAccountLockout unlocks the account and clears its failed logins.
+func (repo *AccountRepository) DeleteAccountLockout(ctx context.Context, tenantName, username string) error {
+	if repo.database == nil {
+		return cerrors.ErrInvalidDatabaseClient
+	}
+
+	tx, err := repo.database.BeginTx(ctx, &sql.TxOptions{})
+	defer func() {
+		cErr := tx.Commit()
+		if cErr != nil && err == nil {
+			err = cErr
+		}
+	}()
+
+	_, err = tx.NewDelete().Model(&entities.AccountLockout{TenantName: tenantName, Username: username}).WherePK().Exec(ctx)
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	_, err = tx.NewDelete().Model((*entities.LoginFailure)(nil)).
+		Where("tenant_name = ?", tenantName).
+		Where("username = ?", username).
+		Exec(ctx)
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return nil
+}
+
+func (repo *AccountRepository) SelectLicensesByUsername(ctx context.Context, tenantName, username string, queryParam constants.QueryCommonParam) ([]entities.License, int, error) {
+	var count = 0
+	if repo.database == nil {
+		return nil, count, cerrors.ErrInvalidDatabaseClient
+	}
+
+	licenses := make([]entities.License, 0)
+	count, err := repo.database.NewSelect().Model(new(entities.License)).
+		Join("JOIN license_users AS lu ON lu.license_id = l.id").
+		Where("lu.tenant_name = ? AND lu.username = ?", tenantName, username).
+		Order("l.created_at DESC").
+		Limit(utils.DerefPointer(queryParam.Limit)).
+		Offset(utils.DerefPointer(queryParam.Offset)).
+		ScanAndCount(ctx, &licenses)
+	if err != nil {
+		return licenses, count, err
+	}
+
+	return licenses, count, nil
+}
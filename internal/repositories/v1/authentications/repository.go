@@ -2,10 +2,14 @@ package authentications
 
 import (
 	"context"
+	"database/sql"
+	"github.com/google/uuid"
 	"github.com/uptrace/bun"
 	"go-license-management/internal/cerrors"
 	"go-license-management/internal/infrastructure/database/entities"
+	"go-license-management/internal/infrastructure/envelope"
 	"go-license-management/server/api"
+	"time"
 )
 
 type AuthenticationRepository struct {
@@ -25,7 +29,13 @@ func (repo *AuthenticationRepository) SelectTenantByPK(ctx context.Context, tena
 
 	tenant := &entities.Tenant{Name: tenantName}
 
-	err := repo.database.NewSelect().Model(tenant).ColumnExpr("name, ed25519_private_key").WherePK().Scan(ctx)
+	err := repo.database.NewSelect().Model(tenant).ColumnExpr("name, ed25519_public_key, ed25519_private_key").WherePK().Scan(ctx)
+	if err != nil {
+		return tenant, err
+	}
+
+	// The private key is stored sealed with the key encryption key
+	tenant.Ed25519PrivateKey, err = envelope.GetInstance().Open(tenant.Ed25519PrivateKey, envelope.TenantKeyAssociatedData(tenant.Name))
 	if err != nil {
 		return tenant, err
 	}
@@ -56,5 +66,236 @@ func (repo *AuthenticationRepository) SelectMasterByPK(ctx context.Context, user
 	if err != nil {
 		return master, err
 	}
+
+	master.Ed25519PrivateKey, err = envelope.GetInstance().Open(master.Ed25519PrivateKey, envelope.MasterKeyAssociatedData(master.Username))
+	if err != nil {
+		return master, err
+	}
 	return master, nil
 }
+
+func (repo *AuthenticationRepository) InsertNewRefreshToken(ctx context.Context, refreshToken *entities.RefreshToken) error {
+	if repo.database == nil {
+		return cerrors.ErrInvalidDatabaseClient
+	}
+
+	_, err := repo.database.NewInsert().Model(refreshToken).Exec(ctx)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (repo *AuthenticationRepository) SelectRefreshTokenByHash(ctx context.Context, tokenHash string) (*entities.RefreshToken, error) {
+	if repo.database == nil {
+		return nil, cerrors.ErrInvalidDatabaseClient
+	}
+
+	refreshToken := &entities.RefreshToken{}
+	err := repo.database.NewSelect().Model(refreshToken).Where("token_hash = ?", tokenHash).Scan(ctx)
+	if err != nil {
+		return refreshToken, err
+	}
+	return refreshToken, nil
+}
+
+// RotateRefreshToken marks the refresh token as used and replaces it with the next token of its family.
+// Returns cerrors.ErrAccountRefreshTokenIsInvalid when the refresh token has already been used, e.g. by a concurrent
+// request.
+func (repo *AuthenticationRepository) RotateRefreshToken(ctx context.Context, usedToken, nextToken *entities.RefreshToken) error {
+	if repo.database == nil {
+		return cerrors.ErrInvalidDatabaseClient
+	}
+
+	tx, err := repo.database.BeginTx(ctx, &sql.TxOptions{})
+	defer func() {
+		cErr := tx.Commit()
+		if cErr != nil && err == nil {
+			err = cErr
+		}
+	}()
+
+	usedToken.UsedAt = time.Now()
+	res, err := tx.NewUpdate().Model(usedToken).
+		Column("used_at").
+		WherePK().
+		Where("used_at IS NULL").
+		Exec(ctx)
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	if affected == 0 {
+		_ = tx.Rollback()
+		return cerrors.ErrAccountRefreshTokenIsInvalid
+	}
+
+	_, err = tx.NewInsert().Model(nextToken).Exec(ctx)
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return nil
+}
+
+func (repo *AuthenticationRepository) DeleteRefreshTokenFamily(ctx context.Context, familyID uuid.UUID) error {
+	if repo.database == nil {
+		return cerrors.ErrInvalidDatabaseClient
+	}
+
+	_, err := repo.database.NewDelete().Model((*entities.RefreshToken)(nil)).Where("family_id = ?", familyID).Exec(ctx)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (repo *AuthenticationRepository) InsertNewRevokedToken(ctx context.Context, revokedToken *entities.RevokedToken) error {
+	if repo.database == nil {
+		return cerrors.ErrInvalidDatabaseClient
+	}
+
+	_, err := repo.database.NewInsert().Model(revokedToken).Exec(ctx)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// DeleteExpiredRevokedTokens deletes the revocations of the access tokens that have expired since.
+func (repo *AuthenticationRepository) DeleteExpiredRevokedTokens(ctx context.Context, now time.Time) (int64, error) {
+	if repo.database == nil {
+		return 0, cerrors.ErrInvalidDatabaseClient
+	}
+
+	res, err := repo.database.NewDelete().Model(new(entities.RevokedToken)).
+		Where("expires_at <= ?", now).
+		Exec(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+func (repo *AuthenticationRepository) SelectAccountLockout(ctx context.Context, tenantName, username string) (*entities.AccountLockout, error) {
+	if repo.database == nil {
+		return nil, cerrors.ErrInvalidDatabaseClient
+	}
+
+	lockout := &entities.AccountLockout{TenantName: tenantName, Username: username}
+	err := repo.database.NewSelect().Model(lockout).WherePK().Scan(ctx)
+	if err != nil {
+		return lockout, err
+	}
+	return lockout, nil
+}
+
+// UpsertAccountLockout locks the account until the end of the lockout, replacing its previous lockout if any.
+func (repo *AuthenticationRepository) UpsertAccountLockout(ctx context.Context, lockout *entities.AccountLockout) error {
+	if repo.database == nil {
+		return cerrors.ErrInvalidDatabaseClient
+	}
+
+	_, err := repo.database.NewInsert().Model(lockout).
+		On("CONFLICT (tenant_name, username) DO UPDATE").
+		Set("locked_until = EXCLUDED.locked_until").
+		Set("created_at = EXCLUDED.created_at").
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// InsertNewLoginFailure records the failed login and returns the number of failed logins of the account since the
+// given time, including this one. The failed logins of an account are recorded one at a time, so concurrent failed
+// logins are each counted once and no more than one of them reaches a given count.
+func (repo *AuthenticationRepository) InsertNewLoginFailure(ctx context.Context, failure *entities.LoginFailure, since time.Time) (int, error) {
+	if repo.database == nil {
+		return 0, cerrors.ErrInvalidDatabaseClient
+	}
+
+	tx, err := repo.database.BeginTx(ctx, &sql.TxOptions{})
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		cErr := tx.Commit()
+		if cErr != nil && err == nil {
+			err = cErr
+		}
+	}()
+
+	// The lock is released when the transaction ends
+	_, err = tx.NewRaw("SELECT pg_advisory_xact_lock(hashtext(?))", failure.TenantName+"/"+failure.Username).Exec(ctx)
+	if err != nil {
+		_ = tx.Rollback()
+		return 0, err
+	}
+
+	_, err = tx.NewInsert().Model(failure).Exec(ctx)
+	if err != nil {
+		_ = tx.Rollback()
+		return 0, err
+	}
+
+	failures, err := tx.NewSelect().Model((*entities.LoginFailure)(nil)).
+		Where("tenant_name = ?", failure.TenantName).
+		Where("username = ?", failure.Username).
+		Where("created_at > ?", since).
+		Count(ctx)
+	if err != nil {
+		_ = tx.Rollback()
+		return 0, err
+	}
+
+	return failures, nil
+}
+
+func (repo *AuthenticationRepository) CountLoginFailuresByIP(ctx context.Context, ip string, since time.Time) (int, error) {
+	if repo.database == nil {
+		return 0, cerrors.ErrInvalidDatabaseClient
+	}
+
+	return repo.database.NewSelect().Model((*entities.LoginFailure)(nil)).
+		Where("ip = ?", ip).
+		Where("created_at > ?", since).
+		Count(ctx)
+}
+
+func (repo *AuthenticationRepository) DeleteLoginFailuresByAccount(ctx context.Context, tenantName, username string) error {
+	if repo.database == nil {
+		return cerrors.ErrInvalidDatabaseClient
+	}
+
+	_, err := repo.database.NewDelete().Model((*entities.LoginFailure)(nil)).
+		Where("tenant_name = ?", tenantName).
+		Where("username = ?", username).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// DeleteExpiredLoginFailures deletes the login failures that are no longer counted.
+func (repo *AuthenticationRepository) DeleteExpiredLoginFailures(ctx context.Context, before time.Time) (int64, error) {
+	if repo.database == nil {
+		return 0, cerrors.ErrInvalidDatabaseClient
+	}
+
+	res, err := repo.database.NewDelete().Model(new(entities.LoginFailure)).
+		Where("created_at <= ?", before).
+		Exec(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
@@ -2,6 +2,8 @@ package licenses
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"github.com/google/uuid"
 	"github.com/uptrace/bun"
 	"go-license-management/internal/cerrors"
@@ -80,6 +82,52 @@ func (repo *LicenseRepository) InsertNewLicense(ctx context.Context, license *en
 	return nil
 }
 
+// InsertNewLicenseWithPooledKey pops the oldest key from the key pool of the license policy and inserts the license
+// with that key in a single transaction. Concurrent callers skip keys locked by each other so a key is never handed out twice.
+func (repo *LicenseRepository) InsertNewLicenseWithPooledKey(ctx context.Context, license *entities.License) error {
+	if repo.database == nil {
+		return cerrors.ErrInvalidDatabaseClient
+	}
+
+	tx, err := repo.database.BeginTx(ctx, &sql.TxOptions{})
+	defer func() {
+		cErr := tx.Commit()
+		if cErr != nil && err == nil {
+			err = cErr
+		}
+	}()
+
+	key := new(entities.Key)
+	err = tx.NewSelect().Model(key).
+		Where("policy_id = ?", license.PolicyID).
+		Order("created_at ASC").
+		Limit(1).
+		For("UPDATE SKIP LOCKED").
+		Scan(ctx)
+	if err != nil {
+		_ = tx.Rollback()
+		if errors.Is(err, sql.ErrNoRows) {
+			return cerrors.ErrLicenseKeyPoolIsExhausted
+		}
+		return err
+	}
+
+	_, err = tx.NewDelete().Model(key).WherePK().Exec(ctx)
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	license.Key = key.Key
+	_, err = tx.NewInsert().Model(license).Exec(ctx)
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return err
+}
+
 func (repo *LicenseRepository) SelectLicenseByPK(ctx context.Context, licenseID uuid.UUID) (*entities.License, error) {
 	if repo.database == nil {
 		return nil, cerrors.ErrInvalidDatabaseClient
@@ -103,8 +151,15 @@ func (repo *LicenseRepository) SelectLicenses(ctx context.Context, tenantName st
 	}
 
 	licenses := make([]entities.License, 0)
-	total, err := repo.database.NewSelect().Model(new(entities.License)).
-		Where("tenant_name = ?", tenantName).
+	query := repo.database.NewSelect().Model(new(entities.License)).
+		Where("tenant_name = ?", tenantName)
+	if queryParam.ProductID != nil {
+		query = query.Where("product_id = ?", utils.DerefPointer(queryParam.ProductID))
+	}
+	if queryParam.LicenseID != nil {
+		query = query.Where("id = ?", utils.DerefPointer(queryParam.LicenseID))
+	}
+	total, err := query.
 		Order("created_at DESC").
 		Limit(utils.DerefPointer(queryParam.Limit)).
 		Offset(utils.DerefPointer(queryParam.Offset)).
@@ -190,3 +245,387 @@ func (repo *LicenseRepository) CheckProductExist(ctx context.Context, productID
 
 	return exist, nil
 }
+
+// CheckActiveMachineExistByFingerprint checks whether the license has an activated machine with the fingerprint.
+func (repo *LicenseRepository) CheckActiveMachineExistByFingerprint(ctx context.Context, licenseID uuid.UUID, fingerprint string) (bool, error) {
+	var exist bool
+
+	if repo.database == nil {
+		return exist, cerrors.ErrInvalidDatabaseClient
+	}
+
+	exist, err := repo.database.NewSelect().Model(new(entities.Machine)).
+		Where("license_id = ? AND fingerprint = ? AND deactivated = false", licenseID, fingerprint).
+		Exists(ctx)
+	if err != nil {
+		return exist, err
+	}
+
+	return exist, nil
+}
+
+// UpdateExpiredLicenses sets the status of every license whose expiry has passed to `expired`.
+// Suspended and banned licenses keep their current status.
+func (repo *LicenseRepository) UpdateExpiredLicenses(ctx context.Context, now time.Time) (int64, error) {
+	if repo.database == nil {
+		return 0, cerrors.ErrInvalidDatabaseClient
+	}
+
+	res, err := repo.database.NewUpdate().Model(new(entities.License)).
+		Set("status = ?", constants.LicenseStatusExpired).
+		Set("last_expiration_event_sent_at = ?", now).
+		Set("updated_at = ?", now).
+		Where("expiry IS NOT NULL").
+		Where("expiry < ?", now).
+		Where("status IN (?)", bun.In([]string{
+			constants.LicenseStatusNotActivated,
+			constants.LicenseStatusActive,
+			constants.LicenseStatusInactive,
+		})).
+		Exec(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	return affected, nil
+}
+
+// InsertLicenseNonce records the nonce of a license action. A nonce already recorded for the license is only replaced
+// once it has expired. Returns false when the nonce is still recorded, meaning the action is replayed.
+func (repo *LicenseRepository) InsertLicenseNonce(ctx context.Context, nonce *entities.LicenseNonce) (bool, error) {
+	if repo.database == nil {
+		return false, cerrors.ErrInvalidDatabaseClient
+	}
+
+	res, err := repo.database.NewInsert().Model(nonce).
+		On("CONFLICT (license_id, nonce) DO UPDATE").
+		Set("action = EXCLUDED.action").
+		Set("expires_at = EXCLUDED.expires_at").
+		Set("created_at = EXCLUDED.created_at").
+		Where("lnn.expires_at <= EXCLUDED.created_at").
+		Exec(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
+
+func (repo *LicenseRepository) DeleteExpiredLicenseNonces(ctx context.Context, now time.Time) (int64, error) {
+	if repo.database == nil {
+		return 0, cerrors.ErrInvalidDatabaseClient
+	}
+
+	res, err := repo.database.NewDelete().Model(new(entities.LicenseNonce)).
+		Where("expires_at <= ?", now).
+		Exec(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	return affected, nil
+}
+
+func (repo *LicenseRepository) InsertNewLicenseToken(ctx context.Context, licenseToken *entities.LicenseToken) error {
+	if repo.database == nil {
+		return cerrors.ErrInvalidDatabaseClient
+	}
+
+	_, err := repo.database.NewInsert().Model(licenseToken).Exec(ctx)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (repo *LicenseRepository) SelectAccountsByUsernames(ctx context.Context, tenantName string, usernames []string) ([]entities.Account, error) {
+	if repo.database == nil {
+		return nil, cerrors.ErrInvalidDatabaseClient
+	}
+
+	accounts := make([]entities.Account, 0)
+	err := repo.database.NewSelect().Model(&accounts).
+		Where("tenant_name = ? AND username IN (?)", tenantName, bun.In(usernames)).
+		Scan(ctx)
+	if err != nil {
+		return accounts, err
+	}
+
+	return accounts, nil
+}
+
+func (repo *LicenseRepository) SelectLicenseUsersByUsernames(ctx context.Context, licenseID uuid.UUID, usernames []string) ([]entities.LicenseUser, error) {
+	if repo.database == nil {
+		return nil, cerrors.ErrInvalidDatabaseClient
+	}
+
+	licenseUsers := make([]entities.LicenseUser, 0)
+	err := repo.database.NewSelect().Model(&licenseUsers).
+		Where("license_id = ? AND username IN (?)", licenseID, bun.In(usernames)).
+		Scan(ctx)
+	if err != nil {
+		return licenseUsers, err
+	}
+
+	return licenseUsers, nil
+}
+
+func (repo *LicenseRepository) SelectLicenseUsers(ctx context.Context, licenseID uuid.UUID, queryParam constants.QueryCommonParam) ([]entities.LicenseUser, int, error) {
+	var total int
+
+	if repo.database == nil {
+		return nil, total, cerrors.ErrInvalidDatabaseClient
+	}
+
+	licenseUsers := make([]entities.LicenseUser, 0)
+	total, err := repo.database.NewSelect().Model(new(entities.LicenseUser)).
+		Relation("Account").
+		Where("lu.license_id = ?", licenseID).
+		Limit(utils.DerefPointer(queryParam.Limit)).
+		Offset(utils.DerefPointer(queryParam.Offset)).
+		Order("lu.created_at DESC").
+		ScanAndCount(ctx, &licenseUsers)
+	if err != nil {
+		return licenseUsers, total, err
+	}
+	return licenseUsers, total, err
+}
+
+// InsertNewLicenseUsersAndUpdateLicense attaches the users to the license and updates the license users count.
+// If the max users of the license are enforced, returns cerrors.ErrLicenseMaxUsersExceeded when the users would exceed
+// them. The users count is checked and incremented at once, so concurrent attachments cannot exceed the max users.
+func (repo *LicenseRepository) InsertNewLicenseUsersAndUpdateLicense(ctx context.Context, license *entities.License, licenseUsers []entities.LicenseUser, enforceMaxUsers bool) error {
+	if repo.database == nil {
+		return cerrors.ErrInvalidDatabaseClient
+	}
+
+	tx, err := repo.database.BeginTx(ctx, &sql.TxOptions{})
+	defer func() {
+		cErr := tx.Commit()
+		if cErr != nil && err == nil {
+			err = cErr
+		}
+	}()
+
+	if enforceMaxUsers {
+		res, err := tx.NewUpdate().Model((*entities.License)(nil)).
+			Set("users = users + ?", len(licenseUsers)).
+			Where("id = ?", license.ID).
+			Where("max_users = 0 OR max_users IS NULL OR users + ? <= max_users", len(licenseUsers)).
+			Exec(ctx)
+		if err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+
+		affected, err := res.RowsAffected()
+		if err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+		if affected == 0 {
+			_ = tx.Rollback()
+			return cerrors.ErrLicenseMaxUsersExceeded
+		}
+	}
+
+	_, err = tx.NewInsert().Model(&licenseUsers).Exec(ctx)
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	err = repo.updateLicenseUsersCount(ctx, tx, license)
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return err
+}
+
+// DeleteLicenseUsersAndUpdateLicense detaches the users from the license and updates the license users count.
+func (repo *LicenseRepository) DeleteLicenseUsersAndUpdateLicense(ctx context.Context, license *entities.License, usernames []string) error {
+	if repo.database == nil {
+		return cerrors.ErrInvalidDatabaseClient
+	}
+
+	tx, err := repo.database.BeginTx(ctx, &sql.TxOptions{})
+	defer func() {
+		cErr := tx.Commit()
+		if cErr != nil && err == nil {
+			err = cErr
+		}
+	}()
+
+	_, err = tx.NewDelete().Model(new(entities.LicenseUser)).
+		Where("license_id = ? AND username IN (?)", license.ID, bun.In(usernames)).
+		Exec(ctx)
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	err = repo.updateLicenseUsersCount(ctx, tx, license)
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return err
+}
+
+func (repo *LicenseRepository) updateLicenseUsersCount(ctx context.Context, tx bun.Tx, license *entities.License) error {
+	count, err := tx.NewSelect().Model(new(entities.LicenseUser)).Where("license_id = ?", license.ID).Count(ctx)
+	if err != nil {
+		return err
+	}
+
+	license.Users = count
+	license.UpdatedAt = time.Now()
+	_, err = tx.NewUpdate().Model(license).Column("users", "updated_at").WherePK().Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (repo *LicenseRepository) SelectEntitlementsByPK(ctx context.Context, tenantName string, entitlementIDs []uuid.UUID) ([]entities.Entitlement, error) {
+	if repo.database == nil {
+		return nil, cerrors.ErrInvalidDatabaseClient
+	}
+
+	entitlements := make([]entities.Entitlement, 0)
+	err := repo.database.NewSelect().Model(&entitlements).
+		Where("tenant_name = ? AND id IN (?)", tenantName, bun.In(entitlementIDs)).
+		Scan(ctx)
+	if err != nil {
+		return entitlements, err
+	}
+
+	return entitlements, nil
+}
+
+// SelectAttachedEntitlementIDs returns the IDs of the given entitlements that are already attached to either the license or its policy.
+func (repo *LicenseRepository) SelectAttachedEntitlementIDs(ctx context.Context, licenseID, policyID uuid.UUID, entitlementIDs []uuid.UUID) ([]uuid.UUID, error) {
+	if repo.database == nil {
+		return nil, cerrors.ErrInvalidDatabaseClient
+	}
+
+	attached := make([]uuid.UUID, 0)
+	err := repo.database.NewSelect().Model(new(entities.LicenseEntitlement)).
+		Column("entitlement_id").
+		Where("license_id = ? AND entitlement_id IN (?)", licenseID, bun.In(entitlementIDs)).
+		UnionAll(
+			repo.database.NewSelect().Model(new(entities.PolicyEntitlement)).
+				Column("entitlement_id").
+				Where("policy_id = ? AND entitlement_id IN (?)", policyID, bun.In(entitlementIDs)),
+		).
+		Scan(ctx, &attached)
+	if err != nil {
+		return attached, err
+	}
+
+	return attached, nil
+}
+
+func (repo *LicenseRepository) SelectLicenseEntitlementsByPK(ctx context.Context, licenseID uuid.UUID, licenseEntitlementIDs []uuid.UUID) ([]entities.LicenseEntitlement, error) {
+	if repo.database == nil {
+		return nil, cerrors.ErrInvalidDatabaseClient
+	}
+
+	licenseEntitlements := make([]entities.LicenseEntitlement, 0)
+	err := repo.database.NewSelect().Model(&licenseEntitlements).
+		Where("license_id = ? AND id IN (?)", licenseID, bun.In(licenseEntitlementIDs)).
+		Scan(ctx)
+	if err != nil {
+		return licenseEntitlements, err
+	}
+
+	return licenseEntitlements, nil
+}
+
+func (repo *LicenseRepository) InsertNewLicenseEntitlements(ctx context.Context, licenseEntitlements []entities.LicenseEntitlement) error {
+	if repo.database == nil {
+		return cerrors.ErrInvalidDatabaseClient
+	}
+
+	_, err := repo.database.NewInsert().Model(&licenseEntitlements).Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (repo *LicenseRepository) DeleteLicenseEntitlementsByPK(ctx context.Context, licenseEntitlementIDs []uuid.UUID) error {
+	if repo.database == nil {
+		return cerrors.ErrInvalidDatabaseClient
+	}
+
+	licenseEntitlements := make([]entities.LicenseEntitlement, 0)
+	for _, id := range licenseEntitlementIDs {
+		licenseEntitlements = append(licenseEntitlements, entities.LicenseEntitlement{ID: id})
+	}
+
+	_, err := repo.database.NewDelete().Model(&licenseEntitlements).WherePK().Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// SelectPolicyEntitlementsByPolicyID returns every entitlement attached to the policy, along with the entitlement itself.
+func (repo *LicenseRepository) SelectPolicyEntitlementsByPolicyID(ctx context.Context, policyID uuid.UUID) ([]entities.PolicyEntitlement, error) {
+	if repo.database == nil {
+		return nil, cerrors.ErrInvalidDatabaseClient
+	}
+
+	policyEntitlements := make([]entities.PolicyEntitlement, 0)
+	err := repo.database.NewSelect().Model(&policyEntitlements).
+		Relation("Entitlement").
+		Where("pe.policy_id = ?", policyID).
+		Order("pe.created_at ASC").
+		Scan(ctx)
+	if err != nil {
+		return policyEntitlements, err
+	}
+
+	return policyEntitlements, nil
+}
+
+// SelectLicenseEntitlementsByLicenseID returns every entitlement attached to the license, along with the entitlement itself.
+func (repo *LicenseRepository) SelectLicenseEntitlementsByLicenseID(ctx context.Context, licenseID uuid.UUID) ([]entities.LicenseEntitlement, error) {
+	if repo.database == nil {
+		return nil, cerrors.ErrInvalidDatabaseClient
+	}
+
+	licenseEntitlements := make([]entities.LicenseEntitlement, 0)
+	err := repo.database.NewSelect().Model(&licenseEntitlements).
+		Relation("Entitlement").
+		Where("le.license_id = ?", licenseID).
+		Order("le.created_at ASC").
+		Scan(ctx)
+	if err != nil {
+		return licenseEntitlements, err
+	}
+
+	return licenseEntitlements, nil
+}
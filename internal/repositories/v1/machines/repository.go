@@ -45,7 +45,7 @@ func (repo *MachineRepository) SelectMachineByPK(ctx context.Context, machineID
 
 	machine := &entities.Machine{ID: machineID}
 
-	err := repo.database.NewSelect().Model(machine).Relation("License").WherePK().Scan(ctx)
+	err := repo.database.NewSelect().Model(machine).Relation("License").Relation("License.Policy").WherePK().Scan(ctx)
 	if err != nil {
 		return machine, err
 	}
@@ -74,9 +74,15 @@ func (repo *MachineRepository) SelectMachines(ctx context.Context, tenantName st
 	}
 
 	machines := make([]entities.Machine, 0)
-	total, err := repo.database.NewSelect().Model(new(entities.Machine)).
-		Where("tenant_name = ?", tenantName).
-		Order("created_at DESC").
+	query := repo.database.NewSelect().Model(new(entities.Machine)).
+		Relation("License").
+		Relation("License.Policy").
+		Where("machine.tenant_name = ?", tenantName)
+	if queryParam.LicenseID != nil {
+		query = query.Where("machine.license_id = ?", utils.DerefPointer(queryParam.LicenseID))
+	}
+	total, err := query.
+		Order("machine.created_at DESC").
 		Limit(utils.DerefPointer(queryParam.Limit)).
 		Offset(utils.DerefPointer(queryParam.Offset)).
 		ScanAndCount(ctx, &machines)
@@ -122,21 +128,24 @@ func (repo *MachineRepository) DeleteMachineByPKAndUpdateLicense(ctx context.Con
 		return err
 	}
 
-	license := &entities.License{ID: machine.LicenseID}
-	err = tx.NewSelect().Model(license).WherePK().Scan(ctx)
-	if err != nil {
-		_ = tx.Rollback()
-		return err
-	}
-	license.UpdatedAt = time.Now()
-	license.MachinesCount -= 1
-	if license.MachinesCount == 0 {
-		license.Status = constants.LicenseStatusInactive
-	}
-	_, err = tx.NewUpdate().Model(license).WherePK().Exec(ctx)
-	if err != nil {
-		_ = tx.Rollback()
-		return err
+	// Deactivated machines are no longer counted against the license
+	if !machine.Deactivated {
+		license := &entities.License{ID: machine.LicenseID}
+		err = tx.NewSelect().Model(license).WherePK().Scan(ctx)
+		if err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+		license.UpdatedAt = time.Now()
+		license.MachinesCount -= 1
+		if license.MachinesCount == 0 {
+			license.Status = constants.LicenseStatusInactive
+		}
+		_, err = tx.NewUpdate().Model(license).WherePK().Exec(ctx)
+		if err != nil {
+			_ = tx.Rollback()
+			return err
+		}
 	}
 
 	_, err = tx.NewDelete().Model(machine).WherePK().Exec(ctx)
@@ -208,6 +217,8 @@ func (repo *MachineRepository) SelectPolicyByPK(ctx context.Context, policyID uu
 	return policy, nil
 }
 
+// CheckMachineExistByFingerprintAndLicense reports whether an active machine of the license has the fingerprint.
+// Machines deactivated by the heartbeat monitor are ignored, so their fingerprint can be activated again.
 func (repo *MachineRepository) CheckMachineExistByFingerprintAndLicense(ctx context.Context, licenseKey, fingerprint string) (bool, error) {
 	if repo.database == nil {
 		return false, cerrors.ErrInvalidDatabaseClient
@@ -216,6 +227,7 @@ func (repo *MachineRepository) CheckMachineExistByFingerprintAndLicense(ctx cont
 	exists, err := repo.database.NewSelect().Model(new(entities.Machine)).
 		Where("license_key = ?", licenseKey).
 		Where("fingerprint = ?", fingerprint).
+		Where("deactivated = ?", false).
 		Exists(ctx)
 	if err != nil {
 		return exists, err
@@ -237,13 +249,79 @@ func (repo *MachineRepository) InsertNewMachineAndUpdateLicense(ctx context.Cont
 		}
 	}()
 
-	license := &entities.License{ID: machine.LicenseID}
-	err = tx.NewSelect().Model(license).WherePK().Scan(ctx)
+	err = insertNewMachineAndUpdateLicense(ctx, tx, machine)
 	if err != nil {
 		_ = tx.Rollback()
 		return err
 	}
 
+	return nil
+}
+
+// InsertNewMachineAndUpdateLicenseToken activates a machine with a license token, counting the activation against
+// the max activations of the token. Returns cerrors.ErrMachineLicenseTokenActivationsExceeded when the token has no
+// activation left.
+func (repo *MachineRepository) InsertNewMachineAndUpdateLicenseToken(ctx context.Context, machine *entities.Machine, licenseTokenID uuid.UUID) error {
+	if repo.database == nil {
+		return cerrors.ErrInvalidDatabaseClient
+	}
+
+	tx, err := repo.database.BeginTx(ctx, &sql.TxOptions{})
+	defer func() {
+		cErr := tx.Commit()
+		if cErr != nil && err == nil {
+			err = cErr
+		}
+	}()
+
+	res, err := tx.NewUpdate().Model((*entities.LicenseToken)(nil)).
+		Set("activations = activations + 1").
+		Where("id = ?", licenseTokenID).
+		Where("max_activations = 0 OR activations < max_activations").
+		Exec(ctx)
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	if affected == 0 {
+		_ = tx.Rollback()
+		return cerrors.ErrMachineLicenseTokenActivationsExceeded
+	}
+
+	err = insertNewMachineAndUpdateLicense(ctx, tx, machine)
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return nil
+}
+
+// insertNewMachineAndUpdateLicense inserts the machine and counts it in the machines of its license, activating the
+// license if it is not active yet. A deactivated machine of the license with the same fingerprint is replaced.
+func insertNewMachineAndUpdateLicense(ctx context.Context, tx bun.Tx, machine *entities.Machine) error {
+	license := &entities.License{ID: machine.LicenseID}
+	err := tx.NewSelect().Model(license).WherePK().Scan(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Deactivated machines are no longer counted against the license
+	_, err = tx.NewDelete().Model((*entities.Machine)(nil)).
+		Where("license_id = ?", machine.LicenseID).
+		Where("fingerprint = ?", machine.Fingerprint).
+		Where("deactivated = ?", true).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+
 	if license.Status == constants.LicenseStatusNotActivated || license.Status == constants.LicenseStatusInactive {
 		license.Status = constants.LicenseStatusActive
 	}
@@ -252,13 +330,11 @@ func (repo *MachineRepository) InsertNewMachineAndUpdateLicense(ctx context.Cont
 
 	_, err = tx.NewUpdate().Model(license).WherePK().Exec(ctx)
 	if err != nil {
-		_ = tx.Rollback()
 		return err
 	}
 
 	_, err = tx.NewInsert().Model(machine).Exec(ctx)
 	if err != nil {
-		_ = tx.Rollback()
 		return err
 	}
 
@@ -291,8 +367,9 @@ func (repo *MachineRepository) UpdateMachineByPKAndLicense(ctx context.Context,
 		}
 	}()
 
-	// Only perform update on license if the new license is not nil
-	if newLicense != nil {
+	// Only perform update on license if the new license is not nil.
+	// Deactivated machines are no longer counted against any license.
+	if newLicense != nil && !machine.Deactivated {
 		currentLicense.UpdatedAt = time.Now()
 		currentLicense.MachinesCount -= 1
 		if currentLicense.MachinesCount == 0 {
@@ -323,3 +400,116 @@ func (repo *MachineRepository) UpdateMachineByPKAndLicense(ctx context.Context,
 	}
 	return machine, nil
 }
+
+// SelectMachinesRequiringHeartbeat returns the active machines whose policy requires a heartbeat.
+func (repo *MachineRepository) SelectMachinesRequiringHeartbeat(ctx context.Context) ([]entities.Machine, error) {
+	if repo.database == nil {
+		return nil, cerrors.ErrInvalidDatabaseClient
+	}
+
+	machines := make([]entities.Machine, 0)
+	err := repo.database.NewSelect().Model(&machines).
+		Relation("License").
+		Relation("License.Policy").
+		Where("machine.deactivated = ?", false).
+		Where("license__policy.require_heartbeat = ?", true).
+		Scan(ctx)
+	if err != nil {
+		return machines, err
+	}
+
+	return machines, nil
+}
+
+// DeactivateMachineAndUpdateLicense marks a dead machine as deactivated and releases its slot on the license.
+func (repo *MachineRepository) DeactivateMachineAndUpdateLicense(ctx context.Context, machine *entities.Machine) error {
+	if repo.database == nil {
+		return cerrors.ErrInvalidDatabaseClient
+	}
+
+	tx, err := repo.database.BeginTx(ctx, &sql.TxOptions{})
+	defer func() {
+		cErr := tx.Commit()
+		if cErr != nil && err == nil {
+			err = cErr
+		}
+	}()
+
+	license := &entities.License{ID: machine.LicenseID}
+	err = tx.NewSelect().Model(license).WherePK().Scan(ctx)
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	now := time.Now()
+	license.UpdatedAt = now
+	if license.MachinesCount > 0 {
+		license.MachinesCount -= 1
+	}
+	if license.MachinesCount == 0 && license.Status == constants.LicenseStatusActive {
+		license.Status = constants.LicenseStatusInactive
+	}
+	_, err = tx.NewUpdate().Model(license).WherePK().Exec(ctx)
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	machine.Deactivated = true
+	machine.LastDeathEventSentAt = now
+	machine.UpdatedAt = now
+	_, err = tx.NewUpdate().Model(machine).
+		Column("deactivated", "last_death_event_sent_at", "updated_at").
+		WherePK().
+		Exec(ctx)
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return nil
+}
+
+// ReactivateMachineAndUpdateLicense marks a resurrected machine as active and counts it against the license again.
+func (repo *MachineRepository) ReactivateMachineAndUpdateLicense(ctx context.Context, machine *entities.Machine) error {
+	if repo.database == nil {
+		return cerrors.ErrInvalidDatabaseClient
+	}
+
+	tx, err := repo.database.BeginTx(ctx, &sql.TxOptions{})
+	defer func() {
+		cErr := tx.Commit()
+		if cErr != nil && err == nil {
+			err = cErr
+		}
+	}()
+
+	license := &entities.License{ID: machine.LicenseID}
+	err = tx.NewSelect().Model(license).WherePK().Scan(ctx)
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if license.Status == constants.LicenseStatusNotActivated || license.Status == constants.LicenseStatusInactive {
+		license.Status = constants.LicenseStatusActive
+	}
+	license.UpdatedAt = time.Now()
+	license.MachinesCount += 1
+	_, err = tx.NewUpdate().Model(license).WherePK().Exec(ctx)
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	machine.Deactivated = false
+	machine.UpdatedAt = time.Now()
+	_, err = tx.NewUpdate().Model(machine).WherePK().Exec(ctx)
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return nil
+}
@@ -2,11 +2,13 @@ package policies
 
 import (
 	"context"
+	"database/sql"
 	"github.com/google/uuid"
 	"github.com/uptrace/bun"
 	"go-license-management/internal/cerrors"
 	"go-license-management/internal/constants"
 	"go-license-management/internal/infrastructure/database/entities"
+	"go-license-management/internal/infrastructure/envelope"
 	"go-license-management/internal/utils"
 	"go-license-management/server/api"
 	"time"
@@ -217,7 +219,14 @@ func (repo *PolicyRepository) InsertNewPolicy(ctx context.Context, policy *entit
 		return cerrors.ErrInvalidDatabaseClient
 	}
 
-	_, err := repo.database.NewInsert().Model(policy).Exec(ctx)
+	// The private key is sealed with the key encryption key before being stored
+	var err error
+	policy.PrivateKey, err = envelope.GetInstance().Seal(policy.PrivateKey, envelope.PolicyKeyAssociatedData(policy.ID.String()))
+	if err != nil {
+		return err
+	}
+
+	_, err = repo.database.NewInsert().Model(policy).Exec(ctx)
 	if err != nil {
 		return err
 	}
@@ -229,8 +238,14 @@ func (repo *PolicyRepository) UpdatePolicyByPK(ctx context.Context, policy *enti
 		return cerrors.ErrInvalidDatabaseClient
 	}
 
+	var err error
+	policy.PrivateKey, err = envelope.GetInstance().Seal(policy.PrivateKey, envelope.PolicyKeyAssociatedData(policy.ID.String()))
+	if err != nil {
+		return err
+	}
+
 	policy.UpdatedAt = time.Now()
-	_, err := repo.database.NewUpdate().Model(policy).WherePK().Exec(ctx)
+	_, err = repo.database.NewUpdate().Model(policy).WherePK().Exec(ctx)
 	if err != nil {
 		return err
 	}
@@ -244,14 +259,129 @@ func (repo *PolicyRepository) SelectPolicies(ctx context.Context, tenantName str
 	}
 
 	policies := make([]entities.Policy, 0)
-	total, err := repo.database.NewSelect().Model(new(entities.Policy)).
-		Where("tenant_name = ?", tenantName).
+	query := repo.database.NewSelect().Model(new(entities.Policy)).
+		Where("tenant_name = ?", tenantName)
+	if queryParam.ProductID != nil {
+		query = query.Where("product_id = ?", utils.DerefPointer(queryParam.ProductID))
+	}
+	total, err := query.
+		Order("created_at DESC").
 		Limit(utils.DerefPointer(queryParam.Limit)).
 		Offset(utils.DerefPointer(queryParam.Offset)).
-		Order("created_at DESC").
 		ScanAndCount(ctx, &policies)
 	if err != nil {
 		return policies, total, err
 	}
 	return policies, total, nil
 }
+
+func (repo *PolicyRepository) InsertNewKeys(ctx context.Context, keys []entities.Key) error {
+	if repo.database == nil {
+		return cerrors.ErrInvalidDatabaseClient
+	}
+
+	_, err := repo.database.NewInsert().Model(&keys).Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (repo *PolicyRepository) CheckKeysExist(ctx context.Context, keys []string) (bool, error) {
+	if repo.database == nil {
+		return false, cerrors.ErrInvalidDatabaseClient
+	}
+
+	exists, err := repo.database.NewSelect().Model(new(entities.Key)).Where("key IN (?)", bun.In(keys)).Exists(ctx)
+	if err != nil {
+		return exists, err
+	}
+	if exists {
+		return exists, nil
+	}
+
+	exists, err = repo.database.NewSelect().Model(new(entities.License)).Where("key IN (?)", bun.In(keys)).Exists(ctx)
+	if err != nil {
+		return exists, err
+	}
+
+	return exists, nil
+}
+
+func (repo *PolicyRepository) SelectKeys(ctx context.Context, policyID uuid.UUID, queryParam constants.QueryCommonParam) ([]entities.Key, int, error) {
+	var total int
+
+	if repo.database == nil {
+		return nil, total, cerrors.ErrInvalidDatabaseClient
+	}
+
+	keys := make([]entities.Key, 0)
+	total, err := repo.database.NewSelect().Model(new(entities.Key)).
+		Where("policy_id = ?", policyID).
+		Limit(utils.DerefPointer(queryParam.Limit)).
+		Offset(utils.DerefPointer(queryParam.Offset)).
+		Order("created_at ASC").
+		ScanAndCount(ctx, &keys)
+	if err != nil {
+		return keys, total, err
+	}
+	return keys, total, err
+}
+
+// RotatePolicyKeys records the previous key pair of the policy in its key history and stores its new key pair.
+func (repo *PolicyRepository) RotatePolicyKeys(ctx context.Context, policy *entities.Policy, previousKey *entities.PolicyKey) error {
+	if repo.database == nil {
+		return cerrors.ErrInvalidDatabaseClient
+	}
+
+	privateKey, err := envelope.GetInstance().Seal(policy.PrivateKey, envelope.PolicyKeyAssociatedData(policy.ID.String()))
+	if err != nil {
+		return err
+	}
+	policy.PrivateKey = privateKey
+
+	tx, err := repo.database.BeginTx(ctx, &sql.TxOptions{})
+	if err != nil {
+		return err
+	}
+	defer func() {
+		cErr := tx.Commit()
+		if cErr != nil && err == nil {
+			err = cErr
+		}
+	}()
+
+	_, err = tx.NewInsert().Model(previousKey).Exec(ctx)
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	policy.UpdatedAt = time.Now()
+	_, err = tx.NewUpdate().Model(policy).WherePK().Exec(ctx)
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return nil
+}
+
+// SelectPolicyKeys returns the key history of the policy, the most recently rotated keys first.
+func (repo *PolicyRepository) SelectPolicyKeys(ctx context.Context, policyID uuid.UUID) ([]entities.PolicyKey, error) {
+	if repo.database == nil {
+		return nil, cerrors.ErrInvalidDatabaseClient
+	}
+
+	keys := make([]entities.PolicyKey, 0)
+	err := repo.database.NewSelect().Model(&keys).
+		Where("policy_id = ?", policyID).
+		Order("created_at DESC").
+		Scan(ctx)
+	if err != nil {
+		return keys, err
+	}
+
+	return keys, nil
+}
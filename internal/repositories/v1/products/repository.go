@@ -70,8 +70,12 @@ func (repo *ProductRepository) SelectProducts(ctx context.Context, tenantName st
 	}
 
 	products := make([]entities.Product, 0)
-	total, err := repo.database.NewSelect().Model(new(entities.Product)).
-		Where("tenant_name = ?", tenantName).
+	query := repo.database.NewSelect().Model(new(entities.Product)).
+		Where("tenant_name = ?", tenantName)
+	if queryParam.ProductID != nil {
+		query = query.Where("id = ?", utils.DerefPointer(queryParam.ProductID))
+	}
+	total, err := query.
 		Order("created_at DESC").
 		Limit(utils.DerefPointer(queryParam.Limit)).
 		Offset(utils.DerefPointer(queryParam.Offset)).
@@ -2,10 +2,12 @@ package tenants
 
 import (
 	"context"
+	"database/sql"
 	"github.com/uptrace/bun"
 	"go-license-management/internal/cerrors"
 	"go-license-management/internal/constants"
 	"go-license-management/internal/infrastructure/database/entities"
+	"go-license-management/internal/infrastructure/envelope"
 	"go-license-management/internal/utils"
 	"go-license-management/server/api"
 	"time"
@@ -74,7 +76,14 @@ func (repo *TenantRepository) InsertNewTenant(ctx context.Context, tenant *entit
 		return cerrors.ErrInvalidDatabaseClient
 	}
 
-	_, err := repo.database.NewInsert().Model(tenant).Exec(ctx)
+	// The private key is sealed with the key encryption key before being stored
+	var err error
+	tenant.Ed25519PrivateKey, err = envelope.GetInstance().Seal(tenant.Ed25519PrivateKey, envelope.TenantKeyAssociatedData(tenant.Name))
+	if err != nil {
+		return err
+	}
+
+	_, err = repo.database.NewInsert().Model(tenant).Exec(ctx)
 	if err != nil {
 		return err
 	}
@@ -100,10 +109,79 @@ func (repo *TenantRepository) UpdateTenantByPK(ctx context.Context, tenant *enti
 		return tenant, cerrors.ErrInvalidDatabaseClient
 	}
 
+	var err error
+	tenant.Ed25519PrivateKey, err = envelope.GetInstance().Seal(tenant.Ed25519PrivateKey, envelope.TenantKeyAssociatedData(tenant.Name))
+	if err != nil {
+		return tenant, err
+	}
+
 	tenant.UpdatedAt = time.Now()
-	_, err := repo.database.NewUpdate().Model(tenant).WherePK().Exec(ctx)
+	_, err = repo.database.NewUpdate().Model(tenant).WherePK().Exec(ctx)
 	if err != nil {
 		return tenant, err
 	}
 	return tenant, nil
 }
+
+// RegenerateTenantKeys stores the new key pair of the tenant. The previous key is added to the key ring of the tenant,
+// which only keeps the most recent unexpired keys. Without a previous key, the key ring is cleared.
+func (repo *TenantRepository) RegenerateTenantKeys(ctx context.Context, tenant *entities.Tenant, previousKey *entities.TenantKey) (*entities.Tenant, error) {
+	if repo.database == nil {
+		return tenant, cerrors.ErrInvalidDatabaseClient
+	}
+
+	privateKey, err := envelope.GetInstance().Seal(tenant.Ed25519PrivateKey, envelope.TenantKeyAssociatedData(tenant.Name))
+	if err != nil {
+		return tenant, err
+	}
+	tenant.Ed25519PrivateKey = privateKey
+
+	tx, err := repo.database.BeginTx(ctx, &sql.TxOptions{})
+	if err != nil {
+		return tenant, err
+	}
+	defer func() {
+		cErr := tx.Commit()
+		if cErr != nil && err == nil {
+			err = cErr
+		}
+	}()
+
+	if previousKey == nil {
+		_, err = tx.NewDelete().Model((*entities.TenantKey)(nil)).Where("tenant_name = ?", tenant.Name).Exec(ctx)
+		if err != nil {
+			_ = tx.Rollback()
+			return tenant, err
+		}
+	} else {
+		_, err = tx.NewInsert().Model(previousKey).Exec(ctx)
+		if err != nil {
+			_ = tx.Rollback()
+			return tenant, err
+		}
+
+		recentKeys := tx.NewSelect().Model((*entities.TenantKey)(nil)).
+			Column("id").
+			Where("tenant_name = ?", tenant.Name).
+			Where("expires_at > ?", time.Now()).
+			Order("created_at DESC").
+			Limit(constants.MaxTenantKeyRingSize)
+		_, err = tx.NewDelete().Model((*entities.TenantKey)(nil)).
+			Where("tenant_name = ?", tenant.Name).
+			Where("id NOT IN (?)", recentKeys).
+			Exec(ctx)
+		if err != nil {
+			_ = tx.Rollback()
+			return tenant, err
+		}
+	}
+
+	tenant.UpdatedAt = time.Now()
+	_, err = tx.NewUpdate().Model(tenant).WherePK().Exec(ctx)
+	if err != nil {
+		_ = tx.Rollback()
+		return tenant, err
+	}
+
+	return tenant, nil
+}
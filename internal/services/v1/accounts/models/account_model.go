@@ -104,3 +104,23 @@ type AccountActionInput struct {
 type AccountActionGenerateResetTokenOutput struct {
 	ResetToken string `json:"reset_token"`
 }
+
+type AccountLicenseListInput struct {
+	TracerCtx context.Context
+	Tracer    trace.Tracer
+	account_attribute.AccountCommonURI
+	constants.QueryCommonParam
+}
+
+type AccountLicenseOutput struct {
+	LicenseID  string                 `json:"license_id"`
+	ProductID  string                 `json:"product_id"`
+	PolicyID   string                 `json:"policy_id"`
+	Name       string                 `json:"name"`
+	LicenseKey string                 `json:"license_key"`
+	Status     string                 `json:"status"`
+	Metadata   map[string]interface{} `json:"metadata"`
+	Expiry     time.Time              `json:"expiry"`
+	CreatedAt  time.Time              `json:"created_at"`
+	UpdatedAt  time.Time              `json:"updated_at"`
+}
@@ -15,4 +15,7 @@ type IAccount interface {
 	CheckAccountExistByPK(ctx context.Context, tenantName, username string) (bool, error)
 	CheckAccountEmailExistByPK(ctx context.Context, tenantName, email string) (bool, error)
 	DeleteAccountByPK(ctx context.Context, tenantName, username string) error
+	RevokeAccountSessions(ctx context.Context, revokedToken *entities.RevokedToken) error
+	DeleteAccountLockout(ctx context.Context, tenantName, username string) error
+	SelectLicensesByUsername(ctx context.Context, tenantName, username string, queryParam constants.QueryCommonParam) ([]entities.License, int, error)
 }
@@ -19,13 +19,16 @@ import (
 )
 
 type AccountService struct {
-	repo   repository.IAccount
-	casbin *xormadapter.Adapter
-	logger *logging.Logger
+	repo           repository.IAccount
+	casbin         *xormadapter.Adapter
+	accessTokenTTL utils.AccessTokenTTL
+	logger         *logging.Logger
 }
 
 func NewAccountService(options ...func(*AccountService)) *AccountService {
-	svc := &AccountService{}
+	svc := &AccountService{
+		accessTokenTTL: utils.AccessTokenTTL{Default: constants.JWTDuration * time.Second},
+	}
 
 	for _, opt := range options {
 		opt(svc)
@@ -48,6 +51,17 @@ func WithCasbinAdapter(casbinAdapter *xormadapter.Adapter) func(*AccountService)
 	}
 }
 
+// WithAccessTokenTTL sets the lifetimes of the access tokens, which bound how long the revocations of the sessions
+// of an account are kept.
+func WithAccessTokenTTL(ttl utils.AccessTokenTTL) func(*AccountService) {
+	return func(c *AccountService) {
+		if ttl.Default <= 0 {
+			ttl.Default = c.accessTokenTTL.Default
+		}
+		c.accessTokenTTL = ttl
+	}
+}
+
 // Create creates new user
 func (svc *AccountService) Create(ctx *gin.Context, input *models.AccountRegistrationInput) (*response.BaseOutput, error) {
 	rootCtx, span := input.Tracer.Start(input.TracerCtx, "create-handler")
@@ -344,6 +358,18 @@ func (svc *AccountService) Delete(ctx *gin.Context, input *models.AccountDeletio
 	}
 	cSpan.End()
 
+	// The tokens issued to the deleted account must not outlive it
+	_, cSpan = input.Tracer.Start(rootCtx, "revoke-account-sessions")
+	err = svc.revokeSessions(ctx, tenant.Name, utils.DerefPointer(input.Username))
+	if err != nil {
+		svc.logger.GetLogger().Error(err.Error())
+		cSpan.End()
+		resp.Code = cerrors.ErrCodeMapper[cerrors.ErrGenericInternalServer]
+		resp.Message = cerrors.ErrMessageMapper[cerrors.ErrGenericInternalServer]
+		return resp, cerrors.ErrGenericInternalServer
+	}
+	cSpan.End()
+
 	// Remove user policy from casbin
 	_, cSpan = input.Tracer.Start(rootCtx, "delete-account-casbin")
 	err = svc.casbin.RemovePolicy("g", "g", []string{utils.DerefPointer(input.TenantName), utils.DerefPointer(input.Username)})
@@ -556,6 +582,24 @@ func (svc *AccountService) Action(ctx *gin.Context, input *models.AccountActionI
 			resp.Message = cerrors.ErrMessageMapper[cerrors.ErrGenericInternalServer]
 			return resp, cerrors.ErrGenericInternalServer
 		}
+	case constants.AccountActionRevokeSessions:
+		output, err = svc.actionRevokeSessions(ctx, account)
+		if err != nil {
+			cSpan.End()
+			svc.logger.GetLogger().Error(err.Error())
+			resp.Code = cerrors.ErrCodeMapper[cerrors.ErrGenericInternalServer]
+			resp.Message = cerrors.ErrMessageMapper[cerrors.ErrGenericInternalServer]
+			return resp, cerrors.ErrGenericInternalServer
+		}
+	case constants.AccountActionUnlock:
+		output, err = svc.actionUnlock(ctx, account)
+		if err != nil {
+			cSpan.End()
+			svc.logger.GetLogger().Error(err.Error())
+			resp.Code = cerrors.ErrCodeMapper[cerrors.ErrGenericInternalServer]
+			resp.Message = cerrors.ErrMessageMapper[cerrors.ErrGenericInternalServer]
+			return resp, cerrors.ErrGenericInternalServer
+		}
 	case constants.AccountActionGenerateResetToken:
 		output, err = svc.actionGenerateResetToken(ctx, account)
 		if err != nil {
@@ -624,3 +668,85 @@ func (svc *AccountService) Action(ctx *gin.Context, input *models.AccountActionI
 	resp.Message = cerrors.ErrMessageMapper[nil]
 	return resp, nil
 }
+
+// ListLicenses lists the licenses the account is attached to.
+func (svc *AccountService) ListLicenses(ctx *gin.Context, input *models.AccountLicenseListInput) (*response.BaseOutput, error) {
+	rootCtx, span := input.Tracer.Start(input.TracerCtx, "list-licenses-handler")
+	defer span.End()
+
+	resp := &response.BaseOutput{}
+	svc.logger.WithCustomFields(
+		zap.String(constants.RequestIDField, ctx.GetString(constants.RequestIDField)),
+		zap.String(constants.ContextValueSubject, ctx.GetString(constants.ContextValueSubject)),
+	)
+
+	_, cSpan := input.Tracer.Start(rootCtx, "query-tenant-by-name")
+	svc.logger.GetLogger().Info(fmt.Sprintf("checking existing tenant [%s]", utils.DerefPointer(input.TenantName)))
+	tenant, err := svc.repo.SelectTenantByPK(ctx, utils.DerefPointer(input.TenantName))
+	if err != nil {
+		svc.logger.GetLogger().Error(err.Error())
+		cSpan.End()
+		if errors.Is(err, sql.ErrNoRows) {
+			resp.Code = cerrors.ErrCodeMapper[cerrors.ErrTenantNameIsInvalid]
+			resp.Message = cerrors.ErrMessageMapper[cerrors.ErrTenantNameIsInvalid]
+			return resp, cerrors.ErrTenantNameIsInvalid
+		} else {
+			resp.Code = cerrors.ErrCodeMapper[cerrors.ErrGenericInternalServer]
+			resp.Message = cerrors.ErrMessageMapper[cerrors.ErrGenericInternalServer]
+			return resp, cerrors.ErrGenericInternalServer
+		}
+	}
+	cSpan.End()
+
+	_, cSpan = input.Tracer.Start(rootCtx, "query-account-by-name")
+	exists, err := svc.repo.CheckAccountExistByPK(ctx, tenant.Name, utils.DerefPointer(input.Username))
+	if err != nil {
+		svc.logger.GetLogger().Error(err.Error())
+		cSpan.End()
+		resp.Code = cerrors.ErrCodeMapper[cerrors.ErrGenericInternalServer]
+		resp.Message = cerrors.ErrMessageMapper[cerrors.ErrGenericInternalServer]
+		return resp, cerrors.ErrGenericInternalServer
+	}
+	cSpan.End()
+
+	if !exists {
+		svc.logger.GetLogger().Info(fmt.Sprintf("username [%s] does not exist in tenant [%s]", utils.DerefPointer(input.Username), tenant.Name))
+		resp.Code = cerrors.ErrCodeMapper[cerrors.ErrAccountUsernameIsInvalid]
+		resp.Message = cerrors.ErrMessageMapper[cerrors.ErrAccountUsernameIsInvalid]
+		return resp, cerrors.ErrAccountUsernameIsInvalid
+	}
+
+	_, cSpan = input.Tracer.Start(rootCtx, "select-account-licenses")
+	licenses, count, err := svc.repo.SelectLicensesByUsername(ctx, tenant.Name, utils.DerefPointer(input.Username), input.QueryCommonParam)
+	if err != nil {
+		svc.logger.GetLogger().Error(err.Error())
+		cSpan.End()
+		resp.Code = cerrors.ErrCodeMapper[cerrors.ErrGenericInternalServer]
+		resp.Message = cerrors.ErrMessageMapper[cerrors.ErrGenericInternalServer]
+		return resp, cerrors.ErrGenericInternalServer
+	}
+	cSpan.End()
+
+	respData := make([]models.AccountLicenseOutput, 0)
+	for _, license := range licenses {
+		respData = append(respData, models.AccountLicenseOutput{
+			LicenseID:  license.ID.String(),
+			ProductID:  license.ProductID.String(),
+			PolicyID:   license.PolicyID.String(),
+			Name:       license.Name,
+			LicenseKey: license.Key,
+			Status:     license.Status,
+			Metadata:   license.Metadata,
+			Expiry:     license.Expiry,
+			CreatedAt:  license.CreatedAt,
+			UpdatedAt:  license.UpdatedAt,
+		})
+	}
+
+	resp.Code = cerrors.ErrCodeMapper[nil]
+	resp.Message = cerrors.ErrMessageMapper[nil]
+	resp.Count = count
+	resp.Data = respData
+
+	return resp, nil
+}
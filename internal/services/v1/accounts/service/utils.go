@@ -3,6 +3,7 @@ package service
 import (
 	"fmt"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"go-license-management/internal/cerrors"
 	"go-license-management/internal/constants"
 	"go-license-management/internal/infrastructure/database/entities"
@@ -20,6 +21,12 @@ func (svc *AccountService) actionBan(ctx *gin.Context, account *entities.Account
 		return account, err
 	}
 
+	// A banned account is logged out of every session
+	err = svc.revokeSessions(ctx, account.TenantName, account.Username)
+	if err != nil {
+		return account, err
+	}
+
 	return account, nil
 }
 
@@ -36,6 +43,26 @@ func (svc *AccountService) actionUnban(ctx *gin.Context, account *entities.Accou
 	return account, nil
 }
 
+func (svc *AccountService) actionRevokeSessions(ctx *gin.Context, account *entities.Account) (*entities.Account, error) {
+	svc.logger.GetLogger().Info(fmt.Sprintf("revoking sessions of account [%s] in tenant [%s]", account.Username, account.TenantName))
+	err := svc.revokeSessions(ctx, account.TenantName, account.Username)
+	if err != nil {
+		return account, err
+	}
+
+	return account, nil
+}
+
+func (svc *AccountService) actionUnlock(ctx *gin.Context, account *entities.Account) (*entities.Account, error) {
+	svc.logger.GetLogger().Info(fmt.Sprintf("unlocking account [%s] in tenant [%s]", account.Username, account.TenantName))
+	err := svc.repo.DeleteAccountLockout(ctx, account.TenantName, account.Username)
+	if err != nil {
+		return account, err
+	}
+
+	return account, nil
+}
+
 func (svc *AccountService) actionGenerateResetToken(ctx *gin.Context, account *entities.Account) (*entities.Account, error) {
 	svc.logger.GetLogger().Info(fmt.Sprintf("generate reset token for account [%s] in tenant [%s]", account.Username, account.TenantName))
 
@@ -93,3 +120,18 @@ func (svc *AccountService) actionUpdatePassword(ctx *gin.Context, currentPass, n
 	return account, nil
 
 }
+
+// revokeSessions revokes every access token issued to the account so far, along with its refresh tokens.
+// The revocation is kept until the longest lived access token of the tenant has expired. It is stored at second
+// precision, as the issue time of the access tokens it is compared with, so tokens issued in the same second are
+// revoked too.
+func (svc *AccountService) revokeSessions(ctx *gin.Context, tenantName, username string) error {
+	now := time.Now().Truncate(time.Second)
+	return svc.repo.RevokeAccountSessions(ctx, &entities.RevokedToken{
+		ID:         uuid.New(),
+		TenantName: tenantName,
+		Username:   username,
+		ExpiresAt:  now.Add(svc.accessTokenTTL.Longest(tenantName)),
+		CreatedAt:  now,
+	})
+}
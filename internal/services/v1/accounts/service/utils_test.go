@@ -0,0 +1,66 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go-license-management/internal/infrastructure/database/entities"
+	"go-license-management/internal/services/v1/accounts/repository"
+	"go-license-management/internal/utils"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// fakeAccountRepository records the revocations of the sessions, the other methods are not implemented.
+type fakeAccountRepository struct {
+	repository.IAccount
+	revokedTokens []*entities.RevokedToken
+	err           error
+}
+
+func (repo *fakeAccountRepository) RevokeAccountSessions(ctx context.Context, revokedToken *entities.RevokedToken) error {
+	if repo.err != nil {
+		return repo.err
+	}
+	repo.revokedTokens = append(repo.revokedTokens, revokedToken)
+	return nil
+}
+
+func TestAccountService_RevokeSessions(t *testing.T) {
+	repo := &fakeAccountRepository{}
+	svc := NewAccountService(
+		WithRepository(repo),
+		WithAccessTokenTTL(utils.AccessTokenTTL{
+			Default: time.Hour,
+			Roles:   map[string]time.Duration{"admin": 2 * time.Hour},
+		}),
+	)
+	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+
+	before := time.Now()
+	err := svc.revokeSessions(ctx, "tenant", "user")
+	assert.NoError(t, err)
+	assert.Len(t, repo.revokedTokens, 1)
+
+	revokedToken := repo.revokedTokens[0]
+	assert.NotEmpty(t, revokedToken.ID)
+	assert.Empty(t, revokedToken.JTI)
+	assert.Equal(t, "tenant", revokedToken.TenantName)
+	assert.Equal(t, "user", revokedToken.Username)
+
+	// The revocation is at the precision of the issue time of the access tokens, and revokes the tokens issued up to
+	// it, including in the same second
+	assert.Zero(t, revokedToken.CreatedAt.Nanosecond())
+	assert.WithinDuration(t, before, revokedToken.CreatedAt, time.Second)
+	issuedAt := before.Truncate(time.Second)
+	assert.False(t, issuedAt.After(revokedToken.CreatedAt))
+
+	// The revocation is kept until the longest lived access token has expired
+	assert.Equal(t, revokedToken.CreatedAt.Add(2*time.Hour), revokedToken.ExpiresAt)
+
+	repo.err = errors.New("database is down")
+	err = svc.revokeSessions(ctx, "tenant", "user")
+	assert.ErrorIs(t, err, repo.err)
+}
@@ -15,6 +15,22 @@ type AuthenticationLoginInput struct {
 }
 
 type AuthenticationLoginOutput struct {
-	Access   string `json:"access"`
-	ExpireAt int64  `json:"expire_at"`
+	Access          string `json:"access"`
+	ExpireAt        int64  `json:"expire_at"`
+	Refresh         string `json:"refresh"`
+	RefreshExpireAt int64  `json:"refresh_expire_at"`
+}
+
+type AuthenticationRefreshInput struct {
+	TracerCtx context.Context
+	Tracer    trace.Tracer
+	authentication_attribute.AuthenticationCommonURI
+	Refresh *string `json:"refresh" validate:"required"`
+}
+
+type AuthenticationLogoutInput struct {
+	TracerCtx context.Context
+	Tracer    trace.Tracer
+	authentication_attribute.AuthenticationCommonURI
+	Refresh *string `json:"refresh" validate:"optional"`
 }
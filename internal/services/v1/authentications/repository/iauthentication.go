@@ -2,11 +2,25 @@ package repository
 
 import (
 	"context"
+	"github.com/google/uuid"
 	"go-license-management/internal/infrastructure/database/entities"
+	"time"
 )
 
 type IAuthentication interface {
 	SelectTenantByPK(ctx context.Context, tenantName string) (*entities.Tenant, error)
 	SelectAccountByPK(ctx context.Context, tenantName, username string) (*entities.Account, error)
 	SelectMasterByPK(ctx context.Context, username string) (*entities.Master, error)
+	InsertNewRefreshToken(ctx context.Context, refreshToken *entities.RefreshToken) error
+	SelectRefreshTokenByHash(ctx context.Context, tokenHash string) (*entities.RefreshToken, error)
+	RotateRefreshToken(ctx context.Context, usedToken, nextToken *entities.RefreshToken) error
+	DeleteRefreshTokenFamily(ctx context.Context, familyID uuid.UUID) error
+	InsertNewRevokedToken(ctx context.Context, revokedToken *entities.RevokedToken) error
+	DeleteExpiredRevokedTokens(ctx context.Context, now time.Time) (int64, error)
+	SelectAccountLockout(ctx context.Context, tenantName, username string) (*entities.AccountLockout, error)
+	UpsertAccountLockout(ctx context.Context, lockout *entities.AccountLockout) error
+	InsertNewLoginFailure(ctx context.Context, failure *entities.LoginFailure, since time.Time) (int, error)
+	CountLoginFailuresByIP(ctx context.Context, ip string, since time.Time) (int, error)
+	DeleteLoginFailuresByAccount(ctx context.Context, tenantName, username string) error
+	DeleteExpiredLoginFailures(ctx context.Context, before time.Time) (int64, error)
 }
@@ -3,25 +3,40 @@ package service
 import (
 	"database/sql"
 	"errors"
+	"fmt"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"go-license-management/internal/cerrors"
 	"go-license-management/internal/config"
 	"go-license-management/internal/constants"
+	"go-license-management/internal/infrastructure/database/entities"
 	"go-license-management/internal/infrastructure/logging"
 	"go-license-management/internal/response"
 	"go-license-management/internal/services/v1/authentications/models"
 	"go-license-management/internal/services/v1/authentications/repository"
 	"go-license-management/internal/utils"
 	"go.uber.org/zap"
+	"time"
 )
 
 type AuthenticationService struct {
-	repo   repository.IAuthentication
-	logger *logging.Logger
+	repo                 repository.IAuthentication
+	accessTokenTTL       utils.AccessTokenTTL
+	refreshTokenTTL      time.Duration
+	maxFailedLogins      int
+	maxFailedLoginsPerIP int
+	lockoutDuration      time.Duration
+	logger               *logging.Logger
 }
 
 func NewAuthenticationService(options ...func(*AuthenticationService)) *AuthenticationService {
-	svc := &AuthenticationService{}
+	svc := &AuthenticationService{
+		accessTokenTTL:       utils.AccessTokenTTL{Default: constants.JWTDuration * time.Second},
+		refreshTokenTTL:      constants.RefreshTokenDuration * time.Second,
+		maxFailedLogins:      constants.DefaultLoginMaxFailedAttempts,
+		maxFailedLoginsPerIP: constants.DefaultLoginMaxFailedAttemptsPerIP,
+		lockoutDuration:      constants.DefaultLoginLockoutDuration * time.Second,
+	}
 
 	for _, opt := range options {
 		opt(svc)
@@ -38,6 +53,41 @@ func WithRepository(repo repository.IAuthentication) func(*AuthenticationService
 	}
 }
 
+// WithAccessTokenTTL sets the lifetimes of the access tokens, per tenant and per role.
+func WithAccessTokenTTL(ttl utils.AccessTokenTTL) func(*AuthenticationService) {
+	return func(c *AuthenticationService) {
+		if ttl.Default <= 0 {
+			ttl.Default = c.accessTokenTTL.Default
+		}
+		c.accessTokenTTL = ttl
+	}
+}
+
+// WithRefreshTokenTTL sets the lifetime of the refresh tokens.
+func WithRefreshTokenTTL(ttl time.Duration) func(*AuthenticationService) {
+	return func(c *AuthenticationService) {
+		if ttl > 0 {
+			c.refreshTokenTTL = ttl
+		}
+	}
+}
+
+// WithLoginLockout sets the number of failed logins after which an account is locked and the logins of an IP are
+// throttled, and the duration of the lockouts. Failed logins are counted within the lockout duration.
+func WithLoginLockout(maxFailedLogins, maxFailedLoginsPerIP int, lockoutDuration time.Duration) func(*AuthenticationService) {
+	return func(c *AuthenticationService) {
+		if maxFailedLogins > 0 {
+			c.maxFailedLogins = maxFailedLogins
+		}
+		if maxFailedLoginsPerIP > 0 {
+			c.maxFailedLoginsPerIP = maxFailedLoginsPerIP
+		}
+		if lockoutDuration > 0 {
+			c.lockoutDuration = lockoutDuration
+		}
+	}
+}
+
 // Login handles the login logic.
 func (svc *AuthenticationService) Login(ctx *gin.Context, input *models.AuthenticationLoginInput) (*response.BaseOutput, error) {
 	rootCtx, span := input.Tracer.Start(input.TracerCtx, "create-handler")
@@ -48,10 +98,30 @@ func (svc *AuthenticationService) Login(ctx *gin.Context, input *models.Authenti
 
 	var token string
 	var exp int64
+	var tenantName, username string
 
 	// Login admin
 	if utils.DerefPointer(input.Username) == config.SuperAdminUsername {
-		_, cSpan := input.Tracer.Start(rootCtx, "query-tenant-by-name")
+		_, cSpan := input.Tracer.Start(rootCtx, "check-login-lockout")
+		err := svc.checkLoginLockout(ctx, "", utils.DerefPointer(input.Username))
+		if err != nil {
+			svc.logger.GetLogger().Error(err.Error())
+			cSpan.End()
+			switch {
+			case errors.Is(err, cerrors.ErrAccountIsLocked),
+				errors.Is(err, cerrors.ErrGenericTooManyRequests):
+				resp.Code = cerrors.ErrCodeMapper[err]
+				resp.Message = cerrors.ErrMessageMapper[err]
+				return resp, err
+			default:
+				resp.Code = cerrors.ErrCodeMapper[cerrors.ErrGenericInternalServer]
+				resp.Message = cerrors.ErrMessageMapper[cerrors.ErrGenericInternalServer]
+				return resp, cerrors.ErrGenericInternalServer
+			}
+		}
+		cSpan.End()
+
+		_, cSpan = input.Tracer.Start(rootCtx, "query-tenant-by-name")
 		master, err := svc.repo.SelectMasterByPK(ctx, utils.DerefPointer(input.Username))
 		if err != nil {
 			svc.logger.GetLogger().Error(err.Error())
@@ -73,9 +143,27 @@ func (svc *AuthenticationService) Login(ctx *gin.Context, input *models.Authenti
 		match := utils.CompareHashedPassword(master.PasswordDigest, utils.DerefPointer(input.Password))
 		if !match {
 			cSpan.End()
-			resp.Code = cerrors.ErrCodeMapper[cerrors.ErrGenericUnauthorized]
-			resp.Message = cerrors.ErrMessageMapper[cerrors.ErrGenericUnauthorized]
-			return resp, cerrors.ErrGenericUnauthorized
+			err = svc.recordLoginFailure(ctx, "", master.Username)
+			resp.Code = cerrors.ErrCodeMapper[err]
+			resp.Message = cerrors.ErrMessageMapper[err]
+			return resp, err
+		}
+		cSpan.End()
+
+		// Concurrent failed logins may have locked the account since it was checked
+		_, cSpan = input.Tracer.Start(rootCtx, "check-account-lockout")
+		err = svc.checkAccountLockout(ctx, "", master.Username)
+		if err != nil {
+			svc.logger.GetLogger().Error(err.Error())
+			cSpan.End()
+			if errors.Is(err, cerrors.ErrAccountIsLocked) {
+				resp.Code = cerrors.ErrCodeMapper[err]
+				resp.Message = cerrors.ErrMessageMapper[err]
+				return resp, err
+			}
+			resp.Code = cerrors.ErrCodeMapper[cerrors.ErrGenericInternalServer]
+			resp.Message = cerrors.ErrMessageMapper[cerrors.ErrGenericInternalServer]
+			return resp, cerrors.ErrGenericInternalServer
 		}
 		cSpan.End()
 
@@ -90,6 +178,7 @@ func (svc *AuthenticationService) Login(ctx *gin.Context, input *models.Authenti
 			return resp, cerrors.ErrGenericInternalServer
 		}
 		cSpan.End()
+		username = master.Username
 
 	} else {
 		// Login user
@@ -110,11 +199,37 @@ func (svc *AuthenticationService) Login(ctx *gin.Context, input *models.Authenti
 		}
 		cSpan.End()
 
+		_, cSpan = input.Tracer.Start(rootCtx, "check-login-lockout")
+		err = svc.checkLoginLockout(ctx, tenant.Name, utils.DerefPointer(input.Username))
+		if err != nil {
+			svc.logger.GetLogger().Error(err.Error())
+			cSpan.End()
+			switch {
+			case errors.Is(err, cerrors.ErrAccountIsLocked),
+				errors.Is(err, cerrors.ErrGenericTooManyRequests):
+				resp.Code = cerrors.ErrCodeMapper[err]
+				resp.Message = cerrors.ErrMessageMapper[err]
+				return resp, err
+			default:
+				resp.Code = cerrors.ErrCodeMapper[cerrors.ErrGenericInternalServer]
+				resp.Message = cerrors.ErrMessageMapper[cerrors.ErrGenericInternalServer]
+				return resp, cerrors.ErrGenericInternalServer
+			}
+		}
+		cSpan.End()
+
 		_, cSpan = input.Tracer.Start(rootCtx, "select-account")
 		account, err := svc.repo.SelectAccountByPK(ctx, tenant.Name, utils.DerefPointer(input.Username))
 		if err != nil {
 			svc.logger.GetLogger().Error(err.Error())
 			cSpan.End()
+			// Unknown usernames count as failed logins, so that they cannot be told apart from wrong passwords
+			if errors.Is(err, sql.ErrNoRows) {
+				err = svc.recordLoginFailure(ctx, tenant.Name, utils.DerefPointer(input.Username))
+				resp.Code = cerrors.ErrCodeMapper[err]
+				resp.Message = cerrors.ErrMessageMapper[err]
+				return resp, err
+			}
 			resp.Code = cerrors.ErrCodeMapper[cerrors.ErrGenericInternalServer]
 			resp.Message = cerrors.ErrMessageMapper[cerrors.ErrGenericInternalServer]
 			return resp, cerrors.ErrGenericInternalServer
@@ -126,9 +241,27 @@ func (svc *AuthenticationService) Login(ctx *gin.Context, input *models.Authenti
 		match := utils.CompareHashedPassword(account.PasswordDigest, utils.DerefPointer(input.Password))
 		if !match {
 			cSpan.End()
-			resp.Code = cerrors.ErrCodeMapper[cerrors.ErrGenericUnauthorized]
-			resp.Message = cerrors.ErrMessageMapper[cerrors.ErrGenericUnauthorized]
-			return resp, cerrors.ErrGenericUnauthorized
+			err = svc.recordLoginFailure(ctx, tenant.Name, account.Username)
+			resp.Code = cerrors.ErrCodeMapper[err]
+			resp.Message = cerrors.ErrMessageMapper[err]
+			return resp, err
+		}
+		cSpan.End()
+
+		// Concurrent failed logins may have locked the account since it was checked
+		_, cSpan = input.Tracer.Start(rootCtx, "check-account-lockout")
+		err = svc.checkAccountLockout(ctx, tenant.Name, account.Username)
+		if err != nil {
+			svc.logger.GetLogger().Error(err.Error())
+			cSpan.End()
+			if errors.Is(err, cerrors.ErrAccountIsLocked) {
+				resp.Code = cerrors.ErrCodeMapper[err]
+				resp.Message = cerrors.ErrMessageMapper[err]
+				return resp, err
+			}
+			resp.Code = cerrors.ErrCodeMapper[cerrors.ErrGenericInternalServer]
+			resp.Message = cerrors.ErrMessageMapper[cerrors.ErrGenericInternalServer]
+			return resp, cerrors.ErrGenericInternalServer
 		}
 		cSpan.End()
 
@@ -156,14 +289,328 @@ func (svc *AuthenticationService) Login(ctx *gin.Context, input *models.Authenti
 			return resp, cerrors.ErrGenericInternalServer
 		}
 		cSpan.End()
+		tenantName = account.TenantName
+		username = account.Username
+	}
+
+	// A successful login clears the failed logins of the account
+	_, cSpan := input.Tracer.Start(rootCtx, "delete-login-failures")
+	err := svc.repo.DeleteLoginFailuresByAccount(ctx, tenantName, username)
+	if err != nil {
+		svc.logger.GetLogger().Error(err.Error())
+		cSpan.End()
+		resp.Code = cerrors.ErrCodeMapper[cerrors.ErrGenericInternalServer]
+		resp.Message = cerrors.ErrMessageMapper[cerrors.ErrGenericInternalServer]
+		return resp, cerrors.ErrGenericInternalServer
+	}
+	cSpan.End()
+
+	// Each login starts a new family of refresh tokens
+	_, cSpan = input.Tracer.Start(rootCtx, "insert-refresh-token")
+	refresh, refreshToken, err := svc.newRefreshToken(uuid.New(), tenantName, username)
+	if err != nil {
+		svc.logger.GetLogger().Error(err.Error())
+		cSpan.End()
+		resp.Code = cerrors.ErrCodeMapper[cerrors.ErrGenericInternalServer]
+		resp.Message = cerrors.ErrMessageMapper[cerrors.ErrGenericInternalServer]
+		return resp, cerrors.ErrGenericInternalServer
+	}
+
+	err = svc.repo.InsertNewRefreshToken(ctx, refreshToken)
+	if err != nil {
+		svc.logger.GetLogger().Error(err.Error())
+		cSpan.End()
+		resp.Code = cerrors.ErrCodeMapper[cerrors.ErrGenericInternalServer]
+		resp.Message = cerrors.ErrMessageMapper[cerrors.ErrGenericInternalServer]
+		return resp, cerrors.ErrGenericInternalServer
+	}
+	cSpan.End()
+
+	resp.Code = cerrors.ErrCodeMapper[nil]
+	resp.Message = cerrors.ErrMessageMapper[nil]
+	resp.Data = models.AuthenticationLoginOutput{
+		Access:          token,
+		ExpireAt:        exp,
+		Refresh:         refresh,
+		RefreshExpireAt: refreshToken.ExpiresAt.Unix(),
+	}
+
+	return resp, nil
+}
+
+// Refresh exchanges a refresh token for a new access token and a new refresh token of the same family.
+// Refresh tokens are single-use: reusing a refresh token revokes every refresh token of its family.
+func (svc *AuthenticationService) Refresh(ctx *gin.Context, input *models.AuthenticationRefreshInput) (*response.BaseOutput, error) {
+	rootCtx, span := input.Tracer.Start(input.TracerCtx, "refresh-handler")
+	defer span.End()
+
+	resp := &response.BaseOutput{}
+	svc.logger.WithCustomFields(zap.String(constants.RequestIDField, ctx.GetString(constants.RequestIDField)))
+
+	_, cSpan := input.Tracer.Start(rootCtx, "query-refresh-token")
+	usedToken, err := svc.repo.SelectRefreshTokenByHash(ctx, utils.HashToken(utils.DerefPointer(input.Refresh)))
+	if err != nil {
+		svc.logger.GetLogger().Error(err.Error())
+		cSpan.End()
+		if errors.Is(err, sql.ErrNoRows) {
+			resp.Code = cerrors.ErrCodeMapper[cerrors.ErrAccountRefreshTokenIsInvalid]
+			resp.Message = cerrors.ErrMessageMapper[cerrors.ErrAccountRefreshTokenIsInvalid]
+			return resp, cerrors.ErrAccountRefreshTokenIsInvalid
+		} else {
+			resp.Code = cerrors.ErrCodeMapper[cerrors.ErrGenericInternalServer]
+			resp.Message = cerrors.ErrMessageMapper[cerrors.ErrGenericInternalServer]
+			return resp, cerrors.ErrGenericInternalServer
+		}
+	}
+	cSpan.End()
+
+	// Refresh tokens of tenant accounts can only be used under their tenant, those of the superadmin under any path
+	if input.TenantName != nil && usedToken.TenantName != "" && usedToken.TenantName != utils.DerefPointer(input.TenantName) {
+		resp.Code = cerrors.ErrCodeMapper[cerrors.ErrAccountRefreshTokenIsInvalid]
+		resp.Message = cerrors.ErrMessageMapper[cerrors.ErrAccountRefreshTokenIsInvalid]
+		return resp, cerrors.ErrAccountRefreshTokenIsInvalid
+	}
+
+	// A used refresh token may have been stolen, every token of its family is revoked
+	if !usedToken.UsedAt.IsZero() {
+		svc.logger.GetLogger().Error(fmt.Sprintf("refresh token [%s] of [%s] has been reused, revoking its family [%s]", usedToken.ID, usedToken.Username, usedToken.FamilyID))
+		_, cSpan = input.Tracer.Start(rootCtx, "delete-refresh-token-family")
+		err = svc.repo.DeleteRefreshTokenFamily(ctx, usedToken.FamilyID)
+		if err != nil {
+			svc.logger.GetLogger().Error(err.Error())
+			cSpan.End()
+			resp.Code = cerrors.ErrCodeMapper[cerrors.ErrGenericInternalServer]
+			resp.Message = cerrors.ErrMessageMapper[cerrors.ErrGenericInternalServer]
+			return resp, cerrors.ErrGenericInternalServer
+		}
+		cSpan.End()
+		resp.Code = cerrors.ErrCodeMapper[cerrors.ErrAccountRefreshTokenIsInvalid]
+		resp.Message = cerrors.ErrMessageMapper[cerrors.ErrAccountRefreshTokenIsInvalid]
+		return resp, cerrors.ErrAccountRefreshTokenIsInvalid
+	}
+
+	if usedToken.ExpiresAt.Before(time.Now()) {
+		resp.Code = cerrors.ErrCodeMapper[cerrors.ErrAccountRefreshTokenIsExpired]
+		resp.Message = cerrors.ErrMessageMapper[cerrors.ErrAccountRefreshTokenIsExpired]
+		return resp, cerrors.ErrAccountRefreshTokenIsExpired
+	}
+
+	var token string
+	var exp int64
+	if usedToken.TenantName == "" {
+		_, cSpan = input.Tracer.Start(rootCtx, "query-master")
+		master, err := svc.repo.SelectMasterByPK(ctx, usedToken.Username)
+		if err != nil {
+			svc.logger.GetLogger().Error(err.Error())
+			cSpan.End()
+			if errors.Is(err, sql.ErrNoRows) {
+				resp.Code = cerrors.ErrCodeMapper[cerrors.ErrAccountRefreshTokenIsInvalid]
+				resp.Message = cerrors.ErrMessageMapper[cerrors.ErrAccountRefreshTokenIsInvalid]
+				return resp, cerrors.ErrAccountRefreshTokenIsInvalid
+			} else {
+				resp.Code = cerrors.ErrCodeMapper[cerrors.ErrGenericInternalServer]
+				resp.Message = cerrors.ErrMessageMapper[cerrors.ErrGenericInternalServer]
+				return resp, cerrors.ErrGenericInternalServer
+			}
+		}
+		cSpan.End()
+
+		_, cSpan = input.Tracer.Start(rootCtx, "generate-master-token")
+		token, exp, err = svc.generateSuperadminJWT(ctx, master)
+		if err != nil {
+			svc.logger.GetLogger().Error(err.Error())
+			cSpan.End()
+			resp.Code = cerrors.ErrCodeMapper[cerrors.ErrGenericInternalServer]
+			resp.Message = cerrors.ErrMessageMapper[cerrors.ErrGenericInternalServer]
+			return resp, cerrors.ErrGenericInternalServer
+		}
+		cSpan.End()
+	} else {
+		_, cSpan = input.Tracer.Start(rootCtx, "query-tenant-by-name")
+		tenant, err := svc.repo.SelectTenantByPK(ctx, usedToken.TenantName)
+		if err != nil {
+			svc.logger.GetLogger().Error(err.Error())
+			cSpan.End()
+			if errors.Is(err, sql.ErrNoRows) {
+				resp.Code = cerrors.ErrCodeMapper[cerrors.ErrAccountRefreshTokenIsInvalid]
+				resp.Message = cerrors.ErrMessageMapper[cerrors.ErrAccountRefreshTokenIsInvalid]
+				return resp, cerrors.ErrAccountRefreshTokenIsInvalid
+			} else {
+				resp.Code = cerrors.ErrCodeMapper[cerrors.ErrGenericInternalServer]
+				resp.Message = cerrors.ErrMessageMapper[cerrors.ErrGenericInternalServer]
+				return resp, cerrors.ErrGenericInternalServer
+			}
+		}
+		cSpan.End()
+
+		// The account is queried again, so that its current role, status and permissions are used
+		_, cSpan = input.Tracer.Start(rootCtx, "select-account")
+		account, err := svc.repo.SelectAccountByPK(ctx, tenant.Name, usedToken.Username)
+		if err != nil {
+			svc.logger.GetLogger().Error(err.Error())
+			cSpan.End()
+			if errors.Is(err, sql.ErrNoRows) {
+				resp.Code = cerrors.ErrCodeMapper[cerrors.ErrAccountRefreshTokenIsInvalid]
+				resp.Message = cerrors.ErrMessageMapper[cerrors.ErrAccountRefreshTokenIsInvalid]
+				return resp, cerrors.ErrAccountRefreshTokenIsInvalid
+			} else {
+				resp.Code = cerrors.ErrCodeMapper[cerrors.ErrGenericInternalServer]
+				resp.Message = cerrors.ErrMessageMapper[cerrors.ErrGenericInternalServer]
+				return resp, cerrors.ErrGenericInternalServer
+			}
+		}
+		cSpan.End()
+
+		if account.Status == constants.AccountStatusInactive {
+			resp.Code = cerrors.ErrCodeMapper[cerrors.ErrAccountIsInactive]
+			resp.Message = cerrors.ErrMessageMapper[cerrors.ErrAccountIsInactive]
+			return resp, cerrors.ErrAccountIsInactive
+		}
+
+		if account.Status == constants.AccountStatusBanned {
+			resp.Code = cerrors.ErrCodeMapper[cerrors.ErrAccountIsBanned]
+			resp.Message = cerrors.ErrMessageMapper[cerrors.ErrAccountIsBanned]
+			return resp, cerrors.ErrAccountIsBanned
+		}
+
+		_, cSpan = input.Tracer.Start(rootCtx, "generate-account-token")
+		token, exp, err = svc.generateJWT(ctx, tenant, account)
+		if err != nil {
+			svc.logger.GetLogger().Error(err.Error())
+			cSpan.End()
+			resp.Code = cerrors.ErrCodeMapper[cerrors.ErrGenericInternalServer]
+			resp.Message = cerrors.ErrMessageMapper[cerrors.ErrGenericInternalServer]
+			return resp, cerrors.ErrGenericInternalServer
+		}
+		cSpan.End()
 	}
 
+	// rotate the refresh token
+	_, cSpan = input.Tracer.Start(rootCtx, "rotate-refresh-token")
+	refresh, nextToken, err := svc.newRefreshToken(usedToken.FamilyID, usedToken.TenantName, usedToken.Username)
+	if err != nil {
+		svc.logger.GetLogger().Error(err.Error())
+		cSpan.End()
+		resp.Code = cerrors.ErrCodeMapper[cerrors.ErrGenericInternalServer]
+		resp.Message = cerrors.ErrMessageMapper[cerrors.ErrGenericInternalServer]
+		return resp, cerrors.ErrGenericInternalServer
+	}
+
+	err = svc.repo.RotateRefreshToken(ctx, usedToken, nextToken)
+	if err != nil {
+		svc.logger.GetLogger().Error(err.Error())
+		cSpan.End()
+		switch {
+		case errors.Is(err, cerrors.ErrAccountRefreshTokenIsInvalid):
+			resp.Code = cerrors.ErrCodeMapper[cerrors.ErrAccountRefreshTokenIsInvalid]
+			resp.Message = cerrors.ErrMessageMapper[cerrors.ErrAccountRefreshTokenIsInvalid]
+			return resp, cerrors.ErrAccountRefreshTokenIsInvalid
+		default:
+			resp.Code = cerrors.ErrCodeMapper[cerrors.ErrGenericInternalServer]
+			resp.Message = cerrors.ErrMessageMapper[cerrors.ErrGenericInternalServer]
+			return resp, cerrors.ErrGenericInternalServer
+		}
+	}
+	cSpan.End()
+
 	resp.Code = cerrors.ErrCodeMapper[nil]
 	resp.Message = cerrors.ErrMessageMapper[nil]
 	resp.Data = models.AuthenticationLoginOutput{
-		Access:   token,
-		ExpireAt: exp,
+		Access:          token,
+		ExpireAt:        exp,
+		Refresh:         refresh,
+		RefreshExpireAt: nextToken.ExpiresAt.Unix(),
 	}
 
 	return resp, nil
 }
+
+// Logout revokes the access token of the request until it expires. When the refresh token of the session is given,
+// every refresh token of its family is revoked as well.
+func (svc *AuthenticationService) Logout(ctx *gin.Context, input *models.AuthenticationLogoutInput) (*response.BaseOutput, error) {
+	rootCtx, span := input.Tracer.Start(input.TracerCtx, "logout-handler")
+	defer span.End()
+
+	resp := &response.BaseOutput{}
+	svc.logger.WithCustomFields(
+		zap.String(constants.RequestIDField, ctx.GetString(constants.RequestIDField)),
+		zap.String(constants.ContextValueSubject, ctx.GetString(constants.ContextValueSubject)),
+	)
+
+	tenantName := ctx.GetString(constants.ContextValueTenant)
+	username := ctx.GetString(constants.ContextValueSubject)
+
+	// The refresh token must belong to the subject of the access token
+	var refreshToken *entities.RefreshToken
+	if input.Refresh != nil {
+		_, cSpan := input.Tracer.Start(rootCtx, "query-refresh-token")
+		token, err := svc.repo.SelectRefreshTokenByHash(ctx, utils.HashToken(utils.DerefPointer(input.Refresh)))
+		if err != nil {
+			svc.logger.GetLogger().Error(err.Error())
+			cSpan.End()
+			if errors.Is(err, sql.ErrNoRows) {
+				resp.Code = cerrors.ErrCodeMapper[cerrors.ErrAccountRefreshTokenIsInvalid]
+				resp.Message = cerrors.ErrMessageMapper[cerrors.ErrAccountRefreshTokenIsInvalid]
+				return resp, cerrors.ErrAccountRefreshTokenIsInvalid
+			} else {
+				resp.Code = cerrors.ErrCodeMapper[cerrors.ErrGenericInternalServer]
+				resp.Message = cerrors.ErrMessageMapper[cerrors.ErrGenericInternalServer]
+				return resp, cerrors.ErrGenericInternalServer
+			}
+		}
+		cSpan.End()
+
+		// Refresh tokens of the superadmin have no tenant, while its access tokens are valid for every tenant
+		if token.Username != username || (token.TenantName != tenantName && !(token.TenantName == "" && tenantName == "*")) {
+			svc.logger.GetLogger().Error(fmt.Sprintf("refresh token [%s] does not belong to [%s]", token.ID, username))
+			resp.Code = cerrors.ErrCodeMapper[cerrors.ErrAccountRefreshTokenIsInvalid]
+			resp.Message = cerrors.ErrMessageMapper[cerrors.ErrAccountRefreshTokenIsInvalid]
+			return resp, cerrors.ErrAccountRefreshTokenIsInvalid
+		}
+		refreshToken = token
+	}
+
+	_, cSpan := input.Tracer.Start(rootCtx, "insert-revoked-token")
+	now := time.Now()
+	err := svc.repo.InsertNewRevokedToken(ctx, &entities.RevokedToken{
+		ID:         uuid.New(),
+		JTI:        ctx.GetString(constants.ContextValueTokenID),
+		TenantName: tenantName,
+		Username:   username,
+		ExpiresAt:  ctx.GetTime(constants.ContextValueTokenExpiry),
+		CreatedAt:  now,
+	})
+	if err != nil {
+		svc.logger.GetLogger().Error(err.Error())
+		cSpan.End()
+		resp.Code = cerrors.ErrCodeMapper[cerrors.ErrGenericInternalServer]
+		resp.Message = cerrors.ErrMessageMapper[cerrors.ErrGenericInternalServer]
+		return resp, cerrors.ErrGenericInternalServer
+	}
+	cSpan.End()
+
+	if refreshToken != nil {
+		_, cSpan = input.Tracer.Start(rootCtx, "delete-refresh-token-family")
+		err = svc.repo.DeleteRefreshTokenFamily(ctx, refreshToken.FamilyID)
+		if err != nil {
+			svc.logger.GetLogger().Error(err.Error())
+			cSpan.End()
+			resp.Code = cerrors.ErrCodeMapper[cerrors.ErrGenericInternalServer]
+			resp.Message = cerrors.ErrMessageMapper[cerrors.ErrGenericInternalServer]
+			return resp, cerrors.ErrGenericInternalServer
+		}
+		cSpan.End()
+	}
+
+	// The revocations of the tokens that have expired since are no longer needed
+	_, cSpan = input.Tracer.Start(rootCtx, "delete-expired-revoked-tokens")
+	_, err = svc.repo.DeleteExpiredRevokedTokens(ctx, now)
+	if err != nil {
+		svc.logger.GetLogger().Error(err.Error())
+	}
+	cSpan.End()
+
+	resp.Code = cerrors.ErrCodeMapper[nil]
+	resp.Message = cerrors.ErrMessageMapper[nil]
+	return resp, nil
+}
@@ -0,0 +1,261 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go-license-management/internal/cerrors"
+	"go-license-management/internal/constants"
+	"go-license-management/internal/infrastructure/database/entities"
+	"go-license-management/internal/services/v1/authentications/models"
+	"go-license-management/internal/services/v1/authentications/repository"
+	"go-license-management/internal/utils"
+	"go.opentelemetry.io/otel/trace/noop"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// fakeAuthenticationRepository keeps the accounts, the refresh tokens, the revoked tokens, the failed logins and the
+// lockouts in memory. The other methods are not implemented.
+type fakeAuthenticationRepository struct {
+	repository.IAuthentication
+	tenants            []*entities.Tenant
+	accounts           []*entities.Account
+	refreshTokens      []*entities.RefreshToken
+	revokedTokens      []*entities.RevokedToken
+	expiredRevocations []time.Time
+	loginFailures      []*entities.LoginFailure
+	lockouts           []*entities.AccountLockout
+	// concurrentLockout is stored once the lockout of the account has been checked, as if concurrent failed logins
+	// had locked the account in the meantime
+	concurrentLockout *entities.AccountLockout
+}
+
+func (repo *fakeAuthenticationRepository) SelectTenantByPK(ctx context.Context, tenantName string) (*entities.Tenant, error) {
+	for _, tenant := range repo.tenants {
+		if tenant.Name == tenantName {
+			return tenant, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (repo *fakeAuthenticationRepository) SelectAccountByPK(ctx context.Context, tenantName, username string) (*entities.Account, error) {
+	for _, account := range repo.accounts {
+		if account.TenantName == tenantName && account.Username == username {
+			return account, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (repo *fakeAuthenticationRepository) SelectAccountLockout(ctx context.Context, tenantName, username string) (*entities.AccountLockout, error) {
+	defer func() {
+		if repo.concurrentLockout != nil {
+			_ = repo.UpsertAccountLockout(ctx, repo.concurrentLockout)
+			repo.concurrentLockout = nil
+		}
+	}()
+
+	for _, lockout := range repo.lockouts {
+		if lockout.TenantName == tenantName && lockout.Username == username {
+			return lockout, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (repo *fakeAuthenticationRepository) UpsertAccountLockout(ctx context.Context, lockout *entities.AccountLockout) error {
+	for i, current := range repo.lockouts {
+		if current.TenantName == lockout.TenantName && current.Username == lockout.Username {
+			repo.lockouts[i] = lockout
+			return nil
+		}
+	}
+	repo.lockouts = append(repo.lockouts, lockout)
+	return nil
+}
+
+func (repo *fakeAuthenticationRepository) InsertNewLoginFailure(ctx context.Context, failure *entities.LoginFailure, since time.Time) (int, error) {
+	repo.loginFailures = append(repo.loginFailures, failure)
+
+	failures := 0
+	for _, current := range repo.loginFailures {
+		if current.TenantName == failure.TenantName && current.Username == failure.Username && current.CreatedAt.After(since) {
+			failures++
+		}
+	}
+	return failures, nil
+}
+
+func (repo *fakeAuthenticationRepository) CountLoginFailuresByIP(ctx context.Context, ip string, since time.Time) (int, error) {
+	failures := 0
+	for _, failure := range repo.loginFailures {
+		if failure.IP == ip && failure.CreatedAt.After(since) {
+			failures++
+		}
+	}
+	return failures, nil
+}
+
+func (repo *fakeAuthenticationRepository) DeleteExpiredLoginFailures(ctx context.Context, before time.Time) (int64, error) {
+	var loginFailures []*entities.LoginFailure
+	for _, failure := range repo.loginFailures {
+		if failure.CreatedAt.After(before) {
+			loginFailures = append(loginFailures, failure)
+		}
+	}
+	deleted := len(repo.loginFailures) - len(loginFailures)
+	repo.loginFailures = loginFailures
+	return int64(deleted), nil
+}
+
+func (repo *fakeAuthenticationRepository) SelectRefreshTokenByHash(ctx context.Context, tokenHash string) (*entities.RefreshToken, error) {
+	for _, token := range repo.refreshTokens {
+		if token.TokenHash == tokenHash {
+			return token, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (repo *fakeAuthenticationRepository) DeleteRefreshTokenFamily(ctx context.Context, familyID uuid.UUID) error {
+	var refreshTokens []*entities.RefreshToken
+	for _, token := range repo.refreshTokens {
+		if token.FamilyID != familyID {
+			refreshTokens = append(refreshTokens, token)
+		}
+	}
+	repo.refreshTokens = refreshTokens
+	return nil
+}
+
+func (repo *fakeAuthenticationRepository) InsertNewRevokedToken(ctx context.Context, revokedToken *entities.RevokedToken) error {
+	repo.revokedTokens = append(repo.revokedTokens, revokedToken)
+	return nil
+}
+
+func (repo *fakeAuthenticationRepository) DeleteExpiredRevokedTokens(ctx context.Context, now time.Time) (int64, error) {
+	repo.expiredRevocations = append(repo.expiredRevocations, now)
+	return 0, nil
+}
+
+// newLogoutContext returns the context of a logout request authenticated with an access token of the account.
+func newLogoutContext(tenantName, username, jti string, expiry time.Time) *gin.Context {
+	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	ctx.Request = httptest.NewRequest("POST", "/api/v1/tenants/"+tenantName+"/logout", nil)
+	ctx.Set(constants.ContextValueTenant, tenantName)
+	ctx.Set(constants.ContextValueSubject, username)
+	ctx.Set(constants.ContextValueTokenID, jti)
+	ctx.Set(constants.ContextValueTokenExpiry, expiry)
+	return ctx
+}
+
+func newLogoutInput(refresh *string) *models.AuthenticationLogoutInput {
+	return &models.AuthenticationLogoutInput{
+		TracerCtx: context.Background(),
+		Tracer:    noop.NewTracerProvider().Tracer("test"),
+		Refresh:   refresh,
+	}
+}
+
+func TestAuthenticationService_Logout(t *testing.T) {
+	repo := &fakeAuthenticationRepository{}
+	svc := NewAuthenticationService(WithRepository(repo))
+	expiry := time.Now().Add(time.Hour).Truncate(time.Second)
+
+	resp, err := svc.Logout(newLogoutContext("tenant", "user", "jti", expiry), newLogoutInput(nil))
+	assert.NoError(t, err)
+	assert.Equal(t, cerrors.ErrCodeMapper[nil], resp.Code)
+
+	// The access token is revoked by its ID until it expires
+	assert.Len(t, repo.revokedTokens, 1)
+	revokedToken := repo.revokedTokens[0]
+	assert.Equal(t, "jti", revokedToken.JTI)
+	assert.Equal(t, "tenant", revokedToken.TenantName)
+	assert.Equal(t, "user", revokedToken.Username)
+	assert.Equal(t, expiry, revokedToken.ExpiresAt)
+
+	// The revocations that are no longer needed are cleaned up
+	assert.Equal(t, []time.Time{revokedToken.CreatedAt}, repo.expiredRevocations)
+}
+
+func TestAuthenticationService_Logout_RefreshToken(t *testing.T) {
+	familyID := uuid.New()
+	refresh := "refresh"
+	repo := &fakeAuthenticationRepository{
+		refreshTokens: []*entities.RefreshToken{
+			{ID: uuid.New(), FamilyID: familyID, TenantName: "tenant", Username: "user", TokenHash: utils.HashToken(refresh)},
+			{ID: uuid.New(), FamilyID: familyID, TenantName: "tenant", Username: "user", TokenHash: utils.HashToken("used")},
+			{ID: uuid.New(), FamilyID: uuid.New(), TenantName: "tenant", Username: "user", TokenHash: utils.HashToken("other")},
+		},
+	}
+	svc := NewAuthenticationService(WithRepository(repo))
+	expiry := time.Now().Add(time.Hour)
+
+	_, err := svc.Logout(newLogoutContext("tenant", "user", "jti", expiry), newLogoutInput(&refresh))
+	assert.NoError(t, err)
+	assert.Len(t, repo.revokedTokens, 1)
+
+	// Only the family of the refresh token of the session is revoked
+	assert.Len(t, repo.refreshTokens, 1)
+	assert.NotEqual(t, familyID, repo.refreshTokens[0].FamilyID)
+}
+
+func TestAuthenticationService_Logout_InvalidRefreshToken(t *testing.T) {
+	familyID := uuid.New()
+	repo := &fakeAuthenticationRepository{
+		refreshTokens: []*entities.RefreshToken{
+			{ID: uuid.New(), FamilyID: familyID, TenantName: "tenant", Username: "other", TokenHash: utils.HashToken("other")},
+		},
+	}
+	svc := NewAuthenticationService(WithRepository(repo))
+	expiry := time.Now().Add(time.Hour)
+
+	for _, refresh := range []string{"unknown", "other"} {
+		t.Run(refresh, func(t *testing.T) {
+			resp, err := svc.Logout(newLogoutContext("tenant", "user", "jti", expiry), newLogoutInput(&refresh))
+			assert.ErrorIs(t, err, cerrors.ErrAccountRefreshTokenIsInvalid)
+			assert.Equal(t, cerrors.ErrCodeMapper[cerrors.ErrAccountRefreshTokenIsInvalid], resp.Code)
+		})
+	}
+
+	// Nothing is revoked when the refresh token does not belong to the account
+	assert.Empty(t, repo.revokedTokens)
+	assert.Len(t, repo.refreshTokens, 1)
+}
+
+func TestAuthenticationService_Login_ConcurrentLockout(t *testing.T) {
+	password := "password"
+	passwordDigest, err := utils.HashPassword(password)
+	assert.NoError(t, err)
+
+	repo := &fakeAuthenticationRepository{
+		tenants:  []*entities.Tenant{{Name: "tenant"}},
+		accounts: []*entities.Account{{TenantName: "tenant", Username: "user", PasswordDigest: passwordDigest}},
+		concurrentLockout: &entities.AccountLockout{
+			TenantName:  "tenant",
+			Username:    "user",
+			LockedUntil: time.Now().Add(time.Minute),
+		},
+	}
+	svc := newLockoutService(repo)
+
+	// The account is locked by concurrent failed logins while the password is compared, so even the right password
+	// is rejected
+	tenantName, username := "tenant", "user"
+	input := &models.AuthenticationLoginInput{
+		TracerCtx: context.Background(),
+		Tracer:    noop.NewTracerProvider().Tracer("test"),
+		Username:  &username,
+		Password:  &password,
+	}
+	input.TenantName = &tenantName
+	resp, err := svc.Login(newLoginContext("192.0.2.1"), input)
+	assert.ErrorIs(t, err, cerrors.ErrAccountIsLocked)
+	assert.Equal(t, cerrors.ErrCodeMapper[cerrors.ErrAccountIsLocked], resp.Code)
+	assert.Empty(t, repo.refreshTokens)
+}
@@ -3,14 +3,18 @@ package service
 import (
 	"crypto/ed25519"
 	"crypto/x509"
+	"database/sql"
 	"encoding/base64"
-	"encoding/hex"
 	"errors"
+	"fmt"
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"go-license-management/internal/cerrors"
 	"go-license-management/internal/constants"
 	"go-license-management/internal/infrastructure/database/entities"
 	"go-license-management/internal/permissions"
+	"go-license-management/internal/utils"
 	"time"
 )
 
@@ -22,7 +26,7 @@ func (svc *AuthenticationService) generateSuperadminJWT(ctx *gin.Context, master
 	}
 
 	now := time.Now()
-	exp := now.Add(time.Hour).Unix()
+	exp := now.Add(svc.accessTokenTTL.Lifetime("", master.RoleName)).Unix()
 	claims := jwt.NewWithClaims(jwt.SigningMethodEdDSA, jwt.MapClaims{
 		"sub":         master.Username,   // Subject (user identifier)
 		"iss":         constants.AppName, // Issuer
@@ -30,6 +34,7 @@ func (svc *AuthenticationService) generateSuperadminJWT(ctx *gin.Context, master
 		"exp":         exp,               // Expiration time
 		"iat":         now.Unix(),
 		"nbf":         now.Unix(),
+		"jti":         uuid.NewString(), // Token ID, to revoke the token
 		"tenant":      "*",
 		"status":      constants.AccountStatusActive,
 		"permissions": jwtPermissions,
@@ -77,7 +82,7 @@ func (svc *AuthenticationService) generateJWT(ctx *gin.Context, tenant *entities
 	}
 
 	now := time.Now()
-	exp := now.Add(time.Hour).Unix()
+	exp := now.Add(svc.accessTokenTTL.Lifetime(account.TenantName, account.RoleName)).Unix()
 	claims := jwt.NewWithClaims(jwt.SigningMethodEdDSA, jwt.MapClaims{
 		"sub":         account.Username,  // Subject (user identifier)
 		"iss":         constants.AppName, // Issuer
@@ -85,12 +90,15 @@ func (svc *AuthenticationService) generateJWT(ctx *gin.Context, tenant *entities
 		"exp":         exp,               // Expiration time
 		"iat":         now.Unix(),
 		"nbf":         now.Unix(),
+		"jti":         uuid.NewString(), // Token ID, to revoke the token
 		"tenant":      account.TenantName,
 		"status":      account.Status,
 		"permissions": jwtPermissions,
 	})
+	// The key id lets the tokens be verified with the key ring of the tenant once its keys are regenerated
+	claims.Header["kid"] = utils.KeyID(tenant.Ed25519PublicKey)
 
-	privateKey, err := hex.DecodeString(tenant.Ed25519PrivateKey)
+	privateKey, err := base64.StdEncoding.DecodeString(tenant.Ed25519PrivateKey)
 	if err != nil {
 		return "", 0, err
 	}
@@ -112,3 +120,99 @@ func (svc *AuthenticationService) generateJWT(ctx *gin.Context, tenant *entities
 
 	return tokenString, exp, nil
 }
+
+// newRefreshToken generates a refresh token of the family for the account. Only the hash of the token is kept in the
+// returned entity, along with its expiry.
+func (svc *AuthenticationService) newRefreshToken(familyID uuid.UUID, tenantName, username string) (string, *entities.RefreshToken, error) {
+	token, err := utils.GenerateRefreshToken()
+	if err != nil {
+		return "", nil, err
+	}
+
+	now := time.Now()
+	refreshToken := &entities.RefreshToken{
+		ID:         uuid.New(),
+		FamilyID:   familyID,
+		TenantName: tenantName,
+		Username:   username,
+		TokenHash:  utils.HashToken(token),
+		ExpiresAt:  now.Add(svc.refreshTokenTTL),
+		CreatedAt:  now,
+	}
+	return token, refreshToken, nil
+}
+
+// checkLoginLockout returns cerrors.ErrAccountIsLocked when the account is locked, and cerrors.ErrGenericTooManyRequests
+// when the IP of the request has failed to log in too many times. Accounts of the superadmin have no tenant.
+func (svc *AuthenticationService) checkLoginLockout(ctx *gin.Context, tenantName, username string) error {
+	err := svc.checkAccountLockout(ctx, tenantName, username)
+	if err != nil {
+		return err
+	}
+
+	failures, err := svc.repo.CountLoginFailuresByIP(ctx, ctx.ClientIP(), time.Now().Add(-svc.lockoutDuration))
+	if err != nil {
+		return err
+	}
+	if failures >= svc.maxFailedLoginsPerIP {
+		return cerrors.ErrGenericTooManyRequests
+	}
+
+	return nil
+}
+
+// checkAccountLockout returns cerrors.ErrAccountIsLocked when the account is locked. It is checked again once the
+// password matches, as concurrent failed logins may have locked the account after the first check.
+func (svc *AuthenticationService) checkAccountLockout(ctx *gin.Context, tenantName, username string) error {
+	lockout, err := svc.repo.SelectAccountLockout(ctx, tenantName, username)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+	if err == nil && lockout.LockedUntil.After(time.Now()) {
+		return cerrors.ErrAccountIsLocked
+	}
+	return nil
+}
+
+// recordLoginFailure records a failed login of the account, and locks the account once it has failed to log in too
+// many times within the lockout duration. Returns the error to report: cerrors.ErrAccountIsLocked when the account has
+// just been locked, cerrors.ErrGenericUnauthorized otherwise.
+func (svc *AuthenticationService) recordLoginFailure(ctx *gin.Context, tenantName, username string) error {
+	now := time.Now()
+	since := now.Add(-svc.lockoutDuration)
+
+	// The failed logins out of the lockout duration are no longer counted
+	_, err := svc.repo.DeleteExpiredLoginFailures(ctx, since)
+	if err != nil {
+		svc.logger.GetLogger().Error(err.Error())
+	}
+
+	failures, err := svc.repo.InsertNewLoginFailure(ctx, &entities.LoginFailure{
+		ID:         uuid.New(),
+		TenantName: tenantName,
+		Username:   username,
+		IP:         ctx.ClientIP(),
+		CreatedAt:  now,
+	}, since)
+	if err != nil {
+		svc.logger.GetLogger().Error(err.Error())
+		return cerrors.ErrGenericInternalServer
+	}
+	if failures < svc.maxFailedLogins {
+		return cerrors.ErrGenericUnauthorized
+	}
+
+	svc.logger.GetLogger().Error(fmt.Sprintf("locking account [%s] of tenant [%s] after [%d] failed logins", username, tenantName, failures))
+	err = svc.repo.UpsertAccountLockout(ctx, &entities.AccountLockout{
+		TenantName:  tenantName,
+		Username:    username,
+		LockedUntil: now.Add(svc.lockoutDuration),
+		CreatedAt:   now,
+	})
+	if err != nil {
+		svc.logger.GetLogger().Error(err.Error())
+		return cerrors.ErrGenericInternalServer
+	}
+
+	return cerrors.ErrAccountIsLocked
+}
@@ -0,0 +1,116 @@
+package service
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go-license-management/internal/cerrors"
+	"go-license-management/internal/infrastructure/database/entities"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// newLoginContext returns the context of a login request sent from the IP.
+func newLoginContext(ip string) *gin.Context {
+	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	ctx.Request = httptest.NewRequest("POST", "/api/v1/tenants/tenant/login", nil)
+	ctx.Request.RemoteAddr = ip + ":1234"
+	return ctx
+}
+
+func newLockoutService(repo *fakeAuthenticationRepository) *AuthenticationService {
+	return NewAuthenticationService(
+		WithRepository(repo),
+		WithLoginLockout(3, 5, 15*time.Minute),
+	)
+}
+
+func TestAuthenticationService_RecordLoginFailure(t *testing.T) {
+	repo := &fakeAuthenticationRepository{}
+	svc := newLockoutService(repo)
+	ctx := newLoginContext("192.0.2.1")
+
+	// The account is locked by the failed login reaching the maximum
+	for i := 0; i < 2; i++ {
+		err := svc.recordLoginFailure(ctx, "tenant", "user")
+		assert.ErrorIs(t, err, cerrors.ErrGenericUnauthorized)
+		assert.Empty(t, repo.lockouts)
+	}
+	err := svc.recordLoginFailure(ctx, "tenant", "user")
+	assert.ErrorIs(t, err, cerrors.ErrAccountIsLocked)
+	assert.Len(t, repo.lockouts, 1)
+	assert.Equal(t, "tenant", repo.lockouts[0].TenantName)
+	assert.Equal(t, "user", repo.lockouts[0].Username)
+	assert.WithinDuration(t, time.Now().Add(15*time.Minute), repo.lockouts[0].LockedUntil, time.Second)
+
+	assert.Len(t, repo.loginFailures, 3)
+	for _, failure := range repo.loginFailures {
+		assert.Equal(t, "192.0.2.1", failure.IP)
+	}
+
+	// The failed logins of the other accounts are not counted
+	err = svc.recordLoginFailure(ctx, "tenant", "other")
+	assert.ErrorIs(t, err, cerrors.ErrGenericUnauthorized)
+	err = svc.recordLoginFailure(ctx, "other", "user")
+	assert.ErrorIs(t, err, cerrors.ErrGenericUnauthorized)
+	assert.Len(t, repo.lockouts, 1)
+}
+
+func TestAuthenticationService_RecordLoginFailure_Expired(t *testing.T) {
+	// The failed logins out of the lockout duration are no longer counted, nor kept
+	expired := time.Now().Add(-time.Hour)
+	repo := &fakeAuthenticationRepository{
+		loginFailures: []*entities.LoginFailure{
+			{ID: uuid.New(), TenantName: "tenant", Username: "user", IP: "192.0.2.1", CreatedAt: expired},
+			{ID: uuid.New(), TenantName: "tenant", Username: "user", IP: "192.0.2.1", CreatedAt: expired},
+		},
+	}
+	svc := newLockoutService(repo)
+
+	err := svc.recordLoginFailure(newLoginContext("192.0.2.1"), "tenant", "user")
+	assert.ErrorIs(t, err, cerrors.ErrGenericUnauthorized)
+	assert.Empty(t, repo.lockouts)
+	assert.Len(t, repo.loginFailures, 1)
+}
+
+func TestAuthenticationService_CheckLoginLockout(t *testing.T) {
+	repo := &fakeAuthenticationRepository{
+		lockouts: []*entities.AccountLockout{
+			{TenantName: "tenant", Username: "locked", LockedUntil: time.Now().Add(time.Minute)},
+			{TenantName: "tenant", Username: "unlocked", LockedUntil: time.Now().Add(-time.Minute)},
+		},
+	}
+	svc := newLockoutService(repo)
+	ctx := newLoginContext("192.0.2.1")
+
+	err := svc.checkLoginLockout(ctx, "tenant", "locked")
+	assert.ErrorIs(t, err, cerrors.ErrAccountIsLocked)
+
+	// Lockouts end after the lockout duration, and only apply to their account
+	err = svc.checkLoginLockout(ctx, "tenant", "unlocked")
+	assert.NoError(t, err)
+	err = svc.checkLoginLockout(ctx, "other", "locked")
+	assert.NoError(t, err)
+	err = svc.checkLoginLockout(ctx, "tenant", "user")
+	assert.NoError(t, err)
+}
+
+func TestAuthenticationService_CheckLoginLockout_IP(t *testing.T) {
+	repo := &fakeAuthenticationRepository{}
+	svc := newLockoutService(repo)
+	ctx := newLoginContext("192.0.2.1")
+
+	// The failed logins of every account are counted against the IP
+	for i := 0; i < 5; i++ {
+		err := svc.checkLoginLockout(ctx, "tenant", "user")
+		assert.NoError(t, err)
+		_ = svc.recordLoginFailure(ctx, "tenant", uuid.NewString())
+	}
+	err := svc.checkLoginLockout(ctx, "tenant", "user")
+	assert.ErrorIs(t, err, cerrors.ErrGenericTooManyRequests)
+
+	// The other IPs are not throttled
+	err = svc.checkLoginLockout(newLoginContext("192.0.2.2"), "tenant", "user")
+	assert.NoError(t, err)
+}
@@ -52,6 +52,8 @@ type LicenseInfoOutput struct {
 	Sha1Checksum   string                 `json:"sha1_checksum"`
 	Sha256Checksum string                 `json:"sha256_checksum"`
 	Status         string                 `json:"status"`
+	Users          int                    `json:"users"`
+	Entitlements   []string               `json:"entitlements,omitempty"`
 	Metadata       map[string]interface{} `json:"metadata"`
 	Expiry         time.Time              `json:"expiry"`
 	CreatedAt      time.Time              `json:"created_at"`
@@ -60,6 +62,51 @@ type LicenseInfoOutput struct {
 	LicenseProduct LicenseProductOutput   `json:"license_product"`
 }
 
+// LicenseFileOutput is the signed content of a license file.
+type LicenseFileOutput struct {
+	Meta LicenseFileMeta   `json:"meta"`
+	Data LicenseInfoOutput `json:"data"`
+}
+
+// LicenseKeyPayload is the content signed into the license key of policies using signed keys,
+// so the license key can be verified with the policy public key without any network access.
+// It holds the expiry, max machines and entitlements of the license at issuance, along with the id of the policy key
+// that signed it.
+type LicenseKeyPayload struct {
+	KID          string     `json:"kid"`
+	LicenseID    string     `json:"license_id"`
+	Tenant       string     `json:"tenant"`
+	ProductID    string     `json:"product_id"`
+	PolicyID     string     `json:"policy_id"`
+	MaxMachines  int        `json:"max_machines"`
+	Entitlements []string   `json:"entitlements,omitempty"`
+	Issued       time.Time  `json:"issued"`
+	Expiry       *time.Time `json:"expiry,omitempty"`
+}
+
+// LicenseValidationPayload is the content signed into the validation response when the request carries a nonce,
+// so the client can bind the answer to its request. It is signed with the policy key pair, as signed license keys.
+type LicenseValidationPayload struct {
+	LicenseID           string    `json:"license_id"`
+	Tenant              string    `json:"tenant"`
+	Valid               bool      `json:"valid"`
+	Code                string    `json:"code"`
+	MissingEntitlements []string  `json:"missing_entitlements,omitempty"`
+	Nonce               int       `json:"nonce"`
+	Issued              time.Time `json:"issued"`
+}
+
+// LicenseFileMeta describes the license file itself. It is signed along with the license,
+// so the validity window of the license file can be enforced offline.
+type LicenseFileMeta struct {
+	Version   int       `json:"version"`
+	Tenant    string    `json:"tenant"`
+	LicenseID string    `json:"license_id"`
+	Issued    time.Time `json:"issued"`
+	Expiry    time.Time `json:"expiry"`
+	TTL       int       `json:"ttl"`
+}
+
 type LicenseProductOutput struct {
 	Name                 string                 `json:"name,type:varchar(256)"`
 	DistributionStrategy string                 `json:"distribution_strategy,type:varchar(128)"`
@@ -85,6 +132,7 @@ type LicensePolicyOutput struct {
 	Strict             bool   `json:"strict"`
 	Floating           bool   `json:"floating"`
 	UsePool            bool   `json:"use_pool"`
+	SignedKeys         bool   `json:"signed_keys"`
 	RateLimited        bool   `json:"rate_limited"`
 	Encrypted          bool   `json:"encrypted"`
 	Protected          bool   `json:"protected"`
@@ -112,15 +160,19 @@ type LicenseActionInput struct {
 	TracerCtx context.Context
 	Tracer    trace.Tracer
 	license_attribute.LicenseCommonURI
-	LicenseKey *string `json:"license_key"`
-	Nonce      *int    `json:"nonce"`
-	Increment  *int    `json:"increment"`
-	Decrement  *int    `json:"decrement"`
+	LicenseKey *string                                   `json:"license_key"`
+	Nonce      *int                                      `json:"nonce"`
+	Increment  *int                                      `json:"increment"`
+	Decrement  *int                                      `json:"decrement"`
+	Scope      *license_attribute.LicenseValidationScope `json:"scope"`
 }
 
 type LicenseValidationOutput struct {
-	Valid bool   `json:"valid"`
-	Code  string `json:"code"`
+	Valid               bool     `json:"valid"`
+	Code                string   `json:"code"`
+	MissingEntitlements []string `json:"missing_entitlements,omitempty"`
+	Nonce               *int     `json:"nonce,omitempty"`
+	SignedResponse      string   `json:"signed_response,omitempty"`
 }
 
 type LicenseActionCheckoutOutput struct {
@@ -129,3 +181,81 @@ type LicenseActionCheckoutOutput struct {
 	ExpiryAt    time.Time `json:"expiry_at"`
 	IssuedAt    time.Time `json:"issued_at"`
 }
+
+type LicenseUsersInput struct {
+	TracerCtx context.Context
+	Tracer    trace.Tracer
+	license_attribute.LicenseCommonURI
+	Usernames []string `json:"usernames"`
+}
+
+type LicenseUserListInput struct {
+	TracerCtx context.Context
+	Tracer    trace.Tracer
+	license_attribute.LicenseCommonURI
+	constants.QueryCommonParam
+}
+
+type LicenseUserOutput struct {
+	ID         string    `json:"id"`
+	TenantName string    `json:"tenant_name"`
+	LicenseID  string    `json:"license_id"`
+	Username   string    `json:"username"`
+	Email      string    `json:"email"`
+	RoleName   string    `json:"role_name"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+type LicenseEntitlementAttachmentInput struct {
+	TracerCtx context.Context
+	Tracer    trace.Tracer
+	license_attribute.LicenseCommonURI
+	EntitlementID []string `json:"entitlement_id"`
+}
+
+type LicenseEntitlementDetachmentInput struct {
+	TracerCtx context.Context
+	Tracer    trace.Tracer
+	license_attribute.LicenseCommonURI
+	ID []string `json:"id"`
+}
+
+type LicenseEntitlementListInput struct {
+	TracerCtx context.Context
+	Tracer    trace.Tracer
+	license_attribute.LicenseCommonURI
+	constants.QueryCommonParam
+}
+
+type LicenseEntitlementOutput struct {
+	ID            string                 `json:"id"`
+	TenantName    string                 `json:"tenant_name"`
+	Source        string                 `json:"source"`
+	EntitlementID string                 `json:"entitlement_id"`
+	Name          string                 `json:"name"`
+	Code          string                 `json:"code"`
+	Metadata      map[string]interface{} `json:"metadata"`
+	CreatedAt     time.Time              `json:"created_at"`
+	UpdatedAt     time.Time              `json:"updated_at"`
+}
+
+type LicenseTokensInput struct {
+	TracerCtx context.Context
+	Tracer    trace.Tracer
+	license_attribute.LicenseCommonURI
+	Name           *string `json:"name"`
+	Expiry         *string `json:"expiry"`
+	MaxActivations *int    `json:"max_activations"`
+}
+
+// LicenseTokenOutput holds the generated license token. The token is only returned once, as only its hash is stored.
+type LicenseTokenOutput struct {
+	ID             string    `json:"id"`
+	Token          string    `json:"token"`
+	LicenseID      string    `json:"license_id"`
+	Name           string    `json:"name"`
+	MaxActivations int       `json:"max_activations"`
+	Expiry         time.Time `json:"expiry"`
+	CreatedAt      time.Time `json:"created_at"`
+}
@@ -5,10 +5,12 @@ import (
 	"github.com/google/uuid"
 	"go-license-management/internal/constants"
 	"go-license-management/internal/infrastructure/database/entities"
+	"time"
 )
 
 type ILicense interface {
 	InsertNewLicense(ctx context.Context, license *entities.License) error
+	InsertNewLicenseWithPooledKey(ctx context.Context, license *entities.License) error
 	SelectTenantByName(ctx context.Context, tenantName string) (*entities.Tenant, error)
 	SelectProductByPK(ctx context.Context, productID uuid.UUID) (*entities.Product, error)
 	SelectPolicyByPK(ctx context.Context, policyID uuid.UUID) (*entities.Policy, error)
@@ -19,4 +21,21 @@ type ILicense interface {
 	UpdateLicenseByPK(ctx context.Context, license *entities.License) (*entities.License, error)
 	CheckPolicyExist(ctx context.Context, policyID uuid.UUID) (bool, error)
 	CheckProductExist(ctx context.Context, productID uuid.UUID) (bool, error)
+	CheckActiveMachineExistByFingerprint(ctx context.Context, licenseID uuid.UUID, fingerprint string) (bool, error)
+	UpdateExpiredLicenses(ctx context.Context, now time.Time) (int64, error)
+	InsertLicenseNonce(ctx context.Context, nonce *entities.LicenseNonce) (bool, error)
+	DeleteExpiredLicenseNonces(ctx context.Context, now time.Time) (int64, error)
+	InsertNewLicenseToken(ctx context.Context, licenseToken *entities.LicenseToken) error
+	SelectAccountsByUsernames(ctx context.Context, tenantName string, usernames []string) ([]entities.Account, error)
+	SelectLicenseUsersByUsernames(ctx context.Context, licenseID uuid.UUID, usernames []string) ([]entities.LicenseUser, error)
+	SelectLicenseUsers(ctx context.Context, licenseID uuid.UUID, queryParam constants.QueryCommonParam) ([]entities.LicenseUser, int, error)
+	InsertNewLicenseUsersAndUpdateLicense(ctx context.Context, license *entities.License, licenseUsers []entities.LicenseUser, enforceMaxUsers bool) error
+	DeleteLicenseUsersAndUpdateLicense(ctx context.Context, license *entities.License, usernames []string) error
+	SelectEntitlementsByPK(ctx context.Context, tenantName string, entitlementIDs []uuid.UUID) ([]entities.Entitlement, error)
+	SelectAttachedEntitlementIDs(ctx context.Context, licenseID, policyID uuid.UUID, entitlementIDs []uuid.UUID) ([]uuid.UUID, error)
+	SelectLicenseEntitlementsByPK(ctx context.Context, licenseID uuid.UUID, licenseEntitlementIDs []uuid.UUID) ([]entities.LicenseEntitlement, error)
+	InsertNewLicenseEntitlements(ctx context.Context, licenseEntitlements []entities.LicenseEntitlement) error
+	DeleteLicenseEntitlementsByPK(ctx context.Context, licenseEntitlementIDs []uuid.UUID) error
+	SelectPolicyEntitlementsByPolicyID(ctx context.Context, policyID uuid.UUID) ([]entities.PolicyEntitlement, error)
+	SelectLicenseEntitlementsByLicenseID(ctx context.Context, licenseID uuid.UUID) ([]entities.LicenseEntitlement, error)
 }
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"crypto/md5"
 	"crypto/sha1"
 	"crypto/sha256"
@@ -13,6 +14,7 @@ import (
 	"go-license-management/internal/constants"
 	"go-license-management/internal/infrastructure/database/entities"
 	"go-license-management/internal/infrastructure/logging"
+	"go-license-management/internal/permissions"
 	"go-license-management/internal/response"
 	"go-license-management/internal/services/v1/licenses/models"
 	"go-license-management/internal/services/v1/licenses/repository"
@@ -22,12 +24,15 @@ import (
 )
 
 type LicenseService struct {
-	repo   repository.ILicense
-	logger *logging.Logger
+	repo     repository.ILicense
+	nonceTTL time.Duration
+	logger   *logging.Logger
 }
 
 func NewLicenseService(options ...func(*LicenseService)) *LicenseService {
-	svc := &LicenseService{}
+	svc := &LicenseService{
+		nonceTTL: constants.DefaultLicenseNonceTTL * time.Second,
+	}
 
 	for _, opt := range options {
 		opt(svc)
@@ -44,6 +49,15 @@ func WithRepository(repo repository.ILicense) func(*LicenseService) {
 	}
 }
 
+// WithNonceTTL sets the window during which a nonce cannot be reused for the actions of a license.
+func WithNonceTTL(ttl time.Duration) func(*LicenseService) {
+	return func(c *LicenseService) {
+		if ttl > 0 {
+			c.nonceTTL = ttl
+		}
+	}
+}
+
 // Create handles new license logics.
 func (svc *LicenseService) Create(ctx *gin.Context, input *models.LicenseRegistrationInput) (*response.BaseOutput, error) {
 	rootCtx, span := input.Tracer.Start(input.TracerCtx, "create-handler")
@@ -109,6 +123,22 @@ func (svc *LicenseService) Create(ctx *gin.Context, input *models.LicenseRegistr
 	}
 	cSpan.End()
 
+	// Product tokens can only create licenses of their product, under a policy of the same product
+	if !inProductTokenScope(ctx, product.ID) || !inProductTokenScope(ctx, policy.ProductID) {
+		svc.logger.GetLogger().Error(fmt.Sprintf("product [%s] or policy [%s] is not in the scope of the product token", product.ID, policy.ID))
+		resp.Code = cerrors.ErrCodeMapper[cerrors.ErrProductTokenScopeIsInvalid]
+		resp.Message = cerrors.ErrMessageMapper[cerrors.ErrProductTokenScopeIsInvalid]
+		return resp, cerrors.ErrProductTokenScopeIsInvalid
+	}
+
+	// Only admins may create licenses under a protected policy
+	if !permissions.CanManageProtectedPolicy(ctx, policy) {
+		svc.logger.GetLogger().Error(fmt.Sprintf("subject [%s] cannot create license under protected policy [%s]", ctx.GetString(constants.ContextValueSubject), policy.ID))
+		resp.Code = cerrors.ErrCodeMapper[cerrors.ErrPolicyIsProtected]
+		resp.Message = cerrors.ErrMessageMapper[cerrors.ErrPolicyIsProtected]
+		return resp, cerrors.ErrPolicyIsProtected
+	}
+
 	_, cSpan = input.Tracer.Start(rootCtx, "generate-new-license")
 	svc.logger.GetLogger().Info("generating new license")
 	license, err := svc.generateLicense(ctx, input, tenant, product, policy)
@@ -123,10 +153,20 @@ func (svc *LicenseService) Create(ctx *gin.Context, input *models.LicenseRegistr
 
 	_, cSpan = input.Tracer.Start(rootCtx, "insert-new-license")
 	svc.logger.GetLogger().Info("inserting new license to database")
-	err = svc.repo.InsertNewLicense(ctx, license)
+	if policy.UsePool {
+		svc.logger.GetLogger().Info(fmt.Sprintf("pulling license key from the key pool of policy [%s]", policy.ID))
+		err = svc.repo.InsertNewLicenseWithPooledKey(ctx, license)
+	} else {
+		err = svc.repo.InsertNewLicense(ctx, license)
+	}
 	if err != nil {
 		svc.logger.GetLogger().Error(err.Error())
 		cSpan.End()
+		if errors.Is(err, cerrors.ErrLicenseKeyPoolIsExhausted) {
+			resp.Code = cerrors.ErrCodeMapper[cerrors.ErrLicenseKeyPoolIsExhausted]
+			resp.Message = cerrors.ErrMessageMapper[cerrors.ErrLicenseKeyPoolIsExhausted]
+			return resp, cerrors.ErrLicenseKeyPoolIsExhausted
+		}
 		resp.Code = cerrors.ErrCodeMapper[cerrors.ErrGenericInternalServer]
 		resp.Message = cerrors.ErrMessageMapper[cerrors.ErrGenericInternalServer]
 		return resp, cerrors.ErrGenericInternalServer
@@ -143,6 +183,7 @@ func (svc *LicenseService) Create(ctx *gin.Context, input *models.LicenseRegistr
 		Sha1Checksum:   fmt.Sprintf("%x", sha1.Sum([]byte(license.Key))),
 		Sha256Checksum: fmt.Sprintf("%x", sha256.Sum256([]byte(license.Key))),
 		Status:         license.Status,
+		Users:          license.Users,
 		Metadata:       license.Metadata,
 		Expiry:         license.Expiry,
 		CreatedAt:      license.CreatedAt,
@@ -160,6 +201,7 @@ func (svc *LicenseService) Create(ctx *gin.Context, input *models.LicenseRegistr
 			Strict:             policy.Strict,
 			Floating:           policy.Floating,
 			UsePool:            policy.UsePool,
+			SignedKeys:         policy.SignedKeys,
 			RateLimited:        policy.RateLimited,
 			Encrypted:          policy.Encrypted,
 			Protected:          policy.Protected,
@@ -255,6 +297,15 @@ func (svc *LicenseService) Update(ctx *gin.Context, input *models.LicenseUpdateI
 					return resp, cerrors.ErrGenericInternalServer
 				}
 			}
+
+			// Only admins may move a license out of or under a protected policy
+			if !permissions.CanManageProtectedPolicy(ctx, license.Policy) || !permissions.CanManageProtectedPolicy(ctx, policy) {
+				svc.logger.GetLogger().Error(fmt.Sprintf("subject [%s] cannot move license [%s] from policy [%s] to policy [%s], one of them is protected", ctx.GetString(constants.ContextValueSubject), license.ID, license.PolicyID, policy.ID))
+				cSpan.End()
+				resp.Code = cerrors.ErrCodeMapper[cerrors.ErrPolicyIsProtected]
+				resp.Message = cerrors.ErrMessageMapper[cerrors.ErrPolicyIsProtected]
+				return resp, cerrors.ErrPolicyIsProtected
+			}
 			license.MaxUsers = policy.MaxUsers
 			license.MaxMachines = policy.MaxMachines
 			license.MaxUses = policy.MaxUses
@@ -287,6 +338,14 @@ func (svc *LicenseService) Update(ctx *gin.Context, input *models.LicenseUpdateI
 		cSpan.End()
 	}
 
+	// Product tokens cannot move a license to another product, nor under a policy of another product
+	if !inProductTokenScope(ctx, license.ProductID) || !inProductTokenScope(ctx, policy.ProductID) {
+		svc.logger.GetLogger().Error(fmt.Sprintf("product [%s] or policy [%s] is not in the scope of the product token", license.ProductID, policy.ID))
+		resp.Code = cerrors.ErrCodeMapper[cerrors.ErrProductTokenScopeIsInvalid]
+		resp.Message = cerrors.ErrMessageMapper[cerrors.ErrProductTokenScopeIsInvalid]
+		return resp, cerrors.ErrProductTokenScopeIsInvalid
+	}
+
 	// Update expiration if specified
 	var expiry time.Time
 	if input.Expiry != nil {
@@ -306,6 +365,9 @@ func (svc *LicenseService) Update(ctx *gin.Context, input *models.LicenseUpdateI
 		}
 	}
 
+	// Expired licenses whose expiry moved into the future are no longer expired
+	restoreExpiredLicenseStatus(license)
+
 	// Update max_users if specified
 	if input.MaxUsers != nil {
 		license.MaxUsers = utils.DerefPointer(input.MaxUsers)
@@ -313,7 +375,7 @@ func (svc *LicenseService) Update(ctx *gin.Context, input *models.LicenseUpdateI
 
 	// Update max_uses if specified
 	if input.MaxUses != nil {
-		license.MaxUsers = utils.DerefPointer(input.MaxUses)
+		license.MaxUses = utils.DerefPointer(input.MaxUses)
 	}
 
 	// Update max_machines if specified
@@ -352,6 +414,7 @@ func (svc *LicenseService) Update(ctx *gin.Context, input *models.LicenseUpdateI
 		Sha1Checksum:   fmt.Sprintf("%x", sha1.Sum([]byte(license.Key))),
 		Sha256Checksum: fmt.Sprintf("%x", sha256.Sum256([]byte(license.Key))),
 		Status:         license.Status,
+		Users:          license.Users,
 		Metadata:       license.Metadata,
 		Expiry:         license.Expiry,
 		CreatedAt:      license.CreatedAt,
@@ -369,6 +432,7 @@ func (svc *LicenseService) Update(ctx *gin.Context, input *models.LicenseUpdateI
 			Strict:             license.Policy.Strict,
 			Floating:           license.Policy.Floating,
 			UsePool:            license.Policy.UsePool,
+			SignedKeys:         license.Policy.SignedKeys,
 			RateLimited:        license.Policy.RateLimited,
 			Encrypted:          license.Policy.Encrypted,
 			Protected:          license.Policy.Protected,
@@ -455,6 +519,7 @@ func (svc *LicenseService) Retrieve(ctx *gin.Context, input *models.LicenseRetri
 		Sha1Checksum:   fmt.Sprintf("%x", sha1.Sum([]byte(license.Key))),
 		Sha256Checksum: fmt.Sprintf("%x", sha256.Sum256([]byte(license.Key))),
 		Status:         license.Status,
+		Users:          license.Users,
 		Metadata:       license.Metadata,
 		Expiry:         license.Expiry,
 		CreatedAt:      license.CreatedAt,
@@ -472,6 +537,7 @@ func (svc *LicenseService) Retrieve(ctx *gin.Context, input *models.LicenseRetri
 			Strict:             license.Policy.Strict,
 			Floating:           license.Policy.Floating,
 			UsePool:            license.Policy.UsePool,
+			SignedKeys:         license.Policy.SignedKeys,
 			RateLimited:        license.Policy.RateLimited,
 			Encrypted:          license.Policy.Encrypted,
 			Protected:          license.Policy.Protected,
@@ -573,6 +639,14 @@ func (svc *LicenseService) List(ctx *gin.Context, input *models.LicenseListInput
 	}
 	cSpan.End()
 
+	// Product tokens only list the licenses of their product, and license tokens their own license
+	switch ctx.GetString(constants.ContextValueRole) {
+	case constants.RoleProduct:
+		input.QueryCommonParam.ProductID = utils.RefPointer(ctx.GetString(constants.ContextValueProduct))
+	case constants.RoleLicense:
+		input.QueryCommonParam.LicenseID = utils.RefPointer(ctx.GetString(constants.ContextValueLicense))
+	}
+
 	_, cSpan = input.Tracer.Start(rootCtx, "query-product-by-pkc")
 	licenses, total, err := svc.repo.SelectLicenses(ctx, tenant.Name, input.QueryCommonParam)
 	if err != nil {
@@ -602,6 +676,7 @@ func (svc *LicenseService) List(ctx *gin.Context, input *models.LicenseListInput
 			Sha1Checksum:   fmt.Sprintf("%x", sha1.Sum([]byte(license.Key))),
 			Sha256Checksum: fmt.Sprintf("%x", sha256.Sum256([]byte(license.Key))),
 			Status:         license.Status,
+			Users:          license.Users,
 			Metadata:       license.Metadata,
 			Expiry:         license.Expiry,
 			CreatedAt:      license.CreatedAt,
@@ -619,6 +694,7 @@ func (svc *LicenseService) List(ctx *gin.Context, input *models.LicenseListInput
 				Strict:             license.Policy.Strict,
 				Floating:           license.Policy.Floating,
 				UsePool:            license.Policy.UsePool,
+				SignedKeys:         license.Policy.SignedKeys,
 				RateLimited:        license.Policy.RateLimited,
 				Encrypted:          license.Policy.Encrypted,
 				Protected:          license.Policy.Protected,
@@ -695,11 +771,46 @@ func (svc *LicenseService) Actions(ctx *gin.Context, input *models.LicenseAction
 	}
 	cSpan.End()
 
-	_, cSpan = input.Tracer.Start(rootCtx, "perform-license-action")
+	// The license is identified by its key, so the product and license token scopes are checked once it is loaded
+	if !inProductTokenScope(ctx, license.ProductID) {
+		svc.logger.GetLogger().Error(fmt.Sprintf("license [%s] is not in the scope of the product token", license.ID))
+		resp.Code = cerrors.ErrCodeMapper[cerrors.ErrProductTokenScopeIsInvalid]
+		resp.Message = cerrors.ErrMessageMapper[cerrors.ErrProductTokenScopeIsInvalid]
+		return resp, cerrors.ErrProductTokenScopeIsInvalid
+	}
+
+	if !inLicenseTokenScope(ctx, license.ID) {
+		svc.logger.GetLogger().Error(fmt.Sprintf("license [%s] is not in the scope of the license token", license.ID))
+		resp.Code = cerrors.ErrCodeMapper[cerrors.ErrLicenseTokenScopeIsInvalid]
+		resp.Message = cerrors.ErrMessageMapper[cerrors.ErrLicenseTokenScopeIsInvalid]
+		return resp, cerrors.ErrLicenseTokenScopeIsInvalid
+	}
+
 	licenseAction := utils.DerefPointer(input.Action)
+	if input.Nonce != nil {
+		_, cSpan = input.Tracer.Start(rootCtx, "record-license-nonce")
+		err = svc.recordLicenseNonce(ctx, license, licenseAction, utils.DerefPointer(input.Nonce))
+		if err != nil {
+			svc.logger.GetLogger().Error(err.Error())
+			cSpan.End()
+			switch {
+			case errors.Is(err, cerrors.ErrLicenseNonceIsReplayed):
+				resp.Code = cerrors.ErrCodeMapper[cerrors.ErrLicenseNonceIsReplayed]
+				resp.Message = cerrors.ErrMessageMapper[cerrors.ErrLicenseNonceIsReplayed]
+				return resp, cerrors.ErrLicenseNonceIsReplayed
+			default:
+				resp.Code = cerrors.ErrCodeMapper[cerrors.ErrGenericInternalServer]
+				resp.Message = cerrors.ErrMessageMapper[cerrors.ErrGenericInternalServer]
+				return resp, cerrors.ErrGenericInternalServer
+			}
+		}
+		cSpan.End()
+	}
+
+	_, cSpan = input.Tracer.Start(rootCtx, "perform-license-action")
 	switch licenseAction {
 	case constants.LicenseActionValidate:
-		output, err := svc.validateLicense(ctx, license)
+		output, err := svc.validateLicense(ctx, license, input.Scope)
 		if err != nil {
 			svc.logger.GetLogger().Error(err.Error())
 			cSpan.End()
@@ -707,6 +818,18 @@ func (svc *LicenseService) Actions(ctx *gin.Context, input *models.LicenseAction
 			resp.Message = cerrors.ErrMessageMapper[cerrors.ErrGenericInternalServer]
 			return resp, cerrors.ErrGenericInternalServer
 		}
+
+		// The nonce is echoed in a signed response, so the client can bind the answer to its request
+		if input.Nonce != nil {
+			err = svc.signValidationResponse(ctx, license, output, utils.DerefPointer(input.Nonce))
+			if err != nil {
+				svc.logger.GetLogger().Error(err.Error())
+				cSpan.End()
+				resp.Code = cerrors.ErrCodeMapper[cerrors.ErrGenericInternalServer]
+				resp.Message = cerrors.ErrMessageMapper[cerrors.ErrGenericInternalServer]
+				return resp, cerrors.ErrGenericInternalServer
+			}
+		}
 		resp.Data = output
 	case constants.LicenseActionCheckout:
 		output, err := svc.checkoutLicense(ctx, license)
@@ -823,6 +946,7 @@ func (svc *LicenseService) Actions(ctx *gin.Context, input *models.LicenseAction
 			Sha1Checksum:   fmt.Sprintf("%x", sha1.Sum([]byte(output.Key))),
 			Sha256Checksum: fmt.Sprintf("%x", sha256.Sum256([]byte(output.Key))),
 			Status:         output.Status,
+			Users:          output.Users,
 			Metadata:       output.Metadata,
 			Expiry:         output.Expiry,
 			CreatedAt:      output.CreatedAt,
@@ -840,6 +964,7 @@ func (svc *LicenseService) Actions(ctx *gin.Context, input *models.LicenseAction
 				Strict:             license.Policy.Strict,
 				Floating:           license.Policy.Floating,
 				UsePool:            license.Policy.UsePool,
+				SignedKeys:         license.Policy.SignedKeys,
 				RateLimited:        license.Policy.RateLimited,
 				Encrypted:          license.Policy.Encrypted,
 				Protected:          license.Policy.Protected,
@@ -866,3 +991,536 @@ func (svc *LicenseService) Actions(ctx *gin.Context, input *models.LicenseAction
 	resp.Message = cerrors.ErrMessageMapper[nil]
 	return resp, nil
 }
+
+// ExpireLicenses transitions every license past its expiry to `expired`, and deletes the nonces past their replay window.
+func (svc *LicenseService) ExpireLicenses(ctx context.Context) (int64, error) {
+	affected, err := svc.repo.UpdateExpiredLicenses(ctx, time.Now())
+	if err != nil {
+		svc.logger.GetLogger().Error(err.Error())
+		return affected, err
+	}
+
+	if affected > 0 {
+		svc.logger.GetLogger().Info(fmt.Sprintf("updated [%d] license(s) to status [%s]", affected, constants.LicenseStatusExpired))
+	}
+
+	// Nonces outside of the replay window are no longer needed
+	nonces, err := svc.repo.DeleteExpiredLicenseNonces(ctx, time.Now())
+	if err != nil {
+		svc.logger.GetLogger().Error(err.Error())
+		return affected, err
+	}
+	if nonces > 0 {
+		svc.logger.GetLogger().Info(fmt.Sprintf("deleted [%d] expired license nonce(s)", nonces))
+	}
+	return affected, nil
+}
+
+// StartExpirationSweeper periodically runs ExpireLicenses until the context is cancelled.
+func (svc *LicenseService) StartExpirationSweeper(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = constants.DefaultLicenseExpirationSweepInterval * time.Second
+	}
+
+	svc.logger.GetLogger().Info(fmt.Sprintf("started license expiration sweeper with interval [%s]", interval))
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		_, _ = svc.ExpireLicenses(ctx)
+
+		select {
+		case <-ctx.Done():
+			svc.logger.GetLogger().Info("stopped license expiration sweeper")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// AttachUsers attaches accounts to a license. If the license would exceed its max users,
+// the attachment is rejected unless the policy overage strategy allows overages.
+func (svc *LicenseService) AttachUsers(ctx *gin.Context, input *models.LicenseUsersInput) (*response.BaseOutput, error) {
+	rootCtx, span := input.Tracer.Start(input.TracerCtx, "attach-users-handler")
+	defer span.End()
+
+	resp := &response.BaseOutput{}
+	svc.logger.WithCustomFields(
+		zap.String(constants.RequestIDField, ctx.GetString(constants.RequestIDField)),
+		zap.String(constants.ContextValueSubject, ctx.GetString(constants.ContextValueSubject)),
+	)
+
+	license, err := svc.queryTenantLicense(ctx, rootCtx, input.Tracer, input.LicenseCommonURI)
+	if err != nil {
+		resp.Code = cerrors.ErrCodeMapper[err]
+		resp.Message = cerrors.ErrMessageMapper[err]
+		return resp, err
+	}
+
+	_, cSpan := input.Tracer.Start(rootCtx, "query-accounts")
+	svc.logger.GetLogger().Info(fmt.Sprintf("verifying accounts %s", input.Usernames))
+	accounts, err := svc.repo.SelectAccountsByUsernames(ctx, license.TenantName, input.Usernames)
+	if err != nil {
+		svc.logger.GetLogger().Error(err.Error())
+		cSpan.End()
+		resp.Code = cerrors.ErrCodeMapper[cerrors.ErrGenericInternalServer]
+		resp.Message = cerrors.ErrMessageMapper[cerrors.ErrGenericInternalServer]
+		return resp, cerrors.ErrGenericInternalServer
+	}
+	cSpan.End()
+
+	if len(accounts) != len(input.Usernames) {
+		svc.logger.GetLogger().Error("one or more accounts do not exist")
+		resp.Code = cerrors.ErrCodeMapper[cerrors.ErrLicenseUserIsInvalid]
+		resp.Message = cerrors.ErrMessageMapper[cerrors.ErrLicenseUserIsInvalid]
+		return resp, cerrors.ErrLicenseUserIsInvalid
+	}
+
+	_, cSpan = input.Tracer.Start(rootCtx, "query-license-users")
+	attached, err := svc.repo.SelectLicenseUsersByUsernames(ctx, license.ID, input.Usernames)
+	if err != nil {
+		svc.logger.GetLogger().Error(err.Error())
+		cSpan.End()
+		resp.Code = cerrors.ErrCodeMapper[cerrors.ErrGenericInternalServer]
+		resp.Message = cerrors.ErrMessageMapper[cerrors.ErrGenericInternalServer]
+		return resp, cerrors.ErrGenericInternalServer
+	}
+	cSpan.End()
+
+	if len(attached) > 0 {
+		svc.logger.GetLogger().Error(fmt.Sprintf("account [%s] is already attached to license [%s]", attached[0].Username, license.ID))
+		resp.Code = cerrors.ErrCodeMapper[cerrors.ErrLicenseUserAlreadyExist]
+		resp.Message = cerrors.ErrMessageMapper[cerrors.ErrLicenseUserAlreadyExist]
+		return resp, cerrors.ErrLicenseUserAlreadyExist
+	}
+
+	// The max users are checked again when the users are attached, in case of concurrent attachments
+	enforceMaxUsers := license.Policy.OverageStrategy == constants.PolicyOverageStrategyNoOverage
+	if enforceMaxUsers && license.MaxUsers > 0 && license.Users+len(accounts) > license.MaxUsers {
+		svc.logger.GetLogger().Error(fmt.Sprintf("license [%s] max users [%d] exceeded", license.ID, license.MaxUsers))
+		resp.Code = cerrors.ErrCodeMapper[cerrors.ErrLicenseMaxUsersExceeded]
+		resp.Message = cerrors.ErrMessageMapper[cerrors.ErrLicenseMaxUsersExceeded]
+		return resp, cerrors.ErrLicenseMaxUsersExceeded
+	}
+
+	_, cSpan = input.Tracer.Start(rootCtx, "insert-license-users")
+	now := time.Now()
+	licenseUsers := make([]entities.LicenseUser, 0, len(accounts))
+	outputs := make([]models.LicenseUserOutput, 0, len(accounts))
+	for _, account := range accounts {
+		licenseUserID := uuid.New()
+		licenseUsers = append(licenseUsers, entities.LicenseUser{
+			ID:         licenseUserID,
+			TenantName: license.TenantName,
+			LicenseID:  license.ID,
+			Username:   account.Username,
+			CreatedAt:  now,
+			UpdatedAt:  now,
+		})
+		outputs = append(outputs, models.LicenseUserOutput{
+			ID:         licenseUserID.String(),
+			TenantName: license.TenantName,
+			LicenseID:  license.ID.String(),
+			Username:   account.Username,
+			Email:      account.Email,
+			RoleName:   account.RoleName,
+			CreatedAt:  now,
+			UpdatedAt:  now,
+		})
+	}
+
+	err = svc.repo.InsertNewLicenseUsersAndUpdateLicense(ctx, license, licenseUsers, enforceMaxUsers)
+	if err != nil {
+		svc.logger.GetLogger().Error(err.Error())
+		cSpan.End()
+		if errors.Is(err, cerrors.ErrLicenseMaxUsersExceeded) {
+			resp.Code = cerrors.ErrCodeMapper[cerrors.ErrLicenseMaxUsersExceeded]
+			resp.Message = cerrors.ErrMessageMapper[cerrors.ErrLicenseMaxUsersExceeded]
+			return resp, cerrors.ErrLicenseMaxUsersExceeded
+		}
+		resp.Code = cerrors.ErrCodeMapper[cerrors.ErrGenericInternalServer]
+		resp.Message = cerrors.ErrMessageMapper[cerrors.ErrGenericInternalServer]
+		return resp, cerrors.ErrGenericInternalServer
+	}
+	cSpan.End()
+
+	resp.Code = cerrors.ErrCodeMapper[nil]
+	resp.Message = cerrors.ErrMessageMapper[nil]
+	resp.Count = len(outputs)
+	resp.Data = outputs
+	return resp, nil
+}
+
+// DetachUsers detaches accounts from a license.
+func (svc *LicenseService) DetachUsers(ctx *gin.Context, input *models.LicenseUsersInput) (*response.BaseOutput, error) {
+	rootCtx, span := input.Tracer.Start(input.TracerCtx, "detach-users-handler")
+	defer span.End()
+
+	resp := &response.BaseOutput{}
+	svc.logger.WithCustomFields(
+		zap.String(constants.RequestIDField, ctx.GetString(constants.RequestIDField)),
+		zap.String(constants.ContextValueSubject, ctx.GetString(constants.ContextValueSubject)),
+	)
+
+	license, err := svc.queryTenantLicense(ctx, rootCtx, input.Tracer, input.LicenseCommonURI)
+	if err != nil {
+		resp.Code = cerrors.ErrCodeMapper[err]
+		resp.Message = cerrors.ErrMessageMapper[err]
+		return resp, err
+	}
+
+	_, cSpan := input.Tracer.Start(rootCtx, "query-license-users")
+	attached, err := svc.repo.SelectLicenseUsersByUsernames(ctx, license.ID, input.Usernames)
+	if err != nil {
+		svc.logger.GetLogger().Error(err.Error())
+		cSpan.End()
+		resp.Code = cerrors.ErrCodeMapper[cerrors.ErrGenericInternalServer]
+		resp.Message = cerrors.ErrMessageMapper[cerrors.ErrGenericInternalServer]
+		return resp, cerrors.ErrGenericInternalServer
+	}
+	cSpan.End()
+
+	if len(attached) != len(input.Usernames) {
+		svc.logger.GetLogger().Error(fmt.Sprintf("one or more accounts are not attached to license [%s]", license.ID))
+		resp.Code = cerrors.ErrCodeMapper[cerrors.ErrLicenseUserIsNotAttached]
+		resp.Message = cerrors.ErrMessageMapper[cerrors.ErrLicenseUserIsNotAttached]
+		return resp, cerrors.ErrLicenseUserIsNotAttached
+	}
+
+	_, cSpan = input.Tracer.Start(rootCtx, "delete-license-users")
+	err = svc.repo.DeleteLicenseUsersAndUpdateLicense(ctx, license, input.Usernames)
+	if err != nil {
+		svc.logger.GetLogger().Error(err.Error())
+		cSpan.End()
+		resp.Code = cerrors.ErrCodeMapper[cerrors.ErrGenericInternalServer]
+		resp.Message = cerrors.ErrMessageMapper[cerrors.ErrGenericInternalServer]
+		return resp, cerrors.ErrGenericInternalServer
+	}
+	cSpan.End()
+
+	resp.Code = cerrors.ErrCodeMapper[nil]
+	resp.Message = cerrors.ErrMessageMapper[nil]
+	return resp, nil
+}
+
+// ListUsers lists the accounts attached to a license.
+func (svc *LicenseService) ListUsers(ctx *gin.Context, input *models.LicenseUserListInput) (*response.BaseOutput, error) {
+	rootCtx, span := input.Tracer.Start(input.TracerCtx, "list-users-handler")
+	defer span.End()
+
+	resp := &response.BaseOutput{}
+	svc.logger.WithCustomFields(
+		zap.String(constants.RequestIDField, ctx.GetString(constants.RequestIDField)),
+		zap.String(constants.ContextValueSubject, ctx.GetString(constants.ContextValueSubject)),
+	)
+
+	license, err := svc.queryTenantLicense(ctx, rootCtx, input.Tracer, input.LicenseCommonURI)
+	if err != nil {
+		resp.Code = cerrors.ErrCodeMapper[err]
+		resp.Message = cerrors.ErrMessageMapper[err]
+		return resp, err
+	}
+
+	_, cSpan := input.Tracer.Start(rootCtx, "list-license-users")
+	licenseUsers, total, err := svc.repo.SelectLicenseUsers(ctx, license.ID, input.QueryCommonParam)
+	if err != nil {
+		svc.logger.GetLogger().Error(err.Error())
+		cSpan.End()
+		resp.Code = cerrors.ErrCodeMapper[cerrors.ErrGenericInternalServer]
+		resp.Message = cerrors.ErrMessageMapper[cerrors.ErrGenericInternalServer]
+		return resp, cerrors.ErrGenericInternalServer
+	}
+	cSpan.End()
+
+	outputs := make([]models.LicenseUserOutput, 0)
+	for _, licenseUser := range licenseUsers {
+		output := models.LicenseUserOutput{
+			ID:         licenseUser.ID.String(),
+			TenantName: licenseUser.TenantName,
+			LicenseID:  licenseUser.LicenseID.String(),
+			Username:   licenseUser.Username,
+			CreatedAt:  licenseUser.CreatedAt,
+			UpdatedAt:  licenseUser.UpdatedAt,
+		}
+		if licenseUser.Account != nil {
+			output.Email = licenseUser.Account.Email
+			output.RoleName = licenseUser.Account.RoleName
+		}
+		outputs = append(outputs, output)
+	}
+
+	resp.Code = cerrors.ErrCodeMapper[nil]
+	resp.Message = cerrors.ErrMessageMapper[nil]
+	resp.Count = total
+	resp.Data = outputs
+	return resp, nil
+}
+
+// AttachEntitlements attaches entitlements to a license, on top of the entitlements of its policy.
+func (svc *LicenseService) AttachEntitlements(ctx *gin.Context, input *models.LicenseEntitlementAttachmentInput) (*response.BaseOutput, error) {
+	rootCtx, span := input.Tracer.Start(input.TracerCtx, "attach-entitlements-handler")
+	defer span.End()
+
+	resp := &response.BaseOutput{}
+	svc.logger.WithCustomFields(
+		zap.String(constants.RequestIDField, ctx.GetString(constants.RequestIDField)),
+		zap.String(constants.ContextValueSubject, ctx.GetString(constants.ContextValueSubject)),
+	)
+
+	license, err := svc.queryTenantLicense(ctx, rootCtx, input.Tracer, input.LicenseCommonURI)
+	if err != nil {
+		resp.Code = cerrors.ErrCodeMapper[err]
+		resp.Message = cerrors.ErrMessageMapper[err]
+		return resp, err
+	}
+
+	_, cSpan := input.Tracer.Start(rootCtx, "query-entitlement")
+	svc.logger.GetLogger().Info(fmt.Sprintf("querying entitlements %s", input.EntitlementID))
+	entitlementIDs := make([]uuid.UUID, 0)
+	for _, id := range input.EntitlementID {
+		entitlementIDs = append(entitlementIDs, uuid.MustParse(id))
+	}
+
+	entitlements, err := svc.repo.SelectEntitlementsByPK(ctx, license.TenantName, entitlementIDs)
+	if err != nil {
+		svc.logger.GetLogger().Error(err.Error())
+		cSpan.End()
+		resp.Code = cerrors.ErrCodeMapper[cerrors.ErrGenericInternalServer]
+		resp.Message = cerrors.ErrMessageMapper[cerrors.ErrGenericInternalServer]
+		return resp, cerrors.ErrGenericInternalServer
+	}
+	cSpan.End()
+
+	if len(entitlements) != len(entitlementIDs) {
+		svc.logger.GetLogger().Error("one or more entitlements do not exist")
+		resp.Code = cerrors.ErrCodeMapper[cerrors.ErrEntitlementIDIsInvalid]
+		resp.Message = cerrors.ErrMessageMapper[cerrors.ErrEntitlementIDIsInvalid]
+		return resp, cerrors.ErrEntitlementIDIsInvalid
+	}
+
+	_, cSpan = input.Tracer.Start(rootCtx, "query-attached-entitlements")
+	attached, err := svc.repo.SelectAttachedEntitlementIDs(ctx, license.ID, license.PolicyID, entitlementIDs)
+	if err != nil {
+		svc.logger.GetLogger().Error(err.Error())
+		cSpan.End()
+		resp.Code = cerrors.ErrCodeMapper[cerrors.ErrGenericInternalServer]
+		resp.Message = cerrors.ErrMessageMapper[cerrors.ErrGenericInternalServer]
+		return resp, cerrors.ErrGenericInternalServer
+	}
+	cSpan.End()
+
+	if len(attached) > 0 {
+		svc.logger.GetLogger().Error(fmt.Sprintf("entitlement [%s] is already attached to license [%s]", attached[0], license.ID))
+		resp.Code = cerrors.ErrCodeMapper[cerrors.ErrLicenseEntitlementAlreadyExist]
+		resp.Message = cerrors.ErrMessageMapper[cerrors.ErrLicenseEntitlementAlreadyExist]
+		return resp, cerrors.ErrLicenseEntitlementAlreadyExist
+	}
+
+	_, cSpan = input.Tracer.Start(rootCtx, "insert-license-entitlements")
+	now := time.Now()
+	licenseEntitlements := make([]entities.LicenseEntitlement, 0, len(entitlements))
+	outputs := make([]models.LicenseEntitlementOutput, 0, len(entitlements))
+	for _, entitlement := range entitlements {
+		licenseEntitlementID := uuid.New()
+		licenseEntitlements = append(licenseEntitlements, entities.LicenseEntitlement{
+			ID:            licenseEntitlementID,
+			TenantName:    license.TenantName,
+			LicenseID:     license.ID,
+			EntitlementID: entitlement.ID,
+			CreatedAt:     now,
+			UpdatedAt:     now,
+		})
+		outputs = append(outputs, models.LicenseEntitlementOutput{
+			ID:            licenseEntitlementID.String(),
+			TenantName:    license.TenantName,
+			Source:        constants.LicenseEntitlementSourceLicense,
+			EntitlementID: entitlement.ID.String(),
+			Name:          entitlement.Name,
+			Code:          entitlement.Code,
+			CreatedAt:     now,
+			UpdatedAt:     now,
+		})
+	}
+
+	err = svc.repo.InsertNewLicenseEntitlements(ctx, licenseEntitlements)
+	if err != nil {
+		svc.logger.GetLogger().Error(err.Error())
+		cSpan.End()
+		resp.Code = cerrors.ErrCodeMapper[cerrors.ErrGenericInternalServer]
+		resp.Message = cerrors.ErrMessageMapper[cerrors.ErrGenericInternalServer]
+		return resp, cerrors.ErrGenericInternalServer
+	}
+	cSpan.End()
+
+	resp.Code = cerrors.ErrCodeMapper[nil]
+	resp.Message = cerrors.ErrMessageMapper[nil]
+	resp.Count = len(outputs)
+	resp.Data = outputs
+	return resp, nil
+}
+
+// DetachEntitlements detaches entitlements from a license. Entitlements inherited from the policy can only be
+// detached from the policy itself.
+func (svc *LicenseService) DetachEntitlements(ctx *gin.Context, input *models.LicenseEntitlementDetachmentInput) (*response.BaseOutput, error) {
+	rootCtx, span := input.Tracer.Start(input.TracerCtx, "detach-entitlements-handler")
+	defer span.End()
+
+	resp := &response.BaseOutput{}
+	svc.logger.WithCustomFields(
+		zap.String(constants.RequestIDField, ctx.GetString(constants.RequestIDField)),
+		zap.String(constants.ContextValueSubject, ctx.GetString(constants.ContextValueSubject)),
+	)
+
+	license, err := svc.queryTenantLicense(ctx, rootCtx, input.Tracer, input.LicenseCommonURI)
+	if err != nil {
+		resp.Code = cerrors.ErrCodeMapper[err]
+		resp.Message = cerrors.ErrMessageMapper[err]
+		return resp, err
+	}
+
+	_, cSpan := input.Tracer.Start(rootCtx, "query-license-entitlements")
+	licenseEntitlementIDs := make([]uuid.UUID, 0)
+	for _, id := range input.ID {
+		licenseEntitlementIDs = append(licenseEntitlementIDs, uuid.MustParse(id))
+	}
+
+	attached, err := svc.repo.SelectLicenseEntitlementsByPK(ctx, license.ID, licenseEntitlementIDs)
+	if err != nil {
+		svc.logger.GetLogger().Error(err.Error())
+		cSpan.End()
+		resp.Code = cerrors.ErrCodeMapper[cerrors.ErrGenericInternalServer]
+		resp.Message = cerrors.ErrMessageMapper[cerrors.ErrGenericInternalServer]
+		return resp, cerrors.ErrGenericInternalServer
+	}
+	cSpan.End()
+
+	if len(attached) != len(licenseEntitlementIDs) {
+		svc.logger.GetLogger().Error(fmt.Sprintf("one or more entitlements are not attached to license [%s]", license.ID))
+		resp.Code = cerrors.ErrCodeMapper[cerrors.ErrLicenseEntitlementIsNotAttached]
+		resp.Message = cerrors.ErrMessageMapper[cerrors.ErrLicenseEntitlementIsNotAttached]
+		return resp, cerrors.ErrLicenseEntitlementIsNotAttached
+	}
+
+	_, cSpan = input.Tracer.Start(rootCtx, "delete-license-entitlements")
+	svc.logger.GetLogger().Info(fmt.Sprintf("deleting license entitlements %s", input.ID))
+	err = svc.repo.DeleteLicenseEntitlementsByPK(ctx, licenseEntitlementIDs)
+	if err != nil {
+		svc.logger.GetLogger().Error(err.Error())
+		cSpan.End()
+		resp.Code = cerrors.ErrCodeMapper[cerrors.ErrGenericInternalServer]
+		resp.Message = cerrors.ErrMessageMapper[cerrors.ErrGenericInternalServer]
+		return resp, cerrors.ErrGenericInternalServer
+	}
+	cSpan.End()
+
+	resp.Code = cerrors.ErrCodeMapper[nil]
+	resp.Message = cerrors.ErrMessageMapper[nil]
+	return resp, nil
+}
+
+// ListEntitlements lists the merged entitlements of a license, from both its policy and the license itself.
+func (svc *LicenseService) ListEntitlements(ctx *gin.Context, input *models.LicenseEntitlementListInput) (*response.BaseOutput, error) {
+	rootCtx, span := input.Tracer.Start(input.TracerCtx, "list-entitlements-handler")
+	defer span.End()
+
+	resp := &response.BaseOutput{}
+	svc.logger.WithCustomFields(
+		zap.String(constants.RequestIDField, ctx.GetString(constants.RequestIDField)),
+		zap.String(constants.ContextValueSubject, ctx.GetString(constants.ContextValueSubject)),
+	)
+
+	license, err := svc.queryTenantLicense(ctx, rootCtx, input.Tracer, input.LicenseCommonURI)
+	if err != nil {
+		resp.Code = cerrors.ErrCodeMapper[err]
+		resp.Message = cerrors.ErrMessageMapper[err]
+		return resp, err
+	}
+
+	_, cSpan := input.Tracer.Start(rootCtx, "list-license-entitlements")
+	entitlements, err := svc.queryLicenseEntitlements(ctx, license)
+	if err != nil {
+		svc.logger.GetLogger().Error(err.Error())
+		cSpan.End()
+		resp.Code = cerrors.ErrCodeMapper[cerrors.ErrGenericInternalServer]
+		resp.Message = cerrors.ErrMessageMapper[cerrors.ErrGenericInternalServer]
+		return resp, cerrors.ErrGenericInternalServer
+	}
+	cSpan.End()
+
+	// Paginate over the merged entitlements
+	total := len(entitlements)
+	offset := min(max(utils.DerefPointer(input.Offset), 0), total)
+	end := min(offset+max(utils.DerefPointer(input.Limit), 0), total)
+
+	resp.Code = cerrors.ErrCodeMapper[nil]
+	resp.Message = cerrors.ErrMessageMapper[nil]
+	resp.Count = total
+	resp.Data = entitlements[offset:end]
+	return resp, nil
+}
+
+// Tokens generates a license token, authenticating client-side requests on the license and its machines only.
+// Only the hash of the token is stored.
+func (svc *LicenseService) Tokens(ctx *gin.Context, input *models.LicenseTokensInput) (*response.BaseOutput, error) {
+	rootCtx, span := input.Tracer.Start(input.TracerCtx, "tokens-handler")
+	defer span.End()
+
+	resp := &response.BaseOutput{}
+	svc.logger.WithCustomFields(
+		zap.String(constants.RequestIDField, ctx.GetString(constants.RequestIDField)),
+		zap.String(constants.ContextValueSubject, ctx.GetString(constants.ContextValueSubject)),
+	)
+
+	license, err := svc.queryTenantLicense(ctx, rootCtx, input.Tracer, input.LicenseCommonURI)
+	if err != nil {
+		resp.Code = cerrors.ErrCodeMapper[err]
+		resp.Message = cerrors.ErrMessageMapper[err]
+		return resp, err
+	}
+
+	_, cSpan := input.Tracer.Start(rootCtx, "generate-license-token")
+	token, err := utils.GenerateLicenseToken()
+	if err != nil {
+		svc.logger.GetLogger().Error(err.Error())
+		cSpan.End()
+		resp.Code = cerrors.ErrCodeMapper[cerrors.ErrGenericInternalServer]
+		resp.Message = cerrors.ErrMessageMapper[cerrors.ErrGenericInternalServer]
+		return resp, cerrors.ErrGenericInternalServer
+	}
+
+	licenseToken := &entities.LicenseToken{
+		ID:             uuid.New(),
+		LicenseID:      license.ID,
+		TenantName:     license.TenantName,
+		Name:           utils.DerefPointer(input.Name),
+		TokenHash:      utils.HashToken(token),
+		MaxActivations: utils.DerefPointer(input.MaxActivations),
+		CreatedAt:      time.Now(),
+	}
+	if input.Expiry != nil {
+		licenseToken.Expiry, _ = time.Parse(time.RFC3339, utils.DerefPointer(input.Expiry))
+	}
+
+	err = svc.repo.InsertNewLicenseToken(ctx, licenseToken)
+	if err != nil {
+		svc.logger.GetLogger().Error(err.Error())
+		cSpan.End()
+		resp.Code = cerrors.ErrCodeMapper[cerrors.ErrGenericInternalServer]
+		resp.Message = cerrors.ErrMessageMapper[cerrors.ErrGenericInternalServer]
+		return resp, cerrors.ErrGenericInternalServer
+	}
+	cSpan.End()
+
+	resp.Code = cerrors.ErrCodeMapper[nil]
+	resp.Message = cerrors.ErrMessageMapper[nil]
+	resp.Data = models.LicenseTokenOutput{
+		ID:             licenseToken.ID.String(),
+		Token:          token,
+		LicenseID:      licenseToken.LicenseID.String(),
+		Name:           licenseToken.Name,
+		MaxActivations: licenseToken.MaxActivations,
+		Expiry:         licenseToken.Expiry,
+		CreatedAt:      licenseToken.CreatedAt,
+	}
+	return resp, nil
+}
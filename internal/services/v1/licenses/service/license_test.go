@@ -0,0 +1,394 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go-license-management/internal/cerrors"
+	"go-license-management/internal/constants"
+	"go-license-management/internal/infrastructure/database/entities"
+	"go-license-management/internal/services/v1/licenses/models"
+	"go-license-management/internal/services/v1/licenses/repository"
+	"go.opentelemetry.io/otel/trace/noop"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// fakeLicenseRepository keeps the tenants, accounts, products, policies, pooled keys, licenses, license users,
+// entitlements and machines in memory, and records the sweeps of the expired licenses and nonces.
+// The other methods are not implemented.
+type fakeLicenseRepository struct {
+	repository.ILicense
+	tenants      []*entities.Tenant
+	accounts     []entities.Account
+	products     []*entities.Product
+	policies     []*entities.Policy
+	keys         []*entities.Key
+	licenses     []*entities.License
+	licenseUsers []entities.LicenseUser
+	// policyEntitlements and licenseEntitlements must have their entitlement
+	policyEntitlements  []entities.PolicyEntitlement
+	licenseEntitlements []entities.LicenseEntitlement
+	machines            []*entities.Machine
+	// concurrentUsers are attached once the users of the license have been checked, as if they had been attached
+	// concurrently in the meantime
+	concurrentUsers []entities.LicenseUser
+	expiredSweeps   []time.Time
+	nonceSweeps     []time.Time
+	expired         int64
+	err             error
+}
+
+func (repo *fakeLicenseRepository) SelectTenantByName(ctx context.Context, tenantName string) (*entities.Tenant, error) {
+	for _, tenant := range repo.tenants {
+		if tenant.Name == tenantName {
+			return tenant, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (repo *fakeLicenseRepository) SelectProductByPK(ctx context.Context, productID uuid.UUID) (*entities.Product, error) {
+	for _, product := range repo.products {
+		if product.ID == productID {
+			return product, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (repo *fakeLicenseRepository) SelectPolicyByPK(ctx context.Context, policyID uuid.UUID) (*entities.Policy, error) {
+	for _, policy := range repo.policies {
+		if policy.ID == policyID {
+			return policy, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (repo *fakeLicenseRepository) InsertNewLicense(ctx context.Context, license *entities.License) error {
+	repo.licenses = append(repo.licenses, license)
+	return nil
+}
+
+func (repo *fakeLicenseRepository) InsertNewLicenseWithPooledKey(ctx context.Context, license *entities.License) error {
+	for i, key := range repo.keys {
+		if key.PolicyID == license.PolicyID {
+			license.Key = key.Key
+			repo.keys = append(repo.keys[:i], repo.keys[i+1:]...)
+			repo.licenses = append(repo.licenses, license)
+			return nil
+		}
+	}
+	return cerrors.ErrLicenseKeyPoolIsExhausted
+}
+
+func (repo *fakeLicenseRepository) SelectLicenseByPK(ctx context.Context, licenseID uuid.UUID) (*entities.License, error) {
+	for _, license := range repo.licenses {
+		if license.ID == licenseID {
+			return license, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (repo *fakeLicenseRepository) SelectAccountsByUsernames(ctx context.Context, tenantName string, usernames []string) ([]entities.Account, error) {
+	accounts := make([]entities.Account, 0)
+	for _, account := range repo.accounts {
+		for _, username := range usernames {
+			if account.TenantName == tenantName && account.Username == username {
+				accounts = append(accounts, account)
+			}
+		}
+	}
+	return accounts, nil
+}
+
+func (repo *fakeLicenseRepository) SelectLicenseUsersByUsernames(ctx context.Context, licenseID uuid.UUID, usernames []string) ([]entities.LicenseUser, error) {
+	licenseUsers := make([]entities.LicenseUser, 0)
+	for _, licenseUser := range repo.licenseUsers {
+		for _, username := range usernames {
+			if licenseUser.LicenseID == licenseID && licenseUser.Username == username {
+				licenseUsers = append(licenseUsers, licenseUser)
+			}
+		}
+	}
+	return licenseUsers, nil
+}
+
+func (repo *fakeLicenseRepository) InsertNewLicenseUsersAndUpdateLicense(ctx context.Context, license *entities.License, licenseUsers []entities.LicenseUser, enforceMaxUsers bool) error {
+	repo.licenseUsers = append(repo.licenseUsers, repo.concurrentUsers...)
+	repo.concurrentUsers = nil
+
+	users := 0
+	for _, licenseUser := range repo.licenseUsers {
+		if licenseUser.LicenseID == license.ID {
+			users++
+		}
+	}
+	if enforceMaxUsers && license.MaxUsers > 0 && users+len(licenseUsers) > license.MaxUsers {
+		return cerrors.ErrLicenseMaxUsersExceeded
+	}
+
+	repo.licenseUsers = append(repo.licenseUsers, licenseUsers...)
+	license.Users = users + len(licenseUsers)
+	return nil
+}
+
+func (repo *fakeLicenseRepository) UpdateLicenseByPK(ctx context.Context, license *entities.License) (*entities.License, error) {
+	for i, current := range repo.licenses {
+		if current.ID == license.ID {
+			repo.licenses[i] = license
+			return license, nil
+		}
+	}
+	repo.licenses = append(repo.licenses, license)
+	return license, nil
+}
+
+func (repo *fakeLicenseRepository) CheckActiveMachineExistByFingerprint(ctx context.Context, licenseID uuid.UUID, fingerprint string) (bool, error) {
+	for _, machine := range repo.machines {
+		if machine.LicenseID == licenseID && machine.Fingerprint == fingerprint && !machine.Deactivated {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (repo *fakeLicenseRepository) SelectPolicyEntitlementsByPolicyID(ctx context.Context, policyID uuid.UUID) ([]entities.PolicyEntitlement, error) {
+	policyEntitlements := make([]entities.PolicyEntitlement, 0)
+	for _, policyEntitlement := range repo.policyEntitlements {
+		if policyEntitlement.PolicyID == policyID {
+			policyEntitlements = append(policyEntitlements, policyEntitlement)
+		}
+	}
+	return policyEntitlements, nil
+}
+
+func (repo *fakeLicenseRepository) SelectLicenseEntitlementsByLicenseID(ctx context.Context, licenseID uuid.UUID) ([]entities.LicenseEntitlement, error) {
+	licenseEntitlements := make([]entities.LicenseEntitlement, 0)
+	for _, licenseEntitlement := range repo.licenseEntitlements {
+		if licenseEntitlement.LicenseID == licenseID {
+			licenseEntitlements = append(licenseEntitlements, licenseEntitlement)
+		}
+	}
+	return licenseEntitlements, nil
+}
+
+func (repo *fakeLicenseRepository) UpdateExpiredLicenses(ctx context.Context, now time.Time) (int64, error) {
+	if repo.err != nil {
+		return 0, repo.err
+	}
+	repo.expiredSweeps = append(repo.expiredSweeps, now)
+	return repo.expired, nil
+}
+
+func (repo *fakeLicenseRepository) DeleteExpiredLicenseNonces(ctx context.Context, now time.Time) (int64, error) {
+	repo.nonceSweeps = append(repo.nonceSweeps, now)
+	return 0, nil
+}
+
+func TestLicenseService_ExpireLicenses(t *testing.T) {
+	repo := &fakeLicenseRepository{expired: 2}
+	svc := NewLicenseService(WithRepository(repo))
+
+	before := time.Now()
+	affected, err := svc.ExpireLicenses(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), affected)
+
+	// The licenses past their expiry at the time of the sweep are expired, and the nonces out of their replay window
+	// are deleted
+	assert.Len(t, repo.expiredSweeps, 1)
+	assert.WithinDuration(t, before, repo.expiredSweeps[0], time.Second)
+	assert.Len(t, repo.nonceSweeps, 1)
+
+	// The nonces are kept when the licenses could not be expired
+	repo.err = errors.New("database is down")
+	_, err = svc.ExpireLicenses(context.Background())
+	assert.ErrorIs(t, err, repo.err)
+	assert.Len(t, repo.nonceSweeps, 1)
+}
+
+func newLicenseRegistrationInput(productID, policyID uuid.UUID) *models.LicenseRegistrationInput {
+	tenantName, product, policy, name := "tenant", productID.String(), policyID.String(), "license"
+	input := &models.LicenseRegistrationInput{
+		TracerCtx: context.Background(),
+		Tracer:    noop.NewTracerProvider().Tracer("test"),
+		ProductID: &product,
+		PolicyID:  &policy,
+		Name:      &name,
+	}
+	input.TenantName = &tenantName
+	return input
+}
+
+func TestLicenseService_Create_PooledKey(t *testing.T) {
+	product := &entities.Product{ID: uuid.New(), TenantName: "tenant"}
+	pooled := &entities.Policy{ID: uuid.New(), ProductID: product.ID, TenantName: "tenant", UsePool: true}
+	other := &entities.Policy{ID: uuid.New(), ProductID: product.ID, TenantName: "tenant", UsePool: true}
+	repo := &fakeLicenseRepository{
+		tenants:  []*entities.Tenant{{Name: "tenant"}},
+		products: []*entities.Product{product},
+		policies: []*entities.Policy{pooled, other},
+		keys:     []*entities.Key{{ID: uuid.New(), Key: "pooled", PolicyID: pooled.ID}},
+	}
+	svc := NewLicenseService(WithRepository(repo))
+	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+
+	// The license key is pulled from the pool of the policy
+	resp, err := svc.Create(ctx, newLicenseRegistrationInput(product.ID, pooled.ID))
+	assert.NoError(t, err)
+	assert.Equal(t, "pooled", resp.Data.(models.LicenseInfoOutput).LicenseKey)
+	assert.Empty(t, repo.keys)
+
+	// An empty pool is reported, instead of generating a key
+	for _, policy := range []*entities.Policy{pooled, other} {
+		resp, err = svc.Create(ctx, newLicenseRegistrationInput(product.ID, policy.ID))
+		assert.ErrorIs(t, err, cerrors.ErrLicenseKeyPoolIsExhausted)
+		assert.Equal(t, cerrors.ErrCodeMapper[cerrors.ErrLicenseKeyPoolIsExhausted], resp.Code)
+	}
+	assert.Len(t, repo.licenses, 1)
+}
+
+func TestLicenseService_Update_ProtectedPolicy(t *testing.T) {
+	cases := []struct {
+		name            string
+		fromProtected   bool
+		toProtected     bool
+		protectedAccess bool
+		err             error
+	}{
+		{name: "unprotected policies"},
+		{name: "out of a protected policy", fromProtected: true, err: cerrors.ErrPolicyIsProtected},
+		{name: "under a protected policy", toProtected: true, err: cerrors.ErrPolicyIsProtected},
+		{name: "out of a protected policy by an admin", fromProtected: true, protectedAccess: true},
+		{name: "under a protected policy by an admin", toProtected: true, protectedAccess: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			product := &entities.Product{ID: uuid.New(), TenantName: "tenant"}
+			from := &entities.Policy{ID: uuid.New(), ProductID: product.ID, TenantName: "tenant", Protected: c.fromProtected}
+			to := &entities.Policy{ID: uuid.New(), ProductID: product.ID, TenantName: "tenant", Protected: c.toProtected, MaxMachines: 5}
+			license := &entities.License{
+				ID:         uuid.New(),
+				TenantName: "tenant",
+				ProductID:  product.ID,
+				PolicyID:   from.ID,
+				Status:     constants.LicenseStatusActive,
+				Policy:     from,
+				Product:    product,
+			}
+			repo := &fakeLicenseRepository{
+				tenants:  []*entities.Tenant{{Name: "tenant"}},
+				policies: []*entities.Policy{from, to},
+				licenses: []*entities.License{license},
+			}
+			svc := NewLicenseService(WithRepository(repo))
+			ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+			ctx.Set(constants.ContextValueProtectedPolicyAccess, c.protectedAccess)
+
+			tenantName, licenseID, policyID := "tenant", license.ID.String(), to.ID.String()
+			input := &models.LicenseUpdateInput{
+				TracerCtx: context.Background(),
+				Tracer:    noop.NewTracerProvider().Tracer("test"),
+				PolicyID:  &policyID,
+			}
+			input.TenantName = &tenantName
+			input.LicenseID = &licenseID
+
+			resp, err := svc.Update(ctx, input)
+			if c.err != nil {
+				assert.ErrorIs(t, err, c.err)
+				assert.Equal(t, cerrors.ErrCodeMapper[c.err], resp.Code)
+				assert.Equal(t, from.ID, license.PolicyID)
+				assert.Zero(t, license.MaxMachines)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, to.ID, license.PolicyID)
+			assert.Equal(t, 5, license.MaxMachines)
+		})
+	}
+}
+
+func TestLicenseService_Update_MaxUses(t *testing.T) {
+	policy := &entities.Policy{ID: uuid.New(), TenantName: "tenant"}
+	product := &entities.Product{ID: uuid.New(), TenantName: "tenant"}
+	license := &entities.License{
+		ID:         uuid.New(),
+		TenantName: "tenant",
+		ProductID:  product.ID,
+		PolicyID:   policy.ID,
+		MaxUses:    10,
+		MaxUsers:   3,
+		Policy:     policy,
+		Product:    product,
+	}
+	repo := &fakeLicenseRepository{
+		tenants:  []*entities.Tenant{{Name: "tenant"}},
+		licenses: []*entities.License{license},
+	}
+	svc := NewLicenseService(WithRepository(repo))
+	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+
+	tenantName, licenseID, maxUses := "tenant", license.ID.String(), 20
+	input := &models.LicenseUpdateInput{
+		TracerCtx: context.Background(),
+		Tracer:    noop.NewTracerProvider().Tracer("test"),
+		MaxUses:   &maxUses,
+	}
+	input.TenantName = &tenantName
+	input.LicenseID = &licenseID
+
+	_, err := svc.Update(ctx, input)
+	assert.NoError(t, err)
+	assert.Equal(t, 20, license.MaxUses)
+	assert.Equal(t, 3, license.MaxUsers)
+}
+
+func TestLicenseService_AttachUsers_MaxUsers(t *testing.T) {
+	license := &entities.License{
+		ID:         uuid.New(),
+		TenantName: "tenant",
+		MaxUsers:   2,
+		Policy:     &entities.Policy{OverageStrategy: constants.PolicyOverageStrategyNoOverage},
+	}
+	repo := &fakeLicenseRepository{
+		tenants:  []*entities.Tenant{{Name: "tenant"}},
+		accounts: []entities.Account{{TenantName: "tenant", Username: "first"}, {TenantName: "tenant", Username: "second"}},
+		licenses: []*entities.License{license},
+		concurrentUsers: []entities.LicenseUser{
+			{ID: uuid.New(), TenantName: "tenant", LicenseID: license.ID, Username: "concurrent"},
+		},
+	}
+	svc := NewLicenseService(WithRepository(repo))
+	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+
+	tenantName, licenseID := "tenant", license.ID.String()
+	input := &models.LicenseUsersInput{
+		TracerCtx: context.Background(),
+		Tracer:    noop.NewTracerProvider().Tracer("test"),
+		Usernames: []string{"first", "second"},
+	}
+	input.TenantName = &tenantName
+	input.LicenseID = &licenseID
+
+	// The users are within the max users when checked, but a user is attached concurrently before they are attached
+	resp, err := svc.AttachUsers(ctx, input)
+	assert.ErrorIs(t, err, cerrors.ErrLicenseMaxUsersExceeded)
+	assert.Equal(t, cerrors.ErrCodeMapper[cerrors.ErrLicenseMaxUsersExceeded], resp.Code)
+	assert.Len(t, repo.licenseUsers, 1)
+
+	// The users are attached regardless of the max users when the policy allows overages
+	license.Policy.OverageStrategy = constants.PolicyOverageStrategyAlwaysAllow
+	_, err = svc.AttachUsers(ctx, input)
+	assert.NoError(t, err)
+	assert.Len(t, repo.licenseUsers, 3)
+	assert.Equal(t, 3, license.Users)
+}
@@ -1,12 +1,15 @@
 package service
 
 import (
+	"context"
 	"crypto/md5"
 	"crypto/sha1"
 	"crypto/sha256"
+	"database/sql"
 	"encoding/base64"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -14,8 +17,10 @@ import (
 	"go-license-management/internal/constants"
 	"go-license-management/internal/infrastructure/database/entities"
 	"go-license-management/internal/infrastructure/models/license_attribute"
+	"go-license-management/internal/infrastructure/signer"
 	"go-license-management/internal/services/v1/licenses/models"
 	"go-license-management/internal/utils"
+	"go.opentelemetry.io/otel/trace"
 	"strconv"
 	"strings"
 	"time"
@@ -87,48 +92,114 @@ func (svc *LicenseService) generateLicense(ctx *gin.Context, input *models.Licen
 		license.MaxUsers = utils.DerefPointer(input.MaxUsers)
 	}
 
-	// Generating license key
-	svc.logger.GetLogger().Info("generating license key")
-	licenseKey := utils.GenerateToken()
-	license.Key = licenseKey
+	// Generating license key. Pooled policies pull the key from the pool when the license is inserted
+	switch {
+	case policy.SignedKeys:
+		svc.logger.GetLogger().Info(fmt.Sprintf("generating signed license key using [%s] scheme", policy.Scheme))
+		licenseKey, err := svc.generateSignedLicenseKey(ctx, license, policy)
+		if err != nil {
+			return nil, err
+		}
+		license.Key = licenseKey
+	case !policy.UsePool:
+		svc.logger.GetLogger().Info("generating license key")
+		licenseKey := utils.GenerateToken()
+		license.Key = licenseKey
+	}
 
 	return license, nil
 }
 
+// generateSignedLicenseKey signs the license ID, expiry, max machines and entitlements of the license with the policy
+// private key, so the license key itself can be verified offline with the policy public key.
+// The signed values are the ones at issuance, the key must be reissued for a renewal or a later change to be reflected.
+func (svc *LicenseService) generateSignedLicenseKey(ctx *gin.Context, license *entities.License, policy *entities.Policy) (string, error) {
+	entitlements, err := svc.queryLicenseEntitlements(ctx, license)
+	if err != nil {
+		return "", err
+	}
+
+	policySigner, err := signer.GetInstance().PolicySigner(ctx, policy)
+	if err != nil {
+		return "", err
+	}
+
+	payload := models.LicenseKeyPayload{
+		KID:         utils.KeyID(policySigner.PublicKey()),
+		LicenseID:   license.ID.String(),
+		Tenant:      license.TenantName,
+		ProductID:   license.ProductID.String(),
+		PolicyID:    license.PolicyID.String(),
+		MaxMachines: license.MaxMachines,
+		Issued:      license.CreatedAt,
+	}
+	for _, entitlement := range entitlements {
+		payload.Entitlements = append(payload.Entitlements, entitlement.Code)
+	}
+	if !license.Expiry.IsZero() {
+		payload.Expiry = utils.RefPointer(license.Expiry)
+	}
+
+	return signer.NewSignedKey(ctx, policySigner, payload)
+}
+
 // validateLicense validates a license. This will check the following: if the license is suspended, if the license is expired,
 // if the license is overdue for check-in, and if the license meets its machine requirements (if strict).
-func (svc *LicenseService) validateLicense(ctx *gin.Context, license *entities.License) (*models.LicenseValidationOutput, error) {
+// Node-locked licenses without a machine report `no_machine`, floating licenses without a machine report `no_machines`.
+// If a scope is provided, the license must also match every scoped assertion.
+func (svc *LicenseService) validateLicense(ctx *gin.Context, license *entities.License, scope *license_attribute.LicenseValidationScope) (*models.LicenseValidationOutput, error) {
 	resp := &models.LicenseValidationOutput{}
 
-	// Checking license status
-	switch license.Status {
-	case constants.LicenseStatusNotActivated:
-		resp.Valid = true
-		if license.MachinesCount == 0 && license.Policy.MaxMachines == 1 {
-			resp.Code = constants.LicenseValidationStatusNoMachine
-		} else {
+	// Checking license expiry. Licenses past their expiry are flagged as `expired` by the expiration sweeper,
+	// the expiry is checked here as well so a license does not validate in between two sweeps.
+	// Expired licenses keeping their access go through the remaining checks like active licenses.
+	expired := false
+	if license.Status != constants.LicenseStatusBanned && license.Status != constants.LicenseStatusSuspended {
+		if license.Status == constants.LicenseStatusExpired || (!license.Expiry.IsZero() && time.Now().After(license.Expiry)) {
+			expired = true
+			svc.logger.GetLogger().Info(fmt.Sprintf("license [%s] is expired, applying expiration strategy [%s]", license.ID, license.Policy.ExpirationStrategy))
+			switch license.Policy.ExpirationStrategy {
+			case constants.PolicyExpirationStrategyRestrictAccess:
+				resp.Valid = false
+				resp.Code = constants.LicenseValidationStatusExpired
+				return resp, nil
+			case constants.PolicyExpirationStrategyMaintainAccess:
+				resp.Valid = true
+				resp.Code = constants.LicenseValidationStatusExpired
+			case constants.PolicyExpirationStrategyAllowAccess:
+				// Expired licenses are validated as if they were still active
+				resp.Valid = true
+				resp.Code = constants.LicenseValidationStatusValid
+			default:
+				resp.Valid = false
+				resp.Code = constants.LicenseValidationStatusAccessRevoked
+				return resp, nil
+			}
+		}
+	}
+
+	// Checking license status, the status of expired licenses is handled by the expiration strategy
+	if !expired {
+		switch license.Status {
+		case constants.LicenseStatusNotActivated:
+			resp.Valid = true
+			if license.MachinesCount == 0 && license.Policy.MaxMachines == 1 {
+				resp.Code = constants.LicenseValidationStatusNoMachine
+			} else {
+				resp.Code = constants.LicenseValidationStatusValid
+			}
+		case constants.LicenseStatusActive, constants.LicenseStatusInactive:
+			resp.Valid = true
 			resp.Code = constants.LicenseValidationStatusValid
+		case constants.LicenseStatusBanned:
+			resp.Valid = false
+			resp.Code = constants.LicenseValidationStatusBanned
+			return resp, nil
+		case constants.LicenseStatusSuspended:
+			resp.Valid = false
+			resp.Code = constants.LicenseValidationStatusSuspended
+			return resp, nil
 		}
-	case constants.LicenseStatusActive:
-		resp.Valid = true
-		resp.Code = constants.LicenseValidationStatusValid
-		return resp, nil
-	case constants.LicenseStatusInactive:
-		resp.Valid = true
-		resp.Code = constants.LicenseValidationStatusValid
-		return resp, nil
-	case constants.LicenseStatusBanned:
-		resp.Valid = false
-		resp.Code = constants.LicenseValidationStatusBanned
-		return resp, nil
-	case constants.LicenseStatusExpired:
-		resp.Valid = false
-		resp.Code = constants.LicenseValidationStatusExpired
-		return resp, nil
-	case constants.LicenseStatusSuspended:
-		resp.Valid = false
-		resp.Code = constants.LicenseValidationStatusSuspended
-		return resp, nil
 	}
 
 	// If license policy requires periodic check-in, then validate LastCheckInAt
@@ -163,10 +234,42 @@ func (svc *LicenseService) validateLicense(ctx *gin.Context, license *entities.L
 				resp.Code = constants.LicenseValidationStatusOverdue
 			}
 		}
-		return resp, nil
+		if !resp.Valid {
+			return resp, nil
+		}
+	}
+
+	// Strict policies require the license's machines count to be within the allowed range:
+	// exactly 1 machine for node-locked policies, between 1 and max machines for floating policies.
+	if license.Policy.Strict {
+		switch {
+		case license.MachinesCount == 0:
+			resp.Valid = false
+			if license.Policy.Floating {
+				resp.Code = constants.LicenseValidationStatusNoMachines
+			} else {
+				resp.Code = constants.LicenseValidationStatusNoMachine
+			}
+			return resp, nil
+		case !license.Policy.Floating && license.MachinesCount > 1,
+			license.Policy.Floating && license.MaxMachines > 0 && license.MachinesCount > license.MaxMachines:
+			resp.Valid = false
+			resp.Code = constants.LicenseValidationStatusTooManyMachine
+			return resp, nil
+		}
 	}
 
-	if license.MachinesCount > license.MaxMachines {
+	if scope != nil {
+		scopeResp, err := svc.validateLicenseScope(ctx, license, scope)
+		if err != nil {
+			return nil, err
+		}
+		if scopeResp != nil {
+			return scopeResp, nil
+		}
+	}
+
+	if license.MaxMachines > 0 && license.MachinesCount > license.MaxMachines {
 		resp.Code = constants.LicenseValidationStatusTooManyMachine
 		switch license.Policy.OverageStrategy {
 		case constants.PolicyOverageStrategyAlwaysAllow:
@@ -192,6 +295,117 @@ func (svc *LicenseService) validateLicense(ctx *gin.Context, license *entities.L
 	return resp, nil
 }
 
+// validateLicenseScope checks the license against the validation scope: the fingerprint must belong to an activated
+// machine of the license, the product and policy must match, and every entitlement must be granted by the license
+// or its policy. It returns nil if the license matches the scope.
+func (svc *LicenseService) validateLicenseScope(ctx *gin.Context, license *entities.License, scope *license_attribute.LicenseValidationScope) (*models.LicenseValidationOutput, error) {
+	if scope.Fingerprint != nil {
+		svc.logger.GetLogger().Info(fmt.Sprintf("verifying fingerprint scope of license [%s]", license.ID))
+		exist, err := svc.repo.CheckActiveMachineExistByFingerprint(ctx, license.ID, utils.DerefPointer(scope.Fingerprint))
+		if err != nil {
+			return nil, err
+		}
+		if !exist {
+			return &models.LicenseValidationOutput{
+				Valid: false,
+				Code:  constants.LicenseValidationStatusFingerprintScopeMismatch,
+			}, nil
+		}
+	}
+
+	if scope.ProductID != nil && utils.DerefPointer(scope.ProductID) != license.ProductID.String() {
+		return &models.LicenseValidationOutput{
+			Valid: false,
+			Code:  constants.LicenseValidationStatusProductScopeMismatch,
+		}, nil
+	}
+
+	if scope.PolicyID != nil && utils.DerefPointer(scope.PolicyID) != license.PolicyID.String() {
+		return &models.LicenseValidationOutput{
+			Valid: false,
+			Code:  constants.LicenseValidationStatusPolicyScopeMismatch,
+		}, nil
+	}
+
+	if len(scope.Entitlements) > 0 {
+		svc.logger.GetLogger().Info(fmt.Sprintf("verifying entitlements scope of license [%s]", license.ID))
+		entitlements, err := svc.queryLicenseEntitlements(ctx, license)
+		if err != nil {
+			return nil, err
+		}
+
+		granted := make(map[string]bool, len(entitlements))
+		for _, entitlement := range entitlements {
+			granted[entitlement.Code] = true
+		}
+
+		missing := make([]string, 0)
+		for _, code := range scope.Entitlements {
+			if !granted[code] {
+				missing = append(missing, code)
+			}
+		}
+		if len(missing) > 0 {
+			return &models.LicenseValidationOutput{
+				Valid:               false,
+				Code:                constants.LicenseValidationStatusEntitlementsMissing,
+				MissingEntitlements: missing,
+			}, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// recordLicenseNonce records the nonce of a license action for the replay window of the service.
+// Returns ErrLicenseNonceIsReplayed if the nonce was already used for the license within that window.
+func (svc *LicenseService) recordLicenseNonce(ctx *gin.Context, license *entities.License, action string, nonce int) error {
+	now := time.Now()
+	recorded, err := svc.repo.InsertLicenseNonce(ctx, &entities.LicenseNonce{
+		ID:         uuid.New(),
+		TenantName: license.TenantName,
+		LicenseID:  license.ID,
+		Nonce:      nonce,
+		Action:     action,
+		ExpiresAt:  now.Add(svc.nonceTTL),
+		CreatedAt:  now,
+	})
+	if err != nil {
+		return err
+	}
+
+	if !recorded {
+		return fmt.Errorf("%w: nonce [%d] of license [%s]", cerrors.ErrLicenseNonceIsReplayed, nonce, license.ID.String())
+	}
+	return nil
+}
+
+// signValidationResponse signs the validation result along with the nonce of the request with the policy key pair.
+// The signed response uses the signed key format `<scheme>/<payload>.<signature>`.
+func (svc *LicenseService) signValidationResponse(ctx *gin.Context, license *entities.License, output *models.LicenseValidationOutput, nonce int) error {
+	policySigner, err := signer.GetInstance().PolicySigner(ctx, license.Policy)
+	if err != nil {
+		return err
+	}
+
+	signedResponse, err := signer.NewSignedKey(ctx, policySigner, models.LicenseValidationPayload{
+		LicenseID:           license.ID.String(),
+		Tenant:              license.TenantName,
+		Valid:               output.Valid,
+		Code:                output.Code,
+		MissingEntitlements: output.MissingEntitlements,
+		Nonce:               nonce,
+		Issued:              time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+
+	output.Nonce = &nonce
+	output.SignedResponse = signedResponse
+	return nil
+}
+
 // suspendLicense updates the active license status to `suspended`
 func (svc *LicenseService) suspendLicense(ctx *gin.Context, license *entities.License) (*entities.License, error) {
 	if license.Status == constants.LicenseStatusNotActivated {
@@ -260,6 +474,7 @@ func (svc *LicenseService) renewLicense(ctx *gin.Context, license *entities.Lice
 		}
 	} else {
 		license.Expiry = time.Time{}
+		restoreExpiredLicenseStatus(license)
 	}
 
 	svc.logger.GetLogger().Info(fmt.Sprintf("renewing license [%s]", license.ID))
@@ -271,6 +486,23 @@ func (svc *LicenseService) renewLicense(ctx *gin.Context, license *entities.Lice
 	return license, nil
 }
 
+// restoreExpiredLicenseStatus reverts the `expired` status set by the expiration sweeper once the license expiry
+// has been removed or moved into the future. The license is `active` if it has machines, `inactive` otherwise.
+func restoreExpiredLicenseStatus(license *entities.License) {
+	if license.Status != constants.LicenseStatusExpired {
+		return
+	}
+	if !license.Expiry.IsZero() && !time.Now().Before(license.Expiry) {
+		return
+	}
+
+	if license.MachinesCount > 0 {
+		license.Status = constants.LicenseStatusActive
+	} else {
+		license.Status = constants.LicenseStatusInactive
+	}
+}
+
 // checkoutLicense check-outs a license. This will generate a snapshot of the license at time of checkout,
 // encoded into a license file certificate that can be decoded and used for licensing offline and air-gapped
 // environments. The algorithm will depend on the policy's scheme.
@@ -279,6 +511,39 @@ func (svc *LicenseService) checkoutLicense(ctx *gin.Context, license *entities.L
 	var err error
 	policy := license.Policy
 
+	// Embed the merged entitlements so that features can be gated offline
+	entitlements, err := svc.queryLicenseEntitlements(ctx, license)
+	if err != nil {
+		return nil, err
+	}
+	entitlementCodes := make([]string, 0, len(entitlements))
+	for _, entitlement := range entitlements {
+		entitlementCodes = append(entitlementCodes, entitlement.Code)
+	}
+
+	issued := time.Now()
+
+	// Assign TTL to license certificate file
+	ttlParam := strings.ToLower(ctx.Query("ttl"))
+	ttl := constants.DefaultLicenseTTL
+	if ttlParam != "" {
+		ttl, err = strconv.Atoi(ttlParam)
+		if err != nil {
+			svc.logger.GetLogger().Error(err.Error())
+			ttl = constants.DefaultLicenseTTL
+		}
+		// If ttl is smaller than 1 hour, default to 1 hour
+		if ttl < constants.MinimumLicenseTTL {
+			ttl = constants.MinimumLicenseTTL
+		}
+		// If ttl is larger than 1 year, default to 1 year
+		if ttl > constants.MaximumLicenseTTL {
+			ttl = constants.MaximumLicenseTTL
+		}
+	}
+
+	expiry := issued.Add(time.Duration(ttl) * time.Second)
+
 	licenseOutput := models.LicenseInfoOutput{
 		LicenseID:      license.ID.String(),
 		ProductID:      license.ProductID.String(),
@@ -289,6 +554,8 @@ func (svc *LicenseService) checkoutLicense(ctx *gin.Context, license *entities.L
 		Sha1Checksum:   fmt.Sprintf("%x", sha1.Sum([]byte(license.Key))),
 		Sha256Checksum: fmt.Sprintf("%x", sha256.Sum256([]byte(license.Key))),
 		Status:         license.Status,
+		Users:          license.Users,
+		Entitlements:   entitlementCodes,
 		Metadata:       license.Metadata,
 		Expiry:         license.Expiry,
 		CreatedAt:      license.CreatedAt,
@@ -306,6 +573,7 @@ func (svc *LicenseService) checkoutLicense(ctx *gin.Context, license *entities.L
 			Strict:             license.Policy.Strict,
 			Floating:           license.Policy.Floating,
 			UsePool:            license.Policy.UsePool,
+			SignedKeys:         license.Policy.SignedKeys,
 			RateLimited:        license.Policy.RateLimited,
 			Encrypted:          license.Policy.Encrypted,
 			Protected:          license.Policy.Protected,
@@ -327,13 +595,26 @@ func (svc *LicenseService) checkoutLicense(ctx *gin.Context, license *entities.L
 		},
 	}
 
+	// The issued and expiry timestamps are signed along with the license, so the TTL can be enforced offline
+	licenseFileOutput := models.LicenseFileOutput{
+		Meta: models.LicenseFileMeta{
+			Version:   constants.CertificateFileVersion,
+			Tenant:    license.TenantName,
+			LicenseID: license.ID.String(),
+			Issued:    issued,
+			Expiry:    expiry,
+			TTL:       ttl,
+		},
+		Data: licenseOutput,
+	}
+
 	svc.logger.GetLogger().Info(fmt.Sprintf("generating snapshot of license [%s]", license.ID.String()))
-	switch policy.Scheme {
-	case constants.PolicySchemeED25519:
-		encodedLicense, err = utils.NewLicenseKeyWithEd25519(policy.PrivateKey, licenseOutput)
-	case constants.PolicySchemeRSA2048PKCS1:
-		encodedLicense, err = utils.NewLicenseKeyWithRSA2048PKCS1(policy.PrivateKey, licenseOutput)
+	policySigner, err := signer.GetInstance().PolicySigner(ctx, policy)
+	if err != nil {
+		return nil, err
 	}
+
+	encodedLicense, err = signer.NewLicenseKey(ctx, policySigner, licenseFileOutput)
 	if err != nil {
 		return nil, err
 	}
@@ -348,48 +629,28 @@ func (svc *LicenseService) checkoutLicense(ctx *gin.Context, license *entities.L
 		Enc: encodedData,
 		Sig: signature,
 		Alg: policy.Scheme,
+		Kid: utils.KeyID(policy.PublicKey),
 	}
 	bLicenseCert, err := json.Marshal(jsonLicenseCert)
 	if err != nil {
 		return nil, err
 	}
-	licenseCert := base64.StdEncoding.EncodeToString(bLicenseCert)
 
-	// Encrypt key if specified
+	// Encrypt key if specified. The key is derived from the license key, so only its holder can decrypt the file
 	if strings.ToLower(ctx.Query("encrypt")) == "true" || policy.Encrypted {
 		svc.logger.GetLogger().Info(fmt.Sprintf("encrypting license certificate file for license [%s]", license.ID.String()))
-		sha256Hash := fmt.Sprintf("%x", sha256.Sum256([]byte(licenseCert)))
-		encryptedCert, err := utils.Encrypt([]byte(licenseCert), []byte(sha256Hash))
+		encryptedCert, err := utils.EncryptWithPassphrase(bLicenseCert, utils.CertificatePassphrase(license.Key, ""))
 		if err != nil {
 			return nil, err
 		}
-		licenseCert = base64.StdEncoding.EncodeToString(encryptedCert)
-		ctx.Writer.Header().Add(constants.XLicenseChecksumHeader, fmt.Sprintf("sha256=%s", sha256Hash))
-	}
-
-	licenseCert = fmt.Sprintf(constants.LicenseFileFormat, licenseCert)
-	issued := time.Now()
-
-	// Assign TTL to license certificate file
-	ttlParam := strings.ToLower(ctx.Query("ttl"))
-	ttl := constants.DefaultLicenseTTL
-	if ttlParam != "" {
-		ttl, err = strconv.Atoi(ttlParam)
+		bLicenseCert, err = json.Marshal(encryptedCert)
 		if err != nil {
-			svc.logger.GetLogger().Error(err.Error())
-			ttl = constants.DefaultLicenseTTL
-		}
-		// If ttl is smaller than 1 hour, default to 1 hour
-		if ttl < constants.MinimumLicenseTTL {
-			ttl = constants.MinimumLicenseTTL
-		}
-		// If ttl is larger than 1 year, default to 1 year
-		if ttl > constants.MaximumLicenseTTL {
-			ttl = constants.MaximumLicenseTTL
+			return nil, err
 		}
 	}
+	licenseCert := base64.StdEncoding.EncodeToString(bLicenseCert)
 
-	expiry := issued.Add(time.Duration(ttl) * time.Second)
+	licenseCert = fmt.Sprintf(constants.LicenseFileFormat, licenseCert)
 
 	if license.Status == constants.LicenseStatusNotActivated {
 		license.Status = constants.LicenseStatusActive
@@ -473,3 +734,109 @@ func (svc *LicenseService) resetUsageLicense(ctx *gin.Context, license *entities
 
 	return license, nil
 }
+
+// queryTenantLicense verifies the tenant and returns the license identified by the URI if it belongs to the tenant.
+func (svc *LicenseService) queryTenantLicense(ctx *gin.Context, rootCtx context.Context, tracer trace.Tracer, uri license_attribute.LicenseCommonURI) (*entities.License, error) {
+	_, cSpan := tracer.Start(rootCtx, "query-tenant-by-name")
+	defer cSpan.End()
+
+	svc.logger.GetLogger().Info(fmt.Sprintf("verifying tenant [%s]", utils.DerefPointer(uri.TenantName)))
+	tenant, err := svc.repo.SelectTenantByName(ctx, utils.DerefPointer(uri.TenantName))
+	if err != nil {
+		svc.logger.GetLogger().Error(err.Error())
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, cerrors.ErrTenantNameIsInvalid
+		}
+		return nil, cerrors.ErrGenericInternalServer
+	}
+
+	svc.logger.GetLogger().Info(fmt.Sprintf("verifying license [%s]", utils.DerefPointer(uri.LicenseID)))
+	license, err := svc.repo.SelectLicenseByPK(ctx, uuid.MustParse(utils.DerefPointer(uri.LicenseID)))
+	if err != nil {
+		svc.logger.GetLogger().Error(err.Error())
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, cerrors.ErrLicenseIDIsInvalid
+		}
+		return nil, cerrors.ErrGenericInternalServer
+	}
+
+	if license.TenantName != tenant.Name {
+		svc.logger.GetLogger().Error(fmt.Sprintf("license [%s] does not belong to tenant [%s]", license.ID, tenant.Name))
+		return nil, cerrors.ErrLicenseIDIsInvalid
+	}
+
+	return license, nil
+}
+
+// queryLicenseEntitlements returns the merged entitlements of the license: the entitlements of its policy,
+// followed by the entitlements attached to the license itself. An entitlement is only listed once.
+func (svc *LicenseService) queryLicenseEntitlements(ctx *gin.Context, license *entities.License) ([]models.LicenseEntitlementOutput, error) {
+	svc.logger.GetLogger().Info(fmt.Sprintf("querying entitlements of policy [%s]", license.PolicyID))
+	policyEntitlements, err := svc.repo.SelectPolicyEntitlementsByPolicyID(ctx, license.PolicyID)
+	if err != nil {
+		return nil, err
+	}
+
+	svc.logger.GetLogger().Info(fmt.Sprintf("querying entitlements of license [%s]", license.ID))
+	licenseEntitlements, err := svc.repo.SelectLicenseEntitlementsByLicenseID(ctx, license.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[uuid.UUID]bool)
+	outputs := make([]models.LicenseEntitlementOutput, 0, len(policyEntitlements)+len(licenseEntitlements))
+	for _, policyEntitlement := range policyEntitlements {
+		if seen[policyEntitlement.EntitlementID] || policyEntitlement.Entitlement == nil {
+			continue
+		}
+		seen[policyEntitlement.EntitlementID] = true
+		outputs = append(outputs, models.LicenseEntitlementOutput{
+			ID:            policyEntitlement.ID.String(),
+			TenantName:    policyEntitlement.TenantName,
+			Source:        constants.LicenseEntitlementSourcePolicy,
+			EntitlementID: policyEntitlement.EntitlementID.String(),
+			Name:          policyEntitlement.Entitlement.Name,
+			Code:          policyEntitlement.Entitlement.Code,
+			Metadata:      policyEntitlement.Metadata,
+			CreatedAt:     policyEntitlement.CreatedAt,
+			UpdatedAt:     policyEntitlement.UpdatedAt,
+		})
+	}
+	for _, licenseEntitlement := range licenseEntitlements {
+		if seen[licenseEntitlement.EntitlementID] || licenseEntitlement.Entitlement == nil {
+			continue
+		}
+		seen[licenseEntitlement.EntitlementID] = true
+		outputs = append(outputs, models.LicenseEntitlementOutput{
+			ID:            licenseEntitlement.ID.String(),
+			TenantName:    licenseEntitlement.TenantName,
+			Source:        constants.LicenseEntitlementSourceLicense,
+			EntitlementID: licenseEntitlement.EntitlementID.String(),
+			Name:          licenseEntitlement.Entitlement.Name,
+			Code:          licenseEntitlement.Entitlement.Code,
+			Metadata:      licenseEntitlement.Metadata,
+			CreatedAt:     licenseEntitlement.CreatedAt,
+			UpdatedAt:     licenseEntitlement.UpdatedAt,
+		})
+	}
+
+	return outputs, nil
+}
+
+// inProductTokenScope reports whether the product is in the scope of the request. Requests authenticated with a
+// product token can only manage the resources of the product of the token.
+func inProductTokenScope(ctx *gin.Context, productID uuid.UUID) bool {
+	if ctx.GetString(constants.ContextValueRole) != constants.RoleProduct {
+		return true
+	}
+	return ctx.GetString(constants.ContextValueProduct) == productID.String()
+}
+
+// inLicenseTokenScope reports whether the license is in the scope of the request. Requests authenticated with a
+// license token can only act on the license of the token.
+func inLicenseTokenScope(ctx *gin.Context, licenseID uuid.UUID) bool {
+	if ctx.GetString(constants.ContextValueRole) != constants.RoleLicense {
+		return true
+	}
+	return ctx.GetString(constants.ContextValueLicense) == licenseID.String()
+}
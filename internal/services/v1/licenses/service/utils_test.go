@@ -0,0 +1,383 @@
+package service
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go-license-management/internal/constants"
+	"go-license-management/internal/infrastructure/database/entities"
+	"go-license-management/internal/infrastructure/models/license_attribute"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func newTestContext() *gin.Context {
+	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	return ctx
+}
+
+func TestLicenseService_ValidateLicense_ExpirationStrategy(t *testing.T) {
+	cases := []struct {
+		strategy string
+		valid    bool
+		code     string
+	}{
+		{strategy: constants.PolicyExpirationStrategyRestrictAccess, valid: false, code: constants.LicenseValidationStatusExpired},
+		{strategy: constants.PolicyExpirationStrategyRevokeAccess, valid: false, code: constants.LicenseValidationStatusAccessRevoked},
+		{strategy: constants.PolicyExpirationStrategyMaintainAccess, valid: true, code: constants.LicenseValidationStatusExpired},
+		{strategy: constants.PolicyExpirationStrategyAllowAccess, valid: true, code: constants.LicenseValidationStatusValid},
+	}
+
+	for _, c := range cases {
+		t.Run(c.strategy, func(t *testing.T) {
+			// Both licenses flagged by the sweeper and licenses past their expiry in between two sweeps are expired
+			for _, license := range []*entities.License{
+				{ID: uuid.New(), Status: constants.LicenseStatusExpired},
+				{ID: uuid.New(), Status: constants.LicenseStatusActive, Expiry: time.Now().Add(-time.Minute)},
+			} {
+				license.Policy = &entities.Policy{ExpirationStrategy: c.strategy}
+				repo := &fakeLicenseRepository{}
+				svc := NewLicenseService(WithRepository(repo))
+
+				resp, err := svc.validateLicense(newTestContext(), license, nil)
+				assert.NoError(t, err)
+				assert.Equal(t, c.valid, resp.Valid)
+				assert.Equal(t, c.code, resp.Code)
+
+				// Only the validations of the licenses keeping their access are recorded
+				if c.valid {
+					assert.Len(t, repo.licenses, 1)
+					assert.False(t, license.LastValidatedAt.IsZero())
+				} else {
+					assert.Empty(t, repo.licenses)
+				}
+			}
+		})
+	}
+}
+
+func TestLicenseService_ValidateLicense_ExpiredKeepsChecks(t *testing.T) {
+	// Expired licenses keeping their access still have to meet the requirements of their policy
+	for _, strategy := range []string{constants.PolicyExpirationStrategyMaintainAccess, constants.PolicyExpirationStrategyAllowAccess} {
+		t.Run(strategy, func(t *testing.T) {
+			license := &entities.License{
+				ID:     uuid.New(),
+				Status: constants.LicenseStatusExpired,
+				Policy: &entities.Policy{ExpirationStrategy: strategy, Strict: true},
+			}
+			svc := NewLicenseService(WithRepository(&fakeLicenseRepository{}))
+
+			resp, err := svc.validateLicense(newTestContext(), license, nil)
+			assert.NoError(t, err)
+			assert.False(t, resp.Valid)
+			assert.Equal(t, constants.LicenseValidationStatusNoMachine, resp.Code)
+		})
+	}
+}
+
+func TestLicenseService_ValidateLicense_NotExpired(t *testing.T) {
+	// The expiration strategy does not apply to licenses with a future expiry, nor to suspended and banned licenses
+	cases := []struct {
+		status string
+		expiry time.Time
+		valid  bool
+		code   string
+	}{
+		{status: constants.LicenseStatusActive, expiry: time.Now().Add(time.Hour), valid: true, code: constants.LicenseValidationStatusValid},
+		{status: constants.LicenseStatusSuspended, expiry: time.Now().Add(-time.Hour), valid: false, code: constants.LicenseValidationStatusSuspended},
+		{status: constants.LicenseStatusBanned, expiry: time.Now().Add(-time.Hour), valid: false, code: constants.LicenseValidationStatusBanned},
+	}
+
+	for _, c := range cases {
+		t.Run(c.status, func(t *testing.T) {
+			license := &entities.License{
+				ID:     uuid.New(),
+				Status: c.status,
+				Expiry: c.expiry,
+				Policy: &entities.Policy{ExpirationStrategy: constants.PolicyExpirationStrategyRevokeAccess},
+			}
+			svc := NewLicenseService(WithRepository(&fakeLicenseRepository{}))
+
+			resp, err := svc.validateLicense(newTestContext(), license, nil)
+			assert.NoError(t, err)
+			assert.Equal(t, c.valid, resp.Valid)
+			assert.Equal(t, c.code, resp.Code)
+		})
+	}
+}
+
+func TestLicenseService_ValidateLicense_Strict(t *testing.T) {
+	cases := []struct {
+		name          string
+		floating      bool
+		strict        bool
+		machinesCount int
+		maxMachines   int
+		valid         bool
+		code          string
+	}{
+		{name: "node-locked without machine", strict: true, maxMachines: 1, valid: false, code: constants.LicenseValidationStatusNoMachine},
+		{name: "node-locked with a machine", strict: true, machinesCount: 1, maxMachines: 1, valid: true, code: constants.LicenseValidationStatusValid},
+		{name: "node-locked with too many machines", strict: true, machinesCount: 2, maxMachines: 5, valid: false, code: constants.LicenseValidationStatusTooManyMachine},
+		{name: "floating without machine", floating: true, strict: true, maxMachines: 5, valid: false, code: constants.LicenseValidationStatusNoMachines},
+		{name: "floating within max machines", floating: true, strict: true, machinesCount: 5, maxMachines: 5, valid: true, code: constants.LicenseValidationStatusValid},
+		{name: "floating with too many machines", floating: true, strict: true, machinesCount: 6, maxMachines: 5, valid: false, code: constants.LicenseValidationStatusTooManyMachine},
+		{name: "floating without max machines", floating: true, strict: true, machinesCount: 10, valid: true, code: constants.LicenseValidationStatusValid},
+		{name: "not strict without machine", floating: true, maxMachines: 5, valid: true, code: constants.LicenseValidationStatusValid},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			license := &entities.License{
+				ID:            uuid.New(),
+				Status:        constants.LicenseStatusActive,
+				MachinesCount: c.machinesCount,
+				MaxMachines:   c.maxMachines,
+				Policy:        &entities.Policy{Floating: c.floating, Strict: c.strict, MaxMachines: c.maxMachines},
+			}
+			svc := NewLicenseService(WithRepository(&fakeLicenseRepository{}))
+
+			resp, err := svc.validateLicense(newTestContext(), license, nil)
+			assert.NoError(t, err)
+			assert.Equal(t, c.valid, resp.Valid)
+			assert.Equal(t, c.code, resp.Code)
+		})
+	}
+}
+
+func TestLicenseService_ValidateLicense_NotActivated(t *testing.T) {
+	// Node-locked licenses report that they need a machine until their first activation
+	license := &entities.License{
+		ID:     uuid.New(),
+		Status: constants.LicenseStatusNotActivated,
+		Policy: &entities.Policy{MaxMachines: 1},
+	}
+	svc := NewLicenseService(WithRepository(&fakeLicenseRepository{}))
+
+	resp, err := svc.validateLicense(newTestContext(), license, nil)
+	assert.NoError(t, err)
+	assert.True(t, resp.Valid)
+	assert.Equal(t, constants.LicenseValidationStatusNoMachine, resp.Code)
+}
+
+func TestLicenseService_ValidateLicense_NodeLockedFingerprint(t *testing.T) {
+	license := &entities.License{
+		ID:            uuid.New(),
+		Status:        constants.LicenseStatusActive,
+		MachinesCount: 1,
+		MaxMachines:   1,
+		Policy:        &entities.Policy{Strict: true, MaxMachines: 1},
+	}
+	repo := &fakeLicenseRepository{
+		machines: []*entities.Machine{{ID: uuid.New(), LicenseID: license.ID, Fingerprint: "activated"}},
+	}
+	svc := NewLicenseService(WithRepository(repo))
+
+	// Only the machine the license is locked to validates
+	fingerprint := "activated"
+	resp, err := svc.validateLicense(newTestContext(), license, &license_attribute.LicenseValidationScope{Fingerprint: &fingerprint})
+	assert.NoError(t, err)
+	assert.True(t, resp.Valid)
+	assert.Equal(t, constants.LicenseValidationStatusValid, resp.Code)
+
+	fingerprint = "other"
+	resp, err = svc.validateLicense(newTestContext(), license, &license_attribute.LicenseValidationScope{Fingerprint: &fingerprint})
+	assert.NoError(t, err)
+	assert.False(t, resp.Valid)
+	assert.Equal(t, constants.LicenseValidationStatusFingerprintScopeMismatch, resp.Code)
+}
+
+// newScopedLicense returns a license with an activated machine, an entitlement granted by its policy and one granted
+// to the license itself, along with the repository holding them.
+func newScopedLicense(status string, expiry time.Time, strategy string) (*entities.License, *fakeLicenseRepository) {
+	policy := &entities.Policy{ID: uuid.New(), ExpirationStrategy: strategy}
+	license := &entities.License{
+		ID:            uuid.New(),
+		ProductID:     uuid.New(),
+		PolicyID:      policy.ID,
+		Status:        status,
+		Expiry:        expiry,
+		MachinesCount: 1,
+		Policy:        policy,
+	}
+	otherLicenseID := uuid.New()
+	policyEntitlement := &entities.Entitlement{ID: uuid.New(), Code: "policy"}
+	licenseEntitlement := &entities.Entitlement{ID: uuid.New(), Code: "license"}
+	repo := &fakeLicenseRepository{
+		machines: []*entities.Machine{
+			{ID: uuid.New(), LicenseID: license.ID, Fingerprint: "activated"},
+			{ID: uuid.New(), LicenseID: license.ID, Fingerprint: "deactivated", Deactivated: true},
+			{ID: uuid.New(), LicenseID: otherLicenseID, Fingerprint: "other"},
+		},
+		policyEntitlements: []entities.PolicyEntitlement{
+			{ID: uuid.New(), PolicyID: policy.ID, EntitlementID: policyEntitlement.ID, Entitlement: policyEntitlement},
+		},
+		licenseEntitlements: []entities.LicenseEntitlement{
+			{ID: uuid.New(), LicenseID: license.ID, EntitlementID: licenseEntitlement.ID, Entitlement: licenseEntitlement},
+			{ID: uuid.New(), LicenseID: otherLicenseID, EntitlementID: uuid.New(), Entitlement: &entities.Entitlement{Code: "other"}},
+		},
+	}
+	return license, repo
+}
+
+func TestLicenseService_ValidateLicenseScope(t *testing.T) {
+	fingerprint := func(fingerprint string) *license_attribute.LicenseValidationScope {
+		return &license_attribute.LicenseValidationScope{Fingerprint: &fingerprint}
+	}
+	product := func(productID string) *license_attribute.LicenseValidationScope {
+		return &license_attribute.LicenseValidationScope{ProductID: &productID}
+	}
+	policy := func(policyID string) *license_attribute.LicenseValidationScope {
+		return &license_attribute.LicenseValidationScope{PolicyID: &policyID}
+	}
+	entitlements := func(codes ...string) *license_attribute.LicenseValidationScope {
+		return &license_attribute.LicenseValidationScope{Entitlements: codes}
+	}
+
+	cases := []struct {
+		name    string
+		scope   func(license *entities.License) *license_attribute.LicenseValidationScope
+		code    string
+		missing []string
+	}{
+		{
+			name: "activated machine",
+			scope: func(license *entities.License) *license_attribute.LicenseValidationScope {
+				return fingerprint("activated")
+			},
+		},
+		{
+			name: "unknown fingerprint",
+			scope: func(license *entities.License) *license_attribute.LicenseValidationScope {
+				return fingerprint("unknown")
+			},
+			code: constants.LicenseValidationStatusFingerprintScopeMismatch,
+		},
+		{
+			name: "deactivated machine",
+			scope: func(license *entities.License) *license_attribute.LicenseValidationScope {
+				return fingerprint("deactivated")
+			},
+			code: constants.LicenseValidationStatusFingerprintScopeMismatch,
+		},
+		{
+			name:  "machine of another license",
+			scope: func(license *entities.License) *license_attribute.LicenseValidationScope { return fingerprint("other") },
+			code:  constants.LicenseValidationStatusFingerprintScopeMismatch,
+		},
+		{
+			name: "product",
+			scope: func(license *entities.License) *license_attribute.LicenseValidationScope {
+				return product(license.ProductID.String())
+			},
+		},
+		{
+			name: "another product",
+			scope: func(license *entities.License) *license_attribute.LicenseValidationScope {
+				return product(uuid.NewString())
+			},
+			code: constants.LicenseValidationStatusProductScopeMismatch,
+		},
+		{
+			name: "policy",
+			scope: func(license *entities.License) *license_attribute.LicenseValidationScope {
+				return policy(license.PolicyID.String())
+			},
+		},
+		{
+			name: "another policy",
+			scope: func(license *entities.License) *license_attribute.LicenseValidationScope {
+				return policy(uuid.NewString())
+			},
+			code: constants.LicenseValidationStatusPolicyScopeMismatch,
+		},
+		{
+			name: "entitlements of the policy and the license",
+			scope: func(license *entities.License) *license_attribute.LicenseValidationScope {
+				return entitlements("policy", "license")
+			},
+		},
+		{
+			name: "missing entitlements",
+			scope: func(license *entities.License) *license_attribute.LicenseValidationScope {
+				return entitlements("policy", "other", "unknown")
+			},
+			code:    constants.LicenseValidationStatusEntitlementsMissing,
+			missing: []string{"other", "unknown"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			license, repo := newScopedLicense(constants.LicenseStatusActive, time.Time{}, "")
+			svc := NewLicenseService(WithRepository(repo))
+
+			resp, err := svc.validateLicense(newTestContext(), license, c.scope(license))
+			assert.NoError(t, err)
+			if c.code == "" {
+				assert.True(t, resp.Valid)
+				assert.Equal(t, constants.LicenseValidationStatusValid, resp.Code)
+				return
+			}
+			assert.False(t, resp.Valid)
+			assert.Equal(t, c.code, resp.Code)
+			assert.Equal(t, c.missing, resp.MissingEntitlements)
+		})
+	}
+}
+
+func TestLicenseService_ValidateLicenseScope_Expired(t *testing.T) {
+	// The scope of expired licenses keeping their access is checked as well
+	cases := []struct {
+		strategy string
+		code     string
+	}{
+		{strategy: constants.PolicyExpirationStrategyMaintainAccess, code: constants.LicenseValidationStatusFingerprintScopeMismatch},
+		{strategy: constants.PolicyExpirationStrategyAllowAccess, code: constants.LicenseValidationStatusFingerprintScopeMismatch},
+		{strategy: constants.PolicyExpirationStrategyRestrictAccess, code: constants.LicenseValidationStatusExpired},
+		{strategy: constants.PolicyExpirationStrategyRevokeAccess, code: constants.LicenseValidationStatusAccessRevoked},
+	}
+
+	for _, c := range cases {
+		t.Run(c.strategy, func(t *testing.T) {
+			license, repo := newScopedLicense(constants.LicenseStatusActive, time.Now().Add(-time.Minute), c.strategy)
+			svc := NewLicenseService(WithRepository(repo))
+
+			fingerprint := "unknown"
+			resp, err := svc.validateLicense(newTestContext(), license, &license_attribute.LicenseValidationScope{Fingerprint: &fingerprint})
+			assert.NoError(t, err)
+			assert.False(t, resp.Valid)
+			assert.Equal(t, c.code, resp.Code)
+		})
+	}
+}
+
+func TestRestoreExpiredLicenseStatus(t *testing.T) {
+	cases := []struct {
+		name          string
+		status        string
+		expiry        time.Time
+		machinesCount int
+		expected      string
+	}{
+		{name: "no expiry with machines", status: constants.LicenseStatusExpired, machinesCount: 1, expected: constants.LicenseStatusActive},
+		{name: "no expiry without machines", status: constants.LicenseStatusExpired, expected: constants.LicenseStatusInactive},
+		{name: "future expiry", status: constants.LicenseStatusExpired, expiry: time.Now().Add(time.Hour), machinesCount: 2, expected: constants.LicenseStatusActive},
+		{name: "past expiry", status: constants.LicenseStatusExpired, expiry: time.Now().Add(-time.Hour), machinesCount: 1, expected: constants.LicenseStatusExpired},
+		{name: "not expired", status: constants.LicenseStatusSuspended, expected: constants.LicenseStatusSuspended},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			license := &entities.License{
+				ID:            uuid.New(),
+				Status:        c.status,
+				Expiry:        c.expiry,
+				MachinesCount: c.machinesCount,
+			}
+			restoreExpiredLicenseStatus(license)
+			assert.Equal(t, c.expected, license.Status)
+		})
+	}
+}
@@ -27,12 +27,31 @@ type MachineInfoOutput struct {
 	Name            string                 `json:"name"`
 	Metadata        map[string]interface{} `json:"metadata"`
 	Cores           int                    `json:"cores"`
+	HeartbeatStatus string                 `json:"heartbeat_status"`
 	LastHeartbeatAt time.Time              `json:"last_heartbeat_at"`
 	LastCheckOutAt  time.Time              `json:"last_check_out_at"`
 	CreatedAt       time.Time              `json:"created_at"`
 	UpdatedAt       time.Time              `json:"updated_at"`
 }
 
+// MachineFileOutput is the signed content of a machine file.
+type MachineFileOutput struct {
+	Meta MachineFileMeta   `json:"meta"`
+	Data MachineInfoOutput `json:"data"`
+}
+
+// MachineFileMeta describes the machine file itself. It is signed along with the machine,
+// so the validity window of the machine file can be enforced offline.
+type MachineFileMeta struct {
+	Version   int       `json:"version"`
+	Tenant    string    `json:"tenant"`
+	LicenseID string    `json:"license_id"`
+	MachineID string    `json:"machine_id"`
+	Issued    time.Time `json:"issued"`
+	Expiry    time.Time `json:"expiry"`
+	TTL       int       `json:"ttl"`
+}
+
 type MachineUpdateInput struct {
 	TracerCtx context.Context
 	Tracer    trace.Tracer
@@ -71,6 +90,7 @@ type MachineListOutput struct {
 	Name                 string                 `json:"name"`
 	Metadata             map[string]interface{} `json:"metadata"`
 	Cores                int                    `json:"cores"`
+	HeartbeatStatus      string                 `json:"heartbeat_status"`
 	LastHeartbeatAt      time.Time              `json:"last_heartbeat_at"`
 	LastDeathEventSentAt time.Time              `json:"last_death_event_sent_at"`
 	LastCheckOutAt       time.Time              `json:"last_check_out_at"`
@@ -20,6 +20,10 @@ type IMachine interface {
 	UpdateMachineByPK(ctx context.Context, machine *entities.Machine) (*entities.Machine, error)
 	UpdateMachineByPKAndLicense(ctx context.Context, machine *entities.Machine, currentLicense, newLicense *entities.License) (*entities.Machine, error)
 	InsertNewMachineAndUpdateLicense(ctx context.Context, machine *entities.Machine) error
+	InsertNewMachineAndUpdateLicenseToken(ctx context.Context, machine *entities.Machine, licenseTokenID uuid.UUID) error
 	DeleteMachineByPK(ctx context.Context, machineID uuid.UUID) error
 	DeleteMachineByPKAndUpdateLicense(ctx context.Context, machineID uuid.UUID) error
+	SelectMachinesRequiringHeartbeat(ctx context.Context) ([]entities.Machine, error)
+	DeactivateMachineAndUpdateLicense(ctx context.Context, machine *entities.Machine) error
+	ReactivateMachineAndUpdateLicense(ctx context.Context, machine *entities.Machine) error
 }
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
@@ -10,6 +11,7 @@ import (
 	"go-license-management/internal/constants"
 	"go-license-management/internal/infrastructure/database/entities"
 	"go-license-management/internal/infrastructure/logging"
+	"go-license-management/internal/permissions"
 	"go-license-management/internal/response"
 	"go-license-management/internal/services/v1/machines/models"
 	"go-license-management/internal/services/v1/machines/repository"
@@ -105,6 +107,21 @@ func (svc *MachineService) Create(ctx *gin.Context, input *models.MachineRegistr
 		return resp, cerrors.ErrLicenseHasExpired
 	}
 
+	if !inLicenseTokenScope(ctx, license.ID) {
+		svc.logger.GetLogger().Error(fmt.Sprintf("subject [%s] cannot activate machine for license [%s]", ctx.GetString(constants.ContextValueSubject), license.ID))
+		resp.Code = cerrors.ErrCodeMapper[cerrors.ErrLicenseTokenScopeIsInvalid]
+		resp.Message = cerrors.ErrMessageMapper[cerrors.ErrLicenseTokenScopeIsInvalid]
+		return resp, cerrors.ErrLicenseTokenScopeIsInvalid
+	}
+
+	// Only admins may activate machines for licenses under a protected policy
+	if !permissions.CanManageProtectedPolicy(ctx, license.Policy) {
+		svc.logger.GetLogger().Error(fmt.Sprintf("subject [%s] cannot activate machine for license [%s] under a protected policy", ctx.GetString(constants.ContextValueSubject), license.ID))
+		resp.Code = cerrors.ErrCodeMapper[cerrors.ErrPolicyIsProtected]
+		resp.Message = cerrors.ErrMessageMapper[cerrors.ErrPolicyIsProtected]
+		return resp, cerrors.ErrPolicyIsProtected
+	}
+
 	_, cSpan = input.Tracer.Start(rootCtx, "query-machine-by-fingerprint")
 	mExists, err := svc.repo.CheckMachineExistByFingerprintAndLicense(ctx, utils.DerefPointer(input.LicenseKey), utils.DerefPointer(input.Fingerprint))
 	if err != nil {
@@ -123,6 +140,14 @@ func (svc *MachineService) Create(ctx *gin.Context, input *models.MachineRegistr
 		return resp, cerrors.ErrMachineFingerprintAssociatedWithLicense
 	}
 
+	// Node-locked licenses can only be associated with a single machine, regardless of max machines
+	if !license.Policy.Floating && license.MachinesCount >= 1 {
+		svc.logger.GetLogger().Error(fmt.Sprintf("license [%s] is node-locked and already has a machine", license.ID))
+		resp.Code = cerrors.ErrCodeMapper[cerrors.ErrLicenseIsNodeLocked]
+		resp.Message = cerrors.ErrMessageMapper[cerrors.ErrLicenseIsNodeLocked]
+		return resp, cerrors.ErrLicenseIsNodeLocked
+	}
+
 	// Check max machine of the license
 	if license.MachinesCount != 0 {
 		if license.MachinesCount+1 > license.MaxMachines && license.Policy.OverageStrategy == constants.PolicyOverageStrategyNoOverage {
@@ -167,13 +192,25 @@ func (svc *MachineService) Create(ctx *gin.Context, input *models.MachineRegistr
 		machine.Cores = utils.DerefPointer(input.Cores)
 	}
 
-	err = svc.repo.InsertNewMachineAndUpdateLicense(ctx, machine)
+	// Activations with a license token are counted against the max activations of the token
+	if ctx.GetString(constants.ContextValueRole) == constants.RoleLicense {
+		err = svc.repo.InsertNewMachineAndUpdateLicenseToken(ctx, machine, uuid.MustParse(ctx.GetString(constants.ContextValueSubject)))
+	} else {
+		err = svc.repo.InsertNewMachineAndUpdateLicense(ctx, machine)
+	}
 	if err != nil {
 		svc.logger.GetLogger().Error(err.Error())
 		cSpan.End()
-		resp.Code = cerrors.ErrCodeMapper[cerrors.ErrGenericInternalServer]
-		resp.Message = cerrors.ErrMessageMapper[cerrors.ErrGenericInternalServer]
-		return resp, cerrors.ErrGenericInternalServer
+		switch {
+		case errors.Is(err, cerrors.ErrMachineLicenseTokenActivationsExceeded):
+			resp.Code = cerrors.ErrCodeMapper[cerrors.ErrMachineLicenseTokenActivationsExceeded]
+			resp.Message = cerrors.ErrMessageMapper[cerrors.ErrMachineLicenseTokenActivationsExceeded]
+			return resp, cerrors.ErrMachineLicenseTokenActivationsExceeded
+		default:
+			resp.Code = cerrors.ErrCodeMapper[cerrors.ErrGenericInternalServer]
+			resp.Message = cerrors.ErrMessageMapper[cerrors.ErrGenericInternalServer]
+			return resp, cerrors.ErrGenericInternalServer
+		}
 	}
 	cSpan.End()
 
@@ -188,6 +225,7 @@ func (svc *MachineService) Create(ctx *gin.Context, input *models.MachineRegistr
 		Name:            machine.Name,
 		Metadata:        machine.Metadata,
 		Cores:           machine.Cores,
+		HeartbeatStatus: constants.HeartbeatStatusMapper[heartbeatStatus(machine, license.Policy, time.Now())],
 		LastHeartbeatAt: machine.LastHeartbeatAt,
 		LastCheckOutAt:  machine.LastCheckOutAt,
 		CreatedAt:       machine.CreatedAt,
@@ -296,6 +334,12 @@ func (svc *MachineService) Update(ctx *gin.Context, input *models.MachineUpdateI
 				}
 			}
 			cSpan.End()
+			if !inLicenseTokenScope(ctx, license.ID) {
+				svc.logger.GetLogger().Error(fmt.Sprintf("subject [%s] cannot move machine to license [%s]", ctx.GetString(constants.ContextValueSubject), license.ID))
+				resp.Code = cerrors.ErrCodeMapper[cerrors.ErrLicenseTokenScopeIsInvalid]
+				resp.Message = cerrors.ErrMessageMapper[cerrors.ErrLicenseTokenScopeIsInvalid]
+				return resp, cerrors.ErrLicenseTokenScopeIsInvalid
+			}
 			if license.Suspended {
 				resp.Code = cerrors.ErrCodeMapper[cerrors.ErrLicenseIsSuspended]
 				resp.Message = cerrors.ErrMessageMapper[cerrors.ErrLicenseIsSuspended]
@@ -313,6 +357,21 @@ func (svc *MachineService) Update(ctx *gin.Context, input *models.MachineUpdateI
 				resp.Message = cerrors.ErrMessageMapper[cerrors.ErrLicenseHasExpired]
 				return resp, cerrors.ErrLicenseHasExpired
 			}
+
+			// Moving a machine between licenses deactivates it on the current license and activates it on the new one
+			if !permissions.CanManageProtectedPolicy(ctx, machinePolicy(machine)) || !permissions.CanManageProtectedPolicy(ctx, license.Policy) {
+				svc.logger.GetLogger().Error(fmt.Sprintf("subject [%s] cannot move machine [%s] under a protected policy", ctx.GetString(constants.ContextValueSubject), machine.ID))
+				resp.Code = cerrors.ErrCodeMapper[cerrors.ErrPolicyIsProtected]
+				resp.Message = cerrors.ErrMessageMapper[cerrors.ErrPolicyIsProtected]
+				return resp, cerrors.ErrPolicyIsProtected
+			}
+
+			if !license.Policy.Floating && license.MachinesCount >= 1 {
+				svc.logger.GetLogger().Error(fmt.Sprintf("license [%s] is node-locked and already has a machine", license.ID))
+				resp.Code = cerrors.ErrCodeMapper[cerrors.ErrLicenseIsNodeLocked]
+				resp.Message = cerrors.ErrMessageMapper[cerrors.ErrLicenseIsNodeLocked]
+				return resp, cerrors.ErrLicenseIsNodeLocked
+			}
 			if license.MachinesCount != 0 {
 				if license.MachinesCount+1 > license.MaxMachines && license.Policy.OverageStrategy == constants.PolicyOverageStrategyNoOverage {
 					svc.logger.GetLogger().Error("license max machine exceeded")
@@ -349,6 +408,7 @@ func (svc *MachineService) Update(ctx *gin.Context, input *models.MachineUpdateI
 		Name:            machine.Name,
 		Metadata:        machine.Metadata,
 		Cores:           machine.Cores,
+		HeartbeatStatus: constants.HeartbeatStatusMapper[heartbeatStatus(machine, machinePolicy(machine), time.Now())],
 		LastHeartbeatAt: machine.LastHeartbeatAt,
 		LastCheckOutAt:  machine.LastCheckOutAt,
 		CreatedAt:       machine.CreatedAt,
@@ -418,6 +478,7 @@ func (svc *MachineService) Retrieve(ctx *gin.Context, input *models.MachineRetri
 		Name:            machine.Name,
 		Metadata:        machine.Metadata,
 		Cores:           machine.Cores,
+		HeartbeatStatus: constants.HeartbeatStatusMapper[heartbeatStatus(machine, machinePolicy(machine), time.Now())],
 		LastHeartbeatAt: machine.LastHeartbeatAt,
 		LastCheckOutAt:  machine.LastCheckOutAt,
 		CreatedAt:       machine.CreatedAt,
@@ -458,8 +519,33 @@ func (svc *MachineService) Delete(ctx *gin.Context, input *models.MachineDeleteI
 	}
 	cSpan.End()
 
+	_, cSpan = input.Tracer.Start(rootCtx, "query-machine")
+	machine, err := svc.repo.SelectMachineByPK(ctx, uuid.MustParse(utils.DerefPointer(input.MachineID)))
+	if err != nil {
+		svc.logger.GetLogger().Error(err.Error())
+		cSpan.End()
+		if errors.Is(err, sql.ErrNoRows) {
+			resp.Code = cerrors.ErrCodeMapper[cerrors.ErrMachineIDIsInvalid]
+			resp.Message = cerrors.ErrMessageMapper[cerrors.ErrMachineIDIsInvalid]
+			return resp, cerrors.ErrMachineIDIsInvalid
+		} else {
+			resp.Code = cerrors.ErrCodeMapper[cerrors.ErrGenericInternalServer]
+			resp.Message = cerrors.ErrMessageMapper[cerrors.ErrGenericInternalServer]
+			return resp, cerrors.ErrGenericInternalServer
+		}
+	}
+	cSpan.End()
+
+	// Only admins may deactivate machines of licenses under a protected policy
+	if !permissions.CanManageProtectedPolicy(ctx, machinePolicy(machine)) {
+		svc.logger.GetLogger().Error(fmt.Sprintf("subject [%s] cannot deactivate machine [%s] under a protected policy", ctx.GetString(constants.ContextValueSubject), machine.ID))
+		resp.Code = cerrors.ErrCodeMapper[cerrors.ErrPolicyIsProtected]
+		resp.Message = cerrors.ErrMessageMapper[cerrors.ErrPolicyIsProtected]
+		return resp, cerrors.ErrPolicyIsProtected
+	}
+
 	_, cSpan = input.Tracer.Start(rootCtx, "delete-product")
-	err = svc.repo.DeleteMachineByPKAndUpdateLicense(ctx, uuid.MustParse(utils.DerefPointer(input.MachineID)))
+	err = svc.repo.DeleteMachineByPKAndUpdateLicense(ctx, machine.ID)
 	if err != nil {
 		svc.logger.GetLogger().Error(err.Error())
 		cSpan.End()
@@ -503,6 +589,10 @@ func (svc *MachineService) List(ctx *gin.Context, input *models.MachineListInput
 	cSpan.End()
 
 	_, cSpan = input.Tracer.Start(rootCtx, "query-product-by-pkc")
+	// License tokens only list the machines of their license
+	if ctx.GetString(constants.ContextValueRole) == constants.RoleLicense {
+		input.QueryCommonParam.LicenseID = utils.RefPointer(ctx.GetString(constants.ContextValueLicense))
+	}
 	machines, total, err := svc.repo.SelectMachines(ctx, tenant.Name, input.QueryCommonParam)
 	if err != nil {
 		svc.logger.GetLogger().Error(err.Error())
@@ -519,24 +609,27 @@ func (svc *MachineService) List(ctx *gin.Context, input *models.MachineListInput
 	}
 	cSpan.End()
 
+	now := time.Now()
 	machineOutput := make([]models.MachineListOutput, 0)
 	for _, machine := range machines {
 		machineOutput = append(machineOutput, models.MachineListOutput{
-			ID:              machine.ID,
-			LicenseID:       machine.LicenseID,
-			LicenseKey:      machine.LicenseKey,
-			TenantName:      machine.TenantName,
-			Fingerprint:     machine.Fingerprint,
-			IP:              machine.IP,
-			Hostname:        machine.Hostname,
-			Platform:        machine.Platform,
-			Name:            machine.Name,
-			Metadata:        machine.Metadata,
-			Cores:           machine.Cores,
-			LastHeartbeatAt: machine.LastHeartbeatAt,
-			LastCheckOutAt:  machine.LastCheckOutAt,
-			CreatedAt:       machine.CreatedAt,
-			UpdatedAt:       machine.UpdatedAt,
+			ID:                   machine.ID,
+			LicenseID:            machine.LicenseID,
+			LicenseKey:           machine.LicenseKey,
+			TenantName:           machine.TenantName,
+			Fingerprint:          machine.Fingerprint,
+			IP:                   machine.IP,
+			Hostname:             machine.Hostname,
+			Platform:             machine.Platform,
+			Name:                 machine.Name,
+			Metadata:             machine.Metadata,
+			Cores:                machine.Cores,
+			HeartbeatStatus:      constants.HeartbeatStatusMapper[heartbeatStatus(&machine, machinePolicy(&machine), now)],
+			LastHeartbeatAt:      machine.LastHeartbeatAt,
+			LastDeathEventSentAt: machine.LastDeathEventSentAt,
+			LastCheckOutAt:       machine.LastCheckOutAt,
+			CreatedAt:            machine.CreatedAt,
+			UpdatedAt:            machine.UpdatedAt,
 		})
 	}
 
@@ -625,6 +718,18 @@ func (svc *MachineService) Actions(ctx *gin.Context, input *models.MachineAction
 				resp.Code = cerrors.ErrCodeMapper[cerrors.ErrMachineIDIsInvalid]
 				resp.Message = cerrors.ErrMessageMapper[cerrors.ErrMachineIDIsInvalid]
 				return resp, cerrors.ErrMachineIDIsInvalid
+			case errors.Is(err, cerrors.ErrMachineHeartbeatIsDead):
+				resp.Code = cerrors.ErrCodeMapper[cerrors.ErrMachineHeartbeatIsDead]
+				resp.Message = cerrors.ErrMessageMapper[cerrors.ErrMachineHeartbeatIsDead]
+				return resp, cerrors.ErrMachineHeartbeatIsDead
+			case errors.Is(err, cerrors.ErrLicenseMaxMachineExceeded):
+				resp.Code = cerrors.ErrCodeMapper[cerrors.ErrLicenseMaxMachineExceeded]
+				resp.Message = cerrors.ErrMessageMapper[cerrors.ErrLicenseMaxMachineExceeded]
+				return resp, cerrors.ErrLicenseMaxMachineExceeded
+			case errors.Is(err, cerrors.ErrLicenseIsNodeLocked):
+				resp.Code = cerrors.ErrCodeMapper[cerrors.ErrLicenseIsNodeLocked]
+				resp.Message = cerrors.ErrMessageMapper[cerrors.ErrLicenseIsNodeLocked]
+				return resp, cerrors.ErrLicenseIsNodeLocked
 			default:
 				resp.Code = cerrors.ErrCodeMapper[cerrors.ErrGenericInternalServer]
 				resp.Message = cerrors.ErrMessageMapper[cerrors.ErrGenericInternalServer]
@@ -639,3 +744,53 @@ func (svc *MachineService) Actions(ctx *gin.Context, input *models.MachineAction
 	resp.Message = cerrors.ErrMessageMapper[nil]
 	return resp, nil
 }
+
+// CullDeadMachines deactivates every machine whose policy requires a heartbeat and whose heartbeat is dead.
+// Deactivated machines no longer count towards the license's machines count.
+func (svc *MachineService) CullDeadMachines(ctx context.Context) (int, error) {
+	machines, err := svc.repo.SelectMachinesRequiringHeartbeat(ctx)
+	if err != nil {
+		svc.logger.GetLogger().Error(err.Error())
+		return 0, err
+	}
+
+	culled := 0
+	now := time.Now()
+	for _, machine := range machines {
+		if heartbeatStatus(&machine, machinePolicy(&machine), now) != constants.HeartbeatStatusDead {
+			continue
+		}
+
+		svc.logger.GetLogger().Info(fmt.Sprintf("deactivating dead machine [%s] of license [%s]", machine.ID, machine.LicenseID))
+		err = svc.repo.DeactivateMachineAndUpdateLicense(ctx, &machine)
+		if err != nil {
+			svc.logger.GetLogger().Error(err.Error())
+			continue
+		}
+		culled++
+	}
+
+	return culled, nil
+}
+
+// StartHeartbeatMonitor periodically runs CullDeadMachines until the context is cancelled.
+func (svc *MachineService) StartHeartbeatMonitor(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = constants.DefaultHeartbeatMonitorInterval * time.Second
+	}
+
+	svc.logger.GetLogger().Info(fmt.Sprintf("started machine heartbeat monitor with interval [%s]", interval))
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		_, _ = svc.CullDeadMachines(ctx)
+
+		select {
+		case <-ctx.Done():
+			svc.logger.GetLogger().Info("stopped machine heartbeat monitor")
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,207 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go-license-management/internal/cerrors"
+	"go-license-management/internal/constants"
+	"go-license-management/internal/infrastructure/database/entities"
+	"go-license-management/internal/services/v1/machines/models"
+	"go-license-management/internal/services/v1/machines/repository"
+	"go.opentelemetry.io/otel/trace/noop"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// fakeMachineRepository keeps the tenants, the licenses and the machines in memory. The other methods are not
+// implemented.
+type fakeMachineRepository struct {
+	repository.IMachine
+	tenants  []*entities.Tenant
+	licenses []*entities.License
+	machines []*entities.Machine
+	// deactivationErr fails the deactivation of the machine with this ID
+	deactivationErr uuid.UUID
+}
+
+func (repo *fakeMachineRepository) SelectTenantByName(ctx context.Context, tenantName string) (*entities.Tenant, error) {
+	for _, tenant := range repo.tenants {
+		if tenant.Name == tenantName {
+			return tenant, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (repo *fakeMachineRepository) SelectLicenseByLicenseKey(ctx context.Context, licenseKey string) (*entities.License, error) {
+	for _, license := range repo.licenses {
+		if license.Key == licenseKey {
+			return license, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (repo *fakeMachineRepository) CheckMachineExistByFingerprintAndLicense(ctx context.Context, licenseKey, fingerprint string) (bool, error) {
+	for _, machine := range repo.machines {
+		if machine.LicenseKey == licenseKey && machine.Fingerprint == fingerprint && !machine.Deactivated {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (repo *fakeMachineRepository) InsertNewMachineAndUpdateLicense(ctx context.Context, machine *entities.Machine) error {
+	for _, license := range repo.licenses {
+		if license.ID == machine.LicenseID {
+			license.MachinesCount++
+		}
+	}
+	repo.machines = append(repo.machines, machine)
+	return nil
+}
+
+func (repo *fakeMachineRepository) SelectMachineByPK(ctx context.Context, machineID uuid.UUID) (*entities.Machine, error) {
+	for _, machine := range repo.machines {
+		if machine.ID == machineID {
+			return machine, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (repo *fakeMachineRepository) UpdateMachineByPK(ctx context.Context, machine *entities.Machine) (*entities.Machine, error) {
+	return machine, nil
+}
+
+func (repo *fakeMachineRepository) SelectMachinesRequiringHeartbeat(ctx context.Context) ([]entities.Machine, error) {
+	machines := make([]entities.Machine, 0)
+	for _, machine := range repo.machines {
+		if !machine.Deactivated && machinePolicy(machine).RequireHeartbeat {
+			machines = append(machines, *machine)
+		}
+	}
+	return machines, nil
+}
+
+func (repo *fakeMachineRepository) DeactivateMachineAndUpdateLicense(ctx context.Context, machine *entities.Machine) error {
+	if machine.ID == repo.deactivationErr {
+		return errors.New("database is down")
+	}
+	for _, current := range repo.machines {
+		if current.ID == machine.ID {
+			current.Deactivated = true
+			current.License.MachinesCount--
+		}
+	}
+	return nil
+}
+
+func (repo *fakeMachineRepository) ReactivateMachineAndUpdateLicense(ctx context.Context, machine *entities.Machine) error {
+	machine.Deactivated = false
+	machine.License.MachinesCount++
+	return nil
+}
+
+func TestMachineService_CullDeadMachines(t *testing.T) {
+	now := time.Now()
+	policy := &entities.Policy{RequireHeartbeat: true, HeartbeatDuration: 60}
+	license := &entities.License{ID: uuid.New(), MachinesCount: 4, Policy: policy}
+	dead := &entities.Machine{ID: uuid.New(), License: license, LastHeartbeatAt: now.Add(-2 * time.Minute)}
+	alive := &entities.Machine{ID: uuid.New(), License: license, LastHeartbeatAt: now}
+	notStarted := &entities.Machine{ID: uuid.New(), License: license, CreatedAt: now.Add(-time.Hour)}
+	failing := &entities.Machine{ID: uuid.New(), License: license, LastHeartbeatAt: now.Add(-time.Hour)}
+	noHeartbeat := &entities.Machine{
+		ID:              uuid.New(),
+		License:         &entities.License{ID: uuid.New(), MachinesCount: 1, Policy: &entities.Policy{}},
+		LastHeartbeatAt: now.Add(-time.Hour),
+	}
+
+	repo := &fakeMachineRepository{
+		machines:        []*entities.Machine{dead, alive, notStarted, failing, noHeartbeat},
+		deactivationErr: failing.ID,
+	}
+	svc := NewMachineService(WithRepository(repo))
+
+	// Only the dead machines of policies requiring a heartbeat are culled, a failed deactivation does not stop the others
+	culled, err := svc.CullDeadMachines(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, culled)
+	assert.True(t, dead.Deactivated)
+	assert.False(t, alive.Deactivated)
+	assert.False(t, notStarted.Deactivated)
+	assert.False(t, failing.Deactivated)
+	assert.False(t, noHeartbeat.Deactivated)
+	assert.Equal(t, 3, license.MachinesCount)
+
+	// Deactivated machines are not culled again
+	culled, err = svc.CullDeadMachines(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 0, culled)
+	assert.Equal(t, 3, license.MachinesCount)
+}
+
+func newMachineRegistrationInput(licenseKey, fingerprint string) *models.MachineRegistrationInput {
+	tenantName := "tenant"
+	input := &models.MachineRegistrationInput{
+		TracerCtx: context.Background(),
+		Tracer:    noop.NewTracerProvider().Tracer("test"),
+	}
+	input.TenantName = &tenantName
+	input.LicenseKey = &licenseKey
+	input.Fingerprint = &fingerprint
+	return input
+}
+
+func TestMachineService_Create_NodeLocked(t *testing.T) {
+	cases := []struct {
+		name        string
+		floating    bool
+		maxMachines int
+		err         error
+	}{
+		{name: "node-locked", maxMachines: 5, err: cerrors.ErrLicenseIsNodeLocked},
+		{name: "floating", floating: true, maxMachines: 5},
+		{name: "floating, max machines reached", floating: true, maxMachines: 1, err: cerrors.ErrLicenseMaxMachineExceeded},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			license := &entities.License{
+				ID:          uuid.New(),
+				Key:         "key",
+				TenantName:  "tenant",
+				Status:      constants.LicenseStatusNotActivated,
+				MaxMachines: c.maxMachines,
+				Policy:      &entities.Policy{Floating: c.floating, OverageStrategy: constants.PolicyOverageStrategyNoOverage},
+			}
+			repo := &fakeMachineRepository{
+				tenants:  []*entities.Tenant{{Name: "tenant"}},
+				licenses: []*entities.License{license},
+			}
+			svc := NewMachineService(WithRepository(repo))
+
+			// The first machine is always activated
+			ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+			_, err := svc.Create(ctx, newMachineRegistrationInput("key", "first"))
+			assert.NoError(t, err)
+			assert.Equal(t, 1, license.MachinesCount)
+
+			// Node-locked licenses refuse a second machine regardless of their max machines
+			resp, err := svc.Create(ctx, newMachineRegistrationInput("key", "second"))
+			if c.err != nil {
+				assert.ErrorIs(t, err, c.err)
+				assert.Equal(t, cerrors.ErrCodeMapper[c.err], resp.Code)
+				assert.Equal(t, 1, license.MachinesCount)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, 2, license.MachinesCount)
+		})
+	}
+}
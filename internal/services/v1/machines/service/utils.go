@@ -1,7 +1,6 @@
 package service
 
 import (
-	"crypto/sha256"
 	"database/sql"
 	"encoding/base64"
 	"encoding/json"
@@ -9,8 +8,11 @@ import (
 	"fmt"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"go-license-management/internal/cerrors"
 	"go-license-management/internal/constants"
+	"go-license-management/internal/infrastructure/database/entities"
 	"go-license-management/internal/infrastructure/models/machine_attribute"
+	"go-license-management/internal/infrastructure/signer"
 	"go-license-management/internal/services/v1/machines/models"
 	"go-license-management/internal/utils"
 	"strings"
@@ -55,20 +57,34 @@ func (svc *MachineService) checkout(ctx *gin.Context, input *models.MachineActio
 		Name:            machine.Name,
 		Metadata:        machine.Metadata,
 		Cores:           machine.Cores,
+		HeartbeatStatus: constants.HeartbeatStatusMapper[heartbeatStatus(machine, policy, time.Now())],
 		LastHeartbeatAt: machine.LastHeartbeatAt,
 		LastCheckOutAt:  machine.LastCheckOutAt,
 		CreatedAt:       machine.CreatedAt,
 		UpdatedAt:       machine.UpdatedAt,
 	}
 
+	// The issued and expiry timestamps are signed along with the machine, so the TTL can be enforced offline
+	machineFileContent := models.MachineFileOutput{
+		Meta: models.MachineFileMeta{
+			Version:   constants.CertificateFileVersion,
+			Tenant:    machine.TenantName,
+			LicenseID: machine.LicenseID.String(),
+			MachineID: machine.ID.String(),
+			Issued:    issuedAt,
+			Expiry:    expiredAt,
+			TTL:       ttl,
+		},
+		Data: machineContent,
+	}
+
 	svc.logger.GetLogger().Info(fmt.Sprintf("generate new machine file using [%s] scheme", alg))
-	var machineLicense string
-	switch alg {
-	case constants.PolicySchemeED25519:
-		machineLicense, err = utils.NewLicenseKeyWithEd25519(policy.PrivateKey, machineContent)
-	case constants.PolicySchemeRSA2048PKCS1:
-		machineLicense, err = utils.NewLicenseKeyWithRSA2048PKCS1(policy.PrivateKey, machineContent)
+	policySigner, err := signer.GetInstance().PolicySigner(ctx, policy)
+	if err != nil {
+		return nil, err
 	}
+
+	machineLicense, err := signer.NewLicenseKey(ctx, policySigner, machineFileContent)
 	if err != nil {
 		return nil, err
 	}
@@ -80,25 +96,29 @@ func (svc *MachineService) checkout(ctx *gin.Context, input *models.MachineActio
 		Enc: encoded,
 		Sig: signature,
 		Alg: alg,
+		Kid: utils.KeyID(policy.PublicKey),
 	}
 	bMachineCert, err := json.Marshal(jsonMachineCert)
 	if err != nil {
 		return nil, err
 	}
-	// convert the cert to base64
-	machineCert := base64.StdEncoding.EncodeToString(bMachineCert)
 
+	// The key is derived from the license key and the fingerprint, so only the machine holding the license can decrypt the file
 	if strings.ToLower(ctx.Query("encrypt")) == "true" || policy.Encrypted {
 		svc.logger.GetLogger().Info(fmt.Sprintf("encrypting machine certificate file for machine [%s]", machine.ID.String()))
-		sha256Hash := fmt.Sprintf("%x", sha256.Sum256([]byte(machineCert)))
-		encryptedCert, err := utils.Encrypt([]byte(machineCert), []byte(sha256Hash))
+		encryptedCert, err := utils.EncryptWithPassphrase(bMachineCert, utils.CertificatePassphrase(machine.LicenseKey, machine.Fingerprint))
+		if err != nil {
+			return nil, err
+		}
+		bMachineCert, err = json.Marshal(encryptedCert)
 		if err != nil {
 			return nil, err
 		}
-		machineCert = base64.StdEncoding.EncodeToString(encryptedCert)
-		ctx.Writer.Header().Add(constants.XMachineChecksumHeader, fmt.Sprintf("sha256=%s", sha256Hash))
 	}
 
+	// convert the cert to base64
+	machineCert := base64.StdEncoding.EncodeToString(bMachineCert)
+
 	machineCert = fmt.Sprintf(constants.MachineFileFormat, machineCert)
 	output := &models.MachineActionCheckoutOutput{
 		Certificate: machineCert,
@@ -127,7 +147,36 @@ func (svc *MachineService) pingHeartbeat(ctx *gin.Context, input *models.Machine
 		}
 	}
 
-	machine.LastHeartbeatAt = time.Now()
+	// A dead machine can only be resurrected if its policy requires a heartbeat and explicitly allows it
+	policy := machinePolicy(machine)
+	now := time.Now()
+	if policy.RequireHeartbeat && heartbeatStatus(machine, policy, now) == constants.HeartbeatStatusDead {
+		if policy.HeartbeatResurrectionStrategy != constants.PolicyHeartbeatResurrectionStrategyAlwaysRevive {
+			svc.logger.GetLogger().Info(fmt.Sprintf("machine [%s] is dead and its policy does not allow resurrection", machine.ID))
+			return nil, cerrors.ErrMachineHeartbeatIsDead
+		}
+
+		if machine.Deactivated {
+			license := machine.License
+			if !policy.Floating && license.MachinesCount >= 1 {
+				return nil, cerrors.ErrLicenseIsNodeLocked
+			}
+			if license.MaxMachines > 0 && license.MachinesCount+1 > license.MaxMachines && policy.OverageStrategy == constants.PolicyOverageStrategyNoOverage {
+				return nil, cerrors.ErrLicenseMaxMachineExceeded
+			}
+
+			svc.logger.GetLogger().Info(fmt.Sprintf("reactivating machine [%s]", machine.ID))
+			err = svc.repo.ReactivateMachineAndUpdateLicense(ctx, machine)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		svc.logger.GetLogger().Info(fmt.Sprintf("resurrecting machine [%s]", machine.ID))
+		machine.ResurrectedAt = now
+	}
+
+	machine.LastHeartbeatAt = now
 	machine, err = svc.repo.UpdateMachineByPK(ctx, machine)
 	if err != nil {
 		return nil, err
@@ -144,6 +193,7 @@ func (svc *MachineService) pingHeartbeat(ctx *gin.Context, input *models.Machine
 		Name:            machine.Name,
 		Metadata:        machine.Metadata,
 		Cores:           machine.Cores,
+		HeartbeatStatus: constants.HeartbeatStatusMapper[heartbeatStatus(machine, machinePolicy(machine), time.Now())],
 		LastHeartbeatAt: machine.LastHeartbeatAt,
 		LastCheckOutAt:  machine.LastCheckOutAt,
 		CreatedAt:       machine.CreatedAt,
@@ -163,6 +213,7 @@ func (svc *MachineService) resetHeartbeat(ctx *gin.Context, input *models.Machin
 	}
 
 	machine.LastHeartbeatAt = time.Time{}
+	machine.ResurrectedAt = time.Time{}
 	machine, err = svc.repo.UpdateMachineByPK(ctx, machine)
 	if err != nil {
 		return nil, err
@@ -179,9 +230,68 @@ func (svc *MachineService) resetHeartbeat(ctx *gin.Context, input *models.Machin
 		Name:            machine.Name,
 		Metadata:        machine.Metadata,
 		Cores:           machine.Cores,
+		HeartbeatStatus: constants.HeartbeatStatusMapper[heartbeatStatus(machine, machinePolicy(machine), time.Now())],
 		LastHeartbeatAt: machine.LastHeartbeatAt,
 		LastCheckOutAt:  machine.LastCheckOutAt,
 		CreatedAt:       machine.CreatedAt,
 		UpdatedAt:       machine.UpdatedAt,
 	}, nil
 }
+
+// machinePolicy returns the policy of the license the machine belongs to
+func machinePolicy(machine *entities.Machine) *entities.Policy {
+	if machine.License == nil || machine.License.Policy == nil {
+		return &entities.Policy{}
+	}
+	return machine.License.Policy
+}
+
+// inLicenseTokenScope reports whether the license is in the scope of the request. Requests authenticated with a
+// license token can only act on the license of the token.
+func inLicenseTokenScope(ctx *gin.Context, licenseID uuid.UUID) bool {
+	if ctx.GetString(constants.ContextValueRole) != constants.RoleLicense {
+		return true
+	}
+	return ctx.GetString(constants.ContextValueLicense) == licenseID.String()
+}
+
+// heartbeatStatus derives the machine's heartbeat status from the policy's heartbeat duration and basis.
+// With the `from_creation` basis, the heartbeat is started when the machine is created,
+// otherwise it is only started by the first heartbeat ping.
+// Machines of policies which do not require a heartbeat never die.
+func heartbeatStatus(machine *entities.Machine, policy *entities.Policy, now time.Time) int {
+	if machine.Deactivated {
+		return constants.HeartbeatStatusDead
+	}
+
+	if !policy.RequireHeartbeat {
+		if machine.LastHeartbeatAt.IsZero() {
+			return constants.HeartbeatStatusNotStarted
+		}
+		return constants.HeartbeatStatusAlive
+	}
+
+	duration := time.Duration(policy.HeartbeatDuration) * time.Second
+	if duration <= 0 {
+		duration = constants.DefaultHeartbeatDuration * time.Second
+	}
+
+	lastHeartbeatAt := machine.LastHeartbeatAt
+	if lastHeartbeatAt.IsZero() {
+		if policy.HeartbeatBasis != constants.PolicyHeartbeatBasisFromCreation {
+			return constants.HeartbeatStatusNotStarted
+		}
+		lastHeartbeatAt = machine.CreatedAt
+	}
+
+	if now.Sub(lastHeartbeatAt) > duration {
+		return constants.HeartbeatStatusDead
+	}
+
+	// The machine stays resurrected until its next heartbeat ping
+	if !machine.ResurrectedAt.IsZero() && machine.ResurrectedAt.Equal(machine.LastHeartbeatAt) {
+		return constants.HeartbeatStatusResurrected
+	}
+
+	return constants.HeartbeatStatusAlive
+}
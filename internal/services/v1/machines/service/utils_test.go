@@ -0,0 +1,172 @@
+package service
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go-license-management/internal/cerrors"
+	"go-license-management/internal/constants"
+	"go-license-management/internal/infrastructure/database/entities"
+	"go-license-management/internal/services/v1/machines/models"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHeartbeatStatus(t *testing.T) {
+	now := time.Now()
+	fromFirstPing := &entities.Policy{RequireHeartbeat: true, HeartbeatDuration: 60}
+	fromCreation := &entities.Policy{RequireHeartbeat: true, HeartbeatDuration: 60, HeartbeatBasis: constants.PolicyHeartbeatBasisFromCreation}
+	defaultDuration := &entities.Policy{RequireHeartbeat: true}
+
+	cases := []struct {
+		name     string
+		machine  *entities.Machine
+		policy   *entities.Policy
+		expected int
+	}{
+		{
+			name:     "no heartbeat required, not started",
+			machine:  &entities.Machine{CreatedAt: now.Add(-time.Hour)},
+			policy:   &entities.Policy{},
+			expected: constants.HeartbeatStatusNotStarted,
+		},
+		{
+			name:     "no heartbeat required, never dies",
+			machine:  &entities.Machine{LastHeartbeatAt: now.Add(-time.Hour)},
+			policy:   &entities.Policy{},
+			expected: constants.HeartbeatStatusAlive,
+		},
+		{
+			name:     "deactivated",
+			machine:  &entities.Machine{Deactivated: true, LastHeartbeatAt: now},
+			policy:   fromFirstPing,
+			expected: constants.HeartbeatStatusDead,
+		},
+		{
+			name:     "from first ping, not started",
+			machine:  &entities.Machine{CreatedAt: now.Add(-time.Hour)},
+			policy:   fromFirstPing,
+			expected: constants.HeartbeatStatusNotStarted,
+		},
+		{
+			name:     "from creation, dead without a ping",
+			machine:  &entities.Machine{CreatedAt: now.Add(-time.Hour)},
+			policy:   fromCreation,
+			expected: constants.HeartbeatStatusDead,
+		},
+		{
+			name:     "from creation, alive without a ping",
+			machine:  &entities.Machine{CreatedAt: now.Add(-30 * time.Second)},
+			policy:   fromCreation,
+			expected: constants.HeartbeatStatusAlive,
+		},
+		{
+			name:     "alive",
+			machine:  &entities.Machine{LastHeartbeatAt: now.Add(-30 * time.Second)},
+			policy:   fromFirstPing,
+			expected: constants.HeartbeatStatusAlive,
+		},
+		{
+			name:     "dead",
+			machine:  &entities.Machine{LastHeartbeatAt: now.Add(-2 * time.Minute)},
+			policy:   fromFirstPing,
+			expected: constants.HeartbeatStatusDead,
+		},
+		{
+			name:     "default duration, alive",
+			machine:  &entities.Machine{LastHeartbeatAt: now.Add(-(constants.DefaultHeartbeatDuration - 1) * time.Second)},
+			policy:   defaultDuration,
+			expected: constants.HeartbeatStatusAlive,
+		},
+		{
+			name:     "default duration, dead",
+			machine:  &entities.Machine{LastHeartbeatAt: now.Add(-(constants.DefaultHeartbeatDuration + 1) * time.Second)},
+			policy:   defaultDuration,
+			expected: constants.HeartbeatStatusDead,
+		},
+		{
+			name:     "resurrected until the next ping",
+			machine:  &entities.Machine{LastHeartbeatAt: now, ResurrectedAt: now},
+			policy:   fromFirstPing,
+			expected: constants.HeartbeatStatusResurrected,
+		},
+		{
+			name:     "alive after the ping following the resurrection",
+			machine:  &entities.Machine{LastHeartbeatAt: now, ResurrectedAt: now.Add(-30 * time.Second)},
+			policy:   fromFirstPing,
+			expected: constants.HeartbeatStatusAlive,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assert.Equal(t, c.expected, heartbeatStatus(c.machine, c.policy, now))
+		})
+	}
+}
+
+func TestMachineService_PingHeartbeat_Resurrection(t *testing.T) {
+	cases := []struct {
+		name          string
+		strategy      string
+		floating      bool
+		deactivated   bool
+		machinesCount int
+		maxMachines   int
+		err           error
+		reactivated   bool
+	}{
+		{name: "no revive", strategy: constants.PolicyHeartbeatResurrectionStrategyNoRevive, floating: true, err: cerrors.ErrMachineHeartbeatIsDead},
+		{name: "default strategy", floating: true, err: cerrors.ErrMachineHeartbeatIsDead},
+		{name: "dead before the cull", strategy: constants.PolicyHeartbeatResurrectionStrategyAlwaysRevive, floating: true, machinesCount: 1, maxMachines: 1},
+		{name: "culled", strategy: constants.PolicyHeartbeatResurrectionStrategyAlwaysRevive, floating: true, deactivated: true, machinesCount: 1, maxMachines: 2, reactivated: true},
+		{name: "culled, no max machines", strategy: constants.PolicyHeartbeatResurrectionStrategyAlwaysRevive, floating: true, deactivated: true, machinesCount: 3, reactivated: true},
+		{name: "culled, max machines reached", strategy: constants.PolicyHeartbeatResurrectionStrategyAlwaysRevive, floating: true, deactivated: true, machinesCount: 2, maxMachines: 2, err: cerrors.ErrLicenseMaxMachineExceeded},
+		{name: "culled, node-locked with another machine", strategy: constants.PolicyHeartbeatResurrectionStrategyAlwaysRevive, deactivated: true, machinesCount: 1, maxMachines: 1, err: cerrors.ErrLicenseIsNodeLocked},
+		{name: "culled, node-locked", strategy: constants.PolicyHeartbeatResurrectionStrategyAlwaysRevive, deactivated: true, maxMachines: 1, reactivated: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			policy := &entities.Policy{
+				RequireHeartbeat:              true,
+				HeartbeatDuration:             60,
+				HeartbeatResurrectionStrategy: c.strategy,
+				Floating:                      c.floating,
+				OverageStrategy:               constants.PolicyOverageStrategyNoOverage,
+			}
+			license := &entities.License{ID: uuid.New(), MachinesCount: c.machinesCount, MaxMachines: c.maxMachines, Policy: policy}
+			machine := &entities.Machine{
+				ID:              uuid.New(),
+				LicenseID:       license.ID,
+				License:         license,
+				Deactivated:     c.deactivated,
+				LastHeartbeatAt: time.Now().Add(-time.Hour),
+			}
+			svc := NewMachineService(WithRepository(&fakeMachineRepository{machines: []*entities.Machine{machine}}))
+
+			ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+			machineID := machine.ID.String()
+			input := &models.MachineActionsInput{}
+			input.MachineID = &machineID
+			output, err := svc.pingHeartbeat(ctx, input)
+			if c.err != nil {
+				assert.ErrorIs(t, err, c.err)
+				assert.True(t, machine.ResurrectedAt.IsZero())
+				assert.Equal(t, c.deactivated, machine.Deactivated)
+				assert.Equal(t, c.machinesCount, license.MachinesCount)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, constants.HeartbeatStatusMapper[constants.HeartbeatStatusResurrected], output.HeartbeatStatus)
+			assert.False(t, machine.Deactivated)
+			if c.reactivated {
+				assert.Equal(t, c.machinesCount+1, license.MachinesCount)
+			} else {
+				assert.Equal(t, c.machinesCount, license.MachinesCount)
+			}
+		})
+	}
+}
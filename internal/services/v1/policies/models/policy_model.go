@@ -13,7 +13,8 @@ type PolicyRegistrationInput struct {
 	Tracer    trace.Tracer
 	policy_attribute.PolicyCommonURI
 	policy_attribute.PolicyAttributeModel
-	ProductID *string `json:"product_id" validate:"required" example:"test"`
+	ProductID     *string `json:"product_id" validate:"required" example:"test"`
+	SignerBackend *string `json:"signer_backend"`
 }
 
 type PolicyListInput struct {
@@ -30,11 +31,12 @@ type PolicyRetrievalInput struct {
 }
 
 type PolicyRetrievalOutput struct {
-	ID         string    `json:"id"`
-	TenantName string    `json:"tenant_name"`
-	PublicKey  string    `json:"public_key"`
-	CreatedAt  time.Time `json:"created_at"`
-	UpdatedAt  time.Time `json:"updated_at"`
+	ID            string    `json:"id"`
+	TenantName    string    `json:"tenant_name"`
+	PublicKey     string    `json:"public_key"`
+	SignerBackend string    `json:"signer_backend"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
 	policy_attribute.PolicyAttributeModel
 }
 
@@ -91,3 +93,51 @@ type PolicyEntitlementListOutput struct {
 	CreatedAt     time.Time              `json:"created_at"`
 	UpdatedAt     time.Time              `json:"updated_at"`
 }
+
+type PolicyKeyCreationInput struct {
+	TracerCtx context.Context
+	Tracer    trace.Tracer
+	policy_attribute.PolicyCommonURI
+	Keys  []string `json:"keys"`
+	Count int      `json:"count"`
+}
+
+type PolicyKeyListInput struct {
+	TracerCtx context.Context
+	Tracer    trace.Tracer
+	policy_attribute.PolicyCommonURI
+	constants.QueryCommonParam
+}
+
+type PolicyKeyOutput struct {
+	ID        string    `json:"id"`
+	PolicyID  string    `json:"policy_id"`
+	Key       string    `json:"key"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type PolicyKeyRotationInput struct {
+	TracerCtx context.Context
+	Tracer    trace.Tracer
+	policy_attribute.PolicyCommonURI
+	Scheme        *string `json:"scheme"`
+	OverlapPeriod int64   `json:"overlap_period"`
+	SignerBackend *string `json:"signer_backend"`
+}
+
+type PolicySigningKeyListInput struct {
+	TracerCtx context.Context
+	Tracer    trace.Tracer
+	policy_attribute.PolicyCommonURI
+}
+
+type PolicySigningKeyOutput struct {
+	KID       string     `json:"kid"`
+	PolicyID  string     `json:"policy_id"`
+	Scheme    string     `json:"scheme"`
+	PublicKey string     `json:"public_key"`
+	Status    string     `json:"status"`
+	RotatedAt *time.Time `json:"rotated_at,omitempty"`
+	RetiresAt *time.Time `json:"retires_at,omitempty"`
+}
@@ -23,4 +23,9 @@ type IPolicy interface {
 	DeletePolicyEntitlementByPK(ctx context.Context, policyEntitlementID uuid.UUID) error
 	DeletePolicyEntitlementsByPK(ctx context.Context, policyEntitlementID []uuid.UUID) error
 	SelectPolicyEntitlements(ctx context.Context, policyID uuid.UUID, queryParam constants.QueryCommonParam) ([]entities.PolicyEntitlement, int, error)
+	InsertNewKeys(ctx context.Context, keys []entities.Key) error
+	CheckKeysExist(ctx context.Context, keys []string) (bool, error)
+	SelectKeys(ctx context.Context, policyID uuid.UUID, queryParam constants.QueryCommonParam) ([]entities.Key, int, error)
+	RotatePolicyKeys(ctx context.Context, policy *entities.Policy, previousKey *entities.PolicyKey) error
+	SelectPolicyKeys(ctx context.Context, policyID uuid.UUID) ([]entities.PolicyKey, error)
 }
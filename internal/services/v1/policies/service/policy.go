@@ -11,6 +11,8 @@ import (
 	"go-license-management/internal/infrastructure/database/entities"
 	"go-license-management/internal/infrastructure/logging"
 	"go-license-management/internal/infrastructure/models/policy_attribute"
+	"go-license-management/internal/infrastructure/signer"
+	"go-license-management/internal/permissions"
 	"go-license-management/internal/response"
 	"go-license-management/internal/services/v1/policies/models"
 	"go-license-management/internal/services/v1/policies/repository"
@@ -93,71 +95,70 @@ func (svc *PolicyService) Create(ctx *gin.Context, input *models.PolicyRegistrat
 	}
 	cSpan.End()
 
-	// Generate new private/public key pair
-	var privateKey = ""
-	var publicKey = ""
-	scheme := utils.DerefPointer(input.Scheme)
-	svc.logger.GetLogger().Info(fmt.Sprintf("generating private/public key pair using [%s] algorithm", scheme))
-	switch scheme {
-	case constants.PolicySchemeED25519:
-		privateKey, publicKey, err = utils.NewEd25519KeyPair()
-		if err != nil {
-			svc.logger.GetLogger().Error(err.Error())
-			resp.Code = cerrors.ErrCodeMapper[cerrors.ErrGenericInternalServer]
-			resp.Message = cerrors.ErrMessageMapper[cerrors.ErrGenericInternalServer]
-			return resp, cerrors.ErrGenericInternalServer
-		}
-	case constants.PolicySchemeRSA2048PKCS1:
-		privateKey, publicKey, err = utils.NewRSA2048PKCS1KeyPair()
-		if err != nil {
-			svc.logger.GetLogger().Error(err.Error())
-			resp.Code = cerrors.ErrCodeMapper[cerrors.ErrGenericInternalServer]
-			resp.Message = cerrors.ErrMessageMapper[cerrors.ErrGenericInternalServer]
-			return resp, cerrors.ErrGenericInternalServer
-		}
-	default:
-		svc.logger.GetLogger().Error(fmt.Sprintf("invalid supported sheme [%s]", scheme))
-		resp.Code = cerrors.ErrCodeMapper[cerrors.ErrPolicySchemeIsInvalid]
-		resp.Message = cerrors.ErrMessageMapper[cerrors.ErrPolicySchemeIsInvalid]
-		return resp, cerrors.ErrPolicySchemeIsInvalid
+	if !inProductTokenScope(ctx, productID) {
+		svc.logger.GetLogger().Error(fmt.Sprintf("product [%s] is not in the scope of the product token", productID))
+		resp.Code = cerrors.ErrCodeMapper[cerrors.ErrProductTokenScopeIsInvalid]
+		resp.Message = cerrors.ErrMessageMapper[cerrors.ErrProductTokenScopeIsInvalid]
+		return resp, cerrors.ErrProductTokenScopeIsInvalid
+	}
+
+	// The private key is kept on the signer backend of the policy, defaulting to the one of the tenant
+	signerBackend := utils.DerefPointer(input.SignerBackend)
+	if signerBackend == "" {
+		signerBackend = tenant.SignerBackend
 	}
 
-	// Insert new policy
-	_, cSpan = input.Tracer.Start(rootCtx, "insert-new-policy")
-	svc.logger.GetLogger().Info("inserting new policy to database")
 	policyID := uuid.New()
 	now := time.Now()
 	policy := &entities.Policy{
-		ID:                 policyID,
-		ProductID:          productID,
-		TenantName:         tenant.Name,
-		PublicKey:          publicKey,
-		PrivateKey:         privateKey,
-		Name:               utils.DerefPointer(input.Name),
-		Scheme:             scheme,
-		ExpirationStrategy: utils.DerefPointer(input.ExpirationStrategy),
-		CheckInInterval:    utils.DerefPointer(input.CheckInInterval),
-		OverageStrategy:    utils.DerefPointer(input.OverageStrategy),
-		HeartbeatBasis:     utils.DerefPointer(input.HeartbeatBasis),
-		RenewalBasis:       utils.DerefPointer(input.RenewalBasis),
-		Duration:           utils.DerefPointer(input.Duration),
-		MaxMachines:        utils.DerefPointer(input.MaxMachines),
-		MaxUses:            utils.DerefPointer(input.MaxUses),
-		MaxUsers:           utils.DerefPointer(input.MaxUsers),
-		HeartbeatDuration:  utils.DerefPointer(input.HeartbeatDuration),
-		Strict:             utils.DerefPointer(input.Strict),
-		Floating:           utils.DerefPointer(input.Floating),
-		UsePool:            utils.DerefPointer(input.UsePool),
-		RateLimited:        utils.DerefPointer(input.RateLimited),
-		Encrypted:          utils.DerefPointer(input.Encrypted),
-		Protected:          utils.DerefPointer(input.Protected),
-		RequireCheckIn:     utils.DerefPointer(input.RequireCheckIn),
-		RequireHeartbeat:   utils.DerefPointer(input.RequireHeartbeat),
-		Metadata:           input.Metadata,
-		CreatedAt:          now,
-		UpdatedAt:          now,
+		ID:                            policyID,
+		ProductID:                     productID,
+		TenantName:                    tenant.Name,
+		SignerBackend:                 signerBackend,
+		Name:                          utils.DerefPointer(input.Name),
+		Scheme:                        utils.DerefPointer(input.Scheme),
+		ExpirationStrategy:            utils.DerefPointer(input.ExpirationStrategy),
+		CheckInInterval:               utils.DerefPointer(input.CheckInInterval),
+		OverageStrategy:               utils.DerefPointer(input.OverageStrategy),
+		HeartbeatBasis:                utils.DerefPointer(input.HeartbeatBasis),
+		HeartbeatResurrectionStrategy: utils.DerefPointer(input.HeartbeatResurrectionStrategy),
+		RenewalBasis:                  utils.DerefPointer(input.RenewalBasis),
+		Duration:                      utils.DerefPointer(input.Duration),
+		MaxMachines:                   utils.DerefPointer(input.MaxMachines),
+		MaxUses:                       utils.DerefPointer(input.MaxUses),
+		MaxUsers:                      utils.DerefPointer(input.MaxUsers),
+		HeartbeatDuration:             utils.DerefPointer(input.HeartbeatDuration),
+		Strict:                        utils.DerefPointer(input.Strict),
+		Floating:                      utils.DerefPointer(input.Floating),
+		UsePool:                       utils.DerefPointer(input.UsePool),
+		SignedKeys:                    utils.DerefPointer(input.SignedKeys),
+		RateLimited:                   utils.DerefPointer(input.RateLimited),
+		Encrypted:                     utils.DerefPointer(input.Encrypted),
+		Protected:                     utils.DerefPointer(input.Protected),
+		RequireCheckIn:                utils.DerefPointer(input.RequireCheckIn),
+		RequireHeartbeat:              utils.DerefPointer(input.RequireHeartbeat),
+		Metadata:                      input.Metadata,
+		CreatedAt:                     now,
+		UpdatedAt:                     now,
 	}
 
+	// Generate new private/public key pair
+	_, cSpan = input.Tracer.Start(rootCtx, "generate-policy-keys")
+	svc.logger.GetLogger().Info(fmt.Sprintf("generating private/public key pair using [%s] algorithm on [%s] signer backend", policy.Scheme, policySignerBackend(policy)))
+	err = signer.GetInstance().NewPolicyKey(ctx, policy)
+	if err != nil {
+		svc.logger.GetLogger().Error(err.Error())
+		cSpan.End()
+		cErr := policyKeyError(err)
+		resp.Code = cerrors.ErrCodeMapper[cErr]
+		resp.Message = cerrors.ErrMessageMapper[cErr]
+		return resp, cErr
+	}
+	cSpan.End()
+
+	// Insert new policy
+	_, cSpan = input.Tracer.Start(rootCtx, "insert-new-policy")
+	svc.logger.GetLogger().Info("inserting new policy to database")
 	err = svc.repo.InsertNewPolicy(ctx, policy)
 	if err != nil {
 		svc.logger.GetLogger().Error(err.Error())
@@ -169,33 +170,36 @@ func (svc *PolicyService) Create(ctx *gin.Context, input *models.PolicyRegistrat
 	cSpan.End()
 
 	respData := models.PolicyRetrievalOutput{
-		ID:         policyID.String(),
-		TenantName: policy.TenantName,
-		PublicKey:  policy.PublicKey,
-		CreatedAt:  policy.CreatedAt,
-		UpdatedAt:  policy.UpdatedAt,
+		ID:            policyID.String(),
+		TenantName:    policy.TenantName,
+		PublicKey:     policy.PublicKey,
+		SignerBackend: policySignerBackend(policy),
+		CreatedAt:     policy.CreatedAt,
+		UpdatedAt:     policy.UpdatedAt,
 		PolicyAttributeModel: policy_attribute.PolicyAttributeModel{
-			Name:               utils.RefPointer(policy.Name),
-			Scheme:             utils.RefPointer(policy.Scheme),
-			Strict:             utils.RefPointer(policy.Strict),
-			RateLimited:        utils.RefPointer(policy.RateLimited),
-			Floating:           utils.RefPointer(policy.Floating),
-			UsePool:            utils.RefPointer(policy.UsePool),
-			Encrypted:          utils.RefPointer(policy.Encrypted),
-			Protected:          utils.RefPointer(policy.Protected),
-			RequireCheckIn:     utils.RefPointer(policy.RequireCheckIn),
-			RequireHeartbeat:   utils.RefPointer(policy.RequireHeartbeat),
-			MaxMachines:        utils.RefPointer(policy.MaxMachines),
-			MaxUsers:           utils.RefPointer(policy.MaxUsers),
-			MaxUses:            utils.RefPointer(policy.MaxUses),
-			HeartbeatDuration:  utils.RefPointer(policy.HeartbeatDuration),
-			Duration:           utils.RefPointer(policy.Duration),
-			CheckInInterval:    utils.RefPointer(policy.CheckInInterval),
-			HeartbeatBasis:     utils.RefPointer(policy.HeartbeatBasis),
-			ExpirationStrategy: utils.RefPointer(policy.ExpirationStrategy),
-			RenewalBasis:       utils.RefPointer(policy.RenewalBasis),
-			OverageStrategy:    utils.RefPointer(policy.OverageStrategy),
-			Metadata:           policy.Metadata,
+			Name:                          utils.RefPointer(policy.Name),
+			Scheme:                        utils.RefPointer(policy.Scheme),
+			Strict:                        utils.RefPointer(policy.Strict),
+			RateLimited:                   utils.RefPointer(policy.RateLimited),
+			Floating:                      utils.RefPointer(policy.Floating),
+			UsePool:                       utils.RefPointer(policy.UsePool),
+			SignedKeys:                    utils.RefPointer(policy.SignedKeys),
+			Encrypted:                     utils.RefPointer(policy.Encrypted),
+			Protected:                     utils.RefPointer(policy.Protected),
+			RequireCheckIn:                utils.RefPointer(policy.RequireCheckIn),
+			RequireHeartbeat:              utils.RefPointer(policy.RequireHeartbeat),
+			MaxMachines:                   utils.RefPointer(policy.MaxMachines),
+			MaxUsers:                      utils.RefPointer(policy.MaxUsers),
+			MaxUses:                       utils.RefPointer(policy.MaxUses),
+			HeartbeatDuration:             utils.RefPointer(policy.HeartbeatDuration),
+			Duration:                      utils.RefPointer(policy.Duration),
+			CheckInInterval:               utils.RefPointer(policy.CheckInInterval),
+			HeartbeatBasis:                utils.RefPointer(policy.HeartbeatBasis),
+			HeartbeatResurrectionStrategy: utils.RefPointer(policy.HeartbeatResurrectionStrategy),
+			ExpirationStrategy:            utils.RefPointer(policy.ExpirationStrategy),
+			RenewalBasis:                  utils.RefPointer(policy.RenewalBasis),
+			OverageStrategy:               utils.RefPointer(policy.OverageStrategy),
+			Metadata:                      policy.Metadata,
 		},
 	}
 
@@ -233,6 +237,11 @@ func (svc *PolicyService) List(ctx *gin.Context, input *models.PolicyListInput)
 	}
 	cSpan.End()
 
+	// Product tokens only list the policies of their product
+	if ctx.GetString(constants.ContextValueRole) == constants.RoleProduct {
+		input.QueryCommonParam.ProductID = utils.RefPointer(ctx.GetString(constants.ContextValueProduct))
+	}
+
 	_, cSpan = input.Tracer.Start(rootCtx, "query-policies")
 	products, total, err := svc.repo.SelectPolicies(ctx, tenant.Name, input.QueryCommonParam)
 	if err != nil {
@@ -253,33 +262,36 @@ func (svc *PolicyService) List(ctx *gin.Context, input *models.PolicyListInput)
 	policiesOutput := make([]models.PolicyRetrievalOutput, 0)
 	for _, policy := range products {
 		policiesOutput = append(policiesOutput, models.PolicyRetrievalOutput{
-			ID:         policy.ID.String(),
-			TenantName: policy.TenantName,
-			PublicKey:  policy.PublicKey,
-			CreatedAt:  policy.CreatedAt,
-			UpdatedAt:  policy.UpdatedAt,
+			ID:            policy.ID.String(),
+			TenantName:    policy.TenantName,
+			PublicKey:     policy.PublicKey,
+			SignerBackend: policySignerBackend(&policy),
+			CreatedAt:     policy.CreatedAt,
+			UpdatedAt:     policy.UpdatedAt,
 			PolicyAttributeModel: policy_attribute.PolicyAttributeModel{
-				Name:               utils.RefPointer(policy.Name),
-				Scheme:             utils.RefPointer(policy.Scheme),
-				Strict:             utils.RefPointer(policy.Strict),
-				RateLimited:        utils.RefPointer(policy.RateLimited),
-				Floating:           utils.RefPointer(policy.Floating),
-				UsePool:            utils.RefPointer(policy.UsePool),
-				Encrypted:          utils.RefPointer(policy.Encrypted),
-				Protected:          utils.RefPointer(policy.Protected),
-				RequireCheckIn:     utils.RefPointer(policy.RequireCheckIn),
-				RequireHeartbeat:   utils.RefPointer(policy.RequireHeartbeat),
-				MaxMachines:        utils.RefPointer(policy.MaxMachines),
-				MaxUsers:           utils.RefPointer(policy.MaxUsers),
-				MaxUses:            utils.RefPointer(policy.MaxUses),
-				HeartbeatDuration:  utils.RefPointer(policy.HeartbeatDuration),
-				Duration:           utils.RefPointer(policy.Duration),
-				CheckInInterval:    utils.RefPointer(policy.CheckInInterval),
-				HeartbeatBasis:     utils.RefPointer(policy.HeartbeatBasis),
-				ExpirationStrategy: utils.RefPointer(policy.ExpirationStrategy),
-				RenewalBasis:       utils.RefPointer(policy.RenewalBasis),
-				OverageStrategy:    utils.RefPointer(policy.OverageStrategy),
-				Metadata:           policy.Metadata,
+				Name:                          utils.RefPointer(policy.Name),
+				Scheme:                        utils.RefPointer(policy.Scheme),
+				Strict:                        utils.RefPointer(policy.Strict),
+				RateLimited:                   utils.RefPointer(policy.RateLimited),
+				Floating:                      utils.RefPointer(policy.Floating),
+				UsePool:                       utils.RefPointer(policy.UsePool),
+				SignedKeys:                    utils.RefPointer(policy.SignedKeys),
+				Encrypted:                     utils.RefPointer(policy.Encrypted),
+				Protected:                     utils.RefPointer(policy.Protected),
+				RequireCheckIn:                utils.RefPointer(policy.RequireCheckIn),
+				RequireHeartbeat:              utils.RefPointer(policy.RequireHeartbeat),
+				MaxMachines:                   utils.RefPointer(policy.MaxMachines),
+				MaxUsers:                      utils.RefPointer(policy.MaxUsers),
+				MaxUses:                       utils.RefPointer(policy.MaxUses),
+				HeartbeatDuration:             utils.RefPointer(policy.HeartbeatDuration),
+				Duration:                      utils.RefPointer(policy.Duration),
+				CheckInInterval:               utils.RefPointer(policy.CheckInInterval),
+				HeartbeatBasis:                utils.RefPointer(policy.HeartbeatBasis),
+				HeartbeatResurrectionStrategy: utils.RefPointer(policy.HeartbeatResurrectionStrategy),
+				ExpirationStrategy:            utils.RefPointer(policy.ExpirationStrategy),
+				RenewalBasis:                  utils.RefPointer(policy.RenewalBasis),
+				OverageStrategy:               utils.RefPointer(policy.OverageStrategy),
+				Metadata:                      policy.Metadata,
 			},
 		})
 	}
@@ -338,33 +350,36 @@ func (svc *PolicyService) Retrieve(ctx *gin.Context, input *models.PolicyRetriev
 	cSpan.End()
 
 	respData := &models.PolicyRetrievalOutput{
-		ID:         policy.ID.String(),
-		TenantName: policy.TenantName,
-		PublicKey:  policy.PublicKey,
-		CreatedAt:  policy.CreatedAt,
-		UpdatedAt:  policy.UpdatedAt,
+		ID:            policy.ID.String(),
+		TenantName:    policy.TenantName,
+		PublicKey:     policy.PublicKey,
+		SignerBackend: policySignerBackend(policy),
+		CreatedAt:     policy.CreatedAt,
+		UpdatedAt:     policy.UpdatedAt,
 		PolicyAttributeModel: policy_attribute.PolicyAttributeModel{
-			Name:               utils.RefPointer(policy.Name),
-			Scheme:             utils.RefPointer(policy.Scheme),
-			Strict:             utils.RefPointer(policy.Strict),
-			RateLimited:        utils.RefPointer(policy.RateLimited),
-			Floating:           utils.RefPointer(policy.Floating),
-			UsePool:            utils.RefPointer(policy.UsePool),
-			Encrypted:          utils.RefPointer(policy.Encrypted),
-			Protected:          utils.RefPointer(policy.Protected),
-			RequireCheckIn:     utils.RefPointer(policy.RequireCheckIn),
-			RequireHeartbeat:   utils.RefPointer(policy.RequireHeartbeat),
-			MaxMachines:        utils.RefPointer(policy.MaxMachines),
-			MaxUsers:           utils.RefPointer(policy.MaxUsers),
-			MaxUses:            utils.RefPointer(policy.MaxUses),
-			HeartbeatDuration:  utils.RefPointer(policy.HeartbeatDuration),
-			Duration:           utils.RefPointer(policy.Duration),
-			CheckInInterval:    utils.RefPointer(policy.CheckInInterval),
-			HeartbeatBasis:     utils.RefPointer(policy.HeartbeatBasis),
-			ExpirationStrategy: utils.RefPointer(policy.ExpirationStrategy),
-			RenewalBasis:       utils.RefPointer(policy.RenewalBasis),
-			OverageStrategy:    utils.RefPointer(policy.OverageStrategy),
-			Metadata:           policy.Metadata,
+			Name:                          utils.RefPointer(policy.Name),
+			Scheme:                        utils.RefPointer(policy.Scheme),
+			Strict:                        utils.RefPointer(policy.Strict),
+			RateLimited:                   utils.RefPointer(policy.RateLimited),
+			Floating:                      utils.RefPointer(policy.Floating),
+			UsePool:                       utils.RefPointer(policy.UsePool),
+			SignedKeys:                    utils.RefPointer(policy.SignedKeys),
+			Encrypted:                     utils.RefPointer(policy.Encrypted),
+			Protected:                     utils.RefPointer(policy.Protected),
+			RequireCheckIn:                utils.RefPointer(policy.RequireCheckIn),
+			RequireHeartbeat:              utils.RefPointer(policy.RequireHeartbeat),
+			MaxMachines:                   utils.RefPointer(policy.MaxMachines),
+			MaxUsers:                      utils.RefPointer(policy.MaxUsers),
+			MaxUses:                       utils.RefPointer(policy.MaxUses),
+			HeartbeatDuration:             utils.RefPointer(policy.HeartbeatDuration),
+			Duration:                      utils.RefPointer(policy.Duration),
+			CheckInInterval:               utils.RefPointer(policy.CheckInInterval),
+			HeartbeatBasis:                utils.RefPointer(policy.HeartbeatBasis),
+			HeartbeatResurrectionStrategy: utils.RefPointer(policy.HeartbeatResurrectionStrategy),
+			ExpirationStrategy:            utils.RefPointer(policy.ExpirationStrategy),
+			RenewalBasis:                  utils.RefPointer(policy.RenewalBasis),
+			OverageStrategy:               utils.RefPointer(policy.OverageStrategy),
+			Metadata:                      policy.Metadata,
 		},
 	}
 
@@ -403,9 +418,35 @@ func (svc *PolicyService) Delete(ctx *gin.Context, input *models.PolicyDeletionI
 	}
 	cSpan.End()
 
+	_, cSpan = input.Tracer.Start(rootCtx, "query-policy")
+	svc.logger.GetLogger().Info(fmt.Sprintf("querying policy [%s]", utils.DerefPointer(input.PolicyID)))
+	policy, err := svc.repo.SelectPolicyByPK(ctx, uuid.MustParse(utils.DerefPointer(input.PolicyID)))
+	if err != nil {
+		svc.logger.GetLogger().Error(err.Error())
+		cSpan.End()
+		if errors.Is(err, sql.ErrNoRows) {
+			resp.Code = cerrors.ErrCodeMapper[cerrors.ErrPolicyIDIsInvalid]
+			resp.Message = cerrors.ErrMessageMapper[cerrors.ErrPolicyIDIsInvalid]
+			return resp, cerrors.ErrPolicyIDIsInvalid
+		} else {
+			resp.Code = cerrors.ErrCodeMapper[cerrors.ErrGenericInternalServer]
+			resp.Message = cerrors.ErrMessageMapper[cerrors.ErrGenericInternalServer]
+			return resp, cerrors.ErrGenericInternalServer
+		}
+	}
+	cSpan.End()
+
+	// Only admins may change a protected policy
+	if !permissions.CanManageProtectedPolicy(ctx, policy) {
+		svc.logger.GetLogger().Error(fmt.Sprintf("subject [%s] cannot change protected policy [%s]", ctx.GetString(constants.ContextValueSubject), policy.ID))
+		resp.Code = cerrors.ErrCodeMapper[cerrors.ErrPolicyIsProtected]
+		resp.Message = cerrors.ErrMessageMapper[cerrors.ErrPolicyIsProtected]
+		return resp, cerrors.ErrPolicyIsProtected
+	}
+
 	_, cSpan = input.Tracer.Start(rootCtx, "delete-policy")
-	svc.logger.GetLogger().Info(fmt.Sprintf("deleting policy [%s] and associated licenses", utils.DerefPointer(input.PolicyID)))
-	err = svc.repo.DeletePolicyByPK(ctx, uuid.MustParse(utils.DerefPointer(input.PolicyID)))
+	svc.logger.GetLogger().Info(fmt.Sprintf("deleting policy [%s] and associated licenses", policy.ID))
+	err = svc.repo.DeletePolicyByPK(ctx, policy.ID)
 	if err != nil {
 		svc.logger.GetLogger().Error(err.Error())
 		cSpan.End()
@@ -468,20 +509,44 @@ func (svc *PolicyService) Update(ctx *gin.Context, input *models.PolicyUpdateInp
 	}
 	cSpan.End()
 
+	// Only admins may change a protected policy
+	if !permissions.CanManageProtectedPolicy(ctx, policy) {
+		svc.logger.GetLogger().Error(fmt.Sprintf("subject [%s] cannot change protected policy [%s]", ctx.GetString(constants.ContextValueSubject), policy.ID))
+		resp.Code = cerrors.ErrCodeMapper[cerrors.ErrPolicyIsProtected]
+		resp.Message = cerrors.ErrMessageMapper[cerrors.ErrPolicyIsProtected]
+		return resp, cerrors.ErrPolicyIsProtected
+	}
+
+	// Changing the scheme replaces the key pair, the previous key is kept for the default overlap period
+	previousKey := previousPolicyKey(policy, constants.PolicyKeyDefaultOverlapPeriod, time.Now())
+
 	// Update fields
 	policy, err = svc.updatePolicyField(ctx, input, policy)
 	if err != nil {
 		svc.logger.GetLogger().Error(err.Error())
-		resp.Code = cerrors.ErrCodeMapper[cerrors.ErrGenericInternalServer]
-		resp.Message = cerrors.ErrMessageMapper[cerrors.ErrGenericInternalServer]
-		return resp, cerrors.ErrGenericInternalServer
+		cErr := policyKeyError(err)
+		resp.Code = cerrors.ErrCodeMapper[cErr]
+		resp.Message = cerrors.ErrMessageMapper[cErr]
+		return resp, cErr
+	}
+
+	if policy.SignedKeys && policy.UsePool {
+		svc.logger.GetLogger().Error(fmt.Sprintf("policy [%s] cannot use signed keys with a key pool", policy.ID))
+		resp.Code = cerrors.ErrCodeMapper[cerrors.ErrPolicySignedKeysWithPool]
+		resp.Message = cerrors.ErrMessageMapper[cerrors.ErrPolicySignedKeysWithPool]
+		return resp, cerrors.ErrPolicySignedKeysWithPool
 	}
 
 	// Update existing policy
 	_, cSpan = input.Tracer.Start(rootCtx, "insert-new-policy")
 	svc.logger.GetLogger().Info("updating policy to database")
 	policy.UpdatedAt = time.Now()
-	err = svc.repo.UpdatePolicyByPK(ctx, policy)
+	if policy.PublicKey != previousKey.PublicKey {
+		svc.logger.GetLogger().Info(fmt.Sprintf("rotating key [%s] of policy [%s]", previousKey.KID, policy.ID))
+		err = svc.repo.RotatePolicyKeys(ctx, policy, previousKey)
+	} else {
+		err = svc.repo.UpdatePolicyByPK(ctx, policy)
+	}
 	if err != nil {
 		svc.logger.GetLogger().Error(err.Error())
 		cSpan.End()
@@ -492,33 +557,36 @@ func (svc *PolicyService) Update(ctx *gin.Context, input *models.PolicyUpdateInp
 	cSpan.End()
 
 	respData := models.PolicyRetrievalOutput{
-		ID:         policy.ID.String(),
-		TenantName: policy.TenantName,
-		PublicKey:  policy.PublicKey,
-		CreatedAt:  policy.CreatedAt,
-		UpdatedAt:  policy.UpdatedAt,
+		ID:            policy.ID.String(),
+		TenantName:    policy.TenantName,
+		PublicKey:     policy.PublicKey,
+		SignerBackend: policySignerBackend(policy),
+		CreatedAt:     policy.CreatedAt,
+		UpdatedAt:     policy.UpdatedAt,
 		PolicyAttributeModel: policy_attribute.PolicyAttributeModel{
-			Name:               utils.RefPointer(policy.Name),
-			Scheme:             utils.RefPointer(policy.Scheme),
-			Strict:             utils.RefPointer(policy.Strict),
-			RateLimited:        utils.RefPointer(policy.RateLimited),
-			Floating:           utils.RefPointer(policy.Floating),
-			UsePool:            utils.RefPointer(policy.UsePool),
-			Encrypted:          utils.RefPointer(policy.Encrypted),
-			Protected:          utils.RefPointer(policy.Protected),
-			RequireCheckIn:     utils.RefPointer(policy.RequireCheckIn),
-			RequireHeartbeat:   utils.RefPointer(policy.RequireHeartbeat),
-			MaxMachines:        utils.RefPointer(policy.MaxMachines),
-			MaxUsers:           utils.RefPointer(policy.MaxUsers),
-			MaxUses:            utils.RefPointer(policy.MaxUses),
-			HeartbeatDuration:  utils.RefPointer(policy.HeartbeatDuration),
-			Duration:           utils.RefPointer(policy.Duration),
-			CheckInInterval:    utils.RefPointer(policy.CheckInInterval),
-			HeartbeatBasis:     utils.RefPointer(policy.HeartbeatBasis),
-			ExpirationStrategy: utils.RefPointer(policy.ExpirationStrategy),
-			RenewalBasis:       utils.RefPointer(policy.RenewalBasis),
-			OverageStrategy:    utils.RefPointer(policy.OverageStrategy),
-			Metadata:           policy.Metadata,
+			Name:                          utils.RefPointer(policy.Name),
+			Scheme:                        utils.RefPointer(policy.Scheme),
+			Strict:                        utils.RefPointer(policy.Strict),
+			RateLimited:                   utils.RefPointer(policy.RateLimited),
+			Floating:                      utils.RefPointer(policy.Floating),
+			UsePool:                       utils.RefPointer(policy.UsePool),
+			SignedKeys:                    utils.RefPointer(policy.SignedKeys),
+			Encrypted:                     utils.RefPointer(policy.Encrypted),
+			Protected:                     utils.RefPointer(policy.Protected),
+			RequireCheckIn:                utils.RefPointer(policy.RequireCheckIn),
+			RequireHeartbeat:              utils.RefPointer(policy.RequireHeartbeat),
+			MaxMachines:                   utils.RefPointer(policy.MaxMachines),
+			MaxUsers:                      utils.RefPointer(policy.MaxUsers),
+			MaxUses:                       utils.RefPointer(policy.MaxUses),
+			HeartbeatDuration:             utils.RefPointer(policy.HeartbeatDuration),
+			Duration:                      utils.RefPointer(policy.Duration),
+			CheckInInterval:               utils.RefPointer(policy.CheckInInterval),
+			HeartbeatBasis:                utils.RefPointer(policy.HeartbeatBasis),
+			HeartbeatResurrectionStrategy: utils.RefPointer(policy.HeartbeatResurrectionStrategy),
+			ExpirationStrategy:            utils.RefPointer(policy.ExpirationStrategy),
+			RenewalBasis:                  utils.RefPointer(policy.RenewalBasis),
+			OverageStrategy:               utils.RefPointer(policy.OverageStrategy),
+			Metadata:                      policy.Metadata,
 		},
 	}
 
@@ -574,6 +642,14 @@ func (svc *PolicyService) Attach(ctx *gin.Context, input *models.PolicyAttachmen
 	}
 	cSpan.End()
 
+	// Only admins may change a protected policy
+	if !permissions.CanManageProtectedPolicy(ctx, policy) {
+		svc.logger.GetLogger().Error(fmt.Sprintf("subject [%s] cannot change protected policy [%s]", ctx.GetString(constants.ContextValueSubject), policy.ID))
+		resp.Code = cerrors.ErrCodeMapper[cerrors.ErrPolicyIsProtected]
+		resp.Message = cerrors.ErrMessageMapper[cerrors.ErrPolicyIsProtected]
+		return resp, cerrors.ErrPolicyIsProtected
+	}
+
 	_, cSpan = input.Tracer.Start(rootCtx, "query-entitlement")
 	svc.logger.GetLogger().Info(fmt.Sprintf("querying entitlements [%s]", input.EntitlementID))
 	entitlementIDs := make([]uuid.UUID, 0)
@@ -685,7 +761,7 @@ func (svc *PolicyService) Detach(ctx *gin.Context, input *models.PolicyDetachmen
 
 	_, cSpan = input.Tracer.Start(rootCtx, "query-policy")
 	svc.logger.GetLogger().Info(fmt.Sprintf("querying policy [%s]", utils.DerefPointer(input.PolicyID)))
-	_, err = svc.repo.SelectPolicyByPK(ctx, uuid.MustParse(utils.DerefPointer(input.PolicyID)))
+	policy, err := svc.repo.SelectPolicyByPK(ctx, uuid.MustParse(utils.DerefPointer(input.PolicyID)))
 	if err != nil {
 		svc.logger.GetLogger().Error(err.Error())
 		cSpan.End()
@@ -701,6 +777,14 @@ func (svc *PolicyService) Detach(ctx *gin.Context, input *models.PolicyDetachmen
 	}
 	cSpan.End()
 
+	// Only admins may change a protected policy
+	if !permissions.CanManageProtectedPolicy(ctx, policy) {
+		svc.logger.GetLogger().Error(fmt.Sprintf("subject [%s] cannot change protected policy [%s]", ctx.GetString(constants.ContextValueSubject), policy.ID))
+		resp.Code = cerrors.ErrCodeMapper[cerrors.ErrPolicyIsProtected]
+		resp.Message = cerrors.ErrMessageMapper[cerrors.ErrPolicyIsProtected]
+		return resp, cerrors.ErrPolicyIsProtected
+	}
+
 	_, cSpan = input.Tracer.Start(rootCtx, "delete-policy-entitlement")
 	svc.logger.GetLogger().Info(fmt.Sprintf("deleting policy entitlements [%s]", input.ID))
 	policyEntitlementIDs := make([]uuid.UUID, 0)
@@ -800,3 +884,414 @@ func (svc *PolicyService) ListEntitlements(ctx *gin.Context, input *models.Polic
 	resp.Data = outputs
 	return resp, nil
 }
+
+// CreateKeys loads keys into the key pool of a policy. When no keys are provided, the requested number of keys is generated.
+// Keys are pulled from the pool, oldest first, when a license is created for a policy with `use_pool` enabled.
+func (svc *PolicyService) CreateKeys(ctx *gin.Context, input *models.PolicyKeyCreationInput) (*response.BaseOutput, error) {
+	rootCtx, span := input.Tracer.Start(input.TracerCtx, "create-policy-keys-handler")
+	defer span.End()
+
+	resp := &response.BaseOutput{}
+	svc.logger.WithCustomFields(
+		zap.String(constants.RequestIDField, ctx.GetString(constants.RequestIDField)),
+		zap.String(constants.ContextValueSubject, ctx.GetString(constants.ContextValueSubject)),
+	)
+
+	_, cSpan := input.Tracer.Start(rootCtx, "query-tenant-by-name")
+	svc.logger.GetLogger().Info(fmt.Sprintf("verifying tenant [%s]", utils.DerefPointer(input.TenantName)))
+	tenant, err := svc.repo.SelectTenantByName(ctx, utils.DerefPointer(input.TenantName))
+	if err != nil {
+		svc.logger.GetLogger().Error(err.Error())
+		cSpan.End()
+		if errors.Is(err, sql.ErrNoRows) {
+			resp.Code = cerrors.ErrCodeMapper[cerrors.ErrTenantNameIsInvalid]
+			resp.Message = cerrors.ErrMessageMapper[cerrors.ErrTenantNameIsInvalid]
+			return resp, cerrors.ErrTenantNameIsInvalid
+		} else {
+			resp.Code = cerrors.ErrCodeMapper[cerrors.ErrGenericInternalServer]
+			resp.Message = cerrors.ErrMessageMapper[cerrors.ErrGenericInternalServer]
+			return resp, cerrors.ErrGenericInternalServer
+		}
+	}
+	cSpan.End()
+
+	_, cSpan = input.Tracer.Start(rootCtx, "query-policy")
+	svc.logger.GetLogger().Info(fmt.Sprintf("querying policy [%s]", utils.DerefPointer(input.PolicyID)))
+	policy, err := svc.repo.SelectPolicyByPK(ctx, uuid.MustParse(utils.DerefPointer(input.PolicyID)))
+	if err != nil {
+		svc.logger.GetLogger().Error(err.Error())
+		cSpan.End()
+		if errors.Is(err, sql.ErrNoRows) {
+			resp.Code = cerrors.ErrCodeMapper[cerrors.ErrPolicyIDIsInvalid]
+			resp.Message = cerrors.ErrMessageMapper[cerrors.ErrPolicyIDIsInvalid]
+			return resp, cerrors.ErrPolicyIDIsInvalid
+		} else {
+			resp.Code = cerrors.ErrCodeMapper[cerrors.ErrGenericInternalServer]
+			resp.Message = cerrors.ErrMessageMapper[cerrors.ErrGenericInternalServer]
+			return resp, cerrors.ErrGenericInternalServer
+		}
+	}
+	cSpan.End()
+
+	if policy.TenantName != tenant.Name {
+		svc.logger.GetLogger().Error(fmt.Sprintf("policy [%s] does not belong to tenant [%s]", policy.ID, tenant.Name))
+		resp.Code = cerrors.ErrCodeMapper[cerrors.ErrPolicyIDIsInvalid]
+		resp.Message = cerrors.ErrMessageMapper[cerrors.ErrPolicyIDIsInvalid]
+		return resp, cerrors.ErrPolicyIDIsInvalid
+	}
+
+	// Only admins may change a protected policy
+	if !permissions.CanManageProtectedPolicy(ctx, policy) {
+		svc.logger.GetLogger().Error(fmt.Sprintf("subject [%s] cannot change protected policy [%s]", ctx.GetString(constants.ContextValueSubject), policy.ID))
+		resp.Code = cerrors.ErrCodeMapper[cerrors.ErrPolicyIsProtected]
+		resp.Message = cerrors.ErrMessageMapper[cerrors.ErrPolicyIsProtected]
+		return resp, cerrors.ErrPolicyIsProtected
+	}
+
+	if !policy.UsePool {
+		svc.logger.GetLogger().Error(fmt.Sprintf("policy [%s] does not use a key pool", policy.ID))
+		resp.Code = cerrors.ErrCodeMapper[cerrors.ErrPolicyIsNotPooled]
+		resp.Message = cerrors.ErrMessageMapper[cerrors.ErrPolicyIsNotPooled]
+		return resp, cerrors.ErrPolicyIsNotPooled
+	}
+
+	// Generate the keys if none are provided
+	keys := input.Keys
+	if len(keys) == 0 {
+		svc.logger.GetLogger().Info(fmt.Sprintf("generating [%d] keys for policy [%s]", input.Count, policy.ID))
+		keys = make([]string, 0, input.Count)
+		for i := 0; i < input.Count; i++ {
+			keys = append(keys, utils.GenerateToken())
+		}
+	}
+
+	_, cSpan = input.Tracer.Start(rootCtx, "check-keys-exist")
+	svc.logger.GetLogger().Info(fmt.Sprintf("verifying [%d] keys are unique", len(keys)))
+	seen := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		if seen[key] {
+			cSpan.End()
+			resp.Code = cerrors.ErrCodeMapper[cerrors.ErrPolicyKeyAlreadyExist]
+			resp.Message = cerrors.ErrMessageMapper[cerrors.ErrPolicyKeyAlreadyExist]
+			return resp, cerrors.ErrPolicyKeyAlreadyExist
+		}
+		seen[key] = true
+	}
+
+	exists, err := svc.repo.CheckKeysExist(ctx, keys)
+	if err != nil {
+		svc.logger.GetLogger().Error(err.Error())
+		cSpan.End()
+		resp.Code = cerrors.ErrCodeMapper[cerrors.ErrGenericInternalServer]
+		resp.Message = cerrors.ErrMessageMapper[cerrors.ErrGenericInternalServer]
+		return resp, cerrors.ErrGenericInternalServer
+	}
+	cSpan.End()
+
+	if exists {
+		svc.logger.GetLogger().Error("one or more keys already exist")
+		resp.Code = cerrors.ErrCodeMapper[cerrors.ErrPolicyKeyAlreadyExist]
+		resp.Message = cerrors.ErrMessageMapper[cerrors.ErrPolicyKeyAlreadyExist]
+		return resp, cerrors.ErrPolicyKeyAlreadyExist
+	}
+
+	_, cSpan = input.Tracer.Start(rootCtx, "insert-policy-keys")
+	now := time.Now()
+	policyKeys := make([]entities.Key, 0, len(keys))
+	outputs := make([]models.PolicyKeyOutput, 0, len(keys))
+	for _, key := range keys {
+		keyID := uuid.New()
+		policyKeys = append(policyKeys, entities.Key{
+			ID:        keyID,
+			Key:       key,
+			PolicyID:  policy.ID,
+			CreatedAt: now,
+			UpdatedAt: now,
+		})
+		outputs = append(outputs, models.PolicyKeyOutput{
+			ID:        keyID.String(),
+			PolicyID:  policy.ID.String(),
+			Key:       key,
+			CreatedAt: now,
+			UpdatedAt: now,
+		})
+	}
+
+	err = svc.repo.InsertNewKeys(ctx, policyKeys)
+	if err != nil {
+		svc.logger.GetLogger().Error(err.Error())
+		cSpan.End()
+		resp.Code = cerrors.ErrCodeMapper[cerrors.ErrGenericInternalServer]
+		resp.Message = cerrors.ErrMessageMapper[cerrors.ErrGenericInternalServer]
+		return resp, cerrors.ErrGenericInternalServer
+	}
+	cSpan.End()
+
+	resp.Code = cerrors.ErrCodeMapper[nil]
+	resp.Message = cerrors.ErrMessageMapper[nil]
+	resp.Count = len(outputs)
+	resp.Data = outputs
+	return resp, nil
+}
+
+// ListKeys lists the keys remaining in the key pool of a policy. The count is the remaining pool size.
+func (svc *PolicyService) ListKeys(ctx *gin.Context, input *models.PolicyKeyListInput) (*response.BaseOutput, error) {
+	rootCtx, span := input.Tracer.Start(input.TracerCtx, "list-policy-keys-handler")
+	defer span.End()
+
+	resp := &response.BaseOutput{}
+	svc.logger.WithCustomFields(
+		zap.String(constants.RequestIDField, ctx.GetString(constants.RequestIDField)),
+		zap.String(constants.ContextValueSubject, ctx.GetString(constants.ContextValueSubject)),
+	)
+
+	_, cSpan := input.Tracer.Start(rootCtx, "query-tenant-by-name")
+	svc.logger.GetLogger().Info(fmt.Sprintf("verifying tenant [%s]", utils.DerefPointer(input.TenantName)))
+	tenant, err := svc.repo.SelectTenantByName(ctx, utils.DerefPointer(input.TenantName))
+	if err != nil {
+		svc.logger.GetLogger().Error(err.Error())
+		cSpan.End()
+		if errors.Is(err, sql.ErrNoRows) {
+			resp.Code = cerrors.ErrCodeMapper[cerrors.ErrTenantNameIsInvalid]
+			resp.Message = cerrors.ErrMessageMapper[cerrors.ErrTenantNameIsInvalid]
+			return resp, cerrors.ErrTenantNameIsInvalid
+		} else {
+			resp.Code = cerrors.ErrCodeMapper[cerrors.ErrGenericInternalServer]
+			resp.Message = cerrors.ErrMessageMapper[cerrors.ErrGenericInternalServer]
+			return resp, cerrors.ErrGenericInternalServer
+		}
+	}
+	cSpan.End()
+
+	_, cSpan = input.Tracer.Start(rootCtx, "query-policy")
+	svc.logger.GetLogger().Info(fmt.Sprintf("querying policy [%s]", utils.DerefPointer(input.PolicyID)))
+	policy, err := svc.repo.SelectPolicyByPK(ctx, uuid.MustParse(utils.DerefPointer(input.PolicyID)))
+	if err != nil {
+		svc.logger.GetLogger().Error(err.Error())
+		cSpan.End()
+		if errors.Is(err, sql.ErrNoRows) {
+			resp.Code = cerrors.ErrCodeMapper[cerrors.ErrPolicyIDIsInvalid]
+			resp.Message = cerrors.ErrMessageMapper[cerrors.ErrPolicyIDIsInvalid]
+			return resp, cerrors.ErrPolicyIDIsInvalid
+		} else {
+			resp.Code = cerrors.ErrCodeMapper[cerrors.ErrGenericInternalServer]
+			resp.Message = cerrors.ErrMessageMapper[cerrors.ErrGenericInternalServer]
+			return resp, cerrors.ErrGenericInternalServer
+		}
+	}
+	cSpan.End()
+
+	if policy.TenantName != tenant.Name {
+		svc.logger.GetLogger().Error(fmt.Sprintf("policy [%s] does not belong to tenant [%s]", policy.ID, tenant.Name))
+		resp.Code = cerrors.ErrCodeMapper[cerrors.ErrPolicyIDIsInvalid]
+		resp.Message = cerrors.ErrMessageMapper[cerrors.ErrPolicyIDIsInvalid]
+		return resp, cerrors.ErrPolicyIDIsInvalid
+	}
+
+	_, cSpan = input.Tracer.Start(rootCtx, "listing-policy-keys")
+	svc.logger.GetLogger().Info("listing policy keys")
+	keys, total, err := svc.repo.SelectKeys(ctx, policy.ID, input.QueryCommonParam)
+	if err != nil {
+		svc.logger.GetLogger().Error(err.Error())
+		cSpan.End()
+		resp.Code = cerrors.ErrCodeMapper[cerrors.ErrGenericInternalServer]
+		resp.Message = cerrors.ErrMessageMapper[cerrors.ErrGenericInternalServer]
+		return resp, cerrors.ErrGenericInternalServer
+	}
+	cSpan.End()
+
+	outputs := make([]models.PolicyKeyOutput, 0)
+	for _, key := range keys {
+		outputs = append(outputs, models.PolicyKeyOutput{
+			ID:        key.ID.String(),
+			PolicyID:  key.PolicyID.String(),
+			Key:       key.Key,
+			CreatedAt: key.CreatedAt,
+			UpdatedAt: key.UpdatedAt,
+		})
+	}
+
+	resp.Code = cerrors.ErrCodeMapper[nil]
+	resp.Message = cerrors.ErrMessageMapper[nil]
+	resp.Count = total
+	resp.Data = outputs
+	return resp, nil
+}
+
+// RotateKeys replaces the signing key pair of a policy. The previous key is kept in the key history of the policy,
+// and the certificates it signed are still accepted until the end of the overlap period.
+func (svc *PolicyService) RotateKeys(ctx *gin.Context, input *models.PolicyKeyRotationInput) (*response.BaseOutput, error) {
+	rootCtx, span := input.Tracer.Start(input.TracerCtx, "rotate-policy-keys-handler")
+	defer span.End()
+
+	resp := &response.BaseOutput{}
+	svc.logger.WithCustomFields(
+		zap.String(constants.RequestIDField, ctx.GetString(constants.RequestIDField)),
+		zap.String(constants.ContextValueSubject, ctx.GetString(constants.ContextValueSubject)),
+	)
+
+	_, cSpan := input.Tracer.Start(rootCtx, "query-tenant-by-name")
+	svc.logger.GetLogger().Info(fmt.Sprintf("verifying tenant [%s]", utils.DerefPointer(input.TenantName)))
+	tenant, err := svc.repo.SelectTenantByName(ctx, utils.DerefPointer(input.TenantName))
+	if err != nil {
+		svc.logger.GetLogger().Error(err.Error())
+		cSpan.End()
+		if errors.Is(err, sql.ErrNoRows) {
+			resp.Code = cerrors.ErrCodeMapper[cerrors.ErrTenantNameIsInvalid]
+			resp.Message = cerrors.ErrMessageMapper[cerrors.ErrTenantNameIsInvalid]
+			return resp, cerrors.ErrTenantNameIsInvalid
+		} else {
+			resp.Code = cerrors.ErrCodeMapper[cerrors.ErrGenericInternalServer]
+			resp.Message = cerrors.ErrMessageMapper[cerrors.ErrGenericInternalServer]
+			return resp, cerrors.ErrGenericInternalServer
+		}
+	}
+	cSpan.End()
+
+	_, cSpan = input.Tracer.Start(rootCtx, "query-policy")
+	svc.logger.GetLogger().Info(fmt.Sprintf("querying policy [%s]", utils.DerefPointer(input.PolicyID)))
+	policy, err := svc.repo.SelectPolicyByPK(ctx, uuid.MustParse(utils.DerefPointer(input.PolicyID)))
+	if err != nil {
+		svc.logger.GetLogger().Error(err.Error())
+		cSpan.End()
+		if errors.Is(err, sql.ErrNoRows) {
+			resp.Code = cerrors.ErrCodeMapper[cerrors.ErrPolicyIDIsInvalid]
+			resp.Message = cerrors.ErrMessageMapper[cerrors.ErrPolicyIDIsInvalid]
+			return resp, cerrors.ErrPolicyIDIsInvalid
+		} else {
+			resp.Code = cerrors.ErrCodeMapper[cerrors.ErrGenericInternalServer]
+			resp.Message = cerrors.ErrMessageMapper[cerrors.ErrGenericInternalServer]
+			return resp, cerrors.ErrGenericInternalServer
+		}
+	}
+	cSpan.End()
+
+	if policy.TenantName != tenant.Name {
+		svc.logger.GetLogger().Error(fmt.Sprintf("policy [%s] does not belong to tenant [%s]", policy.ID, tenant.Name))
+		resp.Code = cerrors.ErrCodeMapper[cerrors.ErrPolicyIDIsInvalid]
+		resp.Message = cerrors.ErrMessageMapper[cerrors.ErrPolicyIDIsInvalid]
+		return resp, cerrors.ErrPolicyIDIsInvalid
+	}
+
+	// Only admins may change a protected policy
+	if !permissions.CanManageProtectedPolicy(ctx, policy) {
+		svc.logger.GetLogger().Error(fmt.Sprintf("subject [%s] cannot change protected policy [%s]", ctx.GetString(constants.ContextValueSubject), policy.ID))
+		resp.Code = cerrors.ErrCodeMapper[cerrors.ErrPolicyIsProtected]
+		resp.Message = cerrors.ErrMessageMapper[cerrors.ErrPolicyIsProtected]
+		return resp, cerrors.ErrPolicyIsProtected
+	}
+
+	// Generate new private/public key pair, using the current scheme and signer backend unless others are provided
+	_, cSpan = input.Tracer.Start(rootCtx, "generate-policy-keys")
+	now := time.Now()
+	previousKey := previousPolicyKey(policy, input.OverlapPeriod, now)
+	if input.Scheme != nil {
+		policy.Scheme = utils.DerefPointer(input.Scheme)
+	}
+	if input.SignerBackend != nil {
+		policy.SignerBackend = utils.DerefPointer(input.SignerBackend)
+	}
+	svc.logger.GetLogger().Info(fmt.Sprintf("generating private/public key pair using [%s] algorithm on [%s] signer backend", policy.Scheme, policySignerBackend(policy)))
+	err = signer.GetInstance().NewPolicyKey(ctx, policy)
+	if err != nil {
+		svc.logger.GetLogger().Error(err.Error())
+		cSpan.End()
+		cErr := policyKeyError(err)
+		resp.Code = cerrors.ErrCodeMapper[cErr]
+		resp.Message = cerrors.ErrMessageMapper[cErr]
+		return resp, cErr
+	}
+	cSpan.End()
+
+	_, cSpan = input.Tracer.Start(rootCtx, "rotate-policy-keys")
+	svc.logger.GetLogger().Info(fmt.Sprintf("rotating key [%s] of policy [%s], retiring at [%s]", previousKey.KID, policy.ID, previousKey.RetiresAt))
+	err = svc.repo.RotatePolicyKeys(ctx, policy, previousKey)
+	if err != nil {
+		svc.logger.GetLogger().Error(err.Error())
+		cSpan.End()
+		resp.Code = cerrors.ErrCodeMapper[cerrors.ErrGenericInternalServer]
+		resp.Message = cerrors.ErrMessageMapper[cerrors.ErrGenericInternalServer]
+		return resp, cerrors.ErrGenericInternalServer
+	}
+	cSpan.End()
+
+	resp.Code = cerrors.ErrCodeMapper[nil]
+	resp.Message = cerrors.ErrMessageMapper[nil]
+	resp.Data = policyKeyOutputs(policy, []entities.PolicyKey{*previousKey}, now)
+	return resp, nil
+}
+
+// ListSigningKeys lists the active signing key of a policy, along with its rotated and retired keys.
+func (svc *PolicyService) ListSigningKeys(ctx *gin.Context, input *models.PolicySigningKeyListInput) (*response.BaseOutput, error) {
+	rootCtx, span := input.Tracer.Start(input.TracerCtx, "list-policy-signing-keys-handler")
+	defer span.End()
+
+	resp := &response.BaseOutput{}
+	svc.logger.WithCustomFields(
+		zap.String(constants.RequestIDField, ctx.GetString(constants.RequestIDField)),
+		zap.String(constants.ContextValueSubject, ctx.GetString(constants.ContextValueSubject)),
+	)
+
+	_, cSpan := input.Tracer.Start(rootCtx, "query-tenant-by-name")
+	svc.logger.GetLogger().Info(fmt.Sprintf("verifying tenant [%s]", utils.DerefPointer(input.TenantName)))
+	tenant, err := svc.repo.SelectTenantByName(ctx, utils.DerefPointer(input.TenantName))
+	if err != nil {
+		svc.logger.GetLogger().Error(err.Error())
+		cSpan.End()
+		if errors.Is(err, sql.ErrNoRows) {
+			resp.Code = cerrors.ErrCodeMapper[cerrors.ErrTenantNameIsInvalid]
+			resp.Message = cerrors.ErrMessageMapper[cerrors.ErrTenantNameIsInvalid]
+			return resp, cerrors.ErrTenantNameIsInvalid
+		} else {
+			resp.Code = cerrors.ErrCodeMapper[cerrors.ErrGenericInternalServer]
+			resp.Message = cerrors.ErrMessageMapper[cerrors.ErrGenericInternalServer]
+			return resp, cerrors.ErrGenericInternalServer
+		}
+	}
+	cSpan.End()
+
+	_, cSpan = input.Tracer.Start(rootCtx, "query-policy")
+	svc.logger.GetLogger().Info(fmt.Sprintf("querying policy [%s]", utils.DerefPointer(input.PolicyID)))
+	policy, err := svc.repo.SelectPolicyByPK(ctx, uuid.MustParse(utils.DerefPointer(input.PolicyID)))
+	if err != nil {
+		svc.logger.GetLogger().Error(err.Error())
+		cSpan.End()
+		if errors.Is(err, sql.ErrNoRows) {
+			resp.Code = cerrors.ErrCodeMapper[cerrors.ErrPolicyIDIsInvalid]
+			resp.Message = cerrors.ErrMessageMapper[cerrors.ErrPolicyIDIsInvalid]
+			return resp, cerrors.ErrPolicyIDIsInvalid
+		} else {
+			resp.Code = cerrors.ErrCodeMapper[cerrors.ErrGenericInternalServer]
+			resp.Message = cerrors.ErrMessageMapper[cerrors.ErrGenericInternalServer]
+			return resp, cerrors.ErrGenericInternalServer
+		}
+	}
+	cSpan.End()
+
+	if policy.TenantName != tenant.Name {
+		svc.logger.GetLogger().Error(fmt.Sprintf("policy [%s] does not belong to tenant [%s]", policy.ID, tenant.Name))
+		resp.Code = cerrors.ErrCodeMapper[cerrors.ErrPolicyIDIsInvalid]
+		resp.Message = cerrors.ErrMessageMapper[cerrors.ErrPolicyIDIsInvalid]
+		return resp, cerrors.ErrPolicyIDIsInvalid
+	}
+
+	_, cSpan = input.Tracer.Start(rootCtx, "listing-policy-signing-keys")
+	svc.logger.GetLogger().Info(fmt.Sprintf("listing signing keys of policy [%s]", policy.ID))
+	keys, err := svc.repo.SelectPolicyKeys(ctx, policy.ID)
+	if err != nil {
+		svc.logger.GetLogger().Error(err.Error())
+		cSpan.End()
+		resp.Code = cerrors.ErrCodeMapper[cerrors.ErrGenericInternalServer]
+		resp.Message = cerrors.ErrMessageMapper[cerrors.ErrGenericInternalServer]
+		return resp, cerrors.ErrGenericInternalServer
+	}
+	cSpan.End()
+
+	outputs := policyKeyOutputs(policy, keys, time.Now())
+
+	resp.Code = cerrors.ErrCodeMapper[nil]
+	resp.Message = cerrors.ErrMessageMapper[nil]
+	resp.Count = len(outputs)
+	resp.Data = outputs
+	return resp, nil
+}
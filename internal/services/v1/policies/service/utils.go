@@ -1,12 +1,17 @@
 package service
 
 import (
+	"errors"
 	"fmt"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go-license-management/internal/cerrors"
 	"go-license-management/internal/constants"
 	"go-license-management/internal/infrastructure/database/entities"
+	"go-license-management/internal/infrastructure/signer"
 	"go-license-management/internal/services/v1/policies/models"
 	"go-license-management/internal/utils"
+	"time"
 )
 
 func (svc *PolicyService) updatePolicyField(ctx *gin.Context, input *models.PolicyUpdateInput, policy *entities.Policy) (*entities.Policy, error) {
@@ -16,28 +21,13 @@ func (svc *PolicyService) updatePolicyField(ctx *gin.Context, input *models.Poli
 	if input.Scheme != nil {
 		scheme := utils.DerefPointer(input.Scheme)
 		if policy.Scheme != scheme {
-			var privateKey = ""
-			var publicKey = ""
+			policy.Scheme = scheme
 			svc.logger.GetLogger().Info(fmt.Sprintf("generating private/public key pair using [%s] algorithm", scheme))
-			switch scheme {
-			case constants.PolicySchemeED25519:
-				privateKey, publicKey, err = utils.NewEd25519KeyPair()
-				if err != nil {
-					svc.logger.GetLogger().Error(err.Error())
-					return policy, err
-				}
-			case constants.PolicySchemeRSA2048PKCS1:
-				privateKey, publicKey, err = utils.NewRSA2048PKCS1KeyPair()
-				if err != nil {
-					svc.logger.GetLogger().Error(err.Error())
-					return policy, err
-				}
-			default:
-				svc.logger.GetLogger().Error(fmt.Sprintf("invalid supported sheme [%s]", scheme))
+			err = signer.GetInstance().NewPolicyKey(ctx, policy)
+			if err != nil {
+				svc.logger.GetLogger().Error(err.Error())
 				return policy, err
 			}
-			policy.PrivateKey = privateKey
-			policy.PublicKey = publicKey
 		}
 	}
 
@@ -77,6 +67,10 @@ func (svc *PolicyService) updatePolicyField(ctx *gin.Context, input *models.Poli
 		policy.HeartbeatBasis = utils.DerefPointer(input.HeartbeatBasis)
 	}
 
+	if input.HeartbeatResurrectionStrategy != nil {
+		policy.HeartbeatResurrectionStrategy = utils.DerefPointer(input.HeartbeatResurrectionStrategy)
+	}
+
 	if input.CheckInInterval != nil {
 		policy.CheckInInterval = utils.DerefPointer(input.CheckInInterval)
 	}
@@ -90,6 +84,10 @@ func (svc *PolicyService) updatePolicyField(ctx *gin.Context, input *models.Poli
 	if input.UsePool != nil {
 		policy.UsePool = utils.DerefPointer(input.UsePool)
 	}
+	if input.SignedKeys != nil {
+		policy.SignedKeys = utils.DerefPointer(input.SignedKeys)
+	}
+
 	if input.Protected != nil {
 		policy.Protected = utils.DerefPointer(input.Protected)
 	}
@@ -102,3 +100,78 @@ func (svc *PolicyService) updatePolicyField(ctx *gin.Context, input *models.Poli
 
 	return policy, nil
 }
+
+// previousPolicyKey records the current key pair of the policy as a rotated key, so the certificates it signed are
+// still accepted during the overlap period. The private key is not kept, a rotated key never signs again.
+func previousPolicyKey(policy *entities.Policy, overlapPeriod int64, now time.Time) *entities.PolicyKey {
+	return &entities.PolicyKey{
+		ID:         uuid.New(),
+		KID:        utils.KeyID(policy.PublicKey),
+		TenantName: policy.TenantName,
+		PolicyID:   policy.ID,
+		Scheme:     policy.Scheme,
+		PublicKey:  policy.PublicKey,
+		RetiresAt:  now.Add(time.Duration(overlapPeriod) * time.Second),
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+}
+
+// policyKeyOutputs lists the active key pair of the policy, followed by its rotated and retired keys.
+func policyKeyOutputs(policy *entities.Policy, keys []entities.PolicyKey, now time.Time) []models.PolicySigningKeyOutput {
+	outputs := make([]models.PolicySigningKeyOutput, 0, len(keys)+1)
+	outputs = append(outputs, models.PolicySigningKeyOutput{
+		KID:       utils.KeyID(policy.PublicKey),
+		PolicyID:  policy.ID.String(),
+		Scheme:    policy.Scheme,
+		PublicKey: policy.PublicKey,
+		Status:    constants.PolicyKeyStatusActive,
+	})
+
+	for _, key := range keys {
+		status := constants.PolicyKeyStatusRotated
+		if !now.Before(key.RetiresAt) {
+			status = constants.PolicyKeyStatusRetired
+		}
+		outputs = append(outputs, models.PolicySigningKeyOutput{
+			KID:       key.KID,
+			PolicyID:  key.PolicyID.String(),
+			Scheme:    key.Scheme,
+			PublicKey: key.PublicKey,
+			Status:    status,
+			RotatedAt: utils.RefPointer(key.CreatedAt),
+			RetiresAt: utils.RefPointer(key.RetiresAt),
+		})
+	}
+
+	return outputs
+}
+
+// policySignerBackend returns the signer backend holding the private key of the policy.
+func policySignerBackend(policy *entities.Policy) string {
+	if policy.SignerBackend == "" {
+		return constants.PolicySignerBackendDatabase
+	}
+	return policy.SignerBackend
+}
+
+// policyKeyError maps the errors of the key pair generation to the policy errors.
+func policyKeyError(err error) error {
+	switch {
+	case errors.Is(err, utils.ErrSigningSchemeIsNotSupported):
+		return cerrors.ErrPolicySchemeIsInvalid
+	case errors.Is(err, signer.ErrBackendIsUnavailable), errors.Is(err, signer.ErrBackendIsNotCompiled):
+		return cerrors.ErrPolicySignerBackendIsInvalid
+	default:
+		return cerrors.ErrGenericInternalServer
+	}
+}
+
+// inProductTokenScope reports whether the product is in the scope of the request. Requests authenticated with a
+// product token can only manage the resources of the product of the token.
+func inProductTokenScope(ctx *gin.Context, productID uuid.UUID) bool {
+	if ctx.GetString(constants.ContextValueRole) != constants.RoleProduct {
+		return true
+	}
+	return ctx.GetString(constants.ContextValueProduct) == productID.String()
+}
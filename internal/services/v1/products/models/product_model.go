@@ -90,7 +90,12 @@ type ProductTokensInput struct {
 	Permissions []string `json:"permissions" validate:"required" example:"test"`
 }
 
+// ProductTokenOutput holds the generated product token. The token is only returned once, as only its hash is stored.
 type ProductTokenOutput struct {
-	ID    string `json:"id"`
-	Token string `json:"token"`
+	ID          string    `json:"id"`
+	Token       string    `json:"token"`
+	Name        string    `json:"name"`
+	Permissions []string  `json:"permissions"`
+	Expiry      time.Time `json:"expiry"`
+	CreatedAt   time.Time `json:"created_at"`
 }
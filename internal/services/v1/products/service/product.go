@@ -10,11 +10,13 @@ import (
 	"go-license-management/internal/constants"
 	"go-license-management/internal/infrastructure/database/entities"
 	"go-license-management/internal/infrastructure/logging"
+	"go-license-management/internal/permissions"
 	"go-license-management/internal/response"
 	"go-license-management/internal/services/v1/products/models"
 	"go-license-management/internal/services/v1/products/repository"
 	"go-license-management/internal/utils"
 	"go.uber.org/zap"
+	"slices"
 	"time"
 )
 
@@ -231,6 +233,11 @@ func (svc *ProductService) List(ctx *gin.Context, input *models.ProductListInput
 	}
 	cSpan.End()
 
+	// Product tokens only list their own product
+	if ctx.GetString(constants.ContextValueRole) == constants.RoleProduct {
+		input.QueryCommonParam.ProductID = utils.RefPointer(ctx.GetString(constants.ContextValueProduct))
+	}
+
 	_, cSpan = input.Tracer.Start(rootCtx, "query-product-by-pkc")
 	products, total, err := svc.repo.SelectProducts(ctx, tenant.Name, input.QueryCommonParam)
 	if err != nil {
@@ -461,7 +468,7 @@ func (svc *ProductService) Tokens(ctx *gin.Context, input *models.ProductTokensI
 	cSpan.End()
 
 	// Check product
-	svc.logger.GetLogger().Info(fmt.Sprintf("verifying product [%s]", input.ProductID))
+	svc.logger.GetLogger().Info(fmt.Sprintf("verifying product [%s]", utils.DerefPointer(input.ProductID)))
 	_, cSpan = input.Tracer.Start(rootCtx, "query-product")
 	product, err := svc.repo.SelectProductByPK(ctx, uuid.MustParse(utils.DerefPointer(input.ProductID)))
 	if err != nil {
@@ -479,16 +486,53 @@ func (svc *ProductService) Tokens(ctx *gin.Context, input *models.ProductTokensI
 	}
 	cSpan.End()
 
-	// Generate token
+	// Product tokens cannot grant more permissions than their own
+	if ctx.GetString(constants.ContextValueRole) == constants.RoleProduct {
+		tokenPermissions := ctx.GetStringSlice(constants.ContextValuePermissions)
+		for _, permission := range input.Permissions {
+			granted := permissions.ProductTokenHasPermission(tokenPermissions, permission)
+			if permission == permissions.ProductTokenPermissionWildcard {
+				granted = slices.Contains(tokenPermissions, permissions.ProductTokenPermissionWildcard)
+			}
+			if !granted {
+				svc.logger.GetLogger().Error(fmt.Sprintf("product token cannot grant permission [%s]", permission))
+				resp.Code = cerrors.ErrCodeMapper[cerrors.ErrProductTokenPermissionIsInvalid]
+				resp.Message = cerrors.ErrMessageMapper[cerrors.ErrProductTokenPermissionIsInvalid]
+				return resp, cerrors.ErrProductTokenPermissionIsInvalid
+			}
+		}
+	}
+
+	// Generate token, only its hash is stored
 	_, cSpan = input.Tracer.Start(rootCtx, "generate-product-token")
-	token := utils.GenerateToken()
+	token, err := utils.GenerateProductToken()
+	if err != nil {
+		svc.logger.GetLogger().Error(err.Error())
+		cSpan.End()
+		resp.Code = cerrors.ErrCodeMapper[cerrors.ErrGenericInternalServer]
+		resp.Message = cerrors.ErrMessageMapper[cerrors.ErrGenericInternalServer]
+		return resp, cerrors.ErrGenericInternalServer
+	}
+
 	id := uuid.New()
 	productToken := &entities.ProductToken{
-		ID:         id,
-		TenantName: tenant.Name,
-		ProductID:  product.ID,
-		Token:      token,
-		CreatedAt:  time.Now(),
+		ID:          id,
+		TenantName:  tenant.Name,
+		ProductID:   product.ID,
+		Name:        utils.DerefPointer(input.Name),
+		TokenHash:   utils.HashToken(token),
+		Permissions: input.Permissions,
+		CreatedAt:   time.Now(),
+	}
+	if input.Expiry != nil {
+		productToken.Expiry, _ = time.Parse(constants.DateFormatISO8601Hyphen, utils.DerefPointer(input.Expiry))
+	}
+	// Product tokens cannot outlive the token generating them
+	if ctx.GetString(constants.ContextValueRole) == constants.RoleProduct {
+		tokenExpiry := ctx.GetTime(constants.ContextValueTokenExpiry)
+		if !tokenExpiry.IsZero() && (productToken.Expiry.IsZero() || productToken.Expiry.After(tokenExpiry)) {
+			productToken.Expiry = tokenExpiry
+		}
 	}
 	err = svc.repo.InsertNewProductToken(ctx, productToken)
 	if err != nil {
@@ -503,8 +547,12 @@ func (svc *ProductService) Tokens(ctx *gin.Context, input *models.ProductTokensI
 	resp.Code = cerrors.ErrCodeMapper[nil]
 	resp.Message = cerrors.ErrMessageMapper[nil]
 	resp.Data = models.ProductTokenOutput{
-		ID:    id.String(),
-		Token: token,
+		ID:          id.String(),
+		Token:       token,
+		Name:        productToken.Name,
+		Permissions: productToken.Permissions,
+		Expiry:      productToken.Expiry,
+		CreatedAt:   productToken.CreatedAt,
 	}
 	return resp, nil
 }
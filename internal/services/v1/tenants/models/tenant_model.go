@@ -8,15 +8,17 @@ import (
 )
 
 type TenantRegistrationInput struct {
-	TracerCtx context.Context
-	Tracer    trace.Tracer
-	Name      *string `json:"name,omitempty" validate:"required" example:"test"`
+	TracerCtx     context.Context
+	Tracer        trace.Tracer
+	Name          *string `json:"name,omitempty" validate:"required" example:"test"`
+	SignerBackend *string `json:"signer_backend,omitempty" validate:"optional" example:"database"`
 }
 
 type TenantRegistrationOutput struct {
-	Name      string    `json:"name"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	Name          string    `json:"name"`
+	SignerBackend string    `json:"signer_backend"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
 }
 
 type TenantListInput struct {
@@ -33,7 +35,9 @@ type TenantRetrievalInput struct {
 
 type TenantRetrievalOutput struct {
 	Name             string    `json:"name"`
+	KID              string    `json:"kid"`
 	Ed25519PublicKey string    `json:"ed25519_public_key"`
+	SignerBackend    string    `json:"signer_backend"`
 	CreatedAt        time.Time `json:"created_at"`
 	UpdatedAt        time.Time `json:"updated_at"`
 }
@@ -48,4 +52,5 @@ type TenantRegenerationInput struct {
 	TracerCtx context.Context
 	Tracer    trace.Tracer
 	Name      *string `json:"name,omitempty" validate:"required" example:"test"`
+	Mode      *string `json:"mode,omitempty" validate:"optional" example:"graceful"`
 }
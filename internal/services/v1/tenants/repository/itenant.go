@@ -13,4 +13,5 @@ type ITenant interface {
 	CheckTenantExistByPK(ctx context.Context, name string) (bool, error)
 	DeleteTenantByPK(ctx context.Context, name string) error
 	UpdateTenantByPK(ctx context.Context, tenant *entities.Tenant) (*entities.Tenant, error)
+	RegenerateTenantKeys(ctx context.Context, tenant *entities.Tenant, previousKey *entities.TenantKey) (*entities.Tenant, error)
 }
@@ -5,10 +5,12 @@ import (
 	"errors"
 	"fmt"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"go-license-management/internal/cerrors"
 	"go-license-management/internal/constants"
 	"go-license-management/internal/infrastructure/database/entities"
 	"go-license-management/internal/infrastructure/logging"
+	"go-license-management/internal/infrastructure/signer"
 	"go-license-management/internal/response"
 	"go-license-management/internal/services/v1/tenants/models"
 	"go-license-management/internal/services/v1/tenants/repository"
@@ -18,12 +20,15 @@ import (
 )
 
 type TenantService struct {
-	repo   repository.ITenant
-	logger *logging.Logger
+	repo           repository.ITenant
+	accessTokenTTL utils.AccessTokenTTL
+	logger         *logging.Logger
 }
 
 func NewTenantService(options ...func(*TenantService)) *TenantService {
-	svc := &TenantService{}
+	svc := &TenantService{
+		accessTokenTTL: utils.AccessTokenTTL{Default: constants.JWTDuration * time.Second},
+	}
 
 	for _, opt := range options {
 		opt(svc)
@@ -40,6 +45,17 @@ func WithRepository(repo repository.ITenant) func(*TenantService) {
 	}
 }
 
+// WithAccessTokenTTL sets the lifetimes of the access tokens, which bound how long the previous keys of a tenant are
+// kept after a graceful regeneration.
+func WithAccessTokenTTL(ttl utils.AccessTokenTTL) func(*TenantService) {
+	return func(c *TenantService) {
+		if ttl.Default <= 0 {
+			ttl.Default = c.accessTokenTTL.Default
+		}
+		c.accessTokenTTL = ttl
+	}
+}
+
 func (svc *TenantService) Create(ctx *gin.Context, input *models.TenantRegistrationInput) (*response.BaseOutput, error) {
 	rootCtx, span := input.Tracer.Start(input.TracerCtx, "create-handler")
 	defer span.End()
@@ -69,6 +85,16 @@ func (svc *TenantService) Create(ctx *gin.Context, input *models.TenantRegistrat
 		return resp, cerrors.ErrTenantNameAlreadyExist
 	}
 
+	// The signer backend must be configured before policies can generate their keys on it
+	signerBackend := utils.DerefPointer(input.SignerBackend)
+	_, err = signer.GetInstance().Backend(signerBackend)
+	if err != nil {
+		svc.logger.GetLogger().Error(err.Error())
+		resp.Code = cerrors.ErrCodeMapper[cerrors.ErrTenantSignerBackendIsInvalid]
+		resp.Message = cerrors.ErrMessageMapper[cerrors.ErrTenantSignerBackendIsInvalid]
+		return resp, cerrors.ErrTenantSignerBackendIsInvalid
+	}
+
 	// If not, generate additional required info
 	_, cSpan = input.Tracer.Start(rootCtx, "generate-tenant-key")
 	svc.logger.GetLogger().Info(fmt.Sprintf("generating new private/public key pair for tenant [%s]", utils.DerefPointer(input.Name)))
@@ -90,6 +116,7 @@ func (svc *TenantService) Create(ctx *gin.Context, input *models.TenantRegistrat
 		Name:              utils.DerefPointer(input.Name),
 		Ed25519PublicKey:  publicKey,
 		Ed25519PrivateKey: privateKey,
+		SignerBackend:     signerBackend,
 		CreatedAt:         now,
 		UpdatedAt:         now,
 	}
@@ -104,9 +131,10 @@ func (svc *TenantService) Create(ctx *gin.Context, input *models.TenantRegistrat
 	cSpan.End()
 
 	output := &models.TenantRegistrationOutput{
-		Name:      tenant.Name,
-		CreatedAt: tenant.CreatedAt,
-		UpdatedAt: tenant.UpdatedAt,
+		Name:          tenant.Name,
+		SignerBackend: tenantSignerBackend(tenant),
+		CreatedAt:     tenant.CreatedAt,
+		UpdatedAt:     tenant.UpdatedAt,
 	}
 
 	resp.Code = cerrors.ErrCodeMapper[nil]
@@ -142,7 +170,9 @@ func (svc *TenantService) List(ctx *gin.Context, input *models.TenantListInput)
 	for _, tenant := range tenants {
 		respData = append(respData, models.TenantRetrievalOutput{
 			Name:             tenant.Name,
+			KID:              utils.KeyID(tenant.Ed25519PublicKey),
 			Ed25519PublicKey: tenant.Ed25519PublicKey,
+			SignerBackend:    tenantSignerBackend(&tenant),
 			CreatedAt:        tenant.CreatedAt,
 			UpdatedAt:        tenant.UpdatedAt,
 		})
@@ -186,7 +216,9 @@ func (svc *TenantService) Retrieve(ctx *gin.Context, input *models.TenantRetriev
 	_, cSpan = input.Tracer.Start(rootCtx, "convert-tenant-to-output")
 	respData := models.TenantRetrievalOutput{
 		Name:             tenant.Name,
+		KID:              utils.KeyID(tenant.Ed25519PublicKey),
 		Ed25519PublicKey: tenant.Ed25519PublicKey,
+		SignerBackend:    tenantSignerBackend(tenant),
 		CreatedAt:        tenant.CreatedAt,
 		UpdatedAt:        tenant.UpdatedAt,
 	}
@@ -265,11 +297,27 @@ func (svc *TenantService) Regenerate(ctx *gin.Context, input *models.TenantRegen
 	}
 	cSpan.End()
 
+	// A graceful regeneration keeps the previous key in the key ring of the tenant, so the access tokens it signed
+	// are still accepted until they expire. An emergency regeneration revokes them immediately.
+	var previousKey *entities.TenantKey
+	if utils.DerefPointer(input.Mode) != constants.TenantRegenerationModeEmergency {
+		now := time.Now()
+		previousKey = &entities.TenantKey{
+			ID:               uuid.New(),
+			KID:              utils.KeyID(tenant.Ed25519PublicKey),
+			TenantName:       tenant.Name,
+			Ed25519PublicKey: tenant.Ed25519PublicKey,
+			ExpiresAt:        now.Add(svc.accessTokenTTL.Longest(tenant.Name)),
+			CreatedAt:        now,
+		}
+	}
+
 	tenant.Ed25519PrivateKey = privateKey
 	tenant.Ed25519PublicKey = publicKey
 
 	_, cSpan = input.Tracer.Start(rootCtx, "update-tenant")
-	tenant, err = svc.repo.UpdateTenantByPK(ctx, tenant)
+	svc.logger.GetLogger().Info(fmt.Sprintf("regenerating keys of tenant [%s] in [%s] mode", tenant.Name, utils.DerefPointer(input.Mode)))
+	tenant, err = svc.repo.RegenerateTenantKeys(ctx, tenant, previousKey)
 	if err != nil {
 		svc.logger.GetLogger().Error(err.Error())
 		cSpan.End()
@@ -282,7 +330,9 @@ func (svc *TenantService) Regenerate(ctx *gin.Context, input *models.TenantRegen
 	_, cSpan = input.Tracer.Start(rootCtx, "convert-tenant-to-output")
 	respData := models.TenantRetrievalOutput{
 		Name:             tenant.Name,
+		KID:              utils.KeyID(tenant.Ed25519PublicKey),
 		Ed25519PublicKey: tenant.Ed25519PublicKey,
+		SignerBackend:    tenantSignerBackend(tenant),
 		CreatedAt:        tenant.CreatedAt,
 		UpdatedAt:        tenant.UpdatedAt,
 	}
@@ -294,3 +344,11 @@ func (svc *TenantService) Regenerate(ctx *gin.Context, input *models.TenantRegen
 
 	return resp, nil
 }
+
+// tenantSignerBackend returns the default signer backend of the policies of the tenant.
+func tenantSignerBackend(tenant *entities.Tenant) string {
+	if tenant.SignerBackend == "" {
+		return constants.PolicySignerBackendDatabase
+	}
+	return tenant.SignerBackend
+}
@@ -0,0 +1,116 @@
+package utils
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+// NewECDSAP256KeyPair generates the private signing key and the public verify key using ECDSA algorithm on the P-256 curve
+// Return te signingKey (private key) and verifyKey (public key)
+func NewECDSAP256KeyPair() (string, string, error) {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return "", "", err
+	}
+
+	// Export the private key in PKCS#8 DER format
+	privateKeyBytes, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		return "", "", err
+	}
+	signingKey := base64.StdEncoding.EncodeToString(privateKeyBytes)
+
+	// Export the public key in SPKI DER format
+	publicKeyBytes, err := x509.MarshalPKIXPublicKey(&privateKey.PublicKey)
+	if err != nil {
+		return "", "", err
+	}
+	verifyKey := base64.StdEncoding.EncodeToString(publicKeyBytes)
+
+	return signingKey, verifyKey, nil
+}
+
+// NewLicenseKeyWithECDSAP256 generates new license key using ECDSA algorithm on the P-256 curve
+// Returns a license string in format {{signature}}.{{data}}
+func NewLicenseKeyWithECDSAP256(signingKey string, data any) (string, error) {
+	bData, err := json.Marshal(data)
+	if err != nil {
+		return "", err
+	}
+
+	signature, err := SignMessageWithECDSAP256(signingKey, bData)
+	if err != nil {
+		return "", err
+	}
+
+	return EncodeLicenseKey(signature, bData), nil
+}
+
+// SignMessageWithECDSAP256 signs the SHA-256 digest of the message using ECDSA algorithm on the P-256 curve
+// and returns the ASN.1 DER encoded signature
+func SignMessageWithECDSAP256(signingKey string, message []byte) ([]byte, error) {
+	privateKeyBytes, err := base64.StdEncoding.DecodeString(signingKey)
+	if err != nil {
+		return nil, err
+	}
+
+	decodedPrivateKey, err := x509.ParsePKCS8PrivateKey(privateKeyBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	// Assert that it is of type *ecdsa.PrivateKey on the P-256 curve
+	privateKey, ok := decodedPrivateKey.(*ecdsa.PrivateKey)
+	if !ok || privateKey.Curve != elliptic.P256() {
+		return nil, errors.New("decoded key is not of type ecdsa.PrivateKey on the P-256 curve")
+	}
+
+	// Sign the SHA-256 digest of the message with the private key
+	hashed := sha256.Sum256(message)
+	return ecdsa.SignASN1(rand.Reader, privateKey, hashed[:])
+}
+
+// VerifyLicenseKeyWithECDSAP256 verifies a license key against the provided public key using ECDSA algorithm on the P-256 curve
+func VerifyLicenseKeyWithECDSAP256(verifyKey string, licenseKey string) (bool, []byte, error) {
+	parts := strings.Split(licenseKey, ".")
+	if len(parts) != 2 {
+		return false, nil, errors.New("invalid license key format")
+	}
+	encodedSignature := parts[0]
+	encodedData := parts[1]
+
+	data, err := base64.StdEncoding.DecodeString(encodedData)
+	if err != nil {
+		return false, nil, err
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(encodedSignature)
+	if err != nil {
+		return false, nil, err
+	}
+
+	publicKeyBytes, err := base64.StdEncoding.DecodeString(verifyKey)
+	if err != nil {
+		return false, nil, err
+	}
+
+	decodedPublicKey, err := x509.ParsePKIXPublicKey(publicKeyBytes)
+	if err != nil {
+		return false, nil, err
+	}
+
+	publicKey, ok := decodedPublicKey.(*ecdsa.PublicKey)
+	if !ok || publicKey.Curve != elliptic.P256() {
+		return false, nil, errors.New("decoded key is not of type ecdsa.PublicKey on the P-256 curve")
+	}
+
+	hashed := sha256.Sum256(data)
+	return ecdsa.VerifyASN1(publicKey, hashed[:], signature), data, nil
+}
@@ -6,7 +6,6 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"errors"
-	"fmt"
 	"strings"
 )
 
@@ -38,36 +37,39 @@ func NewEd25519KeyPair() (string, string, error) {
 // NewLicenseKeyWithEd25519 generates new license key using Ed25519 algorithm
 // Returns a license string in format {{signature}}.{{data}}
 func NewLicenseKeyWithEd25519(signingKey string, data any) (string, error) {
-	privateKeyBytes, err := base64.StdEncoding.DecodeString(signingKey)
+	bData, err := json.Marshal(data)
 	if err != nil {
 		return "", err
 	}
 
-	decodedPrivateKey, err := x509.ParsePKCS8PrivateKey(privateKeyBytes)
+	signature, err := SignMessageWithEd25519(signingKey, bData)
 	if err != nil {
 		return "", err
 	}
 
-	// Assert that it is of type ed25519.PrivateKey
-	privateKey, ok := decodedPrivateKey.(ed25519.PrivateKey)
-	if !ok {
-		return "", errors.New("decoded key is not of type ed25519.PrivateKey")
-	}
+	return EncodeLicenseKey(signature, bData), nil
+}
 
-	bData, err := json.Marshal(data)
+// SignMessageWithEd25519 signs the message using Ed25519 algorithm and returns the raw signature
+func SignMessageWithEd25519(signingKey string, message []byte) ([]byte, error) {
+	privateKeyBytes, err := base64.StdEncoding.DecodeString(signingKey)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	// Sign the data with the private key
-	signature := ed25519.Sign(privateKey, bData)
-	encodedSignature := base64.StdEncoding.EncodeToString(signature)
-	encodedData := base64.StdEncoding.EncodeToString(bData)
+	decodedPrivateKey, err := x509.ParsePKCS8PrivateKey(privateKeyBytes)
+	if err != nil {
+		return nil, err
+	}
 
-	// Combine the encoded data and signature to create the license key
-	licenseKey := fmt.Sprintf("%s.%s", encodedSignature, encodedData)
+	// Assert that it is of type ed25519.PrivateKey
+	privateKey, ok := decodedPrivateKey.(ed25519.PrivateKey)
+	if !ok {
+		return nil, errors.New("decoded key is not of type ed25519.PrivateKey")
+	}
 
-	return licenseKey, nil
+	// Sign the message with the private key
+	return ed25519.Sign(privateKey, message), nil
 }
 
 // VerifyLicenseKeyWithEd25519 verifies a license key against the provided public key using Ed25519 algorithm
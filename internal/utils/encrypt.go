@@ -5,11 +5,32 @@ import (
 	"crypto/cipher"
 	"crypto/rand"
 	"crypto/sha256"
+	"encoding/base64"
 	"errors"
+	"fmt"
 	"golang.org/x/crypto/bcrypt"
+	"golang.org/x/crypto/pbkdf2"
 	"io"
 )
 
+const (
+	PassphraseEncryptionAlgorithm = "aes-256-gcm"
+	PassphraseKDF                 = "pbkdf2-sha256"
+	PassphraseKDFIterations       = 100000
+	PassphraseKDFMaxIterations    = 1000000
+	PassphraseKDFSaltSize         = 16
+)
+
+// PassphraseEncryptedContent is the content encrypted with a key derived from a passphrase,
+// along with the parameters required to derive the key again.
+type PassphraseEncryptedContent struct {
+	Enc        string `json:"enc"`
+	Alg        string `json:"alg"`
+	KDF        string `json:"kdf"`
+	Salt       string `json:"salt"`
+	Iterations int    `json:"iterations"`
+}
+
 // Encrypt encrypts data using 256-bit AES-GCM.  This both hides the content of
 // the data and provides a check that it hasn't been altered. Output takes the
 // form nonce|ciphertext|tag where '|' indicates concatenation.
@@ -60,6 +81,64 @@ func Decrypt(ciphertext []byte, key []byte) (plaintext []byte, err error) {
 	)
 }
 
+// EncryptWithPassphrase encrypts data using 256-bit AES-GCM with a key derived from the passphrase
+// using PBKDF2-SHA256 and a random salt.
+func EncryptWithPassphrase(plaintext []byte, passphrase string) (*PassphraseEncryptedContent, error) {
+	salt := make([]byte, PassphraseKDFSaltSize)
+	_, err := io.ReadFull(rand.Reader, salt)
+	if err != nil {
+		return nil, err
+	}
+
+	key := pbkdf2.Key([]byte(passphrase), salt, PassphraseKDFIterations, 32, sha256.New)
+	ciphertext, err := Encrypt(plaintext, key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PassphraseEncryptedContent{
+		Enc:        base64.StdEncoding.EncodeToString(ciphertext),
+		Alg:        PassphraseEncryptionAlgorithm,
+		KDF:        PassphraseKDF,
+		Salt:       base64.StdEncoding.EncodeToString(salt),
+		Iterations: PassphraseKDFIterations,
+	}, nil
+}
+
+// DecryptWithPassphrase decrypts content encrypted by EncryptWithPassphrase.
+func DecryptWithPassphrase(content *PassphraseEncryptedContent, passphrase string) ([]byte, error) {
+	if content.Alg != PassphraseEncryptionAlgorithm {
+		return nil, fmt.Errorf("unsupported encryption algorithm [%s]", content.Alg)
+	}
+	if content.KDF != PassphraseKDF {
+		return nil, fmt.Errorf("unsupported key derivation function [%s]", content.KDF)
+	}
+	// The iterations are read from the content, they are capped so a crafted content cannot force unbounded work
+	if content.Iterations <= 0 || content.Iterations > PassphraseKDFMaxIterations {
+		return nil, fmt.Errorf("invalid key derivation iterations [%d]", content.Iterations)
+	}
+
+	salt, err := base64.StdEncoding.DecodeString(content.Salt)
+	if err != nil {
+		return nil, err
+	}
+
+	ciphertext, err := base64.StdEncoding.DecodeString(content.Enc)
+	if err != nil {
+		return nil, err
+	}
+
+	key := pbkdf2.Key([]byte(passphrase), salt, content.Iterations, 32, sha256.New)
+	return Decrypt(ciphertext, key)
+}
+
+// CertificatePassphrase returns the passphrase encrypting license files and machine files, derived from the license
+// key and the machine fingerprint, which is empty for license files. Each input is labeled and prefixed with its
+// length, so distinct license key and fingerprint pairs never produce the same passphrase.
+func CertificatePassphrase(licenseKey string, fingerprint string) string {
+	return fmt.Sprintf("license_key:%d:%s;fingerprint:%d:%s", len(licenseKey), licenseKey, len(fingerprint), fingerprint)
+}
+
 // HashPassword hashes the password using default cost
 func HashPassword(password string) (string, error) {
 	bPassword := []byte(password)
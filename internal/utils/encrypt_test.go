@@ -14,6 +14,36 @@ func TestEncrypt(t *testing.T) {
 
 }
 
+func TestEncryptWithPassphrase(t *testing.T) {
+	content, err := EncryptWithPassphrase([]byte("hehe"), "secret")
+	assert.NoError(t, err)
+	assert.Equal(t, PassphraseEncryptionAlgorithm, content.Alg)
+	assert.Equal(t, PassphraseKDF, content.KDF)
+	assert.Equal(t, PassphraseKDFIterations, content.Iterations)
+
+	plaintext, err := DecryptWithPassphrase(content, "secret")
+	assert.NoError(t, err)
+	assert.Equal(t, "hehe", string(plaintext))
+
+	_, err = DecryptWithPassphrase(content, "not secret")
+	assert.Error(t, err)
+
+	content.Iterations = PassphraseKDFMaxIterations + 1
+	_, err = DecryptWithPassphrase(content, "secret")
+	assert.Error(t, err)
+
+	content.Iterations = PassphraseKDFIterations
+	content.KDF = "sha256"
+	_, err = DecryptWithPassphrase(content, "secret")
+	assert.Error(t, err)
+}
+
+func TestCertificatePassphrase(t *testing.T) {
+	assert.NotEqual(t, CertificatePassphrase("AB", "C"), CertificatePassphrase("A", "BC"))
+	assert.NotEqual(t, CertificatePassphrase("ABC", ""), CertificatePassphrase("AB", "C"))
+	assert.Equal(t, CertificatePassphrase("AB", "C"), CertificatePassphrase("AB", "C"))
+}
+
 func TestHashPassword(t *testing.T) {
 	password := "abcd1234"
 	hashed, err := HashPassword(password)
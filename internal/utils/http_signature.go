@@ -0,0 +1,168 @@
+package utils
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var ErrHTTPSignatureIsInvalid = errors.New("http message signature is invalid")
+
+const (
+	HTTPSignatureLabel     = "sig"
+	HTTPSignatureAlgorithm = "ed25519"
+)
+
+// httpSignatureNoncePattern matches the nonces of the requests that are signed into the responses.
+var httpSignatureNoncePattern = regexp.MustCompile(`^[A-Za-z0-9_-]{16,128}$`)
+
+// httpResponseSignatureComponents are the components covered by the response signatures, in signature base order.
+var httpResponseSignatureComponents = []string{`"@status"`, `"@path";req`, `"date"`, `"x-request-id"`, `"content-digest"`}
+
+// HTTPResponseSignature holds the covered components and the parameters of a response signed following
+// HTTP Message Signatures (RFC 9421). The path is the path of the request the response answers, and the nonce the
+// one sent along with the request, if any, which ties the response to this request.
+type HTTPResponseSignature struct {
+	Status        int
+	Path          string
+	Date          string
+	RequestID     string
+	ContentDigest string
+	Created       int64
+	KeyID         string
+	Nonce         string
+}
+
+// IsValidHTTPSignatureNonce reports whether the nonce of a request can be signed into its response, i.e. is 16 to 128
+// characters of the URL safe base64 alphabet.
+func IsValidHTTPSignatureNonce(nonce string) bool {
+	return httpSignatureNoncePattern.MatchString(nonce)
+}
+
+// ContentDigest returns the value of the Content-Digest header of the content, as defined by RFC 9530.
+func ContentDigest(content []byte) string {
+	sum := sha256.Sum256(content)
+	return fmt.Sprintf("sha-256=:%s:", base64.StdEncoding.EncodeToString(sum[:]))
+}
+
+// SignatureParams returns the value of the `@signature-params` component, which is also the value of the
+// Signature-Input header member.
+func (s *HTTPResponseSignature) SignatureParams() string {
+	params := fmt.Sprintf(`(%s);created=%d;keyid="%s";alg="%s"`,
+		strings.Join(httpResponseSignatureComponents, " "), s.Created, s.KeyID, HTTPSignatureAlgorithm)
+	if s.Nonce != "" {
+		params += fmt.Sprintf(`;nonce="%s"`, s.Nonce)
+	}
+	return params
+}
+
+// SignatureBase returns the signature base of the response, i.e. the message that is signed.
+func (s *HTTPResponseSignature) SignatureBase() []byte {
+	values := []string{strconv.Itoa(s.Status), s.Path, s.Date, s.RequestID, s.ContentDigest}
+
+	var base strings.Builder
+	for i, component := range httpResponseSignatureComponents {
+		base.WriteString(fmt.Sprintf("%s: %s\n", component, values[i]))
+	}
+	base.WriteString(fmt.Sprintf(`"@signature-params": %s`, s.SignatureParams()))
+	return []byte(base.String())
+}
+
+// Sign signs the response with the Ed25519 signing key.
+// Returns the values of the Signature-Input and Signature headers
+func (s *HTTPResponseSignature) Sign(signingKey string) (string, string, error) {
+	signature, err := SignMessageWithEd25519(signingKey, s.SignatureBase())
+	if err != nil {
+		return "", "", err
+	}
+
+	signatureInput := fmt.Sprintf("%s=%s", HTTPSignatureLabel, s.SignatureParams())
+	return signatureInput, fmt.Sprintf("%s=:%s:", HTTPSignatureLabel, base64.StdEncoding.EncodeToString(signature)), nil
+}
+
+// Verify verifies the raw signature of the response against the Ed25519 verify key.
+func (s *HTTPResponseSignature) Verify(verifyKey string, signature []byte) error {
+	publicKeyBytes, err := base64.StdEncoding.DecodeString(verifyKey)
+	if err != nil {
+		return err
+	}
+
+	decodedPublicKey, err := x509.ParsePKIXPublicKey(publicKeyBytes)
+	if err != nil {
+		return err
+	}
+
+	publicKey, ok := decodedPublicKey.(ed25519.PublicKey)
+	if !ok {
+		return errors.New("decoded key is not of type ed25519.PublicKey")
+	}
+
+	if !ed25519.Verify(publicKey, s.SignatureBase(), signature) {
+		return ErrHTTPSignatureIsInvalid
+	}
+	return nil
+}
+
+// ParseHTTPResponseSignature parses the Signature-Input and Signature headers of a response signed with Sign.
+// Returns the signature parameters (created, keyid, nonce) and the raw signature. Signatures covering other components
+// than the ones signed by Sign are rejected.
+func ParseHTTPResponseSignature(signatureInput string, signature string) (*HTTPResponseSignature, []byte, error) {
+	params, ok := dictionaryMember(signatureInput, HTTPSignatureLabel)
+	if !ok {
+		return nil, nil, fmt.Errorf("%w: missing signature input [%s]", ErrHTTPSignatureIsInvalid, HTTPSignatureLabel)
+	}
+
+	encodedSignature, ok := dictionaryMember(signature, HTTPSignatureLabel)
+	if !ok || len(encodedSignature) < 2 || !strings.HasPrefix(encodedSignature, ":") || !strings.HasSuffix(encodedSignature, ":") {
+		return nil, nil, fmt.Errorf("%w: missing signature [%s]", ErrHTTPSignatureIsInvalid, HTTPSignatureLabel)
+	}
+
+	rawSignature, err := base64.StdEncoding.DecodeString(strings.Trim(encodedSignature, ":"))
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %s", ErrHTTPSignatureIsInvalid, err)
+	}
+
+	s := &HTTPResponseSignature{}
+	_, rest, _ := strings.Cut(params, ")")
+	for _, param := range strings.Split(rest, ";") {
+		name, value, _ := strings.Cut(param, "=")
+		switch name {
+		case "created":
+			s.Created, err = strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return nil, nil, fmt.Errorf("%w: %s", ErrHTTPSignatureIsInvalid, err)
+			}
+		case "keyid":
+			s.KeyID = strings.Trim(value, `"`)
+		case "nonce":
+			s.Nonce = strings.Trim(value, `"`)
+			if !IsValidHTTPSignatureNonce(s.Nonce) {
+				return nil, nil, fmt.Errorf("%w: invalid nonce [%s]", ErrHTTPSignatureIsInvalid, s.Nonce)
+			}
+		}
+	}
+
+	// The parameters are rebuilt from the covered components of this service, so any other set of components is rejected
+	if s.SignatureParams() != params {
+		return nil, nil, fmt.Errorf("%w: unexpected signature parameters [%s]", ErrHTTPSignatureIsInvalid, params)
+	}
+
+	return s, rawSignature, nil
+}
+
+// dictionaryMember returns the value of a member of a structured field dictionary whose values hold no comma.
+func dictionaryMember(dictionary string, name string) (string, bool) {
+	for _, member := range strings.Split(dictionary, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(member), "=")
+		if ok && key == name {
+			return value, true
+		}
+	}
+	return "", false
+}
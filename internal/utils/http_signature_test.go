@@ -0,0 +1,88 @@
+package utils
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestHTTPResponseSignature(t *testing.T) {
+	signingKey, verifyKey, err := NewEd25519KeyPair()
+	assert.NoError(t, err)
+
+	content := []byte(`{"code":"00000","message":"OK"}`)
+	signed := &HTTPResponseSignature{
+		Status:        200,
+		Path:          "/api/v1/tenants/test/licenses/actions/validate",
+		Date:          "Fri, 17 Oct 2025 10:00:00 GMT",
+		RequestID:     "b8c4d7f2-3c1a-4f0e-9a62-2f7d8e1c5b90",
+		ContentDigest: ContentDigest(content),
+		Created:       1760695200,
+		KeyID:         KeyID(verifyKey),
+	}
+	assert.True(t, strings.HasPrefix(signed.ContentDigest, "sha-256=:"))
+
+	signatureInput, signature, err := signed.Sign(signingKey)
+	assert.NoError(t, err)
+	assert.Equal(t, `sig=("@status" "@path";req "date" "x-request-id" "content-digest");created=1760695200;keyid="`+KeyID(verifyKey)+`";alg="ed25519"`, signatureInput)
+
+	parsed, rawSignature, err := ParseHTTPResponseSignature(signatureInput, signature)
+	assert.NoError(t, err)
+	assert.Equal(t, signed.Created, parsed.Created)
+	assert.Equal(t, signed.KeyID, parsed.KeyID)
+
+	parsed.Status, parsed.Path, parsed.Date, parsed.RequestID, parsed.ContentDigest = signed.Status, signed.Path, signed.Date, signed.RequestID, signed.ContentDigest
+	assert.NoError(t, parsed.Verify(verifyKey, rawSignature))
+
+	// Any change of a covered component invalidates the signature
+	parsed.Status = 400
+	assert.ErrorIs(t, parsed.Verify(verifyKey, rawSignature), ErrHTTPSignatureIsInvalid)
+	parsed.Status = signed.Status
+	parsed.ContentDigest = ContentDigest([]byte(`{"code":"00000","message":"OK","data":{"valid":true}}`))
+	assert.ErrorIs(t, parsed.Verify(verifyKey, rawSignature), ErrHTTPSignatureIsInvalid)
+
+	// Signatures covering other components are rejected
+	_, _, err = ParseHTTPResponseSignature(strings.Replace(signatureInput, ` "content-digest"`, "", 1), signature)
+	assert.ErrorIs(t, err, ErrHTTPSignatureIsInvalid)
+
+	_, _, err = ParseHTTPResponseSignature(signatureInput, "")
+	assert.ErrorIs(t, err, ErrHTTPSignatureIsInvalid)
+}
+
+func TestHTTPResponseSignature_Nonce(t *testing.T) {
+	signingKey, verifyKey, err := NewEd25519KeyPair()
+	assert.NoError(t, err)
+
+	signed := &HTTPResponseSignature{
+		Status:        200,
+		Path:          "/api/v1/tenants/test/licenses/actions/validate",
+		Date:          "Fri, 17 Oct 2025 10:00:00 GMT",
+		RequestID:     "b8c4d7f2-3c1a-4f0e-9a62-2f7d8e1c5b90",
+		ContentDigest: ContentDigest([]byte(`{"code":"00000","message":"OK"}`)),
+		Created:       1760695200,
+		KeyID:         KeyID(verifyKey),
+		Nonce:         "Vq3TfXb0nR8kLmZpQ2sWyA",
+	}
+
+	signatureInput, signature, err := signed.Sign(signingKey)
+	assert.NoError(t, err)
+	assert.True(t, strings.HasSuffix(signatureInput, `;alg="ed25519";nonce="Vq3TfXb0nR8kLmZpQ2sWyA"`))
+
+	parsed, rawSignature, err := ParseHTTPResponseSignature(signatureInput, signature)
+	assert.NoError(t, err)
+	assert.Equal(t, signed.Nonce, parsed.Nonce)
+
+	parsed.Status, parsed.Path, parsed.Date, parsed.RequestID, parsed.ContentDigest = signed.Status, signed.Path, signed.Date, signed.RequestID, signed.ContentDigest
+	assert.NoError(t, parsed.Verify(verifyKey, rawSignature))
+
+	// The nonce is covered by the signature
+	parsed.Nonce = "AAAAAAAAAAAAAAAAAAAAAA"
+	assert.ErrorIs(t, parsed.Verify(verifyKey, rawSignature), ErrHTTPSignatureIsInvalid)
+
+	_, _, err = ParseHTTPResponseSignature(strings.Replace(signatureInput, "Vq3TfXb0nR8kLmZpQ2sWyA", `Vq3TfXb0nR8kLmZpQ2sW"A`, 1), signature)
+	assert.ErrorIs(t, err, ErrHTTPSignatureIsInvalid)
+
+	assert.True(t, IsValidHTTPSignatureNonce("Vq3TfXb0nR8kLmZpQ2sWyA"))
+	assert.False(t, IsValidHTTPSignatureNonce("short"))
+	assert.False(t, IsValidHTTPSignatureNonce("Vq3TfXb0nR8kLmZp,Q2sWyA"))
+}
@@ -0,0 +1,171 @@
+package utils
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RateLimitWindow allows up to Limit requests per Duration.
+type RateLimitWindow struct {
+	Name     string
+	Limit    int
+	Duration time.Duration
+}
+
+// RateLimitResult is the state of the rate limiting window closest to being reached, percentage-wise.
+// If the request is rejected, it is the state of the exceeded window that resets last.
+type RateLimitResult struct {
+	Allowed    bool
+	Window     string
+	Count      int
+	Limit      int
+	Remaining  int
+	Reset      time.Time
+	RetryAfter time.Duration
+}
+
+type rateLimitCounter struct {
+	start time.Time
+	count int
+}
+
+// RateLimiter is an in-process fixed window rate limiter. A request is only allowed if it fits in every window.
+type RateLimiter struct {
+	mu        sync.Mutex
+	windows   []RateLimitWindow
+	counters  map[string][]rateLimitCounter
+	maxWindow time.Duration
+	lastSweep time.Time
+}
+
+// ParseRateLimitWindows parses rate limiting windows formatted as `<limit>/<duration>`, e.g. `60/30s` or `500/5m`.
+func ParseRateLimitWindows(windows []string) ([]RateLimitWindow, error) {
+	parsed := make([]RateLimitWindow, 0, len(windows))
+	for _, window := range windows {
+		limit, duration, found := strings.Cut(strings.TrimSpace(window), "/")
+		if !found {
+			return nil, fmt.Errorf("invalid rate limit window [%s]", window)
+		}
+
+		l, err := strconv.Atoi(limit)
+		if err != nil || l <= 0 {
+			return nil, fmt.Errorf("invalid rate limit window limit [%s]", window)
+		}
+
+		d, err := time.ParseDuration(duration)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("invalid rate limit window duration [%s]", window)
+		}
+
+		parsed = append(parsed, RateLimitWindow{
+			Name:     duration,
+			Limit:    l,
+			Duration: d,
+		})
+	}
+	return parsed, nil
+}
+
+func NewRateLimiter(windows []RateLimitWindow) *RateLimiter {
+	var maxWindow time.Duration
+	for _, window := range windows {
+		if window.Duration > maxWindow {
+			maxWindow = window.Duration
+		}
+	}
+
+	return &RateLimiter{
+		windows:   windows,
+		counters:  make(map[string][]rateLimitCounter),
+		maxWindow: maxWindow,
+	}
+}
+
+// Allow records a request for the key at the given time and reports whether it is within every window.
+// Rejected requests are not counted.
+func (l *RateLimiter) Allow(key string, now time.Time) RateLimitResult {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if len(l.windows) == 0 {
+		return RateLimitResult{Allowed: true}
+	}
+
+	l.sweep(now)
+
+	counters, ok := l.counters[key]
+	if !ok {
+		counters = make([]rateLimitCounter, len(l.windows))
+		l.counters[key] = counters
+	}
+
+	for i, window := range l.windows {
+		if counters[i].start.IsZero() || now.Sub(counters[i].start) >= window.Duration {
+			counters[i] = rateLimitCounter{start: now}
+		}
+	}
+
+	// Reject the request if any window is exhausted, reporting the window that resets last
+	exceeded := -1
+	for i, window := range l.windows {
+		if counters[i].count >= window.Limit {
+			if exceeded < 0 || counters[i].start.Add(window.Duration).After(counters[exceeded].start.Add(l.windows[exceeded].Duration)) {
+				exceeded = i
+			}
+		}
+	}
+	if exceeded >= 0 {
+		reset := counters[exceeded].start.Add(l.windows[exceeded].Duration)
+		return RateLimitResult{
+			Allowed:    false,
+			Window:     l.windows[exceeded].Name,
+			Count:      counters[exceeded].count,
+			Limit:      l.windows[exceeded].Limit,
+			Remaining:  0,
+			Reset:      reset,
+			RetryAfter: reset.Sub(now),
+		}
+	}
+
+	// Count the request and report the window closest to being reached
+	closest := 0
+	for i, window := range l.windows {
+		counters[i].count++
+		if float64(counters[i].count)/float64(window.Limit) > float64(counters[closest].count)/float64(l.windows[closest].Limit) {
+			closest = i
+		}
+	}
+
+	return RateLimitResult{
+		Allowed:   true,
+		Window:    l.windows[closest].Name,
+		Count:     counters[closest].count,
+		Limit:     l.windows[closest].Limit,
+		Remaining: l.windows[closest].Limit - counters[closest].count,
+		Reset:     counters[closest].start.Add(l.windows[closest].Duration),
+	}
+}
+
+// sweep drops the counters of keys whose windows have all expired, at most once per longest window.
+func (l *RateLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < l.maxWindow {
+		return
+	}
+	l.lastSweep = now
+
+	for key, counters := range l.counters {
+		expired := true
+		for i, window := range l.windows {
+			if now.Sub(counters[i].start) < window.Duration {
+				expired = false
+				break
+			}
+		}
+		if expired {
+			delete(l.counters, key)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package utils
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestParseRateLimitWindows(t *testing.T) {
+	windows, err := ParseRateLimitWindows([]string{"60/30s", "500/5m"})
+	assert.NoError(t, err)
+	assert.Equal(t, []RateLimitWindow{
+		{Name: "30s", Limit: 60, Duration: 30 * time.Second},
+		{Name: "5m", Limit: 500, Duration: 5 * time.Minute},
+	}, windows)
+
+	_, err = ParseRateLimitWindows([]string{"60"})
+	assert.Error(t, err)
+
+	_, err = ParseRateLimitWindows([]string{"0/30s"})
+	assert.Error(t, err)
+
+	_, err = ParseRateLimitWindows([]string{"60/abc"})
+	assert.Error(t, err)
+}
+
+func TestRateLimiter_Allow(t *testing.T) {
+	limiter := NewRateLimiter([]RateLimitWindow{
+		{Name: "1s", Limit: 2, Duration: time.Second},
+		{Name: "1m", Limit: 3, Duration: time.Minute},
+	})
+	now := time.Now()
+
+	result := limiter.Allow("key", now)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, "1s", result.Window)
+	assert.Equal(t, 1, result.Remaining)
+
+	result = limiter.Allow("key", now)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 0, result.Remaining)
+
+	result = limiter.Allow("key", now)
+	assert.False(t, result.Allowed)
+	assert.Equal(t, "1s", result.Window)
+	assert.Equal(t, time.Second, result.RetryAfter)
+
+	// Other keys are limited separately
+	assert.True(t, limiter.Allow("other", now).Allowed)
+
+	result = limiter.Allow("key", now.Add(time.Second))
+	assert.True(t, result.Allowed)
+	assert.Equal(t, "1m", result.Window)
+	assert.Equal(t, 0, result.Remaining)
+
+	result = limiter.Allow("key", now.Add(2*time.Second))
+	assert.False(t, result.Allowed)
+	assert.Equal(t, "1m", result.Window)
+	assert.Equal(t, now.Add(time.Minute), result.Reset)
+}
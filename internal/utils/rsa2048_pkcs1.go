@@ -10,7 +10,6 @@ import (
 	"encoding/json"
 	"encoding/pem"
 	"errors"
-	"fmt"
 	"strings"
 )
 
@@ -48,43 +47,40 @@ func NewLicenseKeyWithRSA2048PKCS1(signingKey string, data any) (string, error)
 		return "", err
 	}
 
-	// Encode the original data to base64
-	encodedData := base64.StdEncoding.EncodeToString(bData)
+	signature, err := SignMessageWithRSA2048PKCS1(signingKey, bData)
+	if err != nil {
+		return "", err
+	}
 
+	return EncodeLicenseKey(signature, bData), nil
+}
+
+// SignMessageWithRSA2048PKCS1 signs the SHA-512 digest of the message using RSA2048 algorithm with PKCS#1 v1.5 padding
+// and returns the raw signature
+func SignMessageWithRSA2048PKCS1(signingKey string, message []byte) ([]byte, error) {
 	// Sign the data using the private key with SHA-512 hashing
 	hash := sha512.New()
-	hash.Write(bData)
+	hash.Write(message)
 	hashed := hash.Sum(nil)
 
 	// Decode the private key string
 	privateKeyPEM, err := base64.StdEncoding.DecodeString(signingKey)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	block, _ := pem.Decode(privateKeyPEM)
 
 	if block == nil || block.Type != RSAPrivateKeyStr {
-		return "", errors.New("failed to decode PEM block containing private key")
+		return nil, errors.New("failed to decode PEM block containing private key")
 	}
 
 	privateKey, err := x509.ParsePKCS1PrivateKey(block.Bytes)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	signature, err := rsa.SignPKCS1v15(rand.Reader, privateKey, crypto.SHA512, hashed)
-	if err != nil {
-		return "", err
-	}
-
-	// Encode the signature in base64
-	encodedSignature := base64.StdEncoding.EncodeToString(signature)
-
-	// Combine the encoded data and signature to create the license key
-	licenseKey := fmt.Sprintf("%s.%s", encodedSignature, encodedData)
-
-	return licenseKey, nil
+	return rsa.SignPKCS1v15(rand.Reader, privateKey, crypto.SHA512, hashed)
 }
 
 // VerifyLicenseKeyWithRSA2048PKCS1 verifies a license key against the provided public key using Ed25519 algorithm
@@ -0,0 +1,134 @@
+package utils
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha512"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// NewRSAPSSKeyPair generates the private key and the public key pair using RSA algorithm with the given key size
+// Return te signingKey (private key) and verifyKey (public key)
+func NewRSAPSSKeyPair(bits int) (string, string, error) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, bits)
+	if err != nil {
+		return "", "", err
+	}
+
+	// Encode the private key to PEM format (PKCS1)
+	privateKeyPEM := pem.EncodeToMemory(&pem.Block{
+		Type:  RSAPrivateKeyStr,
+		Bytes: x509.MarshalPKCS1PrivateKey(privateKey),
+	})
+
+	// Encode the public key to PEM format (PKCS1)
+	publicKeyPEM := pem.EncodeToMemory(&pem.Block{
+		Type:  RSAPublicKeyStr,
+		Bytes: x509.MarshalPKCS1PublicKey(&privateKey.PublicKey),
+	})
+
+	return base64.StdEncoding.EncodeToString(privateKeyPEM), base64.StdEncoding.EncodeToString(publicKeyPEM), nil
+}
+
+// NewLicenseKeyWithRSAPSS generates new license key using RSA algorithm with PSS padding
+// Returns a license string in format {{signature}}.{{data}}
+func NewLicenseKeyWithRSAPSS(signingKey string, data any, bits int) (string, error) {
+	bData, err := json.Marshal(data)
+	if err != nil {
+		return "", err
+	}
+
+	signature, err := SignMessageWithRSAPSS(signingKey, bData, bits)
+	if err != nil {
+		return "", err
+	}
+
+	return EncodeLicenseKey(signature, bData), nil
+}
+
+// SignMessageWithRSAPSS signs the SHA-512 digest of the message using RSA algorithm with PSS padding
+// and returns the raw signature
+func SignMessageWithRSAPSS(signingKey string, message []byte, bits int) ([]byte, error) {
+	// Decode the private key string
+	privateKeyPEM, err := base64.StdEncoding.DecodeString(signingKey)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(privateKeyPEM)
+
+	if block == nil || block.Type != RSAPrivateKeyStr {
+		return nil, errors.New("failed to decode PEM block containing private key")
+	}
+
+	privateKey, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	if privateKey.N.BitLen() != bits {
+		return nil, fmt.Errorf("private key size is [%d] bits, expected [%d] bits", privateKey.N.BitLen(), bits)
+	}
+
+	// Sign the message using the private key with SHA-512 hashing
+	hashed := sha512.Sum512(message)
+	return rsa.SignPSS(rand.Reader, privateKey, crypto.SHA512, hashed[:], &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash})
+}
+
+// VerifyLicenseKeyWithRSAPSS verifies a license key against the provided public key using RSA algorithm with PSS padding
+func VerifyLicenseKeyWithRSAPSS(verifyKey string, licenseKey string, bits int) (bool, []byte, error) {
+	parts := strings.Split(licenseKey, ".")
+	if len(parts) != 2 {
+		return false, nil, errors.New("invalid license key format")
+	}
+	encodedSignature := parts[0]
+	encodedData := parts[1]
+
+	data, err := base64.StdEncoding.DecodeString(encodedData)
+	if err != nil {
+		return false, nil, err
+	}
+
+	// Decode signature
+	signature, err := base64.StdEncoding.DecodeString(encodedSignature)
+	if err != nil {
+		return false, nil, err
+	}
+
+	// Decode the public key string
+	publicKeyPEM, err := base64.StdEncoding.DecodeString(verifyKey)
+	if err != nil {
+		return false, nil, err
+	}
+
+	block, _ := pem.Decode(publicKeyPEM)
+
+	if block == nil || block.Type != RSAPublicKeyStr {
+		return false, nil, errors.New("failed to decode PEM block containing public key")
+	}
+
+	publicKey, err := x509.ParsePKCS1PublicKey(block.Bytes)
+	if err != nil {
+		return false, nil, err
+	}
+
+	if publicKey.N.BitLen() != bits {
+		return false, nil, fmt.Errorf("public key size is [%d] bits, expected [%d] bits", publicKey.N.BitLen(), bits)
+	}
+
+	// Check sum of data
+	hashed := sha512.Sum512(data)
+	err = rsa.VerifyPSS(publicKey, crypto.SHA512, hashed[:], signature, &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash})
+	if err != nil {
+		return false, nil, err
+	}
+
+	return true, data, nil
+}
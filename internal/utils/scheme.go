@@ -0,0 +1,183 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+const (
+	SigningSchemeED25519      = "ED25519"
+	SigningSchemeRSA2048PKCS1 = "RSA2048PKCS1"
+	SigningSchemeRSA2048PSS   = "RSA2048PSS"
+	SigningSchemeRSA4096PSS   = "RSA4096PSS"
+	SigningSchemeECDSAP256    = "ECDSA_P256"
+)
+
+var ErrSigningSchemeIsNotSupported = errors.New("signing scheme is not supported")
+
+// SigningScheme generates the key pairs of a policy, signs license keys and license files with its private key,
+// and verifies them with its public key.
+type SigningScheme interface {
+	// NewKeyPair returns the signingKey (private key) and verifyKey (public key)
+	NewKeyPair() (string, string, error)
+	// Sign returns a license string in format {{signature}}.{{data}}
+	Sign(signingKey string, data any) (string, error)
+	// SignMessage returns the raw signature of the message
+	SignMessage(signingKey string, message []byte) ([]byte, error)
+	// Verify checks the signature of a license string and returns its data
+	Verify(verifyKey string, licenseKey string) (bool, []byte, error)
+}
+
+// signingSchemes is the registry of the supported signing schemes, indexed by the name stored on the policies.
+var signingSchemes = map[string]SigningScheme{
+	SigningSchemeED25519:      ed25519Scheme{},
+	SigningSchemeRSA2048PKCS1: rsa2048PKCS1Scheme{},
+	SigningSchemeRSA2048PSS:   rsaPSSScheme{bits: 2048},
+	SigningSchemeRSA4096PSS:   rsaPSSScheme{bits: 4096},
+	SigningSchemeECDSAP256:    ecdsaP256Scheme{},
+}
+
+// GetSigningScheme returns the signing scheme registered under the name.
+func GetSigningScheme(name string) (SigningScheme, error) {
+	scheme, ok := signingSchemes[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: [%s]", ErrSigningSchemeIsNotSupported, name)
+	}
+	return scheme, nil
+}
+
+// SigningSchemes returns the names of the registered signing schemes.
+func SigningSchemes() []string {
+	names := make([]string, 0, len(signingSchemes))
+	for name := range signingSchemes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// KeyID returns the identifier (kid) of a verify key, so certificates can reference the key which signed them.
+func KeyID(verifyKey string) string {
+	sum := sha256.Sum256([]byte(verifyKey))
+	return hex.EncodeToString(sum[:8])
+}
+
+// NewKeyPair generates a key pair using the named signing scheme.
+func NewKeyPair(name string) (string, string, error) {
+	scheme, err := GetSigningScheme(name)
+	if err != nil {
+		return "", "", err
+	}
+	return scheme.NewKeyPair()
+}
+
+// NewLicenseKey signs the data using the named signing scheme.
+// Returns a license string in format {{signature}}.{{data}}
+func NewLicenseKey(name string, signingKey string, data any) (string, error) {
+	scheme, err := GetSigningScheme(name)
+	if err != nil {
+		return "", err
+	}
+	return scheme.Sign(signingKey, data)
+}
+
+// SignMessage signs the message using the named signing scheme and returns the raw signature.
+func SignMessage(name string, signingKey string, message []byte) ([]byte, error) {
+	scheme, err := GetSigningScheme(name)
+	if err != nil {
+		return nil, err
+	}
+	return scheme.SignMessage(signingKey, message)
+}
+
+// EncodeLicenseKey combines a raw signature and the signed data into a license string in format {{signature}}.{{data}}
+func EncodeLicenseKey(signature []byte, data []byte) string {
+	return fmt.Sprintf("%s.%s", base64.StdEncoding.EncodeToString(signature), base64.StdEncoding.EncodeToString(data))
+}
+
+// VerifyLicenseKey verifies a license key against the provided public key using the named signing scheme.
+func VerifyLicenseKey(name string, verifyKey string, licenseKey string) (bool, []byte, error) {
+	scheme, err := GetSigningScheme(name)
+	if err != nil {
+		return false, nil, err
+	}
+	return scheme.Verify(verifyKey, licenseKey)
+}
+
+type ed25519Scheme struct{}
+
+func (ed25519Scheme) NewKeyPair() (string, string, error) {
+	return NewEd25519KeyPair()
+}
+
+func (ed25519Scheme) Sign(signingKey string, data any) (string, error) {
+	return NewLicenseKeyWithEd25519(signingKey, data)
+}
+
+func (ed25519Scheme) SignMessage(signingKey string, message []byte) ([]byte, error) {
+	return SignMessageWithEd25519(signingKey, message)
+}
+
+func (ed25519Scheme) Verify(verifyKey string, licenseKey string) (bool, []byte, error) {
+	return VerifyLicenseKeyWithEd25519(verifyKey, licenseKey)
+}
+
+type rsa2048PKCS1Scheme struct{}
+
+func (rsa2048PKCS1Scheme) NewKeyPair() (string, string, error) {
+	return NewRSA2048PKCS1KeyPair()
+}
+
+func (rsa2048PKCS1Scheme) Sign(signingKey string, data any) (string, error) {
+	return NewLicenseKeyWithRSA2048PKCS1(signingKey, data)
+}
+
+func (rsa2048PKCS1Scheme) SignMessage(signingKey string, message []byte) ([]byte, error) {
+	return SignMessageWithRSA2048PKCS1(signingKey, message)
+}
+
+func (rsa2048PKCS1Scheme) Verify(verifyKey string, licenseKey string) (bool, []byte, error) {
+	return VerifyLicenseKeyWithRSA2048PKCS1(verifyKey, licenseKey)
+}
+
+type rsaPSSScheme struct {
+	bits int
+}
+
+func (s rsaPSSScheme) NewKeyPair() (string, string, error) {
+	return NewRSAPSSKeyPair(s.bits)
+}
+
+func (s rsaPSSScheme) Sign(signingKey string, data any) (string, error) {
+	return NewLicenseKeyWithRSAPSS(signingKey, data, s.bits)
+}
+
+func (s rsaPSSScheme) SignMessage(signingKey string, message []byte) ([]byte, error) {
+	return SignMessageWithRSAPSS(signingKey, message, s.bits)
+}
+
+func (s rsaPSSScheme) Verify(verifyKey string, licenseKey string) (bool, []byte, error) {
+	return VerifyLicenseKeyWithRSAPSS(verifyKey, licenseKey, s.bits)
+}
+
+type ecdsaP256Scheme struct{}
+
+func (ecdsaP256Scheme) NewKeyPair() (string, string, error) {
+	return NewECDSAP256KeyPair()
+}
+
+func (ecdsaP256Scheme) Sign(signingKey string, data any) (string, error) {
+	return NewLicenseKeyWithECDSAP256(signingKey, data)
+}
+
+func (ecdsaP256Scheme) SignMessage(signingKey string, message []byte) ([]byte, error) {
+	return SignMessageWithECDSAP256(signingKey, message)
+}
+
+func (ecdsaP256Scheme) Verify(verifyKey string, licenseKey string) (bool, []byte, error) {
+	return VerifyLicenseKeyWithECDSAP256(verifyKey, licenseKey)
+}
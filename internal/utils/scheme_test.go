@@ -0,0 +1,95 @@
+package utils
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestSigningSchemes(t *testing.T) {
+	for _, name := range SigningSchemes() {
+		t.Run(name, func(t *testing.T) {
+			signingKey, verifyKey, err := NewKeyPair(name)
+			assert.NoError(t, err)
+
+			licenseKey, err := NewLicenseKey(name, signingKey, "sart")
+			assert.NoError(t, err)
+
+			valid, data, err := VerifyLicenseKey(name, verifyKey, licenseKey)
+			assert.NoError(t, err)
+			assert.True(t, valid)
+			assert.Equal(t, `"sart"`, string(data))
+
+			_, otherVerifyKey, err := NewKeyPair(name)
+			assert.NoError(t, err)
+
+			valid, _, _ = VerifyLicenseKey(name, otherVerifyKey, licenseKey)
+			assert.False(t, valid)
+		})
+	}
+}
+
+func TestSignMessage(t *testing.T) {
+	for _, name := range SigningSchemes() {
+		t.Run(name, func(t *testing.T) {
+			signingKey, verifyKey, err := NewKeyPair(name)
+			assert.NoError(t, err)
+
+			// A raw signature combined with its message is a valid license key
+			signature, err := SignMessage(name, signingKey, []byte(`"sart"`))
+			assert.NoError(t, err)
+
+			valid, data, err := VerifyLicenseKey(name, verifyKey, EncodeLicenseKey(signature, []byte(`"sart"`)))
+			assert.NoError(t, err)
+			assert.True(t, valid)
+			assert.Equal(t, `"sart"`, string(data))
+
+			_, _, err = VerifySignedKey(verifyKey, EncodeSignedKey(name, signature, []byte(`"sart"`)))
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestSigningSchemes_KeySizeMismatch(t *testing.T) {
+	signingKey, verifyKey, err := NewKeyPair(SigningSchemeRSA2048PSS)
+	assert.NoError(t, err)
+
+	_, err = NewLicenseKey(SigningSchemeRSA4096PSS, signingKey, "sart")
+	assert.Error(t, err)
+
+	licenseKey, err := NewLicenseKey(SigningSchemeRSA2048PSS, signingKey, "sart")
+	assert.NoError(t, err)
+
+	valid, _, err := VerifyLicenseKey(SigningSchemeRSA4096PSS, verifyKey, licenseKey)
+	assert.Error(t, err)
+	assert.False(t, valid)
+}
+
+func TestGetSigningScheme_NotSupported(t *testing.T) {
+	_, err := GetSigningScheme("RSA1024")
+	assert.ErrorIs(t, err, ErrSigningSchemeIsNotSupported)
+}
+
+func TestSignedKey(t *testing.T) {
+	for _, name := range SigningSchemes() {
+		t.Run(name, func(t *testing.T) {
+			signingKey, verifyKey, err := NewKeyPair(name)
+			assert.NoError(t, err)
+
+			key, err := NewSignedKey(name, signingKey, map[string]string{"license_id": "sart"})
+			assert.NoError(t, err)
+			assert.True(t, strings.HasPrefix(key, name+"/"))
+
+			scheme, data, err := VerifySignedKey(verifyKey, key)
+			assert.NoError(t, err)
+			assert.Equal(t, name, scheme)
+			assert.Equal(t, `{"license_id":"sart"}`, string(data))
+
+			_, _, err = VerifySignedKey(verifyKey, strings.Replace(key, ".", ".A", 1))
+			assert.Error(t, err)
+		})
+	}
+
+	_, _, err := VerifySignedKey("", "not a signed key")
+	assert.ErrorIs(t, err, ErrSignedKeyIsInvalid)
+}
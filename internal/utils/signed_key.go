@@ -0,0 +1,86 @@
+package utils
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var ErrSignedKeyIsInvalid = errors.New("signed key format is invalid")
+
+// NewSignedKey signs the payload with the named signing scheme into a compact key that can be verified offline.
+// Returns a key string in format {{scheme}}/{{payload}}.{{signature}}, both parts being unpadded base64url encoded
+func NewSignedKey(name string, signingKey string, payload any) (string, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+
+	signature, err := SignMessage(name, signingKey, data)
+	if err != nil {
+		return "", err
+	}
+
+	return EncodeSignedKey(name, signature, data), nil
+}
+
+// EncodeSignedKey combines a raw signature and the signed data into a key string in format {{scheme}}/{{payload}}.{{signature}}
+func EncodeSignedKey(name string, signature []byte, data []byte) string {
+	return fmt.Sprintf("%s/%s.%s", name, base64.RawURLEncoding.EncodeToString(data), base64.RawURLEncoding.EncodeToString(signature))
+}
+
+// SignedKeyPayload returns the payload of a key generated by NewSignedKey, without verifying its signature.
+func SignedKeyPayload(key string) ([]byte, error) {
+	_, token, ok := strings.Cut(key, "/")
+	if !ok {
+		return nil, ErrSignedKeyIsInvalid
+	}
+
+	encodedData, _, ok := strings.Cut(token, ".")
+	if !ok {
+		return nil, ErrSignedKeyIsInvalid
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(encodedData)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrSignedKeyIsInvalid, err)
+	}
+	return data, nil
+}
+
+// VerifySignedKey verifies a key generated by NewSignedKey against the provided public key.
+// Returns the signing scheme and the signed payload
+func VerifySignedKey(verifyKey string, key string) (string, []byte, error) {
+	name, token, ok := strings.Cut(key, "/")
+	if !ok {
+		return "", nil, ErrSignedKeyIsInvalid
+	}
+
+	encodedData, encodedSignature, ok := strings.Cut(token, ".")
+	if !ok {
+		return "", nil, ErrSignedKeyIsInvalid
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(encodedData)
+	if err != nil {
+		return "", nil, fmt.Errorf("%w: %s", ErrSignedKeyIsInvalid, err)
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(encodedSignature)
+	if err != nil {
+		return "", nil, fmt.Errorf("%w: %s", ErrSignedKeyIsInvalid, err)
+	}
+
+	licenseKey := fmt.Sprintf("%s.%s", base64.StdEncoding.EncodeToString(signature), base64.StdEncoding.EncodeToString(data))
+	valid, data, err := VerifyLicenseKey(name, verifyKey, licenseKey)
+	if err != nil {
+		return name, nil, err
+	}
+	if !valid {
+		return name, nil, errors.New("signed key signature is invalid")
+	}
+
+	return name, data, nil
+}
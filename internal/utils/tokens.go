@@ -2,10 +2,23 @@ package utils
 
 import (
 	"bytes"
+	crand "crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"math/rand"
+	"strings"
 	"time"
 )
 
+// ProductTokenPrefix, LicenseTokenPrefix and RefreshTokenPrefix prefix the product, license and refresh tokens, so
+// they can be told apart from each other and from the other credentials.
+const (
+	ProductTokenPrefix = "prod-"
+	LicenseTokenPrefix = "lic-"
+	RefreshTokenPrefix = "ref-"
+)
+
 func GenerateToken() string {
 	const charset = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789"
 	const segmentLength = 8
@@ -23,3 +36,67 @@ func GenerateToken() string {
 
 	return buffer.String()
 }
+
+// GenerateProductToken generates a product token from 32 bytes of cryptographically secure randomness.
+func GenerateProductToken() (string, error) {
+	return generatePrefixedToken(ProductTokenPrefix)
+}
+
+// GenerateLicenseToken generates a license token from 32 bytes of cryptographically secure randomness.
+func GenerateLicenseToken() (string, error) {
+	return generatePrefixedToken(LicenseTokenPrefix)
+}
+
+// GenerateRefreshToken generates a refresh token from 32 bytes of cryptographically secure randomness.
+func GenerateRefreshToken() (string, error) {
+	return generatePrefixedToken(RefreshTokenPrefix)
+}
+
+func generatePrefixedToken(prefix string) (string, error) {
+	secret := make([]byte, 32)
+	_, err := crand.Read(secret)
+	if err != nil {
+		return "", err
+	}
+	return prefix + base64.RawURLEncoding.EncodeToString(secret), nil
+}
+
+// HashToken returns the hex encoded SHA-256 digest of a token, which is the only form of the token that is stored.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// AccessTokenTTL holds the lifetimes of the access tokens. The lifetime configured for the tenant takes precedence
+// over the lifetime configured for the role of the account, which takes precedence over the default lifetime.
+// Tenant names and roles are matched case-insensitively.
+type AccessTokenTTL struct {
+	Default time.Duration
+	Tenants map[string]time.Duration
+	Roles   map[string]time.Duration
+}
+
+// Lifetime returns the lifetime of the access tokens issued to an account of the tenant with the role.
+func (ttl AccessTokenTTL) Lifetime(tenantName, role string) time.Duration {
+	if lifetime, ok := ttl.Tenants[strings.ToLower(tenantName)]; ok && lifetime > 0 {
+		return lifetime
+	}
+	if lifetime, ok := ttl.Roles[strings.ToLower(role)]; ok && lifetime > 0 {
+		return lifetime
+	}
+	return ttl.Default
+}
+
+// Longest returns the longest lifetime of the access tokens issued to the accounts of the tenant, whatever their role.
+func (ttl AccessTokenTTL) Longest(tenantName string) time.Duration {
+	if lifetime, ok := ttl.Tenants[strings.ToLower(tenantName)]; ok && lifetime > 0 {
+		return lifetime
+	}
+	longest := ttl.Default
+	for _, lifetime := range ttl.Roles {
+		if lifetime > longest {
+			longest = lifetime
+		}
+	}
+	return longest
+}
@@ -2,7 +2,10 @@ package utils
 
 import (
 	"fmt"
+	"github.com/stretchr/testify/assert"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestGenerateKey(t *testing.T) {
@@ -10,3 +13,54 @@ func TestGenerateKey(t *testing.T) {
 		fmt.Println(GenerateToken())
 	}
 }
+
+func TestGenerateProductToken(t *testing.T) {
+	token, err := GenerateProductToken()
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(token, ProductTokenPrefix))
+
+	other, err := GenerateProductToken()
+	assert.NoError(t, err)
+	assert.NotEqual(t, token, other)
+
+	hash := HashToken(token)
+	assert.Len(t, hash, 64)
+	assert.Equal(t, hash, HashToken(token))
+	assert.NotEqual(t, hash, HashToken(other))
+}
+
+func TestGenerateLicenseToken(t *testing.T) {
+	token, err := GenerateLicenseToken()
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(token, LicenseTokenPrefix))
+	assert.False(t, strings.HasPrefix(token, ProductTokenPrefix))
+	assert.Len(t, HashToken(token), 64)
+}
+
+func TestGenerateRefreshToken(t *testing.T) {
+	token, err := GenerateRefreshToken()
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(token, RefreshTokenPrefix))
+
+	other, err := GenerateRefreshToken()
+	assert.NoError(t, err)
+	assert.NotEqual(t, HashToken(token), HashToken(other))
+}
+
+func TestAccessTokenTTL(t *testing.T) {
+	ttl := AccessTokenTTL{
+		Default: time.Hour,
+		Tenants: map[string]time.Duration{"acme": 2 * time.Hour},
+		Roles:   map[string]time.Duration{"superadmin": 15 * time.Minute, "user": 3 * time.Hour},
+	}
+
+	assert.Equal(t, 2*time.Hour, ttl.Lifetime("acme", "user"))
+	assert.Equal(t, 2*time.Hour, ttl.Lifetime("ACME", "admin"))
+	assert.Equal(t, 15*time.Minute, ttl.Lifetime("", "superadmin"))
+	assert.Equal(t, 3*time.Hour, ttl.Lifetime("other", "user"))
+	assert.Equal(t, time.Hour, ttl.Lifetime("other", "admin"))
+
+	assert.Equal(t, 2*time.Hour, ttl.Longest("acme"))
+	assert.Equal(t, 3*time.Hour, ttl.Longest("other"))
+	assert.Equal(t, time.Minute, AccessTokenTTL{Default: time.Minute}.Longest("other"))
+}
@@ -3,13 +3,16 @@ package main
 import (
 	"context"
 	"fmt"
+	"github.com/gin-gonic/gin"
 	"github.com/spf13/viper"
 	"go-license-management/internal/config"
 	"go-license-management/internal/constants"
 	"go-license-management/internal/infrastructure/casbin_adapter"
 	"go-license-management/internal/infrastructure/database/postgres"
+	"go-license-management/internal/infrastructure/envelope"
 	"go-license-management/internal/infrastructure/logging"
 	_ "go-license-management/internal/infrastructure/logging"
+	"go-license-management/internal/infrastructure/signer"
 	"go-license-management/internal/infrastructure/tracer"
 	accountRepo "go-license-management/internal/repositories/v1/accounts"
 	authRepo "go-license-management/internal/repositories/v1/authentications"
@@ -27,6 +30,7 @@ import (
 	policySvc "go-license-management/internal/services/v1/policies/service"
 	productSvc "go-license-management/internal/services/v1/products/service"
 	tenantSvc "go-license-management/internal/services/v1/tenants/service"
+	"go-license-management/internal/utils"
 	"go-license-management/server"
 	"go-license-management/server/api"
 	"go-license-management/server/api/v1"
@@ -37,6 +41,9 @@ import (
 	"time"
 )
 
+// rewrapKeysCommand re-wraps the private keys stored in the database with the current key encryption key and exits.
+const rewrapKeysCommand = "rewrap-keys"
+
 func init() {
 	// init logger
 	logging.NewDefaultLogger()
@@ -53,6 +60,20 @@ func init() {
 	replacer := strings.NewReplacer(".", "__")
 	viper.SetEnvKeyReplacer(replacer)
 
+	// Loading the key encryption key sealing private keys at rest
+	err = newEnvelope()
+	if err != nil {
+		logging.GetInstance().GetLogger().Error(fmt.Sprintf("failed to initialize key encryption key: %v", err))
+		os.Exit(1)
+	}
+
+	// Registering the signer backends holding the policy private keys outside the database
+	err = newSigners()
+	if err != nil {
+		logging.GetInstance().GetLogger().Error(fmt.Sprintf("failed to initialize signer backends: %v", err))
+		os.Exit(1)
+	}
+
 	// Seeding database
 	_, err = postgres.NewPostgresClient(
 		viper.GetString(config.PostgresHost),
@@ -97,6 +118,53 @@ func init() {
 	}
 }
 
+// newEnvelope loads the current and previous key encryption keys. A key encryption key is required in release mode.
+func newEnvelope() error {
+	kek, err := envelope.LoadKEK(viper.GetString(config.CryptoKEK), viper.GetString(config.CryptoKEKFile))
+	if err != nil {
+		return err
+	}
+
+	previousKEK, err := envelope.LoadKEK(viper.GetString(config.CryptoPreviousKEK), viper.GetString(config.CryptoPreviousKEKFile))
+	if err != nil {
+		return err
+	}
+
+	if kek == "" {
+		if viper.GetString(config.ServerMode) == gin.ReleaseMode {
+			return fmt.Errorf("%w: [crypto]kek or [crypto]kek_file is required in release mode", envelope.ErrKEKIsMissing)
+		}
+		logging.GetInstance().GetLogger().Warn("no key encryption key configured, private keys are stored unencrypted")
+	}
+
+	_, err = envelope.NewEnvelope(kek, previousKEK)
+	return err
+}
+
+// newSigners registers the external signer backends which are configured. The database backend is always available.
+func newSigners() error {
+	backends := make(map[string]signer.Backend)
+
+	if module := viper.GetString(config.SignerPKCS11Module); module != "" {
+		backend, err := signer.NewPKCS11Backend(module, viper.GetString(config.SignerPKCS11TokenLabel), viper.GetString(config.SignerPKCS11PIN))
+		if err != nil {
+			return err
+		}
+		backends[constants.PolicySignerBackendPKCS11] = backend
+	}
+
+	if url := viper.GetString(config.SignerRemoteURL); url != "" {
+		timeout := viper.GetInt(config.SignerRemoteTimeout)
+		if timeout <= 0 {
+			timeout = 10
+		}
+		backends[constants.PolicySignerBackendRemote] = signer.NewRemoteBackend(url, viper.GetString(config.SignerRemoteToken), time.Duration(timeout)*time.Second)
+	}
+
+	signer.NewRegistry(backends)
+	return nil
+}
+
 func newDataSource() (*api.DataSource, error) {
 	dataSource := &api.DataSource{}
 
@@ -119,23 +187,57 @@ func newDataSource() (*api.DataSource, error) {
 	return dataSource, nil
 }
 
+// newAccessTokenTTL reads the lifetimes of the access tokens, per tenant and per role, from the config.
+func newAccessTokenTTL() utils.AccessTokenTTL {
+	ttl := utils.AccessTokenTTL{
+		Default: time.Duration(viper.GetInt(config.AccessTokenTTL)) * time.Second,
+		Tenants: make(map[string]time.Duration),
+		Roles:   make(map[string]time.Duration),
+	}
+	if ttl.Default <= 0 {
+		ttl.Default = constants.JWTDuration * time.Second
+	}
+
+	for tenantName := range viper.GetStringMap(config.AccessTokenTenantsTTL) {
+		ttl.Tenants[tenantName] = time.Duration(viper.GetInt(config.AccessTokenTenantsTTL+"."+tenantName)) * time.Second
+	}
+	for role := range viper.GetStringMap(config.AccessTokenRolesTTL) {
+		ttl.Roles[role] = time.Duration(viper.GetInt(config.AccessTokenRolesTTL+"."+role)) * time.Second
+	}
+	return ttl
+}
+
 func NewAppService(ds *api.DataSource) *api.AppService {
 	appSvc := &api.AppService{}
+	accessTokenTTL := newAccessTokenTTL()
 
 	// register v1
 	v1Svc := &v1.V1AppService{}
 
 	// tenant
-	v1Svc.SetTenant(tenantSvc.NewTenantService(tenantSvc.WithRepository(tenantRepo.NewTenantRepository(ds))))
+	v1Svc.SetTenant(tenantSvc.NewTenantService(
+		tenantSvc.WithRepository(tenantRepo.NewTenantRepository(ds)),
+		tenantSvc.WithAccessTokenTTL(accessTokenTTL),
+	))
 
 	// auth
-	v1Svc.SetAuth(authSvc.NewAuthenticationService(authSvc.WithRepository(authRepo.NewAuthenticationRepository(ds))))
+	v1Svc.SetAuth(authSvc.NewAuthenticationService(
+		authSvc.WithRepository(authRepo.NewAuthenticationRepository(ds)),
+		authSvc.WithAccessTokenTTL(accessTokenTTL),
+		authSvc.WithRefreshTokenTTL(time.Duration(viper.GetInt(config.RefreshTokenTTL))*time.Second),
+		authSvc.WithLoginLockout(
+			viper.GetInt(config.LoginMaxFailedAttempts),
+			viper.GetInt(config.LoginMaxFailedAttemptsPerIP),
+			time.Duration(viper.GetInt(config.LoginLockoutDuration))*time.Second,
+		),
+	))
 
 	// account
 	v1Svc.SetAccount(accountSvc.NewAccountService(
 		accountSvc.WithRepository(accountRepo.NewAccountRepository(ds)),
-		accountSvc.WithCasbinAdapter(ds.GetCasbin())),
-	)
+		accountSvc.WithCasbinAdapter(ds.GetCasbin()),
+		accountSvc.WithAccessTokenTTL(accessTokenTTL),
+	))
 
 	// product
 	v1Svc.SetProduct(productSvc.NewProductService(productSvc.WithRepository(productRepo.NewProductRepository(ds))))
@@ -147,7 +249,10 @@ func NewAppService(ds *api.DataSource) *api.AppService {
 	v1Svc.SetEntitlement(entitlementSvc.NewEntitlementService(entitlementSvc.WithRepository(entitlementRepo.NewEntitlementRepository(ds))))
 
 	// licenses
-	v1Svc.SetLicense(licenseSvc.NewLicenseService(licenseSvc.WithRepository(licenseRepo.NewLicenseRepository(ds))))
+	v1Svc.SetLicense(licenseSvc.NewLicenseService(
+		licenseSvc.WithRepository(licenseRepo.NewLicenseRepository(ds)),
+		licenseSvc.WithNonceTTL(time.Duration(viper.GetInt(config.LicenseNonceTTL))*time.Second),
+	))
 
 	// machines
 	v1Svc.SetMachine(machineSvc.NewMachineService(machineSvc.WithRepository(machineRepo.NewMachineRepository(ds))))
@@ -170,7 +275,18 @@ func NewAppService(ds *api.DataSource) *api.AppService {
 // @in header
 // @name Authorization
 func main() {
-	quit := make(chan os.Signal)
+	// Re-wrapping private keys with the current key encryption key, after rotating it
+	if len(os.Args) > 1 && os.Args[1] == rewrapKeysCommand {
+		count, err := postgres.RewrapPrivateKeys(context.Background())
+		if err != nil {
+			logging.GetInstance().GetLogger().Error(fmt.Sprintf("failed to re-wrap private keys: %v", err))
+			os.Exit(1)
+		}
+		logging.GetInstance().GetLogger().Info(fmt.Sprintf("completed re-wrapping [%d] private keys", count))
+		return
+	}
+
+	quit := make(chan os.Signal, 1)
 	serverQuit := make(chan os.Signal)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM, syscall.SIGQUIT, syscall.SIGKILL)
 
@@ -183,7 +299,19 @@ func main() {
 
 	go server.StartServer(appSvc, serverQuit)
 
+	// start background jobs
+	jobCtx, jobCancel := context.WithCancel(context.Background())
+	go appSvc.GetV1Svc().GetLicense().StartExpirationSweeper(
+		jobCtx,
+		time.Duration(viper.GetInt(config.LicenseExpirationSweepInterval))*time.Second,
+	)
+	go appSvc.GetV1Svc().GetMachine().StartHeartbeatMonitor(
+		jobCtx,
+		time.Duration(viper.GetInt(config.MachineHeartbeatMonitorInterval))*time.Second,
+	)
+
 	<-quit
+	jobCancel()
 	serverQuit <- syscall.SIGKILL
 
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
@@ -0,0 +1,138 @@
+// Package httpsig verifies the signatures of the API responses, so clients can detect responses tampered with in
+// transit, e.g. a validation result forged by a local proxy.
+//
+// When response signing is enabled (`[server]enable_response_signing`), the responses of the tenant routes are
+// signed with the Ed25519 key of the tenant following HTTP Message Signatures (RFC 9421). The signature covers the
+// status (`@status`), the request path (`@path;req`), the `Date` and `X-Request-ID` headers and the SHA-256 digest of
+// the content (`Content-Digest`, RFC 9530), and carries the ID of the tenant key that signed it (`keyid`).
+//
+// The `X-Request-ID` is generated by the server, so it does not tie a response to its request. Requests are sent with
+// a random nonce in the `X-Signature-Nonce` header, see NewNonce, which the server signs into the response (`nonce`).
+// A response is only accepted for the request carrying its nonce, and for MaxSignatureAge after it was signed.
+//
+// The digest covers the content as it is sent. Requests must therefore be sent with `Accept-Encoding: identity`,
+// as a response transparently decompressed by the HTTP client cannot be verified.
+package httpsig
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"go-license-management/internal/constants"
+	"go-license-management/internal/utils"
+	"io"
+	"net/http"
+	"time"
+)
+
+var (
+	ErrSignatureMissing     = errors.New("response is not signed")
+	ErrInvalidSignature     = errors.New("response signature is invalid")
+	ErrDigestMismatch       = errors.New("response content digest does not match")
+	ErrUnknownKeyID         = errors.New("response signing key id is unknown")
+	ErrResponseDecompressed = errors.New("response content was decompressed by the http client")
+	ErrNonceMissing         = errors.New("request has no signature nonce")
+	ErrNonceMismatch        = errors.New("response signature nonce does not match the request")
+	ErrSignatureExpired     = errors.New("response signature has expired")
+)
+
+// MaxSignatureAge is the maximum difference between the creation of a response signature and its verification.
+const MaxSignatureAge = 5 * time.Minute
+
+// NewNonce returns a random nonce to send in the X-Signature-Nonce header of a request.
+func NewNonce() (string, error) {
+	nonce := make([]byte, 16)
+	_, err := rand.Read(nonce)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(nonce), nil
+}
+
+// Signature describes a verified response signature.
+type Signature struct {
+	KeyID     string
+	RequestID string
+	Created   time.Time
+}
+
+// VerifyResponse verifies the signature and the content digest of a response against the public keys of the
+// tenant, i.e. its current public key and the public keys of its key ring, and checks that the response answers the
+// request, i.e. is signed with the nonce of the request, no longer than MaxSignatureAge ago. The content of the
+// response is read and restored, so it can still be decoded by the caller.
+func VerifyResponse(resp *http.Response, publicKeys ...string) (*Signature, error) {
+	if resp.Request == nil || resp.Request.URL == nil {
+		return nil, errors.New("response has no request")
+	}
+
+	nonce := resp.Request.Header.Get(constants.XSignatureNonceHeader)
+	if nonce == "" {
+		return nil, ErrNonceMissing
+	}
+
+	signatureInput := resp.Header.Get(constants.SignatureInputHeader)
+	signatureValue := resp.Header.Get(constants.SignatureHeader)
+	if signatureInput == "" || signatureValue == "" {
+		return nil, ErrSignatureMissing
+	}
+
+	if resp.Uncompressed {
+		return nil, ErrResponseDecompressed
+	}
+
+	signature, rawSignature, err := utils.ParseHTTPResponseSignature(signatureInput, signatureValue)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidSignature, err)
+	}
+
+	var verifyKey string
+	for _, publicKey := range publicKeys {
+		if utils.KeyID(publicKey) == signature.KeyID {
+			verifyKey = publicKey
+			break
+		}
+	}
+	if verifyKey == "" {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownKeyID, signature.KeyID)
+	}
+
+	content, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	_ = resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(content))
+
+	signature.Status = resp.StatusCode
+	signature.Path = resp.Request.URL.EscapedPath()
+	signature.Date = resp.Header.Get(constants.DateHeader)
+	signature.RequestID = resp.Header.Get(constants.XRequestIDHeader)
+	signature.ContentDigest = resp.Header.Get(constants.ContentDigestHeader)
+
+	// The digest header is covered by the signature, and the content by the digest
+	err = signature.Verify(verifyKey, rawSignature)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidSignature, err)
+	}
+
+	if signature.Nonce != nonce {
+		return nil, fmt.Errorf("%w: expected [%s], got [%s]", ErrNonceMismatch, nonce, signature.Nonce)
+	}
+
+	created := time.Unix(signature.Created, 0)
+	if age := time.Since(created); age > MaxSignatureAge || age < -MaxSignatureAge {
+		return nil, fmt.Errorf("%w: signed at [%s]", ErrSignatureExpired, created.UTC().Format(time.RFC3339))
+	}
+
+	if signature.ContentDigest != utils.ContentDigest(content) {
+		return nil, ErrDigestMismatch
+	}
+
+	return &Signature{
+		KeyID:     signature.KeyID,
+		RequestID: signature.RequestID,
+		Created:   created,
+	}, nil
+}
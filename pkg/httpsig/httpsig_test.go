@@ -0,0 +1,161 @@
+package httpsig
+
+import (
+	"github.com/stretchr/testify/assert"
+	"go-license-management/internal/constants"
+	"go-license-management/internal/utils"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+const content = `{"code":"00000","message":"OK","data":{"valid":false,"code":"EXPIRED"}}`
+
+// newSignedServer serves responses signed as the response signature middleware does.
+// The tamper function alters the content once it is signed.
+func newSignedServer(t *testing.T, signingKey string, verifyKey string, tamper func(string) string) *httptest.Server {
+	return newReplayingServer(t, signingKey, verifyKey, tamper, func(signature *utils.HTTPResponseSignature) {})
+}
+
+// newReplayingServer serves responses signed as the response signature middleware does, after the replay function
+// has altered the signature, e.g. to replay a response signed for another request.
+func newReplayingServer(t *testing.T, signingKey string, verifyKey string, tamper func(string) string, replay func(*utils.HTTPResponseSignature)) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		now := time.Now().UTC()
+		signature := &utils.HTTPResponseSignature{
+			Status:        http.StatusOK,
+			Path:          r.URL.EscapedPath(),
+			Date:          now.Format(http.TimeFormat),
+			RequestID:     "b8c4d7f2-3c1a-4f0e-9a62-2f7d8e1c5b90",
+			ContentDigest: utils.ContentDigest([]byte(content)),
+			Created:       now.Unix(),
+			KeyID:         utils.KeyID(verifyKey),
+			Nonce:         r.Header.Get(constants.XSignatureNonceHeader),
+		}
+		replay(signature)
+		signatureInput, signatureValue, err := signature.Sign(signingKey)
+		assert.NoError(t, err)
+
+		w.Header().Set(constants.DateHeader, signature.Date)
+		w.Header().Set(constants.XRequestIDHeader, signature.RequestID)
+		w.Header().Set(constants.ContentDigestHeader, signature.ContentDigest)
+		w.Header().Set(constants.SignatureInputHeader, signatureInput)
+		w.Header().Set(constants.SignatureHeader, signatureValue)
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(tamper(content)))
+	}))
+}
+
+func get(t *testing.T, url string) *http.Response {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	assert.NoError(t, err)
+	req.Header.Set("Accept-Encoding", "identity")
+	nonce, err := NewNonce()
+	assert.NoError(t, err)
+	req.Header.Set(constants.XSignatureNonceHeader, nonce)
+
+	resp, err := http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+	return resp
+}
+
+func TestVerifyResponse(t *testing.T) {
+	signingKey, verifyKey, err := utils.NewEd25519KeyPair()
+	assert.NoError(t, err)
+	_, previousVerifyKey, err := utils.NewEd25519KeyPair()
+	assert.NoError(t, err)
+
+	server := newSignedServer(t, signingKey, verifyKey, func(s string) string { return s })
+	defer server.Close()
+
+	resp := get(t, server.URL+"/api/v1/tenants/test/licenses/actions/validate")
+	defer resp.Body.Close()
+
+	signature, err := VerifyResponse(resp, previousVerifyKey, verifyKey)
+	assert.NoError(t, err)
+	assert.Equal(t, utils.KeyID(verifyKey), signature.KeyID)
+	assert.Equal(t, "b8c4d7f2-3c1a-4f0e-9a62-2f7d8e1c5b90", signature.RequestID)
+
+	// The content can still be read once verified
+	body, err := io.ReadAll(resp.Body)
+	assert.NoError(t, err)
+	assert.Equal(t, content, string(body))
+
+	// Responses signed with another key are rejected
+	resp = get(t, server.URL+"/api/v1/tenants/test/licenses/actions/validate")
+	defer resp.Body.Close()
+	_, err = VerifyResponse(resp, previousVerifyKey)
+	assert.ErrorIs(t, err, ErrUnknownKeyID)
+}
+
+func TestVerifyResponse_Tampered(t *testing.T) {
+	signingKey, verifyKey, err := utils.NewEd25519KeyPair()
+	assert.NoError(t, err)
+
+	// A proxy turning a failed validation into a successful one
+	server := newSignedServer(t, signingKey, verifyKey, func(s string) string {
+		return strings.Replace(s, `"valid":false`, `"valid":true`, 1)
+	})
+	defer server.Close()
+
+	resp := get(t, server.URL+"/api/v1/tenants/test/licenses/actions/validate")
+	defer resp.Body.Close()
+	_, err = VerifyResponse(resp, verifyKey)
+	assert.ErrorIs(t, err, ErrDigestMismatch)
+
+	// A status or a header altered in transit invalidates the signature
+	resp = get(t, server.URL+"/api/v1/tenants/test/licenses/actions/validate")
+	defer resp.Body.Close()
+	resp.StatusCode = http.StatusBadRequest
+	_, err = VerifyResponse(resp, verifyKey)
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+
+	resp = get(t, server.URL+"/api/v1/tenants/test/licenses/actions/validate")
+	defer resp.Body.Close()
+	resp.Header.Set(constants.XRequestIDHeader, "replayed")
+	_, err = VerifyResponse(resp, verifyKey)
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+
+	resp = get(t, server.URL+"/api/v1/tenants/test/licenses/actions/validate")
+	defer resp.Body.Close()
+	resp.Header.Del(constants.SignatureHeader)
+	_, err = VerifyResponse(resp, verifyKey)
+	assert.ErrorIs(t, err, ErrSignatureMissing)
+}
+
+func TestVerifyResponse_Replayed(t *testing.T) {
+	signingKey, verifyKey, err := utils.NewEd25519KeyPair()
+	assert.NoError(t, err)
+
+	// A response signed for another request
+	server := newReplayingServer(t, signingKey, verifyKey, func(s string) string { return s }, func(signature *utils.HTTPResponseSignature) {
+		signature.Nonce = "Vq3TfXb0nR8kLmZpQ2sWyA"
+	})
+	defer server.Close()
+
+	resp := get(t, server.URL+"/api/v1/tenants/test/licenses/actions/validate")
+	defer resp.Body.Close()
+	_, err = VerifyResponse(resp, verifyKey)
+	assert.ErrorIs(t, err, ErrNonceMismatch)
+
+	// Requests without nonce cannot be tied to their response
+	resp = get(t, server.URL+"/api/v1/tenants/test/licenses/actions/validate")
+	defer resp.Body.Close()
+	resp.Request.Header.Del(constants.XSignatureNonceHeader)
+	_, err = VerifyResponse(resp, verifyKey)
+	assert.ErrorIs(t, err, ErrNonceMissing)
+
+	// A response signed too long ago
+	server = newReplayingServer(t, signingKey, verifyKey, func(s string) string { return s }, func(signature *utils.HTTPResponseSignature) {
+		signature.Created = time.Now().Add(-MaxSignatureAge - time.Minute).Unix()
+	})
+	defer server.Close()
+
+	resp = get(t, server.URL+"/api/v1/tenants/test/licenses/actions/validate")
+	defer resp.Body.Close()
+	_, err = VerifyResponse(resp, verifyKey)
+	assert.ErrorIs(t, err, ErrSignatureExpired)
+}
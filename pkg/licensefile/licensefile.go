@@ -0,0 +1,175 @@
+// Package licensefile parses and verifies the license files and machine files produced by the license and machine
+// checkout actions, so that shipped applications can be licensed offline and in air-gapped environments.
+//
+// A file is a base64 encoded JSON document holding the signed payload (`enc`), its signature (`sig`) and the signing
+// algorithm (`alg`), wrapped between `-----BEGIN LICENSE FILE-----` / `-----BEGIN MACHINE FILE-----` and the matching
+// `-----END ... FILE-----` lines. Encrypted files instead hold the AES-256-GCM ciphertext of that JSON document (`enc`)
+// along with the encryption algorithm (`alg`) and the key derivation parameters (`kdf`, `salt`, `iterations`).
+// The key is derived with PBKDF2-SHA256 from the license key for license files, and from the license key and the
+// machine fingerprint for machine files, so only the holder of the license can decrypt them.
+//
+// Policies using signed keys issue license keys in format `<scheme>/<payload>.<signature>`, which can be verified
+// with the policy public key as well, see Verifier.VerifyLicenseKey. The key carries the expiry, max machines and
+// entitlements of the license at issuance, so it must be reissued after a renewal or a change of these fields.
+//
+// Files also carry the ID of the key that signed them (`kid`). Once the keys of a policy are rotated, files signed
+// with the previous key are still accepted until it retires, provided the verifier is given the signing keys listed
+// by the policy, see WithPublicKeys. Signed license keys carry the `kid` in their payload and are accepted with
+// retired keys too, since license keys outlive the rotation of the policy keys.
+//
+// License validations requested with a nonce return a response signed in the signed key format, echoing the nonce,
+// see Verifier.VerifyValidationResponse.
+package licensefile
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go-license-management/internal/constants"
+	"go-license-management/internal/utils"
+	"strings"
+)
+
+const (
+	TypeLicense = "LICENSE FILE"
+	TypeMachine = "MACHINE FILE"
+)
+
+// SupportedVersion is the latest file version this package can verify. Fields added within a version are ignored.
+const SupportedVersion = constants.CertificateFileVersion
+
+const (
+	AlgorithmED25519      = constants.PolicySchemeED25519
+	AlgorithmRSA2048PKCS1 = constants.PolicySchemeRSA2048PKCS1
+	AlgorithmRSA2048PSS   = constants.PolicySchemeRSA2048PSS
+	AlgorithmRSA4096PSS   = constants.PolicySchemeRSA4096PSS
+	AlgorithmECDSAP256    = constants.PolicySchemeECDSAP256
+)
+
+var (
+	ErrInvalidFormat         = errors.New("file format is invalid")
+	ErrFileTypeMismatch      = errors.New("file type does not match")
+	ErrUnsupportedVersion    = errors.New("file version is not supported")
+	ErrDecryptionKeyRequired = errors.New("file is encrypted and requires a decryption key")
+	ErrDecryptionFailed      = errors.New("file decryption failed")
+	ErrUnsupportedAlgorithm  = errors.New("file signing or encryption algorithm is not supported")
+	ErrInvalidSignature      = errors.New("file signature is invalid")
+	ErrFileExpired           = errors.New("file is expired")
+	ErrFileNotYetValid       = errors.New("file is not yet valid")
+	ErrKeyExpired            = errors.New("license key is expired")
+	ErrUnknownKeyID          = errors.New("file signing key id is unknown")
+	ErrKeyRetired            = errors.New("file signing key is retired")
+	ErrNonceMismatch         = errors.New("validation response nonce does not match")
+)
+
+// Certificate is the decoded content of a license file or a machine file.
+type Certificate struct {
+	Type      string `json:"-"`
+	Encrypted bool   `json:"-"`
+	Enc       string `json:"enc"`
+	Sig       string `json:"sig"`
+	Alg       string `json:"alg"`
+	Kid       string `json:"kid,omitempty"`
+}
+
+// Parse decodes a license file or a machine file. The passphrase is only required for encrypted files,
+// see LicenseFilePassphrase and MachineFilePassphrase.
+func Parse(file string, passphrase string) (*Certificate, error) {
+	fileType, body, err := unwrap(file)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := base64.StdEncoding.DecodeString(body)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidFormat, err)
+	}
+
+	encrypted := &utils.PassphraseEncryptedContent{}
+	err = json.Unmarshal(content, encrypted)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidFormat, err)
+	}
+
+	// Encrypted files are recognized by their key derivation function, plain files hold the JSON document directly
+	cert := &Certificate{Type: fileType}
+	if encrypted.KDF != "" {
+		if encrypted.Alg != utils.PassphraseEncryptionAlgorithm || encrypted.KDF != utils.PassphraseKDF {
+			return nil, fmt.Errorf("%w: %s/%s", ErrUnsupportedAlgorithm, encrypted.Alg, encrypted.KDF)
+		}
+		if passphrase == "" {
+			return nil, ErrDecryptionKeyRequired
+		}
+
+		content, err = utils.DecryptWithPassphrase(encrypted, passphrase)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrDecryptionFailed, err)
+		}
+		cert.Encrypted = true
+	}
+
+	err = json.Unmarshal(content, cert)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidFormat, err)
+	}
+
+	if cert.Enc == "" || cert.Sig == "" || cert.Alg == "" {
+		return nil, ErrInvalidFormat
+	}
+
+	return cert, nil
+}
+
+// LicenseFilePassphrase returns the passphrase decrypting the license files of the license key.
+func LicenseFilePassphrase(licenseKey string) string {
+	return utils.CertificatePassphrase(licenseKey, "")
+}
+
+// MachineFilePassphrase returns the passphrase decrypting the machine files of the machine fingerprint.
+func MachineFilePassphrase(licenseKey string, fingerprint string) string {
+	return utils.CertificatePassphrase(licenseKey, fingerprint)
+}
+
+// Verify checks the signature of the certificate against the policy public key and returns the signed payload.
+func (c *Certificate) Verify(publicKey string) ([]byte, error) {
+	scheme, err := utils.GetSigningScheme(c.Alg)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedAlgorithm, c.Alg)
+	}
+
+	valid, data, err := scheme.Verify(publicKey, fmt.Sprintf("%s.%s", c.Sig, c.Enc))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidSignature, err)
+	}
+	if !valid {
+		return nil, ErrInvalidSignature
+	}
+
+	return data, nil
+}
+
+// unwrap strips the BEGIN/END lines of the file and returns the file type and its body.
+func unwrap(file string) (string, string, error) {
+	file = strings.TrimSpace(file)
+
+	for fileType, format := range map[string]string{
+		TypeLicense: constants.LicenseFileFormat,
+		TypeMachine: constants.MachineFileFormat,
+	} {
+		header, footer, _ := strings.Cut(format, "%s")
+		header = strings.TrimSpace(header)
+		footer = strings.TrimSpace(footer)
+
+		if strings.HasPrefix(file, header) && strings.HasSuffix(file, footer) && len(file) >= len(header)+len(footer) {
+			body := file[len(header) : len(file)-len(footer)]
+			body = strings.Join(strings.Fields(body), "")
+			if body == "" {
+				return "", "", ErrInvalidFormat
+			}
+			return fileType, body, nil
+		}
+	}
+
+	return "", "", ErrInvalidFormat
+}
@@ -0,0 +1,443 @@
+package licensefile
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"go-license-management/internal/constants"
+	"go-license-management/internal/services/v1/licenses/models"
+	"go-license-management/internal/utils"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Test vectors signed with fixed policy key pairs. The files were issued at 2025-01-01T00:00:00Z,
+// the license files expire after 2629746 seconds and the machine file expires after 3600 seconds.
+// The encrypted files are encrypted with the license key below, and the machine fingerprint for the machine file.
+// The last license file is signed with a version this package does not support yet.
+const (
+	testLicenseKey              = "TEST-LICENSE-KEY"
+	ed25519PublicKey            = "MCowBQYDK2VwAyEAVX8OouDBrXTMjccx3NyN1O5GLyHxCtoEWNX3RAwr0j8="
+	rsa2048PKCS1PublicKey       = "LS0tLS1CRUdJTiBSU0EgUFVCTElDIEtFWS0tLS0tCk1JSUJDZ0tDQVFFQXMrQ2dmcTFaWGcyN0JsWk5kQXBzb0JXZTBKUW5BK2FJVFkvODJQeERsL2dNdDdMaEllVVgKVTZQeURKaFJ5aFF6T25HZUh6MUdUOExoVENRRXc2SmhXVTRwSHlVYWs5RWhKY0xGVm1lQzVSMi9wK3QxbTlWNgpmbXVmWGhXUHBseFl1WHdCbEZxZ3oybEVoSnBieFV5eEg5RE1XQjhEVm1DTGJBRlRpaHBLazgvd0U2d3ZkTXJFCnRMVWZCdktLTUxNWGpIVEc3Z3pmNUJwQUV5MlJZeFJLdlI2aWZqdFhiM3RtamhxL2g4ditTZHNlRmlzN0VKbXkKZjhmcjZ3S3RVelZ4QXg3b2pxNHVOWG5CMVlKMG5seEpsT2h4UDk2dDlqSTAwWDJJV3ZRQUxOSytOT3pBa3cvQgpqSjVnQ3A0Y1o1eVpWM0V4dDJXZG5KUXVhZC9WZUlIQmVRSURBUUFCCi0tLS0tRU5EIFJTQSBQVUJMSUMgS0VZLS0tLS0K"
+	ed25519LicenseFile          = "-----BEGIN LICENSE FILE-----\neyJhbGciOiJFRDI1NTE5IiwiZW5jIjoiZXlKdFpYUmhJanA3SW5abGNuTnBiMjRpT2pFc0luUmxibUZ1ZENJNkluUmxjM1FpTENKc2FXTmxibk5sWDJsa0lqb2lNR0l6WkRKaE5HTXRNV1kzWlMwMFlUVXhMVGxtTUdVdE0yTXlZVGxrTldVNFlqY3hJaXdpYVhOemRXVmtJam9pTWpBeU5TMHdNUzB3TVZRd01Eb3dNRG93TUZvaUxDSmxlSEJwY25raU9pSXlNREkxTFRBeExUTXhWREV3T2pJNU9qQTJXaUlzSW5SMGJDSTZNall5T1RjME5uMHNJbVJoZEdFaU9uc2liR2xqWlc1elpWOXBaQ0k2SWpCaU0yUXlZVFJqTFRGbU4yVXROR0UxTVMwNVpqQmxMVE5qTW1FNVpEVmxPR0kzTVNJc0luQnliMlIxWTNSZmFXUWlPaUlpTENKd2IyeHBZM2xmYVdRaU9pSWlMQ0p1WVcxbElqb2lkR1Z6ZENJc0lteHBZMlZ1YzJWZmEyVjVJam9pSWl3aWJXUTFYMk5vWldOcmMzVnRJam9pSWl3aWMyaGhNVjlqYUdWamEzTjFiU0k2SWlJc0luTm9ZVEkxTmw5amFHVmphM04xYlNJNklpSXNJbk4wWVhSMWN5STZJbUZqZEdsMlpTSXNJblZ6WlhKeklqb3dMQ0psYm5ScGRHeGxiV1Z1ZEhNaU9sc2lSa1ZCVkZWU1JWOUJJaXdpUmtWQlZGVlNSVjlDSWwwc0ltMWxkR0ZrWVhSaElqcHVkV3hzTENKbGVIQnBjbmtpT2lJd01EQXhMVEF4TFRBeFZEQXdPakF3T2pBd1dpSXNJbU55WldGMFpXUmZZWFFpT2lJd01EQXhMVEF4TFRBeFZEQXdPakF3T2pBd1dpSXNJblZ3WkdGMFpXUmZZWFFpT2lJd01EQXhMVEF4TFRBeFZEQXdPakF3T2pBd1dpSXNJbXhwWTJWdWMyVmZjRzlzYVdONUlqcDdJbkJ2YkdsamVWOXdkV0pzYVdOZmEyVjVJam9pSWl3aWNHOXNhV041WDNOamFHVnRaU0k2SWlJc0ltVjRjR2x5WVhScGIyNWZjM1J5WVhSbFoza2lPaUlpTENKamFHVmphMTlwYmw5cGJuUmxjblpoYkNJNklpSXNJbTkyWlhKaFoyVmZjM1J5WVhSbFoza2lPaUlpTENKb1pXRnlkR0psWVhSZlltRnphWE1pT2lJaUxDSnlaVzVsZDJGc1gySmhjMmx6SWpvaUlpd2ljbVZ4ZFdseVpWOWphR1ZqYTE5cGJpSTZabUZzYzJVc0ltTnZibU4xY25KbGJuUWlPbVpoYkhObExDSnlaWEYxYVhKbFgyaGxZWEowWW1WaGRDSTZabUZzYzJVc0luTjBjbWxqZENJNlptRnNjMlVzSW1ac2IyRjBhVzVuSWpwbVlXeHpaU3dpZFhObFgzQnZiMndpT21aaGJITmxMQ0p5WVhSbFgyeHBiV2wwWldRaU9tWmhiSE5sTENKbGJtTnllWEIwWldRaU9tWmhiSE5sTENKd2NtOTBaV04wWldRaU9tWmhiSE5sTENKa2RYSmhkR2x2YmlJNk1Dd2liV0Y0WDIxaFkyaHBibVZ6SWpvd0xDSnRZWGhmZFhObGN5STZNQ3dpYldGNFgzVnpaWEp6SWpvd0xDSm9aV0Z5ZEdKbFlYUmZaSFZ5WVhScGIyNGlPakI5TENKc2FXTmxibk5sWDNCeWIyUjFZM1FpT25zaWJtRnRaU0k2SWlJc0ltUnBjM1J5YVdKMWRHbHZibDl6ZEhKaGRHVm5lU0k2SWlJc0ltTnZaR1VpT2lJaUxDSjFjbXdpT2lJaUxDSndiR0YwWm05eWJTSTZiblZzYkN3aWJXVjBZV1JoZEdFaU9tNTFiR3dzSW1OeVpXRjBaV1JmWVhRaU9pSXdNREF4TFRBeExUQXhWREF3T2pBd09qQXdXaUlzSW5Wd1pHRjBaV1JmWVhRaU9pSXdNREF4TFRBeExUQXhWREF3T2pBd09qQXdXaUo5ZlgwPSIsInNpZyI6ImkvQkw0TlVvWkVHS3ViQ0UrVjVSdWlwUHozamF3LzlsQU1MVDVlRkc4U1BJTFh2dmptbmp2QzRxb3JOZ1hYSWxNMkJDeHBobFlGNjBmQUUvVjhZN0N3PT0ifQ==\n-----END LICENSE FILE-----"
+	rsa2048PKCS1LicenseFile     = "-----BEGIN LICENSE FILE-----\neyJhbGciOiJSU0EyMDQ4UEtDUzEiLCJlbmMiOiJleUp0WlhSaElqcDdJblpsY25OcGIyNGlPakVzSW5SbGJtRnVkQ0k2SW5SbGMzUWlMQ0pzYVdObGJuTmxYMmxrSWpvaU1HSXpaREpoTkdNdE1XWTNaUzAwWVRVeExUbG1NR1V0TTJNeVlUbGtOV1U0WWpjeElpd2lhWE56ZFdWa0lqb2lNakF5TlMwd01TMHdNVlF3TURvd01Eb3dNRm9pTENKbGVIQnBjbmtpT2lJeU1ESTFMVEF4TFRNeFZERXdPakk1T2pBMldpSXNJblIwYkNJNk1qWXlPVGMwTm4wc0ltUmhkR0VpT25zaWJHbGpaVzV6WlY5cFpDSTZJakJpTTJReVlUUmpMVEZtTjJVdE5HRTFNUzA1WmpCbExUTmpNbUU1WkRWbE9HSTNNU0lzSW5CeWIyUjFZM1JmYVdRaU9pSWlMQ0p3YjJ4cFkzbGZhV1FpT2lJaUxDSnVZVzFsSWpvaWRHVnpkQ0lzSW14cFkyVnVjMlZmYTJWNUlqb2lJaXdpYldRMVgyTm9aV05yYzNWdElqb2lJaXdpYzJoaE1WOWphR1ZqYTNOMWJTSTZJaUlzSW5Ob1lUSTFObDlqYUdWamEzTjFiU0k2SWlJc0luTjBZWFIxY3lJNkltRmpkR2wyWlNJc0luVnpaWEp6SWpvd0xDSmxiblJwZEd4bGJXVnVkSE1pT2xzaVJrVkJWRlZTUlY5Qklpd2lSa1ZCVkZWU1JWOUNJbDBzSW0xbGRHRmtZWFJoSWpwdWRXeHNMQ0psZUhCcGNua2lPaUl3TURBeExUQXhMVEF4VkRBd09qQXdPakF3V2lJc0ltTnlaV0YwWldSZllYUWlPaUl3TURBeExUQXhMVEF4VkRBd09qQXdPakF3V2lJc0luVndaR0YwWldSZllYUWlPaUl3TURBeExUQXhMVEF4VkRBd09qQXdPakF3V2lJc0lteHBZMlZ1YzJWZmNHOXNhV041SWpwN0luQnZiR2xqZVY5d2RXSnNhV05mYTJWNUlqb2lJaXdpY0c5c2FXTjVYM05qYUdWdFpTSTZJaUlzSW1WNGNHbHlZWFJwYjI1ZmMzUnlZWFJsWjNraU9pSWlMQ0pqYUdWamExOXBibDlwYm5SbGNuWmhiQ0k2SWlJc0ltOTJaWEpoWjJWZmMzUnlZWFJsWjNraU9pSWlMQ0pvWldGeWRHSmxZWFJmWW1GemFYTWlPaUlpTENKeVpXNWxkMkZzWDJKaGMybHpJam9pSWl3aWNtVnhkV2x5WlY5amFHVmphMTlwYmlJNlptRnNjMlVzSW1OdmJtTjFjbkpsYm5RaU9tWmhiSE5sTENKeVpYRjFhWEpsWDJobFlYSjBZbVZoZENJNlptRnNjMlVzSW5OMGNtbGpkQ0k2Wm1Gc2MyVXNJbVpzYjJGMGFXNW5JanBtWVd4elpTd2lkWE5sWDNCdmIyd2lPbVpoYkhObExDSnlZWFJsWDJ4cGJXbDBaV1FpT21aaGJITmxMQ0psYm1OeWVYQjBaV1FpT21aaGJITmxMQ0p3Y205MFpXTjBaV1FpT21aaGJITmxMQ0prZFhKaGRHbHZiaUk2TUN3aWJXRjRYMjFoWTJocGJtVnpJam93TENKdFlYaGZkWE5sY3lJNk1Dd2liV0Y0WDNWelpYSnpJam93TENKb1pXRnlkR0psWVhSZlpIVnlZWFJwYjI0aU9qQjlMQ0pzYVdObGJuTmxYM0J5YjJSMVkzUWlPbnNpYm1GdFpTSTZJaUlzSW1ScGMzUnlhV0oxZEdsdmJsOXpkSEpoZEdWbmVTSTZJaUlzSW1OdlpHVWlPaUlpTENKMWNtd2lPaUlpTENKd2JHRjBabTl5YlNJNmJuVnNiQ3dpYldWMFlXUmhkR0VpT201MWJHd3NJbU55WldGMFpXUmZZWFFpT2lJd01EQXhMVEF4TFRBeFZEQXdPakF3T2pBd1dpSXNJblZ3WkdGMFpXUmZZWFFpT2lJd01EQXhMVEF4TFRBeFZEQXdPakF3T2pBd1dpSjlmWDA9Iiwic2lnIjoiTkRmakxySVkyUkNjT3J0YlJacGtVbyt0dXRNNmpOYmk0R3M3eGRMZFd3bytQUnRUYmxndEMvY1ZzRjRaVHZPZitySUhTUEhXSDByMFo3UDNqZVp0N2ZJbVFYZHMyc202NG1TSjlZeGVVY2JpQWkzVk5PT1lpbzBjUjEzakNZYjBVajlBZWxla21oT3hwbHliZUIxaU5RM1ExVGM2T1hNUnBuNE16eEVHNTN6blRsaSt2NWwzTUlYL1lwdXZpSGF6eWQzK1czcnVKNUlIUDV6YmdQUlJkZUNYUjRqMlNFQjB2ZDVhMDkyNUtjd1FrQ05RcDRPeFo2Z2prNmZPRExmYXZWTWxFLy90SGJ1WC9WS3o0TjdZRlhTb3lSM1FQMnN2RGdZWVdxWjArRHlPS2Jqd0xHVloraWIwUSsxcW81WUd4aDE4eFZkL2p3NmZKMThFNDJMS2VnPT0ifQ==\n-----END LICENSE FILE-----"
+	ed25519EncryptedLicenseFile = "-----BEGIN LICENSE FILE-----\neyJlbmMiOiIvMituaHlnTG9aMmlpZG9FMGdaSnQ3dXMvMEFpNDdiTXpHeXgwVGVYMHFQZittT3RxcGs5SjFRWDNWMld5R3ZRU1ZEWUEyc2NaUlE3aXQ2L05sblRLaHJtUWhsTXI4V2RJTmRMekY1V2UvT1hFMEJIKzJwRXcvM0xzaXU1MzhzZjkxdzFGSDhDRW4wZExsd24vR3pUeEZlYWJKMTZYOVRTTTRqc1NBNmd0YjRXOHFNYjIxNVNPZnBodVhZTW9zL25TZGRyUWZKaW90b3l5eGdjZ2x6Kzk1d2xWU1hEcDVjcituTk82emhtV053Z2gweEwxRDUrS284L3Jua3JXa0wxNnQ2NWQva29DNEFIcWxHL254VkpkWWZIVmNJQUwvMXVGaHkvcjZaTmc3clFnS3VZVng5bkpENE42Q2h0SDBIR2Uza3ZDQW5Sc1czcGZFNVo4ckJRRmVCVE1GLzRWVnRIby8vY1VHNkNSM2p2YzZLTVdEeWdleVduNklPeEFyM05BdEVQMS9GVlBJK3NLRkxxZTB5aXVNT1RBS1ZzczY0RmJtazJLR25COW9tWS9HMHpIZnVlblJoekREUHdjNDZvUDVNRCtQRC9JRTA4MWxWRHhHaXVNOGxFTVcwUVRsZ0FRN3E0VkxEL2ZwSW9IUkxnVmZBWHlQUXBVcGQrOG1aZUd3c2MwdjVCcXhmdmx4RS81NXVDS1hPdWZ1MExtbUtqRWFUM2FnRkJOa052ZUNtbzgxNzkxMHRHcFN5eStuSDhlS1R3eVFpRjV5ZEhZY04vcFFqbkVyR3pSQmVzWWp5ckJRdXZsMnNYT1RoS1dGM3k2QlVGTHh4NjY4UjFyYkNSckd5ZzhRQ3dNdVBmWGljNHpqTmkvT0ZwYjh0RDJFTkZJME1VSmFpUHVaK3p0WEVFbndSdXY3K2NwdUNpSlhjRFNzZW5JOW83Q1l0a3Y4aEtYYXRkSHkwTW5YZnp0TFZrMzZWZjJoenZNU0xuWHV1YnR2dCt0VjR0RHA0WnA3VUxqMXhYY0tOK0JFdEdnTG01Zk5vSnc0amF2NUdSSEpuTnVhMm1BV0g2dWF2S055RE43bDFFVXpuMzg0eGpjRFV3WnFmMXp0cFhSakpkNzFVS2FsbTZqb2VlUFdPVVdwZlNwVThEdDlyVGQzMkFZTkRTdG9ncVhuMytWekt5RFlLL1RETDFaN1EzRCtOd2pzbG1Gd1p4d21VTVhjUm1uaFp2NHBFYllvT0lJRTEwdENTSXhiWFlCVnNYbU5iTVljcGg4V0lPbytoMDliVE53QkxxdW40N3NWcDdqbURjcnhRQ1NIWk5XcmtPR3gvOE9IQ3A5MTh2MmMrWXdIVHBQZXFWRjAra25NWVVGSmdiY2VOeVFvS0EwVTBOOXBJT0lCWnNaaGV0dG5PbTJHczRQRy9RL3oremlUVjc0ejd0UVJjVkt0NVNKN2VoM2VlL210L0M1QndJbDNnS0JDL3E4SjdjQjNIdFJIbGJJUDdyb1Y3aUc3cER2OFZTMFA0SitCeGI1WldZSndhL2hBTnFoMGtERHlYSGhZSUhNZzgxZGVocEtkd2lyTVEwcCtzbjJ0RGNpTlhhR3FEb0djQ1MySGUyUnFlVHRFMW54aDRpQ1BxUjI3enh0Tzgyd2dST2l5ZmVoYk5sTm5SQlpUZGg1ZmRvL3JOM3J4L1FEa2lEaFpHL0E3SDB2Vll4czM4RFlmYkNuUjhaMEZ6cVlrWitwaUVyTENVaXJveVphVTErNkQ4WmJYemZSNFQ4Q1ZsNVFrNXdrWlNCdFNIejVLNDMrbVdWbkNCR1k3NmYwR3RMTmFoUzZqUEM4TFZUWHhyVkxPM0hrRXV3T3hkWTFGQ3lDUTgzY2hEdWF4TDNsTjhUMU1xTGNRS09yUVJ6SzVIUEdIQTlDQndJbUZKY08zSXU1Z25EQjZKSVRyd1g4WXBJTjN0d2UySnN2OFA3WTVRRGg0VE8rWmt3ekF4RmRiN0N0SlpabFV4eExOZURITkdPSTVFNHlpbW02azZwRzU1Y0pFNFlaRGJGZjdGOEthWkNMUkZ5bVlVTkJIdmZ4RjUxVTFHRUFRL3ArUmx2MUo0a2hVMFBlM1VLMExaaU5oWVAxSitsRFdlTkZqRWpPTVFCVjhhbzRCUysxajRRdFBTZDdRWFBybHdsRlhUWjlFNU92ZDdWTytFNjB6SGV3ZFJMZ1l2OTJXVVVHc2FlVWRJNHU0Y1RRc08ySjNvb0gwL3dmVmRraWRqRjFjM3h4RTFTQk5zK1J0M3FybVRnYU5CT0ppS0RPbmpkZmlaanY3NUlIYlBuYm16UE1RUWdpd2d4U0c4NldhVDFTWUpGTlU5Y2VpZ1FtWGxnRjVsS00xUUcxOFhZYm1WVDJrMzdkclhQcXpLU0hBNTV3MkF3bUpNZWpQZXI1V0tkUVl6ck8zcWdGR3czMmRvUjRnZk56RnRRMlNWN1dGUDV3TGZCSDdCYlV0NVF4TUpGZUN3ZUxBTGh5OURrR1hWcCtsQlpubFZydWsvSGdRREM4VmJtSDRZdlNwYlhpcXpPMU1ETEx3ZWs0dS8zNzhlQ3g1U3RxS0hjNXBldU9ENlA5aE1QQ0NoRjE3amloRUEvS1hxcm9XTFQwY1Q4N0pxWXJMT1RHVUFDN281WUVnU21ENlROMVlUcVFiLzNOcFlXc28zZmg0RTdNWnkwRGZjTUV6dU5nc1Q5Mjd5SUxsdUVXWFZHMlJwOFUwTG5TWmZwWU85dUtxU0NUeHI0d2lGanhTcU4wSS92bGVaOTdSQUZkbFV6enVhc0UxUE1iNUlIdEgxYjVhT3dnaU1qNmdZQWdwbTdnZi9nd1RtdkRyeStQZ0tQcTlGcDFITE1YMDQra0RsYzlSS1I3bFlRTE5paHBpK0MzcUpCZHgvMlB3VjNDdm9oblQwZm5RVHBtOHRpY3JtR0RTMFlHQ1FzRDdYVlV1R0JZV25IcVFVPSIsImFsZyI6ImFlcy0yNTYtZ2NtIiwia2RmIjoicGJrZGYyLXNoYTI1NiIsInNhbHQiOiJYMGxaM3JITFpBTVlqdVVqTUUxRzhRPT0iLCJpdGVyYXRpb25zIjoxMDAwMDB9\n-----END LICENSE FILE-----"
+	ed25519MachineFile          = "-----BEGIN MACHINE FILE-----\neyJhbGciOiJFRDI1NTE5IiwiZW5jIjoiZXlKdFpYUmhJanA3SW5abGNuTnBiMjRpT2pFc0luUmxibUZ1ZENJNkluUmxjM1FpTENKc2FXTmxibk5sWDJsa0lqb2lNR0l6WkRKaE5HTXRNV1kzWlMwMFlUVXhMVGxtTUdVdE0yTXlZVGxrTldVNFlqY3hJaXdpYldGamFHbHVaVjlwWkNJNklqWm1NV001WlRKaUxUaGtOR0V0TkdNelpTMWlOV1kzTFRKaE9XVXhaREJqTjJJMk15SXNJbWx6YzNWbFpDSTZJakl3TWpVdE1ERXRNREZVTURBNk1EQTZNREJhSWl3aVpYaHdhWEo1SWpvaU1qQXlOUzB3TVMwd01WUXdNVG93TURvd01Gb2lMQ0owZEd3aU9qTTJNREI5TENKa1lYUmhJanA3SW1sa0lqb2lObVl4WXpsbE1tSXRPR1EwWVMwMFl6TmxMV0kxWmpjdE1tRTVaVEZrTUdNM1lqWXpJaXdpYkdsalpXNXpaVjlyWlhraU9pSWlMQ0owWlc1aGJuUmZibUZ0WlNJNkluUmxjM1FpTENKbWFXNW5aWEp3Y21sdWRDSTZJbVpwYm1kbGNuQnlhVzUwSWl3aWFYQWlPaUlpTENKb2IzTjBibUZ0WlNJNklpSXNJbkJzWVhSbWIzSnRJam9pSWl3aWJtRnRaU0k2SWlJc0ltMWxkR0ZrWVhSaElqcHVkV3hzTENKamIzSmxjeUk2TUN3aWFHVmhjblJpWldGMFgzTjBZWFIxY3lJNklpSXNJbXhoYzNSZmFHVmhjblJpWldGMFgyRjBJam9pTURBd01TMHdNUzB3TVZRd01Eb3dNRG93TUZvaUxDSnNZWE4wWDJOb1pXTnJYMjkxZEY5aGRDSTZJakF3TURFdE1ERXRNREZVTURBNk1EQTZNREJhSWl3aVkzSmxZWFJsWkY5aGRDSTZJakF3TURFdE1ERXRNREZVTURBNk1EQTZNREJhSWl3aWRYQmtZWFJsWkY5aGRDSTZJakF3TURFdE1ERXRNREZVTURBNk1EQTZNREJhSW4xOSIsInNpZyI6IlpJK01HSkR0ZHNPbEMydExyQVVZSDRhREw0bXpFZ280a0FxeUk4ZDVDN3RmVG5oN3Z0cC8rQitBSk1Bb0tITUJCVzAyY0JXZEhmVWlsa2pDTmxtNUF3PT0ifQ==\n-----END MACHINE FILE-----"
+	ed25519EncryptedMachineFile = "-----BEGIN MACHINE FILE-----\neyJlbmMiOiIxNXFFZnVEM3RLN1VHbmdvWmorWmkwRGswN2pIUE5SVnNrMUJidTRxUEp0MHpJWm1hU1lmM3dXaW1Pb1JDelpmUVN1R0U2UGpHREhoMXVIVDUwN3ZNRGJWMkJSMHVIYWlzVHMycW1KWld1Y2h5NU9VbUcxZnN3ZUduUzV6eTJ3bzBROWR6UE9IVGgySElsRmlCZkhNSkM1b3U0OTJiMEFpdnhzc2FyREZaZmd3RmVnUjI5TFNxYkhnVlV5TnV0QSt0cjh2VVYwQW9hUHp0VjJHQkJaN3hUdm1sZWREcEJLN3lMSTg5V0wzM1JkbHd5SkFaVTcyTHBKK0FrQXZNcmlta0Q1VGlpYml0Vm1JMHhBWkJsZkZlZm0vVThXVGo2Y1ZiY1VvTzgzSk5saUF1ZmZ4emIrL2svSlREdm1FQW14c3cvUGlxSTVOWjZPSHRoN0ZyYVhSVjhUMzZxN2NWQlNWQmVFaUhUMk9WZmtBeVE0TmJ6VE9tNGpVOVJ3M1pTS3BJVGJ3RS81L1lVZGwwWlZkQUJQNUpJSXBmL2U4UXJENUJXTzFSZWhGSDVxKzhBU3JFeEJoUVZNaWNrVEd5eUxFTFVLVjhrdzlobFBjcHljVWp4Q2hWVGtUSHVuamFSN09zQ3V6a3Fueks1ZFNKS25QYzFrOFZsa2pMRzVjbkNEd0w3TEswMlJqb0laZlFmNXdkQ3pETlJnWXZaTnBpdVZNUGVtU2J5by9MMjl3U3RVNmFLbXdscStSVitNekxkZzJMU2dRY3pXd1JTUytKTG5lZDc2OUo4ZUNRck1IVGZ1TzUySGVrVU1hRWlNVE11ai8rdDJ6cE5hUW5KdDkzdjJ6ek5ydzk4SFgyMmJwL1llRFdvdXM0TW56M0tjU2xKcTRZcFcyVlIyeTAxanZROVR4djVmT0pHY1NJaEdrNFJRVDArQ3hJOW1JTWllZmNXWVpyQ1RRQmxsQklYUG5LZlZHbDdDejRqNzBHcjNCcDB2Umt5OXgvR0VLWk5GV01rblFNbHU0M2xucG1USTNodFZMVTd5MjQ5R21RQkVHdTRFNzRHZ3crWXd1aEVIUFNKWTNlVFhnMzFscFZLSWh6b2twNzhyQ1dPbTlBU0FwcmczZW4zYXFHM3ZlTnI3SmpBMS9yUVZ1b0NRVXBnZUE0ZExWODY4OUg2bkZ1Q2RVUSs5TTFjakp4WmxsaG1KSGROSmJpWjZxY2gvWkJFWllVYXljYTJJZEhWRGJ6eVl0RTVYT2EyUkFqS2hxUy8xQ0pyVUpFaWppWTdrN1NMeTV6V2dpbXhHeW1XZlNkcjNMTngwUy9xMW8xbzcrMGRuRDZqTmVmNTlpV1IzNmpDelB6a09DanFtKzc4cGhYbXVDVWJuTmV5emdveWUxSEJCN04rV3A2T2lkWldiQTRndWZ0QmUxcXdNUXB1OHNiSTJVUmFDbk1MZkxIVEdkRHl4d0tOMkpBUUlNVUxDWFVQc3A1YThqbThCQ3JsOGVia0ViVW1lR2VOaVhkRERQaHhIT0tVOCtISGM1d2RUZVgxdEQ0UXYzLzdqUWplZXZnOEtIbDNHRE8vQ0dEVXlZT2Rad0VldFhvempXdkVWSVN1bUNxVXBFeW5WOCtQR3ZNUzNRbEUxVFRHTHltc09MeDJ0elRhND0iLCJhbGciOiJhZXMtMjU2LWdjbSIsImtkZiI6InBia2RmMi1zaGEyNTYiLCJzYWx0IjoiOWg1OEdWc3ptdHY2Z1dHaHdsYmxuQT09IiwiaXRlcmF0aW9ucyI6MTAwMDAwfQ==\n-----END MACHINE FILE-----"
+	ed25519LicenseFileV2        = "-----BEGIN LICENSE FILE-----\neyJhbGciOiJFRDI1NTE5IiwiZW5jIjoiZXlKdFpYUmhJanA3SW5abGNuTnBiMjRpT2pJc0luUmxibUZ1ZENJNkluUmxjM1FpTENKc2FXTmxibk5sWDJsa0lqb2lNR0l6WkRKaE5HTXRNV1kzWlMwMFlUVXhMVGxtTUdVdE0yTXlZVGxrTldVNFlqY3hJaXdpYVhOemRXVmtJam9pTWpBeU5TMHdNUzB3TVZRd01Eb3dNRG93TUZvaUxDSmxlSEJwY25raU9pSXlNREkxTFRBeExUTXhWREV3T2pJNU9qQTJXaUlzSW5SMGJDSTZNall5T1RjME5uMHNJbVJoZEdFaU9uc2liR2xqWlc1elpWOXBaQ0k2SWpCaU0yUXlZVFJqTFRGbU4yVXROR0UxTVMwNVpqQmxMVE5qTW1FNVpEVmxPR0kzTVNJc0luQnliMlIxWTNSZmFXUWlPaUlpTENKd2IyeHBZM2xmYVdRaU9pSWlMQ0p1WVcxbElqb2lkR1Z6ZENJc0lteHBZMlZ1YzJWZmEyVjVJam9pSWl3aWJXUTFYMk5vWldOcmMzVnRJam9pSWl3aWMyaGhNVjlqYUdWamEzTjFiU0k2SWlJc0luTm9ZVEkxTmw5amFHVmphM04xYlNJNklpSXNJbk4wWVhSMWN5STZJbUZqZEdsMlpTSXNJblZ6WlhKeklqb3dMQ0psYm5ScGRHeGxiV1Z1ZEhNaU9sc2lSa1ZCVkZWU1JWOUJJaXdpUmtWQlZGVlNSVjlDSWwwc0ltMWxkR0ZrWVhSaElqcHVkV3hzTENKbGVIQnBjbmtpT2lJd01EQXhMVEF4TFRBeFZEQXdPakF3T2pBd1dpSXNJbU55WldGMFpXUmZZWFFpT2lJd01EQXhMVEF4TFRBeFZEQXdPakF3T2pBd1dpSXNJblZ3WkdGMFpXUmZZWFFpT2lJd01EQXhMVEF4TFRBeFZEQXdPakF3T2pBd1dpSXNJbXhwWTJWdWMyVmZjRzlzYVdONUlqcDdJbkJ2YkdsamVWOXdkV0pzYVdOZmEyVjVJam9pSWl3aWNHOXNhV041WDNOamFHVnRaU0k2SWlJc0ltVjRjR2x5WVhScGIyNWZjM1J5WVhSbFoza2lPaUlpTENKamFHVmphMTlwYmw5cGJuUmxjblpoYkNJNklpSXNJbTkyWlhKaFoyVmZjM1J5WVhSbFoza2lPaUlpTENKb1pXRnlkR0psWVhSZlltRnphWE1pT2lJaUxDSnlaVzVsZDJGc1gySmhjMmx6SWpvaUlpd2ljbVZ4ZFdseVpWOWphR1ZqYTE5cGJpSTZabUZzYzJVc0ltTnZibU4xY25KbGJuUWlPbVpoYkhObExDSnlaWEYxYVhKbFgyaGxZWEowWW1WaGRDSTZabUZzYzJVc0luTjBjbWxqZENJNlptRnNjMlVzSW1ac2IyRjBhVzVuSWpwbVlXeHpaU3dpZFhObFgzQnZiMndpT21aaGJITmxMQ0p5WVhSbFgyeHBiV2wwWldRaU9tWmhiSE5sTENKbGJtTnllWEIwWldRaU9tWmhiSE5sTENKd2NtOTBaV04wWldRaU9tWmhiSE5sTENKa2RYSmhkR2x2YmlJNk1Dd2liV0Y0WDIxaFkyaHBibVZ6SWpvd0xDSnRZWGhmZFhObGN5STZNQ3dpYldGNFgzVnpaWEp6SWpvd0xDSm9aV0Z5ZEdKbFlYUmZaSFZ5WVhScGIyNGlPakI5TENKc2FXTmxibk5sWDNCeWIyUjFZM1FpT25zaWJtRnRaU0k2SWlJc0ltUnBjM1J5YVdKMWRHbHZibDl6ZEhKaGRHVm5lU0k2SWlJc0ltTnZaR1VpT2lJaUxDSjFjbXdpT2lJaUxDSndiR0YwWm05eWJTSTZiblZzYkN3aWJXVjBZV1JoZEdFaU9tNTFiR3dzSW1OeVpXRjBaV1JmWVhRaU9pSXdNREF4TFRBeExUQXhWREF3T2pBd09qQXdXaUlzSW5Wd1pHRjBaV1JmWVhRaU9pSXdNREF4TFRBeExUQXhWREF3T2pBd09qQXdXaUo5ZlgwPSIsInNpZyI6IkNqdXprckp3bFFoMG9hVUxDUGJzMUc2QkcvNDA4R21zS2NyM2dvb0NwYnYzb1VjM2dTcFpVYXRCQ2g2Y2RkdmxjN1JGOXNDV00wOHNYNlBjQW44VEFBPT0ifQ==\n-----END LICENSE FILE-----"
+)
+
+var issuedAt = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func clock(t time.Time) func() time.Time {
+	return func() time.Time {
+		return t
+	}
+}
+
+func TestVerifier_VerifyLicenseFile(t *testing.T) {
+	testCases := []struct {
+		name      string
+		publicKey string
+		file      string
+	}{
+		{name: "ed25519", publicKey: ed25519PublicKey, file: ed25519LicenseFile},
+		{name: "rsa2048pkcs1", publicKey: rsa2048PKCS1PublicKey, file: rsa2048PKCS1LicenseFile},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			verifier := NewVerifier(tc.publicKey, WithClock(clock(issuedAt.Add(time.Hour))))
+
+			licenseFile, err := verifier.VerifyLicenseFile(tc.file)
+			assert.NoError(t, err)
+			assert.Equal(t, SupportedVersion, licenseFile.Meta.Version)
+			assert.Equal(t, "test", licenseFile.Meta.Tenant)
+			assert.Equal(t, 2629746, licenseFile.Meta.TTL)
+			assert.True(t, licenseFile.Meta.Issued.Equal(issuedAt))
+			assert.Equal(t, "0b3d2a4c-1f7e-4a51-9f0e-3c2a9d5e8b71", licenseFile.License.LicenseID)
+			assert.Equal(t, "active", licenseFile.License.Status)
+			assert.True(t, licenseFile.License.HasEntitlement("FEATURE_A"))
+			assert.False(t, licenseFile.License.HasEntitlement("FEATURE_C"))
+		})
+	}
+}
+
+func TestVerifier_VerifyEncryptedLicenseFile(t *testing.T) {
+	verifier := NewVerifier(ed25519PublicKey, WithClock(clock(issuedAt.Add(time.Hour))))
+	_, err := verifier.VerifyLicenseFile(ed25519EncryptedLicenseFile)
+	assert.ErrorIs(t, err, ErrDecryptionKeyRequired)
+
+	verifier = NewVerifier(ed25519PublicKey, WithClock(clock(issuedAt.Add(time.Hour))), WithLicenseKey("OTHER-LICENSE-KEY"))
+	_, err = verifier.VerifyLicenseFile(ed25519EncryptedLicenseFile)
+	assert.ErrorIs(t, err, ErrDecryptionFailed)
+
+	verifier = NewVerifier(ed25519PublicKey, WithClock(clock(issuedAt.Add(time.Hour))), WithLicenseKey(testLicenseKey))
+	licenseFile, err := verifier.VerifyLicenseFile(ed25519EncryptedLicenseFile)
+	assert.NoError(t, err)
+	assert.Equal(t, "0b3d2a4c-1f7e-4a51-9f0e-3c2a9d5e8b71", licenseFile.License.LicenseID)
+
+	cert, err := Parse(ed25519EncryptedLicenseFile, LicenseFilePassphrase(testLicenseKey))
+	assert.NoError(t, err)
+	assert.True(t, cert.Encrypted)
+	assert.Equal(t, AlgorithmED25519, cert.Alg)
+}
+
+func TestVerifier_VerifyEncryptedMachineFile(t *testing.T) {
+	verifier := NewVerifier(ed25519PublicKey, WithClock(clock(issuedAt.Add(time.Minute))), WithLicenseKey(testLicenseKey))
+	_, err := verifier.VerifyMachineFile(ed25519EncryptedMachineFile)
+	assert.ErrorIs(t, err, ErrDecryptionFailed)
+
+	verifier = NewVerifier(
+		ed25519PublicKey,
+		WithClock(clock(issuedAt.Add(time.Minute))),
+		WithLicenseKey(testLicenseKey),
+		WithFingerprint("fingerprint"),
+	)
+	machineFile, err := verifier.VerifyMachineFile(ed25519EncryptedMachineFile)
+	assert.NoError(t, err)
+	assert.Equal(t, "6f1c9e2b-8d4a-4c3e-b5f7-2a9e1d0c7b63", machineFile.Machine.ID)
+	assert.Equal(t, "fingerprint", machineFile.Machine.Fingerprint)
+}
+
+func TestVerifier_VerifyMachineFile(t *testing.T) {
+	verifier := NewVerifier(ed25519PublicKey, WithClock(clock(issuedAt.Add(time.Minute))))
+
+	machineFile, err := verifier.VerifyMachineFile(ed25519MachineFile)
+	assert.NoError(t, err)
+	assert.Equal(t, "0b3d2a4c-1f7e-4a51-9f0e-3c2a9d5e8b71", machineFile.Meta.LicenseID)
+	assert.Equal(t, "6f1c9e2b-8d4a-4c3e-b5f7-2a9e1d0c7b63", machineFile.Meta.MachineID)
+	assert.Equal(t, 3600, machineFile.Meta.TTL)
+	assert.Equal(t, "6f1c9e2b-8d4a-4c3e-b5f7-2a9e1d0c7b63", machineFile.Machine.ID)
+	assert.Equal(t, "fingerprint", machineFile.Machine.Fingerprint)
+
+	_, err = verifier.VerifyLicenseFile(ed25519MachineFile)
+	assert.ErrorIs(t, err, ErrFileTypeMismatch)
+}
+
+func TestVerifier_TTL(t *testing.T) {
+	verifier := NewVerifier(ed25519PublicKey, WithClock(clock(issuedAt.Add(-time.Minute))))
+	_, err := verifier.VerifyMachineFile(ed25519MachineFile)
+	assert.ErrorIs(t, err, ErrFileNotYetValid)
+
+	verifier = NewVerifier(ed25519PublicKey, WithClock(clock(issuedAt.Add(time.Hour))))
+	machineFile, err := verifier.VerifyMachineFile(ed25519MachineFile)
+	assert.ErrorIs(t, err, ErrFileExpired)
+	assert.NotNil(t, machineFile)
+}
+
+func TestVerifier_UnsupportedVersion(t *testing.T) {
+	verifier := NewVerifier(ed25519PublicKey, WithClock(clock(issuedAt.Add(time.Hour))))
+	_, err := verifier.VerifyLicenseFile(ed25519LicenseFileV2)
+	assert.ErrorIs(t, err, ErrUnsupportedVersion)
+}
+
+func TestVerifier_InvalidSignature(t *testing.T) {
+	// Verifying against another policy's key
+	verifier := NewVerifier(rsa2048PKCS1PublicKey, WithClock(clock(issuedAt.Add(time.Hour))))
+	_, err := verifier.VerifyLicenseFile(ed25519LicenseFile)
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+
+	// Tampering with the signed payload
+	cert, err := Parse(ed25519LicenseFile, "")
+	assert.NoError(t, err)
+	payload, err := base64.StdEncoding.DecodeString(cert.Enc)
+	assert.NoError(t, err)
+	cert.Enc = base64.StdEncoding.EncodeToString([]byte(strings.Replace(string(payload), "active", "banned", 1)))
+	_, err = cert.Verify(ed25519PublicKey)
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+
+	cert.Alg = "HS256"
+	_, err = cert.Verify(ed25519PublicKey)
+	assert.ErrorIs(t, err, ErrUnsupportedAlgorithm)
+}
+
+func TestParse_InvalidFormat(t *testing.T) {
+	testCases := []string{
+		"",
+		"not a license file",
+		"-----BEGIN LICENSE FILE-----\n-----END LICENSE FILE-----",
+		"-----BEGIN LICENSE FILE-----\n!!!\n-----END LICENSE FILE-----",
+		"-----BEGIN LICENSE FILE-----\n" + strings.Split(ed25519MachineFile, "\n")[1] + "\n-----END MACHINE FILE-----",
+	}
+
+	for _, tc := range testCases {
+		_, err := Parse(tc, "")
+		assert.ErrorIs(t, err, ErrInvalidFormat)
+	}
+}
+
+// TestVerifier_RoundTrip signs a license file with the content signed by the checkout action and verifies it.
+func TestVerifier_RoundTrip(t *testing.T) {
+	signingKey, verifyKey, err := utils.NewEd25519KeyPair()
+	assert.NoError(t, err)
+
+	now := time.Now()
+	licenseKey, err := utils.NewLicenseKeyWithEd25519(signingKey, models.LicenseFileOutput{
+		Meta: models.LicenseFileMeta{
+			Version:   constants.CertificateFileVersion,
+			Tenant:    "test",
+			LicenseID: "license",
+			Issued:    now,
+			Expiry:    now.Add(constants.MinimumLicenseTTL * time.Second),
+			TTL:       constants.MinimumLicenseTTL,
+		},
+		Data: models.LicenseInfoOutput{
+			LicenseID:    "license",
+			Entitlements: []string{"FEATURE_A"},
+		},
+	})
+	assert.NoError(t, err)
+
+	parts := strings.Split(licenseKey, ".")
+	bCert, err := json.Marshal(Certificate{Enc: parts[1], Sig: parts[0], Alg: AlgorithmED25519})
+	assert.NoError(t, err)
+
+	encryptedCert, err := utils.EncryptWithPassphrase(bCert, utils.CertificatePassphrase(testLicenseKey, ""))
+	assert.NoError(t, err)
+	bEncryptedCert, err := json.Marshal(encryptedCert)
+	assert.NoError(t, err)
+
+	file := fmt.Sprintf(constants.LicenseFileFormat, base64.StdEncoding.EncodeToString(bEncryptedCert))
+	licenseFile, err := NewVerifier(verifyKey, WithLicenseKey(testLicenseKey)).VerifyLicenseFile(file)
+	assert.NoError(t, err)
+	assert.Equal(t, "license", licenseFile.License.LicenseID)
+	assert.True(t, licenseFile.License.HasEntitlement("FEATURE_A"))
+}
+
+func TestVerifier_VerifyLicenseFileSchemes(t *testing.T) {
+	algorithms := []string{AlgorithmED25519, AlgorithmRSA2048PKCS1, AlgorithmRSA2048PSS, AlgorithmRSA4096PSS, AlgorithmECDSAP256}
+
+	now := time.Now()
+	for _, alg := range algorithms {
+		t.Run(alg, func(t *testing.T) {
+			signingKey, verifyKey, err := utils.NewKeyPair(alg)
+			assert.NoError(t, err)
+
+			licenseKey, err := utils.NewLicenseKey(alg, signingKey, models.LicenseFileOutput{
+				Meta: models.LicenseFileMeta{
+					Version:   constants.CertificateFileVersion,
+					Tenant:    "test",
+					LicenseID: "license",
+					Issued:    now,
+					Expiry:    now.Add(constants.MinimumLicenseTTL * time.Second),
+					TTL:       constants.MinimumLicenseTTL,
+				},
+				Data: models.LicenseInfoOutput{LicenseID: "license"},
+			})
+			assert.NoError(t, err)
+
+			parts := strings.Split(licenseKey, ".")
+			bCert, err := json.Marshal(Certificate{Enc: parts[1], Sig: parts[0], Alg: alg})
+			assert.NoError(t, err)
+
+			file := fmt.Sprintf(constants.LicenseFileFormat, base64.StdEncoding.EncodeToString(bCert))
+			licenseFile, err := NewVerifier(verifyKey).VerifyLicenseFile(file)
+			assert.NoError(t, err)
+			assert.Equal(t, "license", licenseFile.License.LicenseID)
+		})
+	}
+}
+
+func TestVerifier_VerifyLicenseKey(t *testing.T) {
+	signingKey, verifyKey, err := utils.NewKeyPair(AlgorithmECDSAP256)
+	assert.NoError(t, err)
+
+	expiry := issuedAt.Add(time.Hour)
+	key, err := utils.NewSignedKey(AlgorithmECDSAP256, signingKey, models.LicenseKeyPayload{
+		KID:          utils.KeyID(verifyKey),
+		LicenseID:    "license",
+		Tenant:       "test",
+		ProductID:    "product",
+		PolicyID:     "policy",
+		MaxMachines:  3,
+		Entitlements: []string{"FEATURE_A", "FEATURE_B"},
+		Issued:       issuedAt,
+		Expiry:       &expiry,
+	})
+	assert.NoError(t, err)
+
+	licenseKey, err := NewVerifier(verifyKey, WithClock(clock(issuedAt.Add(time.Minute)))).VerifyLicenseKey(key)
+	assert.NoError(t, err)
+	assert.Equal(t, AlgorithmECDSAP256, licenseKey.Scheme)
+	assert.Equal(t, utils.KeyID(verifyKey), licenseKey.KID)
+	assert.Equal(t, "license", licenseKey.LicenseID)
+	assert.Equal(t, "test", licenseKey.Tenant)
+	assert.Equal(t, "product", licenseKey.ProductID)
+	assert.Equal(t, "policy", licenseKey.PolicyID)
+	assert.Equal(t, 3, licenseKey.MaxMachines)
+	assert.Equal(t, []string{"FEATURE_A", "FEATURE_B"}, licenseKey.Entitlements)
+	assert.True(t, licenseKey.HasEntitlement("FEATURE_A"))
+	assert.True(t, licenseKey.HasEntitlement("FEATURE_B"))
+	assert.False(t, licenseKey.HasEntitlement("FEATURE_C"))
+	assert.True(t, issuedAt.Equal(licenseKey.Issued))
+	assert.True(t, expiry.Equal(*licenseKey.Expiry))
+
+	// Expired keys are rejected, their content is still returned
+	licenseKey, err = NewVerifier(verifyKey, WithClock(clock(expiry))).VerifyLicenseKey(key)
+	assert.ErrorIs(t, err, ErrKeyExpired)
+	assert.Equal(t, "license", licenseKey.LicenseID)
+
+	// Keys without expiry never expire
+	key, err = utils.NewSignedKey(AlgorithmECDSAP256, signingKey, models.LicenseKeyPayload{
+		LicenseID: "license",
+		Tenant:    "test",
+		Issued:    issuedAt,
+	})
+	assert.NoError(t, err)
+	licenseKey, err = NewVerifier(verifyKey, WithClock(clock(issuedAt.Add(24*365*time.Hour)))).VerifyLicenseKey(key)
+	assert.NoError(t, err)
+	assert.Nil(t, licenseKey.Expiry)
+	assert.Empty(t, licenseKey.Entitlements)
+
+	_, otherVerifyKey, err := utils.NewKeyPair(AlgorithmECDSAP256)
+	assert.NoError(t, err)
+	_, err = NewVerifier(otherVerifyKey).VerifyLicenseKey(key)
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+
+	_, err = NewVerifier(verifyKey).VerifyLicenseKey(utils.GenerateToken())
+	assert.ErrorIs(t, err, ErrInvalidFormat)
+
+	_, err = NewVerifier(verifyKey).VerifyLicenseKey("RSA1024/" + strings.SplitN(key, "/", 2)[1])
+	assert.ErrorIs(t, err, ErrUnsupportedAlgorithm)
+}
+
+func TestVerifier_VerifyValidationResponse(t *testing.T) {
+	signingKey, verifyKey, err := utils.NewKeyPair(AlgorithmED25519)
+	assert.NoError(t, err)
+
+	response, err := utils.NewSignedKey(AlgorithmED25519, signingKey, models.LicenseValidationPayload{
+		LicenseID:           "license",
+		Tenant:              "test",
+		Valid:               false,
+		Code:                constants.LicenseValidationStatusExpired,
+		MissingEntitlements: []string{"FEATURE_B"},
+		Nonce:               42,
+		Issued:              issuedAt,
+	})
+	assert.NoError(t, err)
+
+	validationResponse, err := NewVerifier(verifyKey).VerifyValidationResponse(response, 42)
+	assert.NoError(t, err)
+	assert.Equal(t, AlgorithmED25519, validationResponse.Scheme)
+	assert.Equal(t, "license", validationResponse.LicenseID)
+	assert.False(t, validationResponse.Valid)
+	assert.Equal(t, constants.LicenseValidationStatusExpired, validationResponse.Code)
+	assert.Equal(t, []string{"FEATURE_B"}, validationResponse.MissingEntitlements)
+	assert.Equal(t, 42, validationResponse.Nonce)
+
+	// A response issued for another request is rejected
+	_, err = NewVerifier(verifyKey).VerifyValidationResponse(response, 43)
+	assert.ErrorIs(t, err, ErrNonceMismatch)
+
+	_, otherVerifyKey, err := utils.NewKeyPair(AlgorithmED25519)
+	assert.NoError(t, err)
+	_, err = NewVerifier(otherVerifyKey).VerifyValidationResponse(response, 42)
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+}
+
+func TestVerifier_KeyRotation(t *testing.T) {
+	previousSigningKey, previousVerifyKey, err := utils.NewKeyPair(AlgorithmED25519)
+	assert.NoError(t, err)
+	_, currentVerifyKey, err := utils.NewKeyPair(AlgorithmECDSAP256)
+	assert.NoError(t, err)
+
+	licenseKey, err := utils.NewLicenseKey(AlgorithmED25519, previousSigningKey, models.LicenseFileOutput{
+		Meta: models.LicenseFileMeta{
+			Version:   constants.CertificateFileVersion,
+			Tenant:    "test",
+			LicenseID: "license",
+			Issued:    issuedAt,
+			Expiry:    issuedAt.Add(24 * time.Hour),
+			TTL:       86400,
+		},
+		Data: models.LicenseInfoOutput{LicenseID: "license"},
+	})
+	assert.NoError(t, err)
+
+	kid := utils.KeyID(previousVerifyKey)
+	parts := strings.Split(licenseKey, ".")
+	bCert, err := json.Marshal(Certificate{Enc: parts[1], Sig: parts[0], Alg: AlgorithmED25519, Kid: kid})
+	assert.NoError(t, err)
+	file := fmt.Sprintf(constants.LicenseFileFormat, base64.StdEncoding.EncodeToString(bCert))
+
+	retiresAt := issuedAt.Add(2 * time.Hour)
+	previousKey := PublicKey{
+		KID:       kid,
+		Scheme:    AlgorithmED25519,
+		PublicKey: previousVerifyKey,
+		Status:    constants.PolicyKeyStatusRotated,
+		RetiresAt: &retiresAt,
+	}
+
+	// The file was signed before the rotation, so the previous key is required until it retires
+	_, err = NewVerifier(previousVerifyKey, WithClock(clock(issuedAt.Add(time.Hour)))).VerifyLicenseFile(file)
+	assert.NoError(t, err)
+
+	_, err = NewVerifier(currentVerifyKey, WithClock(clock(issuedAt.Add(time.Hour)))).VerifyLicenseFile(file)
+	assert.ErrorIs(t, err, ErrUnknownKeyID)
+
+	licenseFile, err := NewVerifier(currentVerifyKey, WithClock(clock(issuedAt.Add(time.Hour))), WithPublicKeys(previousKey)).VerifyLicenseFile(file)
+	assert.NoError(t, err)
+	assert.Equal(t, "license", licenseFile.License.LicenseID)
+
+	_, err = NewVerifier(currentVerifyKey, WithClock(clock(retiresAt)), WithPublicKeys(previousKey)).VerifyLicenseFile(file)
+	assert.ErrorIs(t, err, ErrKeyRetired)
+
+	previousKey.RetiresAt = nil
+	previousKey.Status = constants.PolicyKeyStatusRetired
+	_, err = NewVerifier(currentVerifyKey, WithClock(clock(issuedAt.Add(time.Hour))), WithPublicKeys(previousKey)).VerifyLicenseFile(file)
+	assert.ErrorIs(t, err, ErrKeyRetired)
+}
+
+func TestVerifier_VerifyLicenseKeyRotation(t *testing.T) {
+	previousSigningKey, previousVerifyKey, err := utils.NewKeyPair(AlgorithmECDSAP256)
+	assert.NoError(t, err)
+	_, currentVerifyKey, err := utils.NewKeyPair(AlgorithmECDSAP256)
+	assert.NoError(t, err)
+
+	key, err := utils.NewSignedKey(AlgorithmECDSAP256, previousSigningKey, models.LicenseKeyPayload{
+		LicenseID: "license",
+		Tenant:    "test",
+		Issued:    issuedAt,
+	})
+	assert.NoError(t, err)
+
+	retiresAt := issuedAt.Add(2 * time.Hour)
+	previousKey := PublicKey{
+		KID:       utils.KeyID(previousVerifyKey),
+		Scheme:    AlgorithmECDSAP256,
+		PublicKey: previousVerifyKey,
+		Status:    constants.PolicyKeyStatusRotated,
+		RetiresAt: &retiresAt,
+	}
+
+	_, err = NewVerifier(currentVerifyKey, WithClock(clock(issuedAt.Add(time.Hour)))).VerifyLicenseKey(key)
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+
+	licenseKey, err := NewVerifier(currentVerifyKey, WithClock(clock(issuedAt.Add(time.Hour))), WithPublicKeys(previousKey)).VerifyLicenseKey(key)
+	assert.NoError(t, err)
+	assert.Equal(t, "license", licenseKey.LicenseID)
+
+	_, err = NewVerifier(currentVerifyKey, WithClock(clock(retiresAt)), WithPublicKeys(previousKey)).VerifyLicenseKey(key)
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+
+	// Keys carrying the id of the key that signed them are still accepted once it retires
+	key, err = utils.NewSignedKey(AlgorithmECDSAP256, previousSigningKey, models.LicenseKeyPayload{
+		KID:       utils.KeyID(previousVerifyKey),
+		LicenseID: "license",
+		Tenant:    "test",
+		Issued:    issuedAt,
+	})
+	assert.NoError(t, err)
+
+	previousKey.Status = constants.PolicyKeyStatusRetired
+	licenseKey, err = NewVerifier(currentVerifyKey, WithClock(clock(retiresAt)), WithPublicKeys(previousKey)).VerifyLicenseKey(key)
+	assert.NoError(t, err)
+	assert.Equal(t, utils.KeyID(previousVerifyKey), licenseKey.KID)
+
+	_, err = NewVerifier(currentVerifyKey, WithClock(clock(retiresAt))).VerifyLicenseKey(key)
+	assert.ErrorIs(t, err, ErrUnknownKeyID)
+}
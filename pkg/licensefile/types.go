@@ -0,0 +1,159 @@
+package licensefile
+
+import "time"
+
+// Meta describes a license file or a machine file. It is signed along with the license or the machine,
+// so the validity window of the file is covered by the signature.
+type Meta struct {
+	Version   int       `json:"version"`
+	Tenant    string    `json:"tenant"`
+	LicenseID string    `json:"license_id"`
+	MachineID string    `json:"machine_id,omitempty"`
+	Issued    time.Time `json:"issued"`
+	Expiry    time.Time `json:"expiry"`
+	TTL       int       `json:"ttl"`
+}
+
+// LicenseFile is the signed content of a license file.
+type LicenseFile struct {
+	Meta    Meta    `json:"meta"`
+	License License `json:"data"`
+}
+
+// MachineFile is the signed content of a machine file.
+type MachineFile struct {
+	Meta    Meta    `json:"meta"`
+	Machine Machine `json:"data"`
+}
+
+// LicenseKey is the content signed into the license key of policies using signed keys. The expiry, max machines and
+// entitlements are the ones of the license when the key was issued.
+type LicenseKey struct {
+	Scheme       string     `json:"-"`
+	KID          string     `json:"kid"`
+	LicenseID    string     `json:"license_id"`
+	Tenant       string     `json:"tenant"`
+	ProductID    string     `json:"product_id"`
+	PolicyID     string     `json:"policy_id"`
+	MaxMachines  int        `json:"max_machines"`
+	Entitlements []string   `json:"entitlements"`
+	Issued       time.Time  `json:"issued"`
+	Expiry       *time.Time `json:"expiry"`
+}
+
+// HasEntitlement reports whether the license key is granted the entitlement code.
+func (k *LicenseKey) HasEntitlement(code string) bool {
+	for _, entitlement := range k.Entitlements {
+		if entitlement == code {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidationResponse is the content signed into the response of a license validation requested with a nonce.
+type ValidationResponse struct {
+	Scheme              string    `json:"-"`
+	LicenseID           string    `json:"license_id"`
+	Tenant              string    `json:"tenant"`
+	Valid               bool      `json:"valid"`
+	Code                string    `json:"code"`
+	MissingEntitlements []string  `json:"missing_entitlements"`
+	Nonce               int       `json:"nonce"`
+	Issued              time.Time `json:"issued"`
+}
+
+// License is the snapshot of a license signed into a license file at checkout.
+type License struct {
+	LicenseID      string                 `json:"license_id"`
+	ProductID      string                 `json:"product_id"`
+	PolicyID       string                 `json:"policy_id"`
+	Name           string                 `json:"name"`
+	LicenseKey     string                 `json:"license_key"`
+	MD5Checksum    string                 `json:"md5_checksum"`
+	Sha1Checksum   string                 `json:"sha1_checksum"`
+	Sha256Checksum string                 `json:"sha256_checksum"`
+	Status         string                 `json:"status"`
+	Users          int                    `json:"users"`
+	Entitlements   []string               `json:"entitlements"`
+	Metadata       map[string]interface{} `json:"metadata"`
+	Expiry         time.Time              `json:"expiry"`
+	CreatedAt      time.Time              `json:"created_at"`
+	UpdatedAt      time.Time              `json:"updated_at"`
+	Policy         LicensePolicy          `json:"license_policy"`
+	Product        LicenseProduct         `json:"license_product"`
+}
+
+// HasEntitlement reports whether the license is granted the entitlement code.
+func (l *License) HasEntitlement(code string) bool {
+	for _, entitlement := range l.Entitlements {
+		if entitlement == code {
+			return true
+		}
+	}
+	return false
+}
+
+type LicensePolicy struct {
+	PolicyPublicKey    string `json:"policy_public_key"`
+	PolicyScheme       string `json:"policy_scheme"`
+	ExpirationStrategy string `json:"expiration_strategy"`
+	CheckInInterval    string `json:"check_in_interval"`
+	OverageStrategy    string `json:"overage_strategy"`
+	HeartbeatBasis     string `json:"heartbeat_basis"`
+	RenewalBasis       string `json:"renewal_basis"`
+	RequireCheckIn     bool   `json:"require_check_in"`
+	Concurrent         bool   `json:"concurrent"`
+	RequireHeartbeat   bool   `json:"require_heartbeat"`
+	Strict             bool   `json:"strict"`
+	Floating           bool   `json:"floating"`
+	UsePool            bool   `json:"use_pool"`
+	SignedKeys         bool   `json:"signed_keys"`
+	RateLimited        bool   `json:"rate_limited"`
+	Encrypted          bool   `json:"encrypted"`
+	Protected          bool   `json:"protected"`
+	Duration           int64  `json:"duration"`
+	MaxMachines        int    `json:"max_machines"`
+	MaxUses            int    `json:"max_uses"`
+	MaxUsers           int    `json:"max_users"`
+	HeartbeatDuration  int    `json:"heartbeat_duration"`
+}
+
+type LicenseProduct struct {
+	Name                 string                 `json:"name"`
+	DistributionStrategy string                 `json:"distribution_strategy"`
+	Code                 string                 `json:"code"`
+	URL                  string                 `json:"url"`
+	Platforms            []string               `json:"platform"`
+	Metadata             map[string]interface{} `json:"metadata"`
+	CreatedAt            time.Time              `json:"created_at"`
+	UpdatedAt            time.Time              `json:"updated_at"`
+}
+
+// Machine is the snapshot of a machine signed into a machine file at checkout.
+type Machine struct {
+	ID              string                 `json:"id"`
+	LicenseKey      string                 `json:"license_key"`
+	TenantName      string                 `json:"tenant_name"`
+	Fingerprint     string                 `json:"fingerprint"`
+	IP              string                 `json:"ip"`
+	Hostname        string                 `json:"hostname"`
+	Platform        string                 `json:"platform"`
+	Name            string                 `json:"name"`
+	Metadata        map[string]interface{} `json:"metadata"`
+	Cores           int                    `json:"cores"`
+	HeartbeatStatus string                 `json:"heartbeat_status"`
+	LastHeartbeatAt time.Time              `json:"last_heartbeat_at"`
+	LastCheckOutAt  time.Time              `json:"last_check_out_at"`
+	CreatedAt       time.Time              `json:"created_at"`
+	UpdatedAt       time.Time              `json:"updated_at"`
+}
+
+// PublicKey is a signing key of a policy, as listed by the policy signing keys endpoint.
+type PublicKey struct {
+	KID       string     `json:"kid"`
+	Scheme    string     `json:"scheme"`
+	PublicKey string     `json:"public_key"`
+	Status    string     `json:"status"`
+	RetiresAt *time.Time `json:"retires_at,omitempty"`
+}
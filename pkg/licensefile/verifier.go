@@ -0,0 +1,325 @@
+package licensefile
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go-license-management/internal/constants"
+	"go-license-management/internal/utils"
+	"strings"
+	"time"
+)
+
+// Verifier verifies license files, machine files, signed license keys and signed validation responses signed with
+// a policy key pair.
+type Verifier struct {
+	publicKey   string
+	publicKeys  []PublicKey
+	licenseKey  string
+	fingerprint string
+	now         func() time.Time
+}
+
+// NewVerifier creates a verifier for files signed by the policy owning the public key.
+func NewVerifier(publicKey string, options ...func(*Verifier)) *Verifier {
+	v := &Verifier{
+		publicKey: publicKey,
+		now:       time.Now,
+	}
+
+	for _, opt := range options {
+		opt(v)
+	}
+
+	return v
+}
+
+// WithPublicKeys sets the rotated signing keys of the policy, so files signed before a rotation are accepted
+// until the key that signed them retires. License keys are accepted with retired keys as well.
+func WithPublicKeys(keys ...PublicKey) func(*Verifier) {
+	return func(v *Verifier) {
+		v.publicKeys = append(v.publicKeys, keys...)
+	}
+}
+
+// WithLicenseKey sets the license key used to decrypt encrypted license files and machine files.
+func WithLicenseKey(licenseKey string) func(*Verifier) {
+	return func(v *Verifier) {
+		v.licenseKey = licenseKey
+	}
+}
+
+// WithFingerprint sets the machine fingerprint used along with the license key to decrypt encrypted machine files.
+func WithFingerprint(fingerprint string) func(*Verifier) {
+	return func(v *Verifier) {
+		v.fingerprint = fingerprint
+	}
+}
+
+// WithClock sets the clock used to enforce the validity window of the files.
+func WithClock(now func() time.Time) func(*Verifier) {
+	return func(v *Verifier) {
+		v.now = now
+	}
+}
+
+// VerifyLicenseFile parses a license file, verifies its signature and validity window and returns its signed content.
+// If the file is only rejected because of its validity window, the content is returned along with the error.
+func (v *Verifier) VerifyLicenseFile(file string) (*LicenseFile, error) {
+	licenseFile := &LicenseFile{}
+
+	err := v.verify(file, TypeLicense, v.passphrase(LicenseFilePassphrase(v.licenseKey)), licenseFile)
+	if err != nil {
+		return nil, err
+	}
+
+	if licenseFile.Meta.LicenseID != licenseFile.License.LicenseID {
+		return nil, fmt.Errorf("%w: license id does not match", ErrInvalidFormat)
+	}
+
+	err = v.checkMeta(licenseFile.Meta)
+	if err != nil {
+		return licenseFile, err
+	}
+
+	return licenseFile, nil
+}
+
+// VerifyLicenseKey verifies a signed license key, in format `<scheme>/<payload>.<signature>`, and returns its signed
+// content. If the key is only rejected because it is expired, the content is returned along with the error.
+// License keys outlive the rotation of the policy keys, so a key is still accepted once the policy key that signed it
+// is retired, provided the verifier is given that key, see WithPublicKeys.
+func (v *Verifier) VerifyLicenseKey(key string) (*LicenseKey, error) {
+	// Keys issued before key rotation do not carry a key id and are verified with the keys that are not retired
+	publicKeys := v.activePublicKeys()
+	if kid := signedKeyID(key); kid != "" {
+		publicKey, err := v.lookupPublicKey(kid)
+		if err != nil {
+			return nil, err
+		}
+		publicKeys = []string{publicKey}
+	}
+
+	scheme, data, err := v.verifySignedKey(key, publicKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	licenseKey := &LicenseKey{Scheme: scheme}
+	err = json.Unmarshal(data, licenseKey)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidFormat, err)
+	}
+
+	if licenseKey.Expiry != nil && !v.now().Before(*licenseKey.Expiry) {
+		return licenseKey, ErrKeyExpired
+	}
+
+	return licenseKey, nil
+}
+
+// VerifyValidationResponse verifies the signed response of a license validation, in format
+// `<scheme>/<payload>.<signature>`, and checks that it echoes the nonce sent along with the request.
+func (v *Verifier) VerifyValidationResponse(response string, nonce int) (*ValidationResponse, error) {
+	scheme, data, err := v.verifySignedKey(response, v.activePublicKeys())
+	if err != nil {
+		return nil, err
+	}
+
+	validationResponse := &ValidationResponse{Scheme: scheme}
+	err = json.Unmarshal(data, validationResponse)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidFormat, err)
+	}
+
+	if validationResponse.Nonce != nonce {
+		return nil, fmt.Errorf("%w: expected [%d], got [%d]", ErrNonceMismatch, nonce, validationResponse.Nonce)
+	}
+
+	return validationResponse, nil
+}
+
+// VerifyMachineFile parses a machine file, verifies its signature and validity window and returns its signed content.
+// If the file is only rejected because of its validity window, the content is returned along with the error.
+func (v *Verifier) VerifyMachineFile(file string) (*MachineFile, error) {
+	machineFile := &MachineFile{}
+
+	err := v.verify(file, TypeMachine, v.passphrase(MachineFilePassphrase(v.licenseKey, v.fingerprint)), machineFile)
+	if err != nil {
+		return nil, err
+	}
+
+	if machineFile.Meta.MachineID != machineFile.Machine.ID {
+		return nil, fmt.Errorf("%w: machine id does not match", ErrInvalidFormat)
+	}
+
+	err = v.checkMeta(machineFile.Meta)
+	if err != nil {
+		return machineFile, err
+	}
+
+	return machineFile, nil
+}
+
+// passphrase discards the passphrase when no license key is set, so encrypted files are reported as such.
+func (v *Verifier) passphrase(passphrase string) string {
+	if v.licenseKey == "" {
+		return ""
+	}
+	return passphrase
+}
+
+func (v *Verifier) verify(file string, fileType string, passphrase string, content any) error {
+	cert, err := Parse(file, passphrase)
+	if err != nil {
+		return err
+	}
+
+	if cert.Type != fileType {
+		return fmt.Errorf("%w: expected [%s], got [%s]", ErrFileTypeMismatch, fileType, cert.Type)
+	}
+
+	publicKey, err := v.resolvePublicKey(cert.Kid)
+	if err != nil {
+		return err
+	}
+
+	data, err := cert.Verify(publicKey)
+	if err != nil {
+		return err
+	}
+
+	// The version is checked before decoding the content, as its layout may differ between versions
+	var versioned struct {
+		Meta struct {
+			Version int `json:"version"`
+		} `json:"meta"`
+	}
+	err = json.Unmarshal(data, &versioned)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidFormat, err)
+	}
+	if versioned.Meta.Version < 1 || versioned.Meta.Version > SupportedVersion {
+		return fmt.Errorf("%w: %d", ErrUnsupportedVersion, versioned.Meta.Version)
+	}
+
+	err = json.Unmarshal(data, content)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidFormat, err)
+	}
+
+	return nil
+}
+
+// verifySignedKey verifies a value in signed key format with the first matching public key and returns its scheme
+// and signed payload.
+func (v *Verifier) verifySignedKey(key string, publicKeys []string) (string, []byte, error) {
+	var scheme string
+	var data []byte
+	var err error
+	for _, publicKey := range publicKeys {
+		scheme, data, err = utils.VerifySignedKey(publicKey, strings.TrimSpace(key))
+		if err == nil {
+			break
+		}
+		if errors.Is(err, utils.ErrSignedKeyIsInvalid) {
+			return "", nil, fmt.Errorf("%w: %s", ErrInvalidFormat, err)
+		}
+		if errors.Is(err, utils.ErrSigningSchemeIsNotSupported) {
+			return "", nil, fmt.Errorf("%w: %s", ErrUnsupportedAlgorithm, scheme)
+		}
+	}
+	if err != nil {
+		return "", nil, fmt.Errorf("%w: %s", ErrInvalidSignature, err)
+	}
+
+	return scheme, data, nil
+}
+
+// resolvePublicKey returns the public key matching the key id of a file. Files without a key id predate key
+// rotation and are verified with the primary key.
+func (v *Verifier) resolvePublicKey(kid string) (string, error) {
+	if kid == "" || kid == utils.KeyID(v.publicKey) {
+		return v.publicKey, nil
+	}
+
+	for _, key := range v.publicKeys {
+		if key.KID != kid && utils.KeyID(key.PublicKey) != kid {
+			continue
+		}
+		if v.isRetired(key) {
+			return "", fmt.Errorf("%w: %s", ErrKeyRetired, kid)
+		}
+		return key.PublicKey, nil
+	}
+
+	return "", fmt.Errorf("%w: %s", ErrUnknownKeyID, kid)
+}
+
+// lookupPublicKey returns the public key matching the key id of a license key, retired or not.
+func (v *Verifier) lookupPublicKey(kid string) (string, error) {
+	if kid == utils.KeyID(v.publicKey) {
+		return v.publicKey, nil
+	}
+
+	for _, key := range v.publicKeys {
+		if key.KID == kid || utils.KeyID(key.PublicKey) == kid {
+			return key.PublicKey, nil
+		}
+	}
+
+	return "", fmt.Errorf("%w: %s", ErrUnknownKeyID, kid)
+}
+
+// signedKeyID returns the key id carried by the payload of a signed key, if any. The payload is not verified yet,
+// the key id only selects the public key to verify it with.
+func signedKeyID(key string) string {
+	data, err := utils.SignedKeyPayload(strings.TrimSpace(key))
+	if err != nil {
+		return ""
+	}
+
+	payload := struct {
+		KID string `json:"kid"`
+	}{}
+	err = json.Unmarshal(data, &payload)
+	if err != nil {
+		return ""
+	}
+	return payload.KID
+}
+
+// activePublicKeys returns the primary key followed by the rotated keys that are not retired yet.
+func (v *Verifier) activePublicKeys() []string {
+	publicKeys := []string{v.publicKey}
+	for _, key := range v.publicKeys {
+		if !v.isRetired(key) && key.PublicKey != v.publicKey {
+			publicKeys = append(publicKeys, key.PublicKey)
+		}
+	}
+	return publicKeys
+}
+
+func (v *Verifier) isRetired(key PublicKey) bool {
+	if key.Status == constants.PolicyKeyStatusRetired {
+		return true
+	}
+	return key.RetiresAt != nil && !v.now().Before(*key.RetiresAt)
+}
+
+// checkMeta checks that the file is used within its validity window.
+func (v *Verifier) checkMeta(meta Meta) error {
+	if meta.Expiry.IsZero() {
+		return fmt.Errorf("%w: missing expiry", ErrInvalidFormat)
+	}
+
+	now := v.now()
+	if now.Before(meta.Issued) {
+		return ErrFileNotYetValid
+	}
+	if !now.Before(meta.Expiry) {
+		return ErrFileExpired
+	}
+
+	return nil
+}
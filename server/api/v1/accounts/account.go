@@ -46,6 +46,7 @@ func (r *AccountRouter) Routes(engine *gin.RouterGroup, path string) {
 		routes.GET("", middlewares.JWTValidationMW(), middlewares.PermissionValidationMW(permissions.UserRead), r.retrieve)
 		routes.PATCH("", middlewares.JWTValidationMW(), middlewares.PermissionValidationMW(permissions.UserUpdate), r.update)
 		routes.DELETE("", middlewares.JWTValidationMW(), middlewares.PermissionValidationMW(permissions.UserDelete), r.delete)
+		routes.GET("/licenses", middlewares.JWTValidationMW(), middlewares.PermissionValidationMW(permissions.UserRead), r.listLicenses)
 		routes.POST("/actions/:action", middlewares.JWTValidationMW(), middlewares.AccountActionPermissionValidationMW(), r.actions)
 
 	}
@@ -615,3 +616,105 @@ func (r *AccountRouter) actions(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, resp)
 	return
 }
+
+// listLicenses lists the licenses the account is attached to.
+//
+// @Summary 		API to list licenses of existing account
+// @Description 	Listing licenses the account is attached to
+// @Tags 			account
+// @Accept 			json
+// @Produce 		json
+// @Security        BearerAuth
+// @Param 			param 			    path 		account_attribute.AccountCommonURI   	true 	"path_param"
+// @Param 			payload 			query 		accounts.AccountLicenseListRequest 	true 	"request"
+// @Success 		200 				{object} 	response.Response
+// @Failure 		400 				{object} 	response.Response
+// @Failure 		500 				{object} 	response.Response
+// @Router 			/tenants/{tenant_name}/accounts/{username}/licenses [get]
+func (r *AccountRouter) listLicenses(ctx *gin.Context) {
+	rootCtx, span := r.tracer.Start(ctx, ctx.Request.URL.Path, trace.WithAttributes(attribute.KeyValue{
+		Key:   constants.RequestIDField,
+		Value: attribute.StringValue(ctx.GetString(constants.RequestIDField)),
+	}))
+	defer span.End()
+
+	resp := response.NewResponse(ctx)
+	r.logger.WithCustomFields(
+		zap.String(constants.RequestIDField, ctx.GetString(constants.RequestIDField)),
+		zap.String(constants.ContextValueSubject, ctx.GetString(constants.ContextValueSubject)),
+	).Info("received new account licenses listing request")
+
+	// serializer
+	var uriReq account_attribute.AccountCommonURI
+	_, cSpan := r.tracer.Start(rootCtx, "serializer")
+	err := ctx.ShouldBindUri(&uriReq)
+	if err != nil {
+		cSpan.End()
+		r.logger.GetLogger().Error(err.Error())
+		resp.ToResponse(cerrors.ErrCodeMapper[cerrors.ErrGenericBadRequest], cerrors.ErrMessageMapper[cerrors.ErrGenericBadRequest], nil, nil, nil)
+		ctx.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	var req AccountLicenseListRequest
+	err = ctx.ShouldBind(&req)
+	if err != nil {
+		cSpan.End()
+		r.logger.GetLogger().Error(err.Error())
+		resp.ToResponse(cerrors.ErrCodeMapper[cerrors.ErrGenericBadRequest], cerrors.ErrMessageMapper[cerrors.ErrGenericBadRequest], nil, nil, nil)
+		ctx.JSON(http.StatusBadRequest, resp)
+		return
+	}
+	cSpan.End()
+
+	// validation
+	_, cSpan = r.tracer.Start(rootCtx, "validation")
+	err = uriReq.Validate()
+	if err != nil {
+		cSpan.End()
+		r.logger.GetLogger().Error(err.Error())
+		resp.ToResponse(cerrors.ErrCodeMapper[err], cerrors.ErrMessageMapper[err], nil, nil, nil)
+		ctx.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	if uriReq.Username == nil {
+		cSpan.End()
+		r.logger.GetLogger().Error(cerrors.ErrAccountUsernameIsEmpty.Error())
+		resp.ToResponse(cerrors.ErrCodeMapper[cerrors.ErrAccountUsernameIsEmpty], cerrors.ErrMessageMapper[cerrors.ErrAccountUsernameIsEmpty], nil, nil, nil)
+		ctx.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	err = req.Validate()
+	if err != nil {
+		cSpan.End()
+		r.logger.GetLogger().Error(err.Error())
+		resp.ToResponse(cerrors.ErrCodeMapper[err], cerrors.ErrMessageMapper[err], nil, nil, nil)
+		ctx.JSON(http.StatusBadRequest, resp)
+		return
+	}
+	cSpan.End()
+
+	// handler
+	_, cSpan = r.tracer.Start(rootCtx, "handler")
+	result, err := r.svc.ListLicenses(ctx, req.ToAccountLicenseListInput(rootCtx, r.tracer, uriReq))
+	if err != nil {
+		cSpan.End()
+		r.logger.GetLogger().Error(err.Error())
+		resp.ToResponse(result.Code, result.Message, result.Data, nil, nil)
+		switch {
+		case errors.Is(err, cerrors.ErrTenantNameIsInvalid),
+			errors.Is(err, cerrors.ErrAccountUsernameIsInvalid):
+			ctx.JSON(http.StatusBadRequest, resp)
+		default:
+			ctx.JSON(http.StatusInternalServerError, resp)
+		}
+		return
+	}
+	cSpan.End()
+
+	r.logger.GetLogger().Info("completed listing account licenses")
+	resp.ToResponse(result.Code, result.Message, result.Data, nil, result.Count)
+	ctx.JSON(http.StatusOK, resp)
+}
@@ -183,3 +183,21 @@ func (req *AccountActionRequest) ToAccountActionInput(ctx context.Context, trace
 		ResetToken:       req.ResetToken,
 	}
 }
+
+type AccountLicenseListRequest struct {
+	constants.QueryCommonParam
+}
+
+func (req *AccountLicenseListRequest) Validate() error {
+	req.QueryCommonParam.Validate()
+	return nil
+}
+
+func (req *AccountLicenseListRequest) ToAccountLicenseListInput(ctx context.Context, tracer trace.Tracer, accountURI account_attribute.AccountCommonURI) *models.AccountLicenseListInput {
+	return &models.AccountLicenseListInput{
+		TracerCtx:        ctx,
+		Tracer:           tracer,
+		AccountCommonURI: accountURI,
+		QueryCommonParam: req.QueryCommonParam,
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"go-license-management/internal/infrastructure/logging"
 	"go-license-management/internal/infrastructure/models/authentication_attribute"
 	"go-license-management/internal/infrastructure/tracer"
+	"go-license-management/internal/middlewares"
 	"go-license-management/internal/response"
 	"go-license-management/internal/services/v1/authentications/service"
 	"go-license-management/internal/utils"
@@ -39,13 +40,18 @@ func (r *AuthenticationRouter) Routes(engine *gin.RouterGroup, path string) {
 	{
 		routes = routes.Group("/auth")
 		routes.POST("/login", r.login)
+		routes.POST("/refresh", r.refresh)
+		// Access tokens are only validated under the tenant path
+		if path != "" {
+			routes.POST("/logout", middlewares.JWTValidationMW(), r.logout)
+		}
 	}
 }
 
 // login validates existing account resource.
 //
 // @Summary 		API to validate existing account and return a corresponding jwt token
-// @Description 	Validating account and generate a JWT token if valid, without tenant_name path parameter, one must provide the superadmin credentials
+// @Description 	Validating account and generate a JWT token if valid, without tenant_name path parameter, one must provide the superadmin credentials. Accounts are locked after too many failed logins
 // @Tags 			authentication
 // @Accept 			mpfd
 // @Produce 		json
@@ -54,6 +60,8 @@ func (r *AuthenticationRouter) Routes(engine *gin.RouterGroup, path string) {
 // @Param        	tenant_name    	    path     	string  				true  	"tenant_name"
 // @Success 		200 				{object} 	response.Response
 // @Failure 		400 				{object} 	response.Response
+// @Failure 		401 				{object} 	response.Response
+// @Failure 		429 				{object} 	response.Response
 // @Failure 		500 				{object} 	response.Response
 // @Router 			/tenants/{tenant_name}/auth/login [post]
 // @Router 			/auth/login [post]
@@ -123,6 +131,94 @@ func (r *AuthenticationRouter) login(ctx *gin.Context) {
 		resp.ToResponse(result.Code, result.Message, result.Data, nil, nil)
 		switch {
 		case errors.Is(err, cerrors.ErrGenericUnauthorized),
+			errors.Is(err, cerrors.ErrAccountIsBanned),
+			errors.Is(err, cerrors.ErrAccountIsInactive),
+			errors.Is(err, cerrors.ErrAccountIsLocked):
+			ctx.JSON(http.StatusUnauthorized, resp)
+		case errors.Is(err, cerrors.ErrGenericTooManyRequests):
+			ctx.JSON(http.StatusTooManyRequests, resp)
+		default:
+			ctx.JSON(http.StatusInternalServerError, resp)
+		}
+		return
+	}
+	cSpan.End()
+
+	resp.ToResponse(result.Code, result.Message, result.Data, nil, nil)
+	ctx.JSON(http.StatusOK, resp)
+	return
+}
+
+// refresh exchanges a refresh token for a new pair of access and refresh tokens.
+//
+// @Summary 		API to exchange a refresh token for a new access token
+// @Description 	Exchanging a single-use refresh token for a new access token and a new refresh token, reusing a refresh token revokes every token issued since the login
+// @Tags 			authentication
+// @Accept 			mpfd
+// @Produce 		json
+// @Param 			refresh 			formData 	string 					true 	"refresh token"
+// @Param        	tenant_name    	    path     	string  				true  	"tenant_name"
+// @Success 		200 				{object} 	response.Response
+// @Failure 		400 				{object} 	response.Response
+// @Failure 		401 				{object} 	response.Response
+// @Failure 		500 				{object} 	response.Response
+// @Router 			/tenants/{tenant_name}/auth/refresh [post]
+// @Router 			/auth/refresh [post]
+func (r *AuthenticationRouter) refresh(ctx *gin.Context) {
+	rootCtx, span := r.tracer.Start(ctx, ctx.Request.URL.Path, trace.WithAttributes(attribute.KeyValue{
+		Key:   constants.RequestIDField,
+		Value: attribute.StringValue(ctx.GetString(constants.RequestIDField)),
+	}))
+	defer span.End()
+
+	resp := response.NewResponse(ctx)
+	r.logger.WithCustomFields(zap.String(constants.RequestIDField, ctx.GetString(constants.RequestIDField))).Info("received new token refresh request")
+
+	// serializer
+	var uriReq authentication_attribute.AuthenticationCommonURI
+	_, cSpan := r.tracer.Start(rootCtx, "serializer")
+	err := ctx.ShouldBindUri(&uriReq)
+	if err != nil {
+		cSpan.End()
+		r.logger.GetLogger().Error(err.Error())
+		resp.ToResponse(cerrors.ErrCodeMapper[cerrors.ErrGenericBadRequest], cerrors.ErrMessageMapper[cerrors.ErrGenericBadRequest], nil, nil, nil)
+		ctx.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	var bodyReq AuthenticationRefreshRequest
+	err = ctx.ShouldBind(&bodyReq)
+	if err != nil {
+		cSpan.End()
+		r.logger.GetLogger().Error(err.Error())
+		resp.ToResponse(cerrors.ErrCodeMapper[cerrors.ErrGenericBadRequest], cerrors.ErrMessageMapper[cerrors.ErrGenericBadRequest], nil, nil, nil)
+		ctx.JSON(http.StatusBadRequest, resp)
+		return
+	}
+	cSpan.End()
+
+	// validation
+	_, cSpan = r.tracer.Start(rootCtx, "validation")
+	err = bodyReq.Validate()
+	if err != nil {
+		cSpan.End()
+		r.logger.GetLogger().Error(err.Error())
+		resp.ToResponse(cerrors.ErrCodeMapper[err], cerrors.ErrMessageMapper[err], nil, nil, nil)
+		ctx.JSON(http.StatusBadRequest, resp)
+		return
+	}
+	cSpan.End()
+
+	// handler
+	_, cSpan = r.tracer.Start(rootCtx, "handler")
+	result, err := r.svc.Refresh(ctx, bodyReq.ToAuthenticationRefreshInput(rootCtx, r.tracer, uriReq))
+	if err != nil {
+		cSpan.End()
+		r.logger.GetLogger().Error(err.Error())
+		resp.ToResponse(result.Code, result.Message, result.Data, nil, nil)
+		switch {
+		case errors.Is(err, cerrors.ErrAccountRefreshTokenIsInvalid),
+			errors.Is(err, cerrors.ErrAccountRefreshTokenIsExpired),
 			errors.Is(err, cerrors.ErrAccountIsBanned),
 			errors.Is(err, cerrors.ErrAccountIsInactive):
 			ctx.JSON(http.StatusUnauthorized, resp)
@@ -137,3 +233,74 @@ func (r *AuthenticationRouter) login(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, resp)
 	return
 }
+
+// logout revokes the access token of the request.
+//
+// @Summary 		API to revoke the access token
+// @Description 	Revoking the access token of the request until it expires, along with the refresh tokens of the session when its refresh token is given
+// @Tags 			authentication
+// @Accept 			mpfd
+// @Produce 		json
+// @Security        BearerAuth
+// @Param 			refresh 			formData 	string 					false 	"refresh token"
+// @Param        	tenant_name    	    path     	string  				true  	"tenant_name"
+// @Success 		200 				{object} 	response.Response
+// @Failure 		400 				{object} 	response.Response
+// @Failure 		401 				{object} 	response.Response
+// @Failure 		500 				{object} 	response.Response
+// @Router 			/tenants/{tenant_name}/auth/logout [post]
+func (r *AuthenticationRouter) logout(ctx *gin.Context) {
+	rootCtx, span := r.tracer.Start(ctx, ctx.Request.URL.Path, trace.WithAttributes(attribute.KeyValue{
+		Key:   constants.RequestIDField,
+		Value: attribute.StringValue(ctx.GetString(constants.RequestIDField)),
+	}))
+	defer span.End()
+
+	resp := response.NewResponse(ctx)
+	r.logger.WithCustomFields(zap.String(constants.RequestIDField, ctx.GetString(constants.RequestIDField))).Info("received new logout request")
+
+	// serializer
+	var uriReq authentication_attribute.AuthenticationCommonURI
+	_, cSpan := r.tracer.Start(rootCtx, "serializer")
+	err := ctx.ShouldBindUri(&uriReq)
+	if err != nil {
+		cSpan.End()
+		r.logger.GetLogger().Error(err.Error())
+		resp.ToResponse(cerrors.ErrCodeMapper[cerrors.ErrGenericBadRequest], cerrors.ErrMessageMapper[cerrors.ErrGenericBadRequest], nil, nil, nil)
+		ctx.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	var bodyReq AuthenticationLogoutRequest
+	err = ctx.ShouldBind(&bodyReq)
+	if err != nil {
+		cSpan.End()
+		r.logger.GetLogger().Error(err.Error())
+		resp.ToResponse(cerrors.ErrCodeMapper[cerrors.ErrGenericBadRequest], cerrors.ErrMessageMapper[cerrors.ErrGenericBadRequest], nil, nil, nil)
+		ctx.JSON(http.StatusBadRequest, resp)
+		return
+	}
+	cSpan.End()
+
+	// handler
+	_, cSpan = r.tracer.Start(rootCtx, "handler")
+	result, err := r.svc.Logout(ctx, bodyReq.ToAuthenticationLogoutInput(rootCtx, r.tracer, uriReq))
+	if err != nil {
+		cSpan.End()
+		r.logger.GetLogger().Error(err.Error())
+		resp.ToResponse(result.Code, result.Message, result.Data, nil, nil)
+		switch {
+		case errors.Is(err, cerrors.ErrAccountRefreshTokenIsInvalid):
+			ctx.JSON(http.StatusBadRequest, resp)
+		default:
+			ctx.JSON(http.StatusInternalServerError, resp)
+		}
+		return
+	}
+	cSpan.End()
+
+	r.logger.GetLogger().Info("completed logout request")
+	resp.ToResponse(result.Code, result.Message, result.Data, nil, nil)
+	ctx.JSON(http.StatusOK, resp)
+	return
+}
@@ -34,3 +34,37 @@ func (req *AuthenticationLoginRequest) ToAuthenticationLoginInput(ctx context.Co
 		Password:                req.Password,
 	}
 }
+
+type AuthenticationRefreshRequest struct {
+	Refresh *string `form:"refresh" json:"refresh" validate:"required" example:"ref-..."`
+}
+
+func (req *AuthenticationRefreshRequest) Validate() error {
+	if req.Refresh == nil || *req.Refresh == "" {
+		return cerrors.ErrAccountRefreshTokenIsEmpty
+	}
+
+	return nil
+}
+
+func (req *AuthenticationRefreshRequest) ToAuthenticationRefreshInput(ctx context.Context, tracer trace.Tracer, uriReq authentication_attribute.AuthenticationCommonURI) *models.AuthenticationRefreshInput {
+	return &models.AuthenticationRefreshInput{
+		TracerCtx:               ctx,
+		Tracer:                  tracer,
+		AuthenticationCommonURI: uriReq,
+		Refresh:                 req.Refresh,
+	}
+}
+
+type AuthenticationLogoutRequest struct {
+	Refresh *string `form:"refresh" json:"refresh" validate:"optional" example:"ref-..."`
+}
+
+func (req *AuthenticationLogoutRequest) ToAuthenticationLogoutInput(ctx context.Context, tracer trace.Tracer, uriReq authentication_attribute.AuthenticationCommonURI) *models.AuthenticationLogoutInput {
+	return &models.AuthenticationLogoutInput{
+		TracerCtx:               ctx,
+		Tracer:                  tracer,
+		AuthenticationCommonURI: uriReq,
+		Refresh:                 req.Refresh,
+	}
+}
@@ -43,7 +43,14 @@ func (r *LicenseRouter) Routes(engine *gin.RouterGroup, path string) {
 		routes.GET("/:license_id", middlewares.JWTValidationMW(), middlewares.PermissionValidationMW(permissions.LicenseRead), r.retrieve)
 		routes.PATCH("/:license_id", middlewares.JWTValidationMW(), middlewares.PermissionValidationMW(permissions.LicenseUpdate), r.update)
 		routes.DELETE("/:license_id", middlewares.JWTValidationMW(), middlewares.PermissionValidationMW(permissions.LicenseDelete), r.delete)
-		routes.POST("/actions/:action", middlewares.JWTValidationMW(), middlewares.LicenseActionPermissionValidationMW(), r.action)
+		routes.POST("/:license_id/users", middlewares.JWTValidationMW(), middlewares.PermissionValidationMW(permissions.LicenseUsersAttach), r.attachUsers)
+		routes.DELETE("/:license_id/users", middlewares.JWTValidationMW(), middlewares.PermissionValidationMW(permissions.LicenseUsersDetach), r.detachUsers)
+		routes.GET("/:license_id/users", middlewares.JWTValidationMW(), middlewares.PermissionValidationMW(permissions.LicenseRead), r.listUsers)
+		routes.POST("/:license_id/entitlements", middlewares.JWTValidationMW(), middlewares.PermissionValidationMW(permissions.LicenseEntitlementsAttach), r.attachEntitlements)
+		routes.DELETE("/:license_id/entitlements", middlewares.JWTValidationMW(), middlewares.PermissionValidationMW(permissions.LicenseEntitlementsDetach), r.detachEntitlements)
+		routes.GET("/:license_id/entitlements", middlewares.JWTValidationMW(), middlewares.PermissionValidationMW(permissions.LicenseRead), r.listEntitlements)
+		routes.POST("/:license_id/tokens", middlewares.JWTValidationMW(), middlewares.PermissionValidationMW(permissions.LicenseTokensGenerate), r.tokens)
+		routes.POST("/actions/:action", middlewares.JWTValidationMW(), middlewares.LicenseActionPermissionValidationMW(), middlewares.LicenseRateLimitMW(), r.action)
 	}
 }
 
@@ -120,8 +127,13 @@ func (r *LicenseRouter) create(ctx *gin.Context) {
 		switch {
 		case errors.Is(err, cerrors.ErrTenantNameIsInvalid),
 			errors.Is(err, cerrors.ErrPolicyIDIsInvalid),
-			errors.Is(err, cerrors.ErrProductIDIsInvalid):
+			errors.Is(err, cerrors.ErrProductIDIsInvalid),
+			errors.Is(err, cerrors.ErrLicenseKeyPoolIsExhausted):
 			ctx.JSON(http.StatusBadRequest, resp)
+		case errors.Is(err, cerrors.ErrPolicyIsProtected):
+			ctx.JSON(http.StatusForbidden, resp)
+		case errors.Is(err, cerrors.ErrProductTokenScopeIsInvalid):
+			ctx.JSON(http.StatusForbidden, resp)
 		default:
 			ctx.JSON(http.StatusInternalServerError, resp)
 		}
@@ -285,6 +297,8 @@ func (r *LicenseRouter) update(ctx *gin.Context) {
 			errors.Is(err, cerrors.ErrProductIDIsInvalid),
 			errors.Is(err, cerrors.ErrLicenseIDIsInvalid):
 			ctx.JSON(http.StatusBadRequest, resp)
+		case errors.Is(err, cerrors.ErrProductTokenScopeIsInvalid):
+			ctx.JSON(http.StatusForbidden, resp)
 		default:
 			ctx.JSON(http.StatusInternalServerError, resp)
 		}
@@ -473,6 +487,9 @@ func (r *LicenseRouter) list(ctx *gin.Context) {
 //   - decrement-usage: Action to decrement a license's uses attribute in accordance with its policy's maxUses attribute.
 //   - reset-usage: Action to reset a license's uses attribute to 0.
 //
+// A request carrying a nonce is rejected if the nonce was already used for the license within the replay window.
+// The validate action then echoes the nonce in a response signed with the policy key pair (signed_response).
+//
 // @Summary 		API to perform action on license resource
 // @Description 	Performing action on license resource
 // @Tags 			license
@@ -483,6 +500,7 @@ func (r *LicenseRouter) list(ctx *gin.Context) {
 // @Param 			payload 			body 		licenses.LicenseActionsRequest 	        true 	"request"
 // @Success 		200 				{object} 	response.Response
 // @Failure 		400 				{object} 	response.Response
+// @Failure 		429 				{object} 	response.Response
 // @Failure 		500 				{object} 	response.Response
 // @Router 			/tenants/{tenant_name}/licenses/actions/{action} [post]
 func (r *LicenseRouter) action(ctx *gin.Context) {
@@ -554,6 +572,9 @@ func (r *LicenseRouter) action(ctx *gin.Context) {
 		switch {
 		case errors.Is(err, cerrors.ErrGenericInternalServer):
 			ctx.JSON(http.StatusInternalServerError, resp)
+		case errors.Is(err, cerrors.ErrProductTokenScopeIsInvalid),
+			errors.Is(err, cerrors.ErrLicenseTokenScopeIsInvalid):
+			ctx.JSON(http.StatusForbidden, resp)
 		default:
 			ctx.JSON(http.StatusBadRequest, resp)
 		}
@@ -565,3 +586,685 @@ func (r *LicenseRouter) action(ctx *gin.Context) {
 	resp.ToResponse(result.Code, result.Message, result.Data, nil, nil)
 	ctx.JSON(http.StatusOK, resp)
 }
+
+// attachUsers attaches accounts to a license.
+//
+// @Summary 		API to attach users to license resource
+// @Description 	Attaching users to license resource. The policy overage strategy applies when the license max users is exceeded
+// @Tags 			license
+// @Accept 			json
+// @Produce 		json
+// @Security        BearerAuth
+// @Param 			param 			    path 		license_attribute.LicenseCommonURI   	true 	"path_param"
+// @Param 			payload 			body 		licenses.LicenseUsersRequest 	        true 	"request"
+// @Success 		201 				{object} 	response.Response
+// @Failure 		400 				{object} 	response.Response
+// @Failure 		500 				{object} 	response.Response
+// @Router 			/tenants/{tenant_name}/licenses/{license_id}/users [post]
+func (r *LicenseRouter) attachUsers(ctx *gin.Context) {
+	rootCtx, span := r.tracer.Start(ctx, ctx.Request.URL.Path, trace.WithAttributes(attribute.KeyValue{
+		Key:   constants.RequestIDField,
+		Value: attribute.StringValue(ctx.GetString(constants.RequestIDField)),
+	}))
+	defer span.End()
+
+	resp := response.NewResponse(ctx)
+	r.logger.WithCustomFields(
+		zap.String(constants.RequestIDField, ctx.GetString(constants.RequestIDField)),
+		zap.String(constants.ContextValueSubject, ctx.GetString(constants.ContextValueSubject)),
+	).Info("received new license user attachment request")
+
+	// serializer
+	r.logger.GetLogger().Info("validating license user attachment request")
+	var uriReq license_attribute.LicenseCommonURI
+	_, cSpan := r.tracer.Start(rootCtx, "serializer")
+	err := ctx.ShouldBindUri(&uriReq)
+	if err != nil {
+		cSpan.End()
+		r.logger.GetLogger().Error(err.Error())
+		resp.ToResponse(cerrors.ErrCodeMapper[cerrors.ErrGenericBadRequest], cerrors.ErrMessageMapper[cerrors.ErrGenericBadRequest], nil, nil, nil)
+		ctx.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	var bodyReq LicenseUsersRequest
+	err = ctx.ShouldBind(&bodyReq)
+	if err != nil {
+		cSpan.End()
+		r.logger.GetLogger().Error(err.Error())
+		resp.ToResponse(cerrors.ErrCodeMapper[cerrors.ErrGenericBadRequest], cerrors.ErrMessageMapper[cerrors.ErrGenericBadRequest], nil, nil, nil)
+		ctx.JSON(http.StatusBadRequest, resp)
+		return
+	}
+	cSpan.End()
+
+	// validation
+	_, cSpan = r.tracer.Start(rootCtx, "validation")
+	err = uriReq.Validate()
+	if err != nil {
+		cSpan.End()
+		r.logger.GetLogger().Error(err.Error())
+		resp.ToResponse(cerrors.ErrCodeMapper[err], cerrors.ErrMessageMapper[err], nil, nil, nil)
+		ctx.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	err = bodyReq.Validate()
+	if err != nil {
+		cSpan.End()
+		r.logger.GetLogger().Error(err.Error())
+		resp.ToResponse(cerrors.ErrCodeMapper[err], cerrors.ErrMessageMapper[err], nil, nil, nil)
+		ctx.JSON(http.StatusBadRequest, resp)
+		return
+	}
+	cSpan.End()
+
+	// handler
+	_, cSpan = r.tracer.Start(rootCtx, "handler")
+	result, err := r.svc.AttachUsers(ctx, bodyReq.ToLicenseUsersInput(rootCtx, r.tracer, uriReq))
+	if err != nil {
+		cSpan.End()
+		r.logger.GetLogger().Error(err.Error())
+		resp.ToResponse(result.Code, result.Message, result.Data, nil, nil)
+		switch {
+		case errors.Is(err, cerrors.ErrTenantNameIsInvalid),
+			errors.Is(err, cerrors.ErrLicenseIDIsInvalid),
+			errors.Is(err, cerrors.ErrLicenseUserIsInvalid),
+			errors.Is(err, cerrors.ErrLicenseUserAlreadyExist),
+			errors.Is(err, cerrors.ErrLicenseMaxUsersExceeded):
+			ctx.JSON(http.StatusBadRequest, resp)
+		default:
+			ctx.JSON(http.StatusInternalServerError, resp)
+		}
+		return
+	}
+	cSpan.End()
+
+	r.logger.GetLogger().Info("completed attaching license users")
+	resp.ToResponse(result.Code, result.Message, result.Data, nil, result.Count)
+	ctx.JSON(http.StatusCreated, resp)
+	return
+}
+
+// detachUsers detaches accounts from a license.
+//
+// @Summary 		API to detach users from license resource
+// @Description 	Detaching users from license resource
+// @Tags 			license
+// @Accept 			json
+// @Produce 		json
+// @Security        BearerAuth
+// @Param 			param 			    path 		license_attribute.LicenseCommonURI   	true 	"path_param"
+// @Param 			payload 			body 		licenses.LicenseUsersRequest 	        true 	"request"
+// @Success 		204 				{object} 	response.Response
+// @Failure 		400 				{object} 	response.Response
+// @Failure 		500 				{object} 	response.Response
+// @Router 			/tenants/{tenant_name}/licenses/{license_id}/users [delete]
+func (r *LicenseRouter) detachUsers(ctx *gin.Context) {
+	rootCtx, span := r.tracer.Start(ctx, ctx.Request.URL.Path, trace.WithAttributes(attribute.KeyValue{
+		Key:   constants.RequestIDField,
+		Value: attribute.StringValue(ctx.GetString(constants.RequestIDField)),
+	}))
+	defer span.End()
+
+	resp := response.NewResponse(ctx)
+	r.logger.WithCustomFields(
+		zap.String(constants.RequestIDField, ctx.GetString(constants.RequestIDField)),
+		zap.String(constants.ContextValueSubject, ctx.GetString(constants.ContextValueSubject)),
+	).Info("received new license user detachment request")
+
+	// serializer
+	r.logger.GetLogger().Info("validating license user detachment request")
+	var uriReq license_attribute.LicenseCommonURI
+	_, cSpan := r.tracer.Start(rootCtx, "serializer")
+	err := ctx.ShouldBindUri(&uriReq)
+	if err != nil {
+		cSpan.End()
+		r.logger.GetLogger().Error(err.Error())
+		resp.ToResponse(cerrors.ErrCodeMapper[cerrors.ErrGenericBadRequest], cerrors.ErrMessageMapper[cerrors.ErrGenericBadRequest], nil, nil, nil)
+		ctx.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	var bodyReq LicenseUsersRequest
+	err = ctx.ShouldBind(&bodyReq)
+	if err != nil {
+		cSpan.End()
+		r.logger.GetLogger().Error(err.Error())
+		resp.ToResponse(cerrors.ErrCodeMapper[cerrors.ErrGenericBadRequest], cerrors.ErrMessageMapper[cerrors.ErrGenericBadRequest], nil, nil, nil)
+		ctx.JSON(http.StatusBadRequest, resp)
+		return
+	}
+	cSpan.End()
+
+	// validation
+	_, cSpan = r.tracer.Start(rootCtx, "validation")
+	err = uriReq.Validate()
+	if err != nil {
+		cSpan.End()
+		r.logger.GetLogger().Error(err.Error())
+		resp.ToResponse(cerrors.ErrCodeMapper[err], cerrors.ErrMessageMapper[err], nil, nil, nil)
+		ctx.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	err = bodyReq.Validate()
+	if err != nil {
+		cSpan.End()
+		r.logger.GetLogger().Error(err.Error())
+		resp.ToResponse(cerrors.ErrCodeMapper[err], cerrors.ErrMessageMapper[err], nil, nil, nil)
+		ctx.JSON(http.StatusBadRequest, resp)
+		return
+	}
+	cSpan.End()
+
+	// handler
+	_, cSpan = r.tracer.Start(rootCtx, "handler")
+	result, err := r.svc.DetachUsers(ctx, bodyReq.ToLicenseUsersInput(rootCtx, r.tracer, uriReq))
+	if err != nil {
+		cSpan.End()
+		r.logger.GetLogger().Error(err.Error())
+		resp.ToResponse(result.Code, result.Message, result.Data, nil, nil)
+		switch {
+		case errors.Is(err, cerrors.ErrTenantNameIsInvalid),
+			errors.Is(err, cerrors.ErrLicenseIDIsInvalid),
+			errors.Is(err, cerrors.ErrLicenseUserIsNotAttached):
+			ctx.JSON(http.StatusBadRequest, resp)
+		default:
+			ctx.JSON(http.StatusInternalServerError, resp)
+		}
+		return
+	}
+	cSpan.End()
+
+	r.logger.GetLogger().Info("completed detaching license users")
+	resp.ToResponse(result.Code, result.Message, result.Data, nil, nil)
+	ctx.JSON(http.StatusNoContent, resp)
+	return
+}
+
+// listUsers lists the accounts attached to a license.
+//
+// @Summary 		API to list users of license resource
+// @Description 	Listing users attached to license resource
+// @Tags 			license
+// @Accept 			json
+// @Produce 		json
+// @Security        BearerAuth
+// @Param 			param 			    path 		license_attribute.LicenseCommonURI   	true 	"path_param"
+// @Param 			payload 			query 		licenses.LicenseUserListRequest 	        true 	"request"
+// @Success 		200 				{object} 	response.Response
+// @Failure 		400 				{object} 	response.Response
+// @Failure 		500 				{object} 	response.Response
+// @Router 			/tenants/{tenant_name}/licenses/{license_id}/users [get]
+func (r *LicenseRouter) listUsers(ctx *gin.Context) {
+	rootCtx, span := r.tracer.Start(ctx, ctx.Request.URL.Path, trace.WithAttributes(attribute.KeyValue{
+		Key:   constants.RequestIDField,
+		Value: attribute.StringValue(ctx.GetString(constants.RequestIDField)),
+	}))
+	defer span.End()
+
+	resp := response.NewResponse(ctx)
+	r.logger.WithCustomFields(
+		zap.String(constants.RequestIDField, ctx.GetString(constants.RequestIDField)),
+		zap.String(constants.ContextValueSubject, ctx.GetString(constants.ContextValueSubject)),
+	).Info("received new license user list request")
+
+	// serializer
+	r.logger.GetLogger().Info("validating license user list request")
+	var uriReq license_attribute.LicenseCommonURI
+	_, cSpan := r.tracer.Start(rootCtx, "serializer")
+	err := ctx.ShouldBindUri(&uriReq)
+	if err != nil {
+		cSpan.End()
+		r.logger.GetLogger().Error(err.Error())
+		resp.ToResponse(cerrors.ErrCodeMapper[cerrors.ErrGenericBadRequest], cerrors.ErrMessageMapper[cerrors.ErrGenericBadRequest], nil, nil, nil)
+		ctx.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	var bodyReq LicenseUserListRequest
+	err = ctx.ShouldBind(&bodyReq)
+	if err != nil {
+		cSpan.End()
+		r.logger.GetLogger().Error(err.Error())
+		resp.ToResponse(cerrors.ErrCodeMapper[cerrors.ErrGenericBadRequest], cerrors.ErrMessageMapper[cerrors.ErrGenericBadRequest], nil, nil, nil)
+		ctx.JSON(http.StatusBadRequest, resp)
+		return
+	}
+	cSpan.End()
+
+	// validation
+	_, cSpan = r.tracer.Start(rootCtx, "validation")
+	err = uriReq.Validate()
+	if err != nil {
+		cSpan.End()
+		r.logger.GetLogger().Error(err.Error())
+		resp.ToResponse(cerrors.ErrCodeMapper[err], cerrors.ErrMessageMapper[err], nil, nil, nil)
+		ctx.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	err = bodyReq.Validate()
+	if err != nil {
+		cSpan.End()
+		r.logger.GetLogger().Error(err.Error())
+		resp.ToResponse(cerrors.ErrCodeMapper[err], cerrors.ErrMessageMapper[err], nil, nil, nil)
+		ctx.JSON(http.StatusBadRequest, resp)
+		return
+	}
+	cSpan.End()
+
+	// handler
+	_, cSpan = r.tracer.Start(rootCtx, "handler")
+	result, err := r.svc.ListUsers(ctx, bodyReq.ToLicenseUserListInput(rootCtx, r.tracer, uriReq))
+	if err != nil {
+		cSpan.End()
+		r.logger.GetLogger().Error(err.Error())
+		resp.ToResponse(result.Code, result.Message, result.Data, nil, nil)
+		switch {
+		case errors.Is(err, cerrors.ErrTenantNameIsInvalid),
+			errors.Is(err, cerrors.ErrLicenseIDIsInvalid):
+			ctx.JSON(http.StatusBadRequest, resp)
+		default:
+			ctx.JSON(http.StatusInternalServerError, resp)
+		}
+		return
+	}
+	cSpan.End()
+
+	r.logger.GetLogger().Info("completed listing license users")
+	resp.ToResponse(result.Code, result.Message, result.Data, nil, result.Count)
+	ctx.JSON(http.StatusOK, resp)
+	return
+}
+
+// attachEntitlements attaches entitlements to a license, on top of the entitlements of its policy.
+//
+// @Summary 		API to attach entitlements to license resource
+// @Description 	Attaching entitlements to license resource. Entitlements already attached to the license or its policy are rejected
+// @Tags 			license
+// @Accept 			json
+// @Produce 		json
+// @Security        BearerAuth
+// @Param 			param 			    path 		license_attribute.LicenseCommonURI   	true 	"path_param"
+// @Param 			payload 			body 		licenses.LicenseEntitlementAttachmentRequest 	true 	"request"
+// @Success 		201 				{object} 	response.Response
+// @Failure 		400 				{object} 	response.Response
+// @Failure 		500 				{object} 	response.Response
+// @Router 			/tenants/{tenant_name}/licenses/{license_id}/entitlements [post]
+func (r *LicenseRouter) attachEntitlements(ctx *gin.Context) {
+	rootCtx, span := r.tracer.Start(ctx, ctx.Request.URL.Path, trace.WithAttributes(attribute.KeyValue{
+		Key:   constants.RequestIDField,
+		Value: attribute.StringValue(ctx.GetString(constants.RequestIDField)),
+	}))
+	defer span.End()
+
+	resp := response.NewResponse(ctx)
+	r.logger.WithCustomFields(
+		zap.String(constants.RequestIDField, ctx.GetString(constants.RequestIDField)),
+		zap.String(constants.ContextValueSubject, ctx.GetString(constants.ContextValueSubject)),
+	).Info("received new license entitlement attachment request")
+
+	// serializer
+	r.logger.GetLogger().Info("validating license entitlement attachment request")
+	var uriReq license_attribute.LicenseCommonURI
+	_, cSpan := r.tracer.Start(rootCtx, "serializer")
+	err := ctx.ShouldBindUri(&uriReq)
+	if err != nil {
+		cSpan.End()
+		r.logger.GetLogger().Error(err.Error())
+		resp.ToResponse(cerrors.ErrCodeMapper[cerrors.ErrGenericBadRequest], cerrors.ErrMessageMapper[cerrors.ErrGenericBadRequest], nil, nil, nil)
+		ctx.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	var bodyReq LicenseEntitlementAttachmentRequest
+	err = ctx.ShouldBind(&bodyReq)
+	if err != nil {
+		cSpan.End()
+		r.logger.GetLogger().Error(err.Error())
+		resp.ToResponse(cerrors.ErrCodeMapper[cerrors.ErrGenericBadRequest], cerrors.ErrMessageMapper[cerrors.ErrGenericBadRequest], nil, nil, nil)
+		ctx.JSON(http.StatusBadRequest, resp)
+		return
+	}
+	cSpan.End()
+
+	// validation
+	_, cSpan = r.tracer.Start(rootCtx, "validation")
+	err = uriReq.Validate()
+	if err != nil {
+		cSpan.End()
+		r.logger.GetLogger().Error(err.Error())
+		resp.ToResponse(cerrors.ErrCodeMapper[err], cerrors.ErrMessageMapper[err], nil, nil, nil)
+		ctx.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	err = bodyReq.Validate()
+	if err != nil {
+		cSpan.End()
+		r.logger.GetLogger().Error(err.Error())
+		resp.ToResponse(cerrors.ErrCodeMapper[err], cerrors.ErrMessageMapper[err], nil, nil, nil)
+		ctx.JSON(http.StatusBadRequest, resp)
+		return
+	}
+	cSpan.End()
+
+	// handler
+	_, cSpan = r.tracer.Start(rootCtx, "handler")
+	result, err := r.svc.AttachEntitlements(ctx, bodyReq.ToLicenseEntitlementAttachmentInput(rootCtx, r.tracer, uriReq))
+	if err != nil {
+		cSpan.End()
+		r.logger.GetLogger().Error(err.Error())
+		resp.ToResponse(result.Code, result.Message, result.Data, nil, nil)
+		switch {
+		case errors.Is(err, cerrors.ErrTenantNameIsInvalid),
+			errors.Is(err, cerrors.ErrLicenseIDIsInvalid),
+			errors.Is(err, cerrors.ErrEntitlementIDIsInvalid),
+			errors.Is(err, cerrors.ErrLicenseEntitlementAlreadyExist):
+			ctx.JSON(http.StatusBadRequest, resp)
+		default:
+			ctx.JSON(http.StatusInternalServerError, resp)
+		}
+		return
+	}
+	cSpan.End()
+
+	r.logger.GetLogger().Info("completed attaching license entitlements")
+	resp.ToResponse(result.Code, result.Message, result.Data, nil, result.Count)
+	ctx.JSON(http.StatusCreated, resp)
+	return
+}
+
+// detachEntitlements detaches entitlements from a license.
+//
+// @Summary 		API to detach entitlements from license resource
+// @Description 	Detaching entitlements from license resource. Entitlements inherited from the policy must be detached from the policy
+// @Tags 			license
+// @Accept 			json
+// @Produce 		json
+// @Security        BearerAuth
+// @Param 			param 			    path 		license_attribute.LicenseCommonURI   	true 	"path_param"
+// @Param 			payload 			body 		licenses.LicenseEntitlementDetachmentRequest 	true 	"request"
+// @Success 		204 				{object} 	response.Response
+// @Failure 		400 				{object} 	response.Response
+// @Failure 		500 				{object} 	response.Response
+// @Router 			/tenants/{tenant_name}/licenses/{license_id}/entitlements [delete]
+func (r *LicenseRouter) detachEntitlements(ctx *gin.Context) {
+	rootCtx, span := r.tracer.Start(ctx, ctx.Request.URL.Path, trace.WithAttributes(attribute.KeyValue{
+		Key:   constants.RequestIDField,
+		Value: attribute.StringValue(ctx.GetString(constants.RequestIDField)),
+	}))
+	defer span.End()
+
+	resp := response.NewResponse(ctx)
+	r.logger.WithCustomFields(
+		zap.String(constants.RequestIDField, ctx.GetString(constants.RequestIDField)),
+		zap.String(constants.ContextValueSubject, ctx.GetString(constants.ContextValueSubject)),
+	).Info("received new license entitlement detachment request")
+
+	// serializer
+	r.logger.GetLogger().Info("validating license entitlement detachment request")
+	var uriReq license_attribute.LicenseCommonURI
+	_, cSpan := r.tracer.Start(rootCtx, "serializer")
+	err := ctx.ShouldBindUri(&uriReq)
+	if err != nil {
+		cSpan.End()
+		r.logger.GetLogger().Error(err.Error())
+		resp.ToResponse(cerrors.ErrCodeMapper[cerrors.ErrGenericBadRequest], cerrors.ErrMessageMapper[cerrors.ErrGenericBadRequest], nil, nil, nil)
+		ctx.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	var bodyReq LicenseEntitlementDetachmentRequest
+	err = ctx.ShouldBind(&bodyReq)
+	if err != nil {
+		cSpan.End()
+		r.logger.GetLogger().Error(err.Error())
+		resp.ToResponse(cerrors.ErrCodeMapper[cerrors.ErrGenericBadRequest], cerrors.ErrMessageMapper[cerrors.ErrGenericBadRequest], nil, nil, nil)
+		ctx.JSON(http.StatusBadRequest, resp)
+		return
+	}
+	cSpan.End()
+
+	// validation
+	_, cSpan = r.tracer.Start(rootCtx, "validation")
+	err = uriReq.Validate()
+	if err != nil {
+		cSpan.End()
+		r.logger.GetLogger().Error(err.Error())
+		resp.ToResponse(cerrors.ErrCodeMapper[err], cerrors.ErrMessageMapper[err], nil, nil, nil)
+		ctx.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	err = bodyReq.Validate()
+	if err != nil {
+		cSpan.End()
+		r.logger.GetLogger().Error(err.Error())
+		resp.ToResponse(cerrors.ErrCodeMapper[err], cerrors.ErrMessageMapper[err], nil, nil, nil)
+		ctx.JSON(http.StatusBadRequest, resp)
+		return
+	}
+	cSpan.End()
+
+	// handler
+	_, cSpan = r.tracer.Start(rootCtx, "handler")
+	result, err := r.svc.DetachEntitlements(ctx, bodyReq.ToLicenseEntitlementDetachmentInput(rootCtx, r.tracer, uriReq))
+	if err != nil {
+		cSpan.End()
+		r.logger.GetLogger().Error(err.Error())
+		resp.ToResponse(result.Code, result.Message, result.Data, nil, nil)
+		switch {
+		case errors.Is(err, cerrors.ErrTenantNameIsInvalid),
+			errors.Is(err, cerrors.ErrLicenseIDIsInvalid),
+			errors.Is(err, cerrors.ErrLicenseEntitlementIsNotAttached):
+			ctx.JSON(http.StatusBadRequest, resp)
+		default:
+			ctx.JSON(http.StatusInternalServerError, resp)
+		}
+		return
+	}
+	cSpan.End()
+
+	r.logger.GetLogger().Info("completed detaching license entitlements")
+	resp.ToResponse(result.Code, result.Message, result.Data, nil, nil)
+	ctx.JSON(http.StatusNoContent, resp)
+	return
+}
+
+// listEntitlements lists the merged entitlements of a license, from both its policy and the license itself.
+//
+// @Summary 		API to list entitlements of license resource
+// @Description 	Listing entitlements of license resource, merged from its policy and the license itself
+// @Tags 			license
+// @Accept 			json
+// @Produce 		json
+// @Security        BearerAuth
+// @Param 			param 			    path 		license_attribute.LicenseCommonURI   	true 	"path_param"
+// @Param 			payload 			query 		licenses.LicenseEntitlementListRequest 	true 	"request"
+// @Success 		200 				{object} 	response.Response
+// @Failure 		400 				{object} 	response.Response
+// @Failure 		500 				{object} 	response.Response
+// @Router 			/tenants/{tenant_name}/licenses/{license_id}/entitlements [get]
+func (r *LicenseRouter) listEntitlements(ctx *gin.Context) {
+	rootCtx, span := r.tracer.Start(ctx, ctx.Request.URL.Path, trace.WithAttributes(attribute.KeyValue{
+		Key:   constants.RequestIDField,
+		Value: attribute.StringValue(ctx.GetString(constants.RequestIDField)),
+	}))
+	defer span.End()
+
+	resp := response.NewResponse(ctx)
+	r.logger.WithCustomFields(
+		zap.String(constants.RequestIDField, ctx.GetString(constants.RequestIDField)),
+		zap.String(constants.ContextValueSubject, ctx.GetString(constants.ContextValueSubject)),
+	).Info("received new license entitlement list request")
+
+	// serializer
+	r.logger.GetLogger().Info("validating license entitlement list request")
+	var uriReq license_attribute.LicenseCommonURI
+	_, cSpan := r.tracer.Start(rootCtx, "serializer")
+	err := ctx.ShouldBindUri(&uriReq)
+	if err != nil {
+		cSpan.End()
+		r.logger.GetLogger().Error(err.Error())
+		resp.ToResponse(cerrors.ErrCodeMapper[cerrors.ErrGenericBadRequest], cerrors.ErrMessageMapper[cerrors.ErrGenericBadRequest], nil, nil, nil)
+		ctx.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	var bodyReq LicenseEntitlementListRequest
+	err = ctx.ShouldBind(&bodyReq)
+	if err != nil {
+		cSpan.End()
+		r.logger.GetLogger().Error(err.Error())
+		resp.ToResponse(cerrors.ErrCodeMapper[cerrors.ErrGenericBadRequest], cerrors.ErrMessageMapper[cerrors.ErrGenericBadRequest], nil, nil, nil)
+		ctx.JSON(http.StatusBadRequest, resp)
+		return
+	}
+	cSpan.End()
+
+	// validation
+	_, cSpan = r.tracer.Start(rootCtx, "validation")
+	err = uriReq.Validate()
+	if err != nil {
+		cSpan.End()
+		r.logger.GetLogger().Error(err.Error())
+		resp.ToResponse(cerrors.ErrCodeMapper[err], cerrors.ErrMessageMapper[err], nil, nil, nil)
+		ctx.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	err = bodyReq.Validate()
+	if err != nil {
+		cSpan.End()
+		r.logger.GetLogger().Error(err.Error())
+		resp.ToResponse(cerrors.ErrCodeMapper[err], cerrors.ErrMessageMapper[err], nil, nil, nil)
+		ctx.JSON(http.StatusBadRequest, resp)
+		return
+	}
+	cSpan.End()
+
+	// handler
+	_, cSpan = r.tracer.Start(rootCtx, "handler")
+	result, err := r.svc.ListEntitlements(ctx, bodyReq.ToLicenseEntitlementListInput(rootCtx, r.tracer, uriReq))
+	if err != nil {
+		cSpan.End()
+		r.logger.GetLogger().Error(err.Error())
+		resp.ToResponse(result.Code, result.Message, result.Data, nil, nil)
+		switch {
+		case errors.Is(err, cerrors.ErrTenantNameIsInvalid),
+			errors.Is(err, cerrors.ErrLicenseIDIsInvalid):
+			ctx.JSON(http.StatusBadRequest, resp)
+		default:
+			ctx.JSON(http.StatusInternalServerError, resp)
+		}
+		return
+	}
+	cSpan.End()
+
+	r.logger.GetLogger().Info("completed listing license entitlements")
+	resp.ToResponse(result.Code, result.Message, result.Data, nil, result.Count)
+	ctx.JSON(http.StatusOK, resp)
+	return
+}
+
+// tokens generates a new license token resource. The token authenticates the requests sent with the X-API-Key
+// header, which can only validate and check out the license, and activate, deactivate and ping its machines.
+// It expires at `expiry` and can activate at most `max_activations` machines, if set.
+// Only the hash of the token is stored, so the token is only returned in this response.
+//
+// @Summary 		API to generate license token resource
+// @Description 	Generate license token
+// @Tags 			license
+// @Accept 			json
+// @Produce 		json
+// @Security        BearerAuth
+// @Param 			param 			    path 		license_attribute.LicenseCommonURI   	true 	"path_param"
+// @Param 			payload 			body 		licenses.LicenseTokenRequest 	        true 	"request"
+// @Success 		201 				{object} 	response.Response
+// @Failure 		400 				{object} 	response.Response
+// @Failure 		500 				{object} 	response.Response
+// @Router 			/tenants/{tenant_name}/licenses/{license_id}/tokens [post]
+func (r *LicenseRouter) tokens(ctx *gin.Context) {
+	rootCtx, span := r.tracer.Start(ctx, ctx.Request.URL.Path, trace.WithAttributes(attribute.KeyValue{
+		Key:   constants.RequestIDField,
+		Value: attribute.StringValue(ctx.GetString(constants.RequestIDField)),
+	}))
+	defer span.End()
+
+	resp := response.NewResponse(ctx)
+	r.logger.WithCustomFields(
+		zap.String(constants.RequestIDField, ctx.GetString(constants.RequestIDField)),
+		zap.String(constants.ContextValueSubject, ctx.GetString(constants.ContextValueSubject)),
+	).Info("received new license token creation request")
+
+	// serializer
+	r.logger.GetLogger().Info("validating license token creation request")
+	var uriReq license_attribute.LicenseCommonURI
+	_, cSpan := r.tracer.Start(rootCtx, "serializer")
+	err := ctx.ShouldBindUri(&uriReq)
+	if err != nil {
+		cSpan.End()
+		r.logger.GetLogger().Error(err.Error())
+		resp.ToResponse(cerrors.ErrCodeMapper[cerrors.ErrGenericBadRequest], cerrors.ErrMessageMapper[cerrors.ErrGenericBadRequest], nil, nil, nil)
+		ctx.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	var bodyReq LicenseTokenRequest
+	err = ctx.ShouldBind(&bodyReq)
+	if err != nil {
+		cSpan.End()
+		r.logger.GetLogger().Error(err.Error())
+		resp.ToResponse(cerrors.ErrCodeMapper[cerrors.ErrGenericBadRequest], cerrors.ErrMessageMapper[cerrors.ErrGenericBadRequest], nil, nil, nil)
+		ctx.JSON(http.StatusBadRequest, resp)
+		return
+	}
+	cSpan.End()
+
+	// validation
+	_, cSpan = r.tracer.Start(rootCtx, "validation")
+	err = uriReq.Validate()
+	if err != nil {
+		cSpan.End()
+		r.logger.GetLogger().Error(err.Error())
+		resp.ToResponse(cerrors.ErrCodeMapper[err], cerrors.ErrMessageMapper[err], nil, nil, nil)
+		ctx.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	err = bodyReq.Validate()
+	if err != nil {
+		cSpan.End()
+		r.logger.GetLogger().Error(err.Error())
+		resp.ToResponse(cerrors.ErrCodeMapper[err], cerrors.ErrMessageMapper[err], nil, nil, nil)
+		ctx.JSON(http.StatusBadRequest, resp)
+		return
+	}
+	cSpan.End()
+
+	// handler
+	_, cSpan = r.tracer.Start(rootCtx, "handler")
+	result, err := r.svc.Tokens(ctx, bodyReq.ToLicenseTokensInput(rootCtx, r.tracer, uriReq))
+	if err != nil {
+		cSpan.End()
+		r.logger.GetLogger().Error(err.Error())
+		resp.ToResponse(result.Code, result.Message, result.Data, nil, nil)
+		switch {
+		case errors.Is(err, cerrors.ErrTenantNameIsInvalid),
+			errors.Is(err, cerrors.ErrLicenseIDIsInvalid):
+			ctx.JSON(http.StatusBadRequest, resp)
+		default:
+			ctx.JSON(http.StatusInternalServerError, resp)
+		}
+		return
+	}
+	cSpan.End()
+
+	r.logger.GetLogger().Info("completed creating license token")
+	resp.ToResponse(result.Code, result.Message, result.Data, nil, nil)
+	ctx.JSON(http.StatusCreated, resp)
+	return
+}
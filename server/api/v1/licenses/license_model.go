@@ -217,10 +217,11 @@ func (req *LicenseDeletionRequest) ToLicenseDeletionInput(ctx context.Context, t
 }
 
 type LicenseActionsRequest struct {
-	LicenseKey *string `json:"license_key"`
-	Nonce      *int    `json:"nonce"`
-	Increment  *int    `json:"increment"`
-	Decrement  *int    `json:"decrement"`
+	LicenseKey *string                                   `json:"license_key"`
+	Nonce      *int                                      `json:"nonce"`
+	Increment  *int                                      `json:"increment"`
+	Decrement  *int                                      `json:"decrement"`
+	Scope      *license_attribute.LicenseValidationScope `json:"scope"`
 }
 
 func (req *LicenseActionsRequest) Validate() error {
@@ -244,6 +245,12 @@ func (req *LicenseActionsRequest) Validate() error {
 		req.Decrement = utils.RefPointer(1)
 	}
 
+	if req.Scope != nil {
+		if err := req.Scope.Validate(); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -256,5 +263,161 @@ func (req *LicenseActionsRequest) ToLicenseActionsInput(ctx context.Context, tra
 		Nonce:            req.Nonce,
 		Increment:        req.Increment,
 		Decrement:        req.Decrement,
+		Scope:            req.Scope,
+	}
+}
+
+type LicenseUsersRequest struct {
+	Usernames []string `json:"usernames" validate:"required" example:"test"`
+}
+
+func (req *LicenseUsersRequest) Validate() error {
+	if len(req.Usernames) == 0 {
+		return cerrors.ErrLicenseUsersAreEmpty
+	}
+
+	// Drop duplicated usernames
+	seen := make(map[string]bool, len(req.Usernames))
+	usernames := make([]string, 0, len(req.Usernames))
+	for _, username := range req.Usernames {
+		if username == "" {
+			return cerrors.ErrLicenseUsersAreEmpty
+		}
+		if !seen[username] {
+			seen[username] = true
+			usernames = append(usernames, username)
+		}
+	}
+	req.Usernames = usernames
+
+	return nil
+}
+
+func (req *LicenseUsersRequest) ToLicenseUsersInput(ctx context.Context, tracer trace.Tracer, licenseURI license_attribute.LicenseCommonURI) *models.LicenseUsersInput {
+	return &models.LicenseUsersInput{
+		TracerCtx:        ctx,
+		Tracer:           tracer,
+		LicenseCommonURI: licenseURI,
+		Usernames:        req.Usernames,
+	}
+}
+
+type LicenseUserListRequest struct {
+	constants.QueryCommonParam
+}
+
+func (req *LicenseUserListRequest) Validate() error {
+	req.QueryCommonParam.Validate()
+	return nil
+}
+
+func (req *LicenseUserListRequest) ToLicenseUserListInput(ctx context.Context, tracer trace.Tracer, licenseURI license_attribute.LicenseCommonURI) *models.LicenseUserListInput {
+	return &models.LicenseUserListInput{
+		TracerCtx:        ctx,
+		Tracer:           tracer,
+		LicenseCommonURI: licenseURI,
+		QueryCommonParam: req.QueryCommonParam,
+	}
+}
+
+type LicenseEntitlementAttachmentRequest struct {
+	EntitlementID []string `json:"entitlement_id" validate:"required" example:"test"`
+}
+
+func (req *LicenseEntitlementAttachmentRequest) Validate() error {
+	if len(req.EntitlementID) == 0 {
+		return cerrors.ErrEntitlementIDIsEmpty
+	}
+	for _, entitlement := range req.EntitlementID {
+		if _, err := uuid.Parse(entitlement); err != nil {
+			return cerrors.ErrEntitlementIDIsInvalid
+		}
+	}
+	return nil
+}
+
+func (req *LicenseEntitlementAttachmentRequest) ToLicenseEntitlementAttachmentInput(ctx context.Context, tracer trace.Tracer, licenseURI license_attribute.LicenseCommonURI) *models.LicenseEntitlementAttachmentInput {
+	return &models.LicenseEntitlementAttachmentInput{
+		TracerCtx:        ctx,
+		Tracer:           tracer,
+		LicenseCommonURI: licenseURI,
+		EntitlementID:    req.EntitlementID,
+	}
+}
+
+type LicenseEntitlementDetachmentRequest struct {
+	ID []string `json:"id" validate:"required" example:"test"`
+}
+
+func (req *LicenseEntitlementDetachmentRequest) Validate() error {
+	if len(req.ID) == 0 {
+		return cerrors.ErrEntitlementIDIsEmpty
+	}
+	for _, entitlement := range req.ID {
+		if _, err := uuid.Parse(entitlement); err != nil {
+			return cerrors.ErrEntitlementIDIsInvalid
+		}
+	}
+	return nil
+}
+
+func (req *LicenseEntitlementDetachmentRequest) ToLicenseEntitlementDetachmentInput(ctx context.Context, tracer trace.Tracer, licenseURI license_attribute.LicenseCommonURI) *models.LicenseEntitlementDetachmentInput {
+	return &models.LicenseEntitlementDetachmentInput{
+		TracerCtx:        ctx,
+		Tracer:           tracer,
+		LicenseCommonURI: licenseURI,
+		ID:               req.ID,
+	}
+}
+
+type LicenseEntitlementListRequest struct {
+	constants.QueryCommonParam
+}
+
+func (req *LicenseEntitlementListRequest) Validate() error {
+	req.QueryCommonParam.Validate()
+	return nil
+}
+
+func (req *LicenseEntitlementListRequest) ToLicenseEntitlementListInput(ctx context.Context, tracer trace.Tracer, licenseURI license_attribute.LicenseCommonURI) *models.LicenseEntitlementListInput {
+	return &models.LicenseEntitlementListInput{
+		TracerCtx:        ctx,
+		Tracer:           tracer,
+		LicenseCommonURI: licenseURI,
+		QueryCommonParam: req.QueryCommonParam,
+	}
+}
+
+type LicenseTokenRequest struct {
+	Name           *string `json:"name" validate:"optional" example:"installer"`
+	Expiry         *string `json:"expiry" validate:"optional" example:"2030-01-01T00:00:00Z"`
+	MaxActivations *int    `json:"max_activations" validate:"optional" example:"1"`
+}
+
+func (req *LicenseTokenRequest) Validate() error {
+	if req.Expiry != nil {
+		exp, err := time.Parse(time.RFC3339, utils.DerefPointer(req.Expiry))
+		if err != nil {
+			return cerrors.ErrLicenseTokenExpiryIsInvalid
+		}
+		if !exp.After(time.Now()) {
+			return cerrors.ErrLicenseTokenExpiryIsInvalid
+		}
+	}
+
+	if req.MaxActivations != nil && utils.DerefPointer(req.MaxActivations) < 0 {
+		return cerrors.ErrLicenseTokenMaxActivationsIsInvalid
+	}
+	return nil
+}
+
+func (req *LicenseTokenRequest) ToLicenseTokensInput(ctx context.Context, tracer trace.Tracer, licenseURI license_attribute.LicenseCommonURI) *models.LicenseTokensInput {
+	return &models.LicenseTokensInput{
+		TracerCtx:        ctx,
+		Tracer:           tracer,
+		LicenseCommonURI: licenseURI,
+		Name:             req.Name,
+		Expiry:           req.Expiry,
+		MaxActivations:   req.MaxActivations,
 	}
 }
@@ -43,7 +43,7 @@ func (r *MachineRouter) Routes(engine *gin.RouterGroup, path string) {
 		routes.GET("/:machine_id", middlewares.JWTValidationMW(), middlewares.PermissionValidationMW(permissions.MachineRead), r.retrieve)
 		routes.PATCH("/:machine_id", middlewares.JWTValidationMW(), middlewares.PermissionValidationMW(permissions.MachineRead), r.update)
 		routes.DELETE("/:machine_id", middlewares.JWTValidationMW(), middlewares.PermissionValidationMW(permissions.MachineDelete), r.deactivate)
-		routes.POST("/:machine_id/actions/:machine_action", middlewares.JWTValidationMW(), middlewares.MachineActionPermissionValidationMW(), r.action)
+		routes.POST("/:machine_id/actions/:machine_action", middlewares.JWTValidationMW(), middlewares.MachineActionPermissionValidationMW(), middlewares.MachineRateLimitMW(), r.action)
 	}
 }
 
@@ -123,8 +123,13 @@ func (r *MachineRouter) create(ctx *gin.Context) {
 			errors.Is(err, cerrors.ErrLicenseIsSuspended),
 			errors.Is(err, cerrors.ErrLicenseIsBanned),
 			errors.Is(err, cerrors.ErrLicenseHasExpired),
-			errors.Is(err, cerrors.ErrLicenseMaxMachineExceeded):
+			errors.Is(err, cerrors.ErrLicenseMaxMachineExceeded),
+			errors.Is(err, cerrors.ErrMachineLicenseTokenActivationsExceeded),
+			errors.Is(err, cerrors.ErrLicenseIsNodeLocked):
 			ctx.JSON(http.StatusBadRequest, resp)
+		case errors.Is(err, cerrors.ErrPolicyIsProtected),
+			errors.Is(err, cerrors.ErrLicenseTokenScopeIsInvalid):
+			ctx.JSON(http.StatusForbidden, resp)
 		default:
 			ctx.JSON(http.StatusInternalServerError, resp)
 		}
@@ -214,8 +219,13 @@ func (r *MachineRouter) update(ctx *gin.Context) {
 			errors.Is(err, cerrors.ErrMachineFingerprintAssociatedWithLicense),
 			errors.Is(err, cerrors.ErrLicenseIsSuspended),
 			errors.Is(err, cerrors.ErrLicenseIsBanned),
-			errors.Is(err, cerrors.ErrLicenseHasExpired):
+			errors.Is(err, cerrors.ErrLicenseHasExpired),
+			errors.Is(err, cerrors.ErrLicenseMaxMachineExceeded),
+			errors.Is(err, cerrors.ErrLicenseIsNodeLocked):
 			ctx.JSON(http.StatusBadRequest, resp)
+		case errors.Is(err, cerrors.ErrPolicyIsProtected),
+			errors.Is(err, cerrors.ErrLicenseTokenScopeIsInvalid):
+			ctx.JSON(http.StatusForbidden, resp)
 		default:
 			ctx.JSON(http.StatusInternalServerError, resp)
 		}
@@ -360,7 +370,15 @@ func (r *MachineRouter) deactivate(ctx *gin.Context) {
 		cSpan.End()
 		r.logger.GetLogger().Error(err.Error())
 		resp.ToResponse(result.Code, result.Message, result.Data, nil, nil)
-		ctx.JSON(http.StatusInternalServerError, resp)
+		switch {
+		case errors.Is(err, cerrors.ErrTenantNameIsInvalid),
+			errors.Is(err, cerrors.ErrMachineIDIsInvalid):
+			ctx.JSON(http.StatusBadRequest, resp)
+		case errors.Is(err, cerrors.ErrPolicyIsProtected):
+			ctx.JSON(http.StatusForbidden, resp)
+		default:
+			ctx.JSON(http.StatusInternalServerError, resp)
+		}
 		return
 	}
 	cSpan.End()
@@ -469,6 +487,7 @@ func (r *MachineRouter) list(ctx *gin.Context) {
 // @Param 			payload 			query 		machine_attribute.MachineActionsQueryParam 	true 	"request"
 // @Success 		200 				{object} 	response.Response
 // @Failure 		400 				{object} 	response.Response
+// @Failure 		429 				{object} 	response.Response
 // @Failure 		500 				{object} 	response.Response
 // @Router 			/tenants/{tenant_name}/machines/{machine_id}/actions/{action} [post]
 func (r *MachineRouter) action(ctx *gin.Context) {
@@ -535,7 +554,11 @@ func (r *MachineRouter) action(ctx *gin.Context) {
 		r.logger.GetLogger().Error(err.Error())
 		resp.ToResponse(result.Code, result.Message, result.Data, nil, nil)
 		switch {
-		case errors.Is(err, cerrors.ErrProductIDIsInvalid):
+		case errors.Is(err, cerrors.ErrProductIDIsInvalid),
+			errors.Is(err, cerrors.ErrMachineIDIsInvalid),
+			errors.Is(err, cerrors.ErrMachineHeartbeatIsDead),
+			errors.Is(err, cerrors.ErrLicenseMaxMachineExceeded),
+			errors.Is(err, cerrors.ErrLicenseIsNodeLocked):
 			ctx.JSON(http.StatusBadRequest, resp)
 		default:
 			ctx.JSON(http.StatusInternalServerError, resp)
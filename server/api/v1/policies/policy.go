@@ -46,6 +46,10 @@ func (r *PolicyRouter) Routes(engine *gin.RouterGroup, path string) {
 		routes.POST("/:policy_id/entitlements", middlewares.JWTValidationMW(), middlewares.PermissionValidationMW(permissions.PolicyEntitlementsAttach), r.attach)
 		routes.DELETE("/:policy_id/entitlements", middlewares.JWTValidationMW(), middlewares.PermissionValidationMW(permissions.PolicyEntitlementsDetach), r.detach)
 		routes.GET("/:policy_id/entitlements", middlewares.JWTValidationMW(), middlewares.PermissionValidationMW(permissions.PolicyRead), r.listEntitlement)
+		routes.POST("/:policy_id/keys", middlewares.JWTValidationMW(), middlewares.PermissionValidationMW(permissions.PolicyUpdate), r.createKeys)
+		routes.GET("/:policy_id/keys", middlewares.JWTValidationMW(), middlewares.PermissionValidationMW(permissions.PolicyRead), r.listKeys)
+		routes.POST("/:policy_id/actions/rotate-keys", middlewares.JWTValidationMW(), middlewares.PermissionValidationMW(permissions.PolicyKeysRotate), r.rotateKeys)
+		routes.GET("/:policy_id/signing-keys", middlewares.JWTValidationMW(), middlewares.PermissionValidationMW(permissions.PolicyRead), r.listSigningKeys)
 	}
 }
 
@@ -131,8 +135,11 @@ func (r *PolicyRouter) create(ctx *gin.Context) {
 		switch {
 		case errors.Is(err, cerrors.ErrTenantNameIsInvalid),
 			errors.Is(err, cerrors.ErrProductIDIsInvalid),
-			errors.Is(err, cerrors.ErrPolicySchemeIsInvalid):
+			errors.Is(err, cerrors.ErrPolicySchemeIsInvalid),
+			errors.Is(err, cerrors.ErrPolicySignerBackendIsInvalid):
 			ctx.JSON(http.StatusBadRequest, resp)
+		case errors.Is(err, cerrors.ErrProductTokenScopeIsInvalid):
+			ctx.JSON(http.StatusForbidden, resp)
 		default:
 			ctx.JSON(http.StatusInternalServerError, resp)
 		}
@@ -228,8 +235,12 @@ func (r *PolicyRouter) update(ctx *gin.Context) {
 		switch {
 		case errors.Is(err, cerrors.ErrTenantNameIsInvalid),
 			errors.Is(err, cerrors.ErrProductIDIsInvalid),
-			errors.Is(err, cerrors.ErrPolicySchemeIsInvalid):
+			errors.Is(err, cerrors.ErrPolicySchemeIsInvalid),
+			errors.Is(err, cerrors.ErrPolicySignerBackendIsInvalid),
+			errors.Is(err, cerrors.ErrPolicySignedKeysWithPool):
 			ctx.JSON(http.StatusBadRequest, resp)
+		case errors.Is(err, cerrors.ErrPolicyIsProtected):
+			ctx.JSON(http.StatusForbidden, resp)
 		default:
 			ctx.JSON(http.StatusInternalServerError, resp)
 		}
@@ -378,7 +389,15 @@ func (r *PolicyRouter) delete(ctx *gin.Context) {
 		cSpan.End()
 		r.logger.GetLogger().Error(err.Error())
 		resp.ToResponse(result.Code, result.Message, result.Data, nil, nil)
-		ctx.JSON(http.StatusInternalServerError, resp)
+		switch {
+		case errors.Is(err, cerrors.ErrTenantNameIsInvalid),
+			errors.Is(err, cerrors.ErrPolicyIDIsInvalid):
+			ctx.JSON(http.StatusBadRequest, resp)
+		case errors.Is(err, cerrors.ErrPolicyIsProtected):
+			ctx.JSON(http.StatusForbidden, resp)
+		default:
+			ctx.JSON(http.StatusInternalServerError, resp)
+		}
 		return
 	}
 	cSpan.End()
@@ -559,6 +578,8 @@ func (r *PolicyRouter) attach(ctx *gin.Context) {
 			errors.Is(err, cerrors.ErrEntitlementIDIsInvalid),
 			errors.Is(err, cerrors.ErrPolicyEntitlementAlreadyExist):
 			ctx.JSON(http.StatusBadRequest, resp)
+		case errors.Is(err, cerrors.ErrPolicyIsProtected):
+			ctx.JSON(http.StatusForbidden, resp)
 		default:
 			ctx.JSON(http.StatusInternalServerError, resp)
 		}
@@ -655,6 +676,8 @@ func (r *PolicyRouter) detach(ctx *gin.Context) {
 			errors.Is(err, cerrors.ErrPolicyIDIsInvalid),
 			errors.Is(err, cerrors.ErrEntitlementIDIsInvalid):
 			ctx.JSON(http.StatusBadRequest, resp)
+		case errors.Is(err, cerrors.ErrPolicyIsProtected):
+			ctx.JSON(http.StatusForbidden, resp)
 		default:
 			ctx.JSON(http.StatusInternalServerError, resp)
 		}
@@ -753,3 +776,377 @@ func (r *PolicyRouter) listEntitlement(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, resp)
 	return
 }
+
+// createKeys loads or generates keys for the key pool of a policy.
+//
+// @Summary 		API to load keys into the key pool of a policy resource
+// @Description 	Loading the provided keys, or generating `count` keys, into the key pool of a policy resource
+// @Tags 			policy
+// @Accept 			json
+// @Produce 		json
+// @Security        BearerAuth
+// @Param 			param    			path 		policy_attribute.PolicyCommonURI     true 	"path_param"
+// @Param 			payload 			body 		policies.PolicyKeyCreationRequest 	true 	"request"
+// @Success 		201 				{object} 	response.Response
+// @Failure 		400 				{object} 	response.Response
+// @Failure 		500 				{object} 	response.Response
+// @Router 			/tenants/{tenant_name}/policies/{policy_id}/keys [post]
+func (r *PolicyRouter) createKeys(ctx *gin.Context) {
+	rootCtx, span := r.tracer.Start(ctx, ctx.Request.URL.Path, trace.WithAttributes(attribute.KeyValue{
+		Key:   constants.RequestIDField,
+		Value: attribute.StringValue(ctx.GetString(constants.RequestIDField)),
+	}))
+	defer span.End()
+
+	resp := response.NewResponse(ctx)
+	r.logger.WithCustomFields(
+		zap.String(constants.RequestIDField, ctx.GetString(constants.RequestIDField)),
+		zap.String(constants.ContextValueSubject, ctx.GetString(constants.ContextValueSubject)),
+	).Info("received new policy key creation request")
+
+	// serializer
+	r.logger.GetLogger().Info("validating policy key creation request")
+	_, cSpan := r.tracer.Start(rootCtx, "serializer")
+	var uriReq policy_attribute.PolicyCommonURI
+	err := ctx.ShouldBindUri(&uriReq)
+	if err != nil {
+		cSpan.End()
+		r.logger.GetLogger().Error(err.Error())
+		resp.ToResponse(cerrors.ErrCodeMapper[cerrors.ErrGenericBadRequest], cerrors.ErrMessageMapper[cerrors.ErrGenericBadRequest], nil, nil, nil)
+		ctx.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	var bodyReq PolicyKeyCreationRequest
+	err = ctx.ShouldBind(&bodyReq)
+	if err != nil {
+		cSpan.End()
+		r.logger.GetLogger().Error(err.Error())
+		resp.ToResponse(cerrors.ErrCodeMapper[cerrors.ErrGenericBadRequest], cerrors.ErrMessageMapper[cerrors.ErrGenericBadRequest], nil, nil, nil)
+		ctx.JSON(http.StatusBadRequest, resp)
+		return
+	}
+	cSpan.End()
+
+	// validation
+	_, cSpan = r.tracer.Start(rootCtx, "validation")
+	err = uriReq.Validate()
+	if err != nil {
+		cSpan.End()
+		r.logger.GetLogger().Error(err.Error())
+		resp.ToResponse(cerrors.ErrCodeMapper[err], cerrors.ErrMessageMapper[err], nil, nil, nil)
+		ctx.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	err = bodyReq.Validate()
+	if err != nil {
+		cSpan.End()
+		r.logger.GetLogger().Error(err.Error())
+		resp.ToResponse(cerrors.ErrCodeMapper[err], cerrors.ErrMessageMapper[err], nil, nil, nil)
+		ctx.JSON(http.StatusBadRequest, resp)
+		return
+	}
+	cSpan.End()
+
+	// handler
+	_, cSpan = r.tracer.Start(rootCtx, "handler")
+	result, err := r.svc.CreateKeys(ctx, bodyReq.ToPolicyKeyCreationInput(rootCtx, r.tracer, uriReq))
+	if err != nil {
+		r.logger.GetLogger().Error(err.Error())
+		cSpan.End()
+		resp.ToResponse(result.Code, result.Message, result.Data, nil, nil)
+		switch {
+		case errors.Is(err, cerrors.ErrTenantNameIsInvalid),
+			errors.Is(err, cerrors.ErrPolicyIDIsInvalid),
+			errors.Is(err, cerrors.ErrPolicyIsNotPooled),
+			errors.Is(err, cerrors.ErrPolicyKeyAlreadyExist):
+			ctx.JSON(http.StatusBadRequest, resp)
+		case errors.Is(err, cerrors.ErrPolicyIsProtected):
+			ctx.JSON(http.StatusForbidden, resp)
+		default:
+			ctx.JSON(http.StatusInternalServerError, resp)
+		}
+		return
+	}
+	cSpan.End()
+
+	r.logger.GetLogger().Info("completed creating policy keys")
+	resp.ToResponse(result.Code, result.Message, result.Data, nil, result.Count)
+	ctx.JSON(http.StatusCreated, resp)
+	return
+}
+
+// listKeys lists the keys remaining in the key pool of a policy.
+//
+// @Summary 		API to list the key pool of a policy resource
+// @Description 	Listing the keys remaining in the key pool of a policy resource, the count is the remaining pool size
+// @Tags 			policy
+// @Accept 			json
+// @Produce 		json
+// @Security        BearerAuth
+// @Param 			param    			path 		policy_attribute.PolicyCommonURI     true 	"path_param"
+// @Param 			payload 			query 		policies.PolicyKeyListRequest 		true 	"request"
+// @Success 		200 				{object} 	response.Response
+// @Failure 		400 				{object} 	response.Response
+// @Failure 		500 				{object} 	response.Response
+// @Router 			/tenants/{tenant_name}/policies/{policy_id}/keys [get]
+func (r *PolicyRouter) listKeys(ctx *gin.Context) {
+	rootCtx, span := r.tracer.Start(ctx, ctx.Request.URL.Path, trace.WithAttributes(attribute.KeyValue{
+		Key:   constants.RequestIDField,
+		Value: attribute.StringValue(ctx.GetString(constants.RequestIDField)),
+	}))
+	defer span.End()
+
+	resp := response.NewResponse(ctx)
+	r.logger.WithCustomFields(
+		zap.String(constants.RequestIDField, ctx.GetString(constants.RequestIDField)),
+		zap.String(constants.ContextValueSubject, ctx.GetString(constants.ContextValueSubject)),
+	).Info("received new policy key list request")
+
+	// serializer
+	r.logger.GetLogger().Info("validating policy key listing request")
+	var uriReq policy_attribute.PolicyCommonURI
+	_, cSpan := r.tracer.Start(rootCtx, "serializer")
+	err := ctx.ShouldBindUri(&uriReq)
+	if err != nil {
+		cSpan.End()
+		r.logger.GetLogger().Error(err.Error())
+		resp.ToResponse(cerrors.ErrCodeMapper[cerrors.ErrGenericBadRequest], cerrors.ErrMessageMapper[cerrors.ErrGenericBadRequest], nil, nil, nil)
+		ctx.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	var bodyReq PolicyKeyListRequest
+	err = ctx.ShouldBind(&bodyReq)
+	if err != nil {
+		cSpan.End()
+		r.logger.GetLogger().Error(err.Error())
+		resp.ToResponse(cerrors.ErrCodeMapper[cerrors.ErrGenericBadRequest], cerrors.ErrMessageMapper[cerrors.ErrGenericBadRequest], nil, nil, nil)
+		ctx.JSON(http.StatusBadRequest, resp)
+		return
+	}
+	cSpan.End()
+
+	// validation
+	_, cSpan = r.tracer.Start(rootCtx, "validation")
+	err = uriReq.Validate()
+	if err != nil {
+		cSpan.End()
+		r.logger.GetLogger().Error(err.Error())
+		resp.ToResponse(cerrors.ErrCodeMapper[err], cerrors.ErrMessageMapper[err], nil, nil, nil)
+		ctx.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	err = bodyReq.Validate()
+	if err != nil {
+		cSpan.End()
+		r.logger.GetLogger().Error(err.Error())
+		resp.ToResponse(cerrors.ErrCodeMapper[err], cerrors.ErrMessageMapper[err], nil, nil, nil)
+		ctx.JSON(http.StatusBadRequest, resp)
+		return
+	}
+	cSpan.End()
+
+	// handler
+	_, cSpan = r.tracer.Start(rootCtx, "handler")
+	result, err := r.svc.ListKeys(ctx, bodyReq.ToPolicyKeyListInput(rootCtx, r.tracer, uriReq))
+	if err != nil {
+		cSpan.End()
+		r.logger.GetLogger().Error(err.Error())
+		resp.ToResponse(result.Code, result.Message, result.Data, nil, nil)
+		switch {
+		case errors.Is(err, cerrors.ErrTenantNameIsInvalid),
+			errors.Is(err, cerrors.ErrPolicyIDIsInvalid):
+			ctx.JSON(http.StatusBadRequest, resp)
+		default:
+			ctx.JSON(http.StatusInternalServerError, resp)
+		}
+		return
+	}
+	cSpan.End()
+
+	r.logger.GetLogger().Info("completed listing policy keys")
+	resp.ToResponse(result.Code, result.Message, result.Data, nil, result.Count)
+	ctx.JSON(http.StatusOK, resp)
+	return
+}
+
+// rotateKeys replaces the signing key pair of a policy. Certificates and license keys signed with the previous key
+// remain valid until the end of the overlap period.
+//
+// @Summary 		API to rotate the signing keys of a policy resource
+// @Description 	Generating a new signing key pair for a policy resource, the previous key is retired after `overlap_period` seconds
+// @Tags 			policy
+// @Accept 			json
+// @Produce 		json
+// @Security        BearerAuth
+// @Param 			param    			path 		policy_attribute.PolicyCommonURI     true 	"path_param"
+// @Param 			payload 			body 		policies.PolicyKeyRotationRequest 	true 	"request"
+// @Success 		200 				{object} 	response.Response
+// @Failure 		400 				{object} 	response.Response
+// @Failure 		403 				{object} 	response.Response
+// @Failure 		500 				{object} 	response.Response
+// @Router 			/tenants/{tenant_name}/policies/{policy_id}/actions/rotate-keys [post]
+func (r *PolicyRouter) rotateKeys(ctx *gin.Context) {
+	rootCtx, span := r.tracer.Start(ctx, ctx.Request.URL.Path, trace.WithAttributes(attribute.KeyValue{
+		Key:   constants.RequestIDField,
+		Value: attribute.StringValue(ctx.GetString(constants.RequestIDField)),
+	}))
+	defer span.End()
+
+	resp := response.NewResponse(ctx)
+	r.logger.WithCustomFields(
+		zap.String(constants.RequestIDField, ctx.GetString(constants.RequestIDField)),
+		zap.String(constants.ContextValueSubject, ctx.GetString(constants.ContextValueSubject)),
+	).Info("received new policy key rotation request")
+
+	// serializer
+	r.logger.GetLogger().Info("validating policy key rotation request")
+	_, cSpan := r.tracer.Start(rootCtx, "serializer")
+	var uriReq policy_attribute.PolicyCommonURI
+	err := ctx.ShouldBindUri(&uriReq)
+	if err != nil {
+		cSpan.End()
+		r.logger.GetLogger().Error(err.Error())
+		resp.ToResponse(cerrors.ErrCodeMapper[cerrors.ErrGenericBadRequest], cerrors.ErrMessageMapper[cerrors.ErrGenericBadRequest], nil, nil, nil)
+		ctx.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	var bodyReq PolicyKeyRotationRequest
+	err = ctx.ShouldBind(&bodyReq)
+	if err != nil {
+		cSpan.End()
+		r.logger.GetLogger().Error(err.Error())
+		resp.ToResponse(cerrors.ErrCodeMapper[cerrors.ErrGenericBadRequest], cerrors.ErrMessageMapper[cerrors.ErrGenericBadRequest], nil, nil, nil)
+		ctx.JSON(http.StatusBadRequest, resp)
+		return
+	}
+	cSpan.End()
+
+	// validation
+	_, cSpan = r.tracer.Start(rootCtx, "validation")
+	err = uriReq.Validate()
+	if err != nil {
+		cSpan.End()
+		r.logger.GetLogger().Error(err.Error())
+		resp.ToResponse(cerrors.ErrCodeMapper[err], cerrors.ErrMessageMapper[err], nil, nil, nil)
+		ctx.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	err = bodyReq.Validate()
+	if err != nil {
+		cSpan.End()
+		r.logger.GetLogger().Error(err.Error())
+		resp.ToResponse(cerrors.ErrCodeMapper[err], cerrors.ErrMessageMapper[err], nil, nil, nil)
+		ctx.JSON(http.StatusBadRequest, resp)
+		return
+	}
+	cSpan.End()
+
+	// handler
+	_, cSpan = r.tracer.Start(rootCtx, "handler")
+	result, err := r.svc.RotateKeys(ctx, bodyReq.ToPolicyKeyRotationInput(rootCtx, r.tracer, uriReq))
+	if err != nil {
+		r.logger.GetLogger().Error(err.Error())
+		cSpan.End()
+		resp.ToResponse(result.Code, result.Message, result.Data, nil, nil)
+		switch {
+		case errors.Is(err, cerrors.ErrTenantNameIsInvalid),
+			errors.Is(err, cerrors.ErrPolicyIDIsInvalid),
+			errors.Is(err, cerrors.ErrPolicySchemeIsInvalid),
+			errors.Is(err, cerrors.ErrPolicySignerBackendIsInvalid):
+			ctx.JSON(http.StatusBadRequest, resp)
+		case errors.Is(err, cerrors.ErrPolicyIsProtected):
+			ctx.JSON(http.StatusForbidden, resp)
+		default:
+			ctx.JSON(http.StatusInternalServerError, resp)
+		}
+		return
+	}
+	cSpan.End()
+
+	r.logger.GetLogger().Info("completed rotating policy keys")
+	resp.ToResponse(result.Code, result.Message, result.Data, nil, nil)
+	ctx.JSON(http.StatusOK, resp)
+	return
+}
+
+// listSigningKeys lists the signing keys of a policy, the active key first followed by the rotated and retired keys.
+//
+// @Summary 		API to list the signing keys of a policy resource
+// @Description 	Listing the active, rotated and retired public keys of a policy resource along with their key IDs
+// @Tags 			policy
+// @Accept 			json
+// @Produce 		json
+// @Security        BearerAuth
+// @Param 			payload 			path 		policies.PolicySigningKeyListRequest 	true 	"request"
+// @Success 		200 				{object} 	response.Response
+// @Failure 		400 				{object} 	response.Response
+// @Failure 		500 				{object} 	response.Response
+// @Router 			/tenants/{tenant_name}/policies/{policy_id}/signing-keys [get]
+func (r *PolicyRouter) listSigningKeys(ctx *gin.Context) {
+	rootCtx, span := r.tracer.Start(ctx, ctx.Request.URL.Path, trace.WithAttributes(attribute.KeyValue{
+		Key:   constants.RequestIDField,
+		Value: attribute.StringValue(ctx.GetString(constants.RequestIDField)),
+	}))
+	defer span.End()
+
+	resp := response.NewResponse(ctx)
+	r.logger.WithCustomFields(
+		zap.String(constants.RequestIDField, ctx.GetString(constants.RequestIDField)),
+		zap.String(constants.ContextValueSubject, ctx.GetString(constants.ContextValueSubject)),
+	).Info("received new policy signing key list request")
+
+	// serializer
+	r.logger.GetLogger().Info("validating policy signing key listing request")
+	var req PolicySigningKeyListRequest
+	_, cSpan := r.tracer.Start(rootCtx, "serializer")
+	err := ctx.ShouldBindUri(&req)
+	if err != nil {
+		cSpan.End()
+		r.logger.GetLogger().Error(err.Error())
+		resp.ToResponse(cerrors.ErrCodeMapper[cerrors.ErrGenericBadRequest], cerrors.ErrMessageMapper[cerrors.ErrGenericBadRequest], nil, nil, nil)
+		ctx.JSON(http.StatusBadRequest, resp)
+		return
+	}
+	cSpan.End()
+
+	// validation
+	_, cSpan = r.tracer.Start(rootCtx, "validation")
+	err = req.Validate()
+	if err != nil {
+		cSpan.End()
+		r.logger.GetLogger().Error(err.Error())
+		resp.ToResponse(cerrors.ErrCodeMapper[err], cerrors.ErrMessageMapper[err], nil, nil, nil)
+		ctx.JSON(http.StatusBadRequest, resp)
+		return
+	}
+	cSpan.End()
+
+	// handler
+	_, cSpan = r.tracer.Start(rootCtx, "handler")
+	result, err := r.svc.ListSigningKeys(ctx, req.ToPolicySigningKeyListInput(rootCtx, r.tracer))
+	if err != nil {
+		cSpan.End()
+		r.logger.GetLogger().Error(err.Error())
+		resp.ToResponse(result.Code, result.Message, result.Data, nil, nil)
+		switch {
+		case errors.Is(err, cerrors.ErrTenantNameIsInvalid),
+			errors.Is(err, cerrors.ErrPolicyIDIsInvalid):
+			ctx.JSON(http.StatusBadRequest, resp)
+		default:
+			ctx.JSON(http.StatusInternalServerError, resp)
+		}
+		return
+	}
+	cSpan.End()
+
+	r.logger.GetLogger().Info("completed listing policy signing keys")
+	resp.ToResponse(result.Code, result.Message, result.Data, nil, result.Count)
+	ctx.JSON(http.StatusOK, resp)
+	return
+}
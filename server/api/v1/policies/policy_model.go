@@ -12,7 +12,8 @@ import (
 )
 
 type PolicyRegistrationRequest struct {
-	ProductID *string `json:"product_id" validate:"required" example:"test"`
+	ProductID     *string `json:"product_id" validate:"required" example:"test"`
+	SignerBackend *string `json:"signer_backend" validate:"optional" example:"database"` // SignerBackend: Where the private key is generated and kept. Default to the tenant signer backend
 	policy_attribute.PolicyAttributeModel
 }
 
@@ -53,6 +54,13 @@ func (req *PolicyRegistrationRequest) Validate() error {
 		}
 	}
 
+	// The backend holding the private key. Default to the signer backend of the tenant
+	if req.SignerBackend != nil {
+		if _, ok := constants.ValidPolicySignerBackendMapper[utils.DerefPointer(req.SignerBackend)]; !ok {
+			return cerrors.ErrPolicySignerBackendIsInvalid
+		}
+	}
+
 	// The strategy for expired licenses during a license validation.
 	if req.ExpirationStrategy == nil {
 		req.ExpirationStrategy = utils.RefPointer(constants.PolicyExpirationStrategyRevokeAccess)
@@ -89,6 +97,15 @@ func (req *PolicyRegistrationRequest) Validate() error {
 		}
 	}
 
+	// Control whether a dead machine can be resurrected by a late heartbeat ping.
+	if req.HeartbeatResurrectionStrategy == nil {
+		req.HeartbeatResurrectionStrategy = utils.RefPointer(constants.PolicyHeartbeatResurrectionStrategyNoRevive)
+	} else {
+		if _, ok := constants.ValidPolicyHeartbeatResurrectionStrategyMapper[utils.DerefPointer(req.HeartbeatResurrectionStrategy)]; !ok {
+			return cerrors.ErrPolicyInvalidResurrectionStrategy
+		}
+	}
+
 	// Control the time duration between each checkin
 	if req.CheckInInterval == nil {
 		req.CheckInInterval = utils.RefPointer(constants.PolicyCheckinIntervalDaily)
@@ -115,6 +132,14 @@ func (req *PolicyRegistrationRequest) Validate() error {
 		req.UsePool = utils.RefPointer(false)
 	}
 
+	// Whether license keys are signed tokens that can be verified offline. Signed keys cannot be pulled from a pool
+	if req.SignedKeys == nil {
+		req.SignedKeys = utils.RefPointer(false)
+	}
+	if utils.DerefPointer(req.SignedKeys) && utils.DerefPointer(req.UsePool) {
+		return cerrors.ErrPolicySignedKeysWithPool
+	}
+
 	//  Whether the policy is protected.
 	if req.Protected == nil {
 		req.Protected = utils.RefPointer(false)
@@ -184,6 +209,7 @@ func (req *PolicyRegistrationRequest) ToPolicyRegistrationInput(ctx context.Cont
 		Tracer:               tracer,
 		PolicyCommonURI:      policyURI,
 		ProductID:            req.ProductID,
+		SignerBackend:        req.SignerBackend,
 		PolicyAttributeModel: req.PolicyAttributeModel,
 	}
 }
@@ -193,6 +219,10 @@ type PolicyUpdateRequest struct {
 }
 
 func (req *PolicyUpdateRequest) Validate() error {
+	if utils.DerefPointer(req.SignedKeys) && utils.DerefPointer(req.UsePool) {
+		return cerrors.ErrPolicySignedKeysWithPool
+	}
+
 	if req.Scheme != nil {
 		if _, ok := constants.ValidPolicySchemeMapper[utils.DerefPointer(req.Scheme)]; !ok {
 			return cerrors.ErrPolicySchemeIsInvalid
@@ -259,6 +289,12 @@ func (req *PolicyUpdateRequest) Validate() error {
 		}
 	}
 
+	if req.HeartbeatResurrectionStrategy != nil {
+		if _, ok := constants.ValidPolicyHeartbeatResurrectionStrategyMapper[utils.DerefPointer(req.HeartbeatResurrectionStrategy)]; !ok {
+			return cerrors.ErrPolicyInvalidResurrectionStrategy
+		}
+	}
+
 	if req.CheckInInterval != nil {
 		if _, ok := constants.ValidPolicyCheckinIntervalMapper[utils.DerefPointer(req.CheckInInterval)]; !ok {
 			return cerrors.ErrPolicyInvalidCheckinInterval
@@ -400,3 +436,118 @@ func (req *PolicyEntitlementListRequest) ToPolicyEntitlementListInput(ctx contex
 		QueryCommonParam: req.QueryCommonParam,
 	}
 }
+
+type PolicyKeyCreationRequest struct {
+	Keys  []string `json:"keys" validate:"optional" example:"test"`
+	Count *int     `json:"count" validate:"optional" example:"10"`
+}
+
+func (req *PolicyKeyCreationRequest) Validate() error {
+	if len(req.Keys) == 0 && req.Count == nil {
+		return cerrors.ErrPolicyKeysAreEmpty
+	}
+
+	if len(req.Keys) > 0 {
+		if len(req.Keys) > constants.MaxPolicyKeyPoolBatchSize {
+			return cerrors.ErrPolicyKeyCountIsInvalid
+		}
+		for _, key := range req.Keys {
+			if key == "" {
+				return cerrors.ErrPolicyKeysAreEmpty
+			}
+		}
+		return nil
+	}
+
+	if utils.DerefPointer(req.Count) <= 0 || utils.DerefPointer(req.Count) > constants.MaxPolicyKeyPoolBatchSize {
+		return cerrors.ErrPolicyKeyCountIsInvalid
+	}
+	return nil
+}
+
+func (req *PolicyKeyCreationRequest) ToPolicyKeyCreationInput(ctx context.Context, tracer trace.Tracer, policyURI policy_attribute.PolicyCommonURI) *models.PolicyKeyCreationInput {
+	return &models.PolicyKeyCreationInput{
+		TracerCtx:       ctx,
+		Tracer:          tracer,
+		PolicyCommonURI: policyURI,
+		Keys:            req.Keys,
+		Count:           utils.DerefPointer(req.Count),
+	}
+}
+
+type PolicyKeyListRequest struct {
+	constants.QueryCommonParam
+}
+
+func (req *PolicyKeyListRequest) Validate() error {
+	req.QueryCommonParam.Validate()
+	return nil
+}
+
+func (req *PolicyKeyListRequest) ToPolicyKeyListInput(ctx context.Context, tracer trace.Tracer, policyURI policy_attribute.PolicyCommonURI) *models.PolicyKeyListInput {
+	return &models.PolicyKeyListInput{
+		TracerCtx:        ctx,
+		Tracer:           tracer,
+		PolicyCommonURI:  policyURI,
+		QueryCommonParam: req.QueryCommonParam,
+	}
+}
+
+type PolicyKeyRotationRequest struct {
+	Scheme        *string `json:"scheme" validate:"optional" example:"ED25519"`
+	OverlapPeriod *int64  `json:"overlap_period" validate:"optional" example:"2592000"`
+	SignerBackend *string `json:"signer_backend" validate:"optional" example:"pkcs11"`
+}
+
+func (req *PolicyKeyRotationRequest) Validate() error {
+	if req.Scheme != nil {
+		if _, ok := constants.ValidPolicySchemeMapper[utils.DerefPointer(req.Scheme)]; !ok {
+			return cerrors.ErrPolicySchemeIsInvalid
+		}
+	}
+
+	if req.SignerBackend != nil {
+		if _, ok := constants.ValidPolicySignerBackendMapper[utils.DerefPointer(req.SignerBackend)]; !ok {
+			return cerrors.ErrPolicySignerBackendIsInvalid
+		}
+	}
+
+	if req.OverlapPeriod == nil {
+		req.OverlapPeriod = utils.RefPointer(int64(constants.PolicyKeyDefaultOverlapPeriod))
+	}
+
+	if utils.DerefPointer(req.OverlapPeriod) < 0 {
+		return cerrors.ErrPolicyKeyOverlapPeriodIsInvalid
+	}
+	return nil
+}
+
+func (req *PolicyKeyRotationRequest) ToPolicyKeyRotationInput(ctx context.Context, tracer trace.Tracer, policyURI policy_attribute.PolicyCommonURI) *models.PolicyKeyRotationInput {
+	return &models.PolicyKeyRotationInput{
+		TracerCtx:       ctx,
+		Tracer:          tracer,
+		PolicyCommonURI: policyURI,
+		Scheme:          req.Scheme,
+		OverlapPeriod:   utils.DerefPointer(req.OverlapPeriod),
+		SignerBackend:   req.SignerBackend,
+	}
+}
+
+type PolicySigningKeyListRequest struct {
+	policy_attribute.PolicyCommonURI
+}
+
+func (req *PolicySigningKeyListRequest) Validate() error {
+	if req.PolicyID == nil {
+		return cerrors.ErrPolicyIDIsEmpty
+	}
+	return req.PolicyCommonURI.Validate()
+}
+
+func (req *PolicySigningKeyListRequest) ToPolicySigningKeyListInput(ctx context.Context, tracer trace.Tracer) *models.PolicySigningKeyListInput {
+	return &models.PolicySigningKeyListInput{
+		TracerCtx:       ctx,
+		Tracer:          tracer,
+		PolicyCommonURI: req.PolicyCommonURI,
+	}
+}
@@ -466,7 +466,9 @@ func (r *ProductRouter) list(ctx *gin.Context) {
 	return
 }
 
-// tokens generates a new product token resource. Product tokens do not expire.
+// tokens generates a new product token resource. The token authenticates the requests sent with the X-API-Key
+// header, with the permissions granted to it, on the resources of the product only. It expires at `expiry` if set.
+// Only the hash of the token is stored, so the token is only returned in this response.
 //
 // @Summary 		API to generate product token resource
 // @Description 	Generate product token
@@ -478,6 +480,7 @@ func (r *ProductRouter) list(ctx *gin.Context) {
 // @Param 			payload 			body 		products.ProductTokenRequest 	    true 	"request"
 // @Success 		200 				{object} 	response.Response
 // @Failure 		400 				{object} 	response.Response
+// @Failure 		403 				{object} 	response.Response
 // @Failure 		500 				{object} 	response.Response
 // @Router 			/tenants/{tenant_name}/products/{product_id}/tokens [post]
 func (r *ProductRouter) tokens(ctx *gin.Context) {
@@ -536,8 +539,11 @@ func (r *ProductRouter) tokens(ctx *gin.Context) {
 		r.logger.GetLogger().Error(err.Error())
 		resp.ToResponse(result.Code, result.Message, result.Data, nil, nil)
 		switch {
-		case errors.Is(err, cerrors.ErrTenantNameIsInvalid):
+		case errors.Is(err, cerrors.ErrTenantNameIsInvalid),
+			errors.Is(err, cerrors.ErrProductIDIsInvalid):
 			ctx.JSON(http.StatusBadRequest, resp)
+		case errors.Is(err, cerrors.ErrProductTokenPermissionIsInvalid):
+			ctx.JSON(http.StatusForbidden, resp)
 		default:
 			ctx.JSON(http.StatusInternalServerError, resp)
 		}
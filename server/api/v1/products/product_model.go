@@ -5,6 +5,7 @@ import (
 	"go-license-management/internal/cerrors"
 	"go-license-management/internal/constants"
 	"go-license-management/internal/infrastructure/models/product_attribute"
+	"go-license-management/internal/permissions"
 	"go-license-management/internal/services/v1/products/models"
 	"go-license-management/internal/utils"
 	"go.opentelemetry.io/otel/trace"
@@ -122,21 +123,27 @@ func (req *ProductDeletionRequest) ToProductDeletionInput(ctx context.Context, t
 }
 
 type ProductTokenRequest struct {
-	Name        *string  `json:"name" validate:"optional" example:"test"`
-	Expiry      *string  `json:"expiry" validate:"optional" example:"test"`
-	Permissions []string `json:"permissions" validate:"required" example:"test"`
+	Name        *string  `json:"name" validate:"optional" example:"ci"`
+	Expiry      *string  `json:"expiry" validate:"optional" example:"2030-01-01T00:00:00.000Z"`
+	Permissions []string `json:"permissions" validate:"optional" example:"license.create,license.read"`
 }
 
 func (req *ProductTokenRequest) Validate() error {
 
 	if req.Expiry != nil {
-		_, err := time.Parse(constants.DateFormatISO8601Hyphen, utils.DerefPointer(req.Expiry))
+		expiry, err := time.Parse(constants.DateFormatISO8601Hyphen, utils.DerefPointer(req.Expiry))
 		if err != nil {
 			return cerrors.ErrProductTokenExpirationFormatIsInvalid
 		}
+		if !expiry.After(time.Now()) {
+			return cerrors.ErrProductTokenExpiryIsInvalid
+		}
 	}
 	if req.Permissions == nil {
-		req.Permissions = []string{"*"}
+		req.Permissions = []string{permissions.ProductTokenPermissionWildcard}
+	}
+	if len(req.Permissions) == 0 || !permissions.ValidateProductTokenPermissions(req.Permissions) {
+		return cerrors.ErrProductTokenPermissionIsInvalid
 	}
 
 	return nil
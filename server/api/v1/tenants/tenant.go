@@ -106,7 +106,8 @@ func (r *TenantRouter) create(ctx *gin.Context) {
 		r.logger.GetLogger().Error(err.Error())
 		resp.ToResponse(result.Code, result.Message, result.Data, nil, nil)
 		switch {
-		case errors.Is(err, cerrors.ErrTenantNameAlreadyExist):
+		case errors.Is(err, cerrors.ErrTenantNameAlreadyExist),
+			errors.Is(err, cerrors.ErrTenantSignerBackendIsInvalid):
 			ctx.JSON(http.StatusBadRequest, resp)
 		default:
 			ctx.JSON(http.StatusInternalServerError, resp)
@@ -334,14 +335,17 @@ func (r *TenantRouter) delete(ctx *gin.Context) {
 }
 
 // regenerate generates a new private/public key pair for a tenant resource by id.
+// In `graceful` mode, the access tokens signed with the previous key are accepted until they expire.
+// In `emergency` mode, they are revoked immediately.
 //
 // @Summary 		API to replace tenant resource's private/public key pair
-// @Description 	Replace tenant resource's private/public key pair
+// @Description 	Replace tenant resource's private/public key pair, either gracefully or revoking outstanding tokens
 // @Tags 			tenant
 // @Accept 			json
 // @Produce 		json
 // @Security        BearerAuth
 // @Param 			payload 			path 		tenants.TenantRegenerationRequest 	true 	"request"
+// @Param 			mode 				query 		string 								false 	"graceful (default) or emergency"
 // @Success 		200 				{object} 	response.Response
 // @Failure 		400 				{object} 	response.Response
 // @Failure 		500 				{object} 	response.Response
@@ -370,6 +374,15 @@ func (r *TenantRouter) regenerate(ctx *gin.Context) {
 		ctx.JSON(http.StatusBadRequest, resp)
 		return
 	}
+
+	err = ctx.ShouldBindQuery(&req)
+	if err != nil {
+		cSpan.End()
+		r.logger.GetLogger().Error(err.Error())
+		resp.ToResponse(cerrors.ErrCodeMapper[cerrors.ErrGenericBadRequest], cerrors.ErrMessageMapper[cerrors.ErrGenericBadRequest], nil, nil, nil)
+		ctx.JSON(http.StatusBadRequest, resp)
+		return
+	}
 	cSpan.End()
 
 	// validation
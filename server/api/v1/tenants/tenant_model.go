@@ -5,11 +5,13 @@ import (
 	"go-license-management/internal/cerrors"
 	"go-license-management/internal/constants"
 	"go-license-management/internal/services/v1/tenants/models"
+	"go-license-management/internal/utils"
 	"go.opentelemetry.io/otel/trace"
 )
 
 type TenantRegistrationRequest struct {
-	Name *string `form:"name" validate:"required" example:"test"`
+	Name          *string `form:"name" validate:"required" example:"test"`
+	SignerBackend *string `form:"signer_backend" validate:"optional" example:"database"`
 }
 
 func (req *TenantRegistrationRequest) Validate() error {
@@ -17,14 +19,24 @@ func (req *TenantRegistrationRequest) Validate() error {
 		return cerrors.ErrTenantNameIsEmpty
 	}
 
+	// The default signer backend of the tenant policies
+	if req.SignerBackend == nil {
+		req.SignerBackend = utils.RefPointer(constants.PolicySignerBackendDatabase)
+	} else {
+		if _, ok := constants.ValidPolicySignerBackendMapper[utils.DerefPointer(req.SignerBackend)]; !ok {
+			return cerrors.ErrTenantSignerBackendIsInvalid
+		}
+	}
+
 	return nil
 }
 
 func (req *TenantRegistrationRequest) ToTenantRegistrationInput(ctx context.Context, tracer trace.Tracer) *models.TenantRegistrationInput {
 	return &models.TenantRegistrationInput{
-		TracerCtx: ctx,
-		Tracer:    tracer,
-		Name:      req.Name,
+		TracerCtx:     ctx,
+		Tracer:        tracer,
+		Name:          req.Name,
+		SignerBackend: req.SignerBackend,
 	}
 }
 
@@ -85,12 +97,21 @@ func (req *TenantListRequest) ToTenantListInput(ctx context.Context, tracer trac
 
 type TenantRegenerationRequest struct {
 	TenantName *string `uri:"tenant_name" binding:"required"`
+	Mode       *string `form:"mode" validate:"optional" example:"graceful"`
 }
 
 func (req *TenantRegenerationRequest) Validate() error {
 	if req.TenantName == nil {
 		return cerrors.ErrTenantNameIsEmpty
 	}
+
+	if req.Mode == nil {
+		req.Mode = utils.RefPointer(constants.TenantRegenerationModeGraceful)
+	}
+
+	if _, ok := constants.ValidTenantRegenerationModeMapper[utils.DerefPointer(req.Mode)]; !ok {
+		return cerrors.ErrTenantRegenerationModeIsInvalid
+	}
 	return nil
 }
 
@@ -99,5 +120,6 @@ func (req *TenantRegenerationRequest) ToTenantRegenerationInput(ctx context.Cont
 		TracerCtx: ctx,
 		Tracer:    tracer,
 		Name:      req.TenantName,
+		Mode:      req.Mode,
 	}
 }
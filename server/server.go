@@ -27,17 +27,32 @@ func StartServer(appService *api.AppService, quit chan os.Signal) {
 	router := gin.New()
 	router.Use(gin.Recovery())
 
+	// The client IP, which the failed logins are throttled by, is only taken from the X-Forwarded-For header of the
+	// requests sent by the trusted proxies, none by default
+	err := router.SetTrustedProxies(viper.GetStringSlice(config.ServerTrustedProxies))
+	if err != nil {
+		logging.GetInstance().GetLogger().Error(fmt.Sprintf("invalid trusted proxies, trusting none: %s", err.Error()))
+		_ = router.SetTrustedProxies(nil)
+	}
+
 	router.Use(cors.New(cors.Config{
 		AllowOrigins: []string{constants.AllowAllOrigins},
 		AllowMethods: []string{http.MethodPost, http.MethodPatch, http.MethodPut, http.MethodGet, http.MethodDelete},
 		AllowHeaders: []string{constants.AccessControlAllowHeadersHeader, constants.OriginHeader, constants.AcceptHeader,
-			constants.XRequestedWithHeader, constants.ContentTypeHeader, constants.AuthorizationHeader, constants.XAPIKeyHeader},
-		ExposeHeaders:    []string{constants.ContentLengthHeader},
+			constants.XRequestedWithHeader, constants.ContentTypeHeader, constants.AuthorizationHeader, constants.XAPIKeyHeader,
+			constants.XSignatureNonceHeader},
+		ExposeHeaders: []string{constants.ContentLengthHeader, constants.ContentDigestHeader, constants.XRequestIDHeader,
+			constants.SignatureInputHeader, constants.SignatureHeader},
 		AllowCredentials: true,
 	}))
 
+	router.Use(middlewares.RequestIDMW())
+	// The signature wraps the compression, so the content digest covers the content as it is sent
+	if viper.GetBool(config.ServerEnableResponseSigning) {
+		router.Use(middlewares.ResponseSignatureMW())
+	}
 	router.Use(
-		middlewares.RequestIDMW(), middlewares.TimeoutMW(), gzip.Gzip(gzip.DefaultCompression),
+		middlewares.TimeoutMW(), gzip.Gzip(gzip.DefaultCompression),
 		middlewares.Recovery(), middlewares.LoggerMW(logging.GetInstance().GetLogger()), middlewares.HashHeaderMW(),
 	)
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))